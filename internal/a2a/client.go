@@ -0,0 +1,178 @@
+package a2a
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Client sends messages on behalf of an agent
+type Client struct {
+	// Agent is the name messages are sent from
+	Agent string
+	// RegistryURL is the registry that receivers are looked up in. Without
+	// one, a receiver is reached at http://<name>:DefaultAgentPort, its
+	// service name on the compose network.
+	RegistryURL string
+
+	httpClient *http.Client
+}
+
+// NewClient creates a client sending as agent, which looks receivers up in
+// the registry at registryURL, if not empty
+func NewClient(agent, registryURL string) *Client {
+	return &Client{
+		Agent:       agent,
+		RegistryURL: strings.TrimRight(registryURL, "/"),
+		httpClient:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Send sends a message of msgType with payload to toAgent, which is an
+// agent name or the base URL of an agent
+func (c *Client) Send(toAgent, msgType string, payload interface{}) error {
+	if msgType == "" {
+		return fmt.Errorf("message type is required")
+	}
+
+	target, err := c.Resolve(toAgent)
+	if err != nil {
+		return err
+	}
+
+	msg := Message{
+		FromAgent: c.Agent,
+		ToAgent:   toAgent,
+		MessageID: newMessageID(),
+		Type:      msgType,
+		Timestamp: time.Now().UTC(),
+	}
+	if strings.Contains(toAgent, "://") {
+		// The receiver fills in its own name
+		msg.ToAgent = ""
+	}
+	if payload != nil {
+		if msg.Payload, err = json.Marshal(payload); err != nil {
+			return fmt.Errorf("failed to marshal payload: %w", err)
+		}
+	}
+
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	resp, err := c.httpClient.Post(target+MessagePath, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to send message to %s: %w", toAgent, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("agent %s rejected message: %s", toAgent, responseError(resp))
+	}
+	return nil
+}
+
+// Resolve returns the base URL of agent: agent itself if it is a URL, its
+// registered URL if there is a registry, or its address on the compose
+// network
+func (c *Client) Resolve(agent string) (string, error) {
+	if agent == "" {
+		return "", fmt.Errorf("receiving agent is required")
+	}
+	if strings.Contains(agent, "://") {
+		return strings.TrimRight(agent, "/"), nil
+	}
+	if c.RegistryURL == "" {
+		return fmt.Sprintf("http://%s:%d", agent, DefaultAgentPort), nil
+	}
+
+	resp, err := c.httpClient.Get(c.RegistryURL + AgentsPath + "/" + url.PathEscape(agent))
+	if err != nil {
+		return "", fmt.Errorf("failed to query registry: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", fmt.Errorf("agent '%s' is not registered in %s", agent, c.RegistryURL)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("registry lookup failed: %s", responseError(resp))
+	}
+
+	var registration Registration
+	if err := json.NewDecoder(resp.Body).Decode(&registration); err != nil {
+		return "", fmt.Errorf("failed to decode registration: %w", err)
+	}
+	return strings.TrimRight(registration.URL, "/"), nil
+}
+
+// Register registers the client's agent in the registry as reachable at
+// agentURL
+func (c *Client) Register(agentURL string) error {
+	if c.RegistryURL == "" {
+		return fmt.Errorf("no registry configured")
+	}
+
+	body, err := json.Marshal(Registration{Name: c.Agent, URL: agentURL})
+	if err != nil {
+		return fmt.Errorf("failed to marshal registration: %w", err)
+	}
+
+	resp, err := c.httpClient.Post(c.RegistryURL+RegisterPath, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to register with %s: %w", c.RegistryURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("registration failed: %s", responseError(resp))
+	}
+	return nil
+}
+
+// Agents returns the agents registered in the registry
+func (c *Client) Agents() ([]Registration, error) {
+	if c.RegistryURL == "" {
+		return nil, fmt.Errorf("no registry configured")
+	}
+
+	resp, err := c.httpClient.Get(c.RegistryURL + AgentsPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query registry: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("registry query failed: %s", responseError(resp))
+	}
+
+	var agents []Registration
+	if err := json.NewDecoder(resp.Body).Decode(&agents); err != nil {
+		return nil, fmt.Errorf("failed to decode agents: %w", err)
+	}
+	return agents, nil
+}
+
+// responseError returns the error reported in a failed response
+func responseError(resp *http.Response) string {
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	// Generated Python agents report errors in FastAPI's detail field
+	var body struct {
+		Error  string `json:"error"`
+		Detail string `json:"detail"`
+	}
+	if json.Unmarshal(data, &body) == nil && body.Error+body.Detail != "" {
+		return fmt.Sprintf("%s (status %d)", body.Error+body.Detail, resp.StatusCode)
+	}
+	if text := strings.TrimSpace(string(data)); text != "" {
+		return fmt.Sprintf("%s (status %d)", text, resp.StatusCode)
+	}
+	return fmt.Sprintf("status %d", resp.StatusCode)
+}
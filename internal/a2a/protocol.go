@@ -0,0 +1,65 @@
+// Package a2a implements the agent2agent protocol, through which agents in
+// a compose stack send each other messages. Messages are JSON documents
+// POSTed to the receiving agent's MessagePath. Agents find each other
+// through a registry sidecar they register with at startup, or by their
+// service name on the stack's network.
+package a2a
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// HTTP paths of the protocol
+const (
+	// MessagePath is where an agent receives messages
+	MessagePath = "/a2a/messages"
+	// RegisterPath is where the registry accepts registrations
+	RegisterPath = "/register"
+	// AgentsPath is where the registry lists registered agents; the
+	// registration of one agent is at AgentsPath/<name>
+	AgentsPath = "/agents"
+)
+
+// DefaultAgentPort is the port an agent is reached on when it is not
+// registered, the port generated agents listen on
+const DefaultAgentPort = 8080
+
+// Message is the wire format of a message between agents
+type Message struct {
+	FromAgent string          `json:"from_agent"`
+	ToAgent   string          `json:"to_agent"`
+	MessageID string          `json:"message_id"`
+	Type      string          `json:"type"`
+	Payload   json.RawMessage `json:"payload,omitempty"`
+	Timestamp time.Time       `json:"timestamp"`
+}
+
+// DecodePayload unmarshals the message payload into v
+func (m *Message) DecodePayload(v interface{}) error {
+	if len(m.Payload) == 0 {
+		return nil
+	}
+	return json.Unmarshal(m.Payload, v)
+}
+
+// Registration is an agent's entry in the registry
+type Registration struct {
+	Name         string    `json:"name"`
+	URL          string    `json:"url"`
+	RegisteredAt time.Time `json:"registered_at,omitempty"`
+}
+
+// errorResponse is the body of a failed request
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// newMessageID returns a random message ID
+func newMessageID() string {
+	id := make([]byte, 16)
+	rand.Read(id)
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,122 @@
+package a2a
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Registry is the service-discovery sidecar of a compose stack: agents
+// register their URL with it at startup, and clients look receivers up in
+// it. Registrations are kept in memory.
+type Registry struct {
+	mu     sync.RWMutex
+	agents map[string]Registration
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{agents: make(map[string]Registration)}
+}
+
+// Handler returns the HTTP handler serving the registry
+func (r *Registry) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(RegisterPath, r.handleRegister)
+	mux.HandleFunc(AgentsPath, r.handleList)
+	mux.HandleFunc(AgentsPath+"/", r.handleLookup)
+	mux.HandleFunc("/health", func(w http.ResponseWriter, req *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "healthy"})
+	})
+	return mux
+}
+
+// ListenAndServe serves the registry on addr until the server fails
+func (r *Registry) ListenAndServe(addr string) error {
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           r.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return server.ListenAndServe()
+}
+
+// Register adds or replaces the registration of an agent
+func (r *Registry) Register(registration Registration) error {
+	if registration.Name == "" {
+		return fmt.Errorf("agent name is required")
+	}
+	u, err := url.Parse(registration.URL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("invalid agent URL '%s'", registration.URL)
+	}
+
+	registration.URL = strings.TrimRight(registration.URL, "/")
+	registration.RegisteredAt = time.Now().UTC()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.agents[registration.Name] = registration
+	return nil
+}
+
+// Lookup returns the registration of the named agent
+func (r *Registry) Lookup(name string) (Registration, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	registration, ok := r.agents[name]
+	return registration, ok
+}
+
+// Agents returns every registration, sorted by name
+func (r *Registry) Agents() []Registration {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	agents := make([]Registration, 0, len(r.agents))
+	for _, registration := range r.agents {
+		agents = append(agents, registration)
+	}
+	sort.Slice(agents, func(i, j int) bool { return agents[i].Name < agents[j].Name })
+	return agents
+}
+
+func (r *Registry) handleRegister(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var registration Registration
+	if err := json.NewDecoder(req.Body).Decode(&registration); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid registration: %v", err))
+		return
+	}
+	if err := r.Register(registration); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	registration, _ = r.Lookup(registration.Name)
+	writeJSON(w, http.StatusOK, registration)
+}
+
+func (r *Registry) handleList(w http.ResponseWriter, req *http.Request) {
+	writeJSON(w, http.StatusOK, r.Agents())
+}
+
+func (r *Registry) handleLookup(w http.ResponseWriter, req *http.Request) {
+	name := strings.TrimPrefix(req.URL.Path, AgentsPath+"/")
+	registration, ok := r.Lookup(name)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("agent '%s' is not registered", name))
+		return
+	}
+	writeJSON(w, http.StatusOK, registration)
+}
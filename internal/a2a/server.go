@@ -0,0 +1,94 @@
+package a2a
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// A2AHandler handles a received message. An error is returned to the
+// sender.
+type A2AHandler func(msg Message) error
+
+// Server receives messages for an agent and dispatches them to the handler
+// registered for their type. It is an http.Handler for MessagePath.
+type Server struct {
+	// Agent is the name of the receiving agent; messages addressed to
+	// another agent are rejected
+	Agent string
+
+	mu       sync.RWMutex
+	handlers map[string]A2AHandler
+}
+
+// NewServer creates a server receiving messages for agent
+func NewServer(agent string) *Server {
+	return &Server{
+		Agent:    agent,
+		handlers: make(map[string]A2AHandler),
+	}
+}
+
+// Handle registers the handler for messages of msgType, replacing an
+// earlier one
+func (s *Server) Handle(msgType string, handler A2AHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[msgType] = handler
+}
+
+// ServeHTTP receives a message
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var msg Message
+	if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid message: %v", err))
+		return
+	}
+	if msg.Type == "" {
+		writeError(w, http.StatusBadRequest, "message type is required")
+		return
+	}
+	if msg.ToAgent == "" {
+		msg.ToAgent = s.Agent
+	}
+	if s.Agent != "" && msg.ToAgent != s.Agent {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("message is addressed to '%s', not '%s'", msg.ToAgent, s.Agent))
+		return
+	}
+	if msg.Timestamp.IsZero() {
+		msg.Timestamp = time.Now().UTC()
+	}
+
+	s.mu.RLock()
+	handler, ok := s.handlers[msg.Type]
+	s.mu.RUnlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("no handler for message type '%s'", msg.Type))
+		return
+	}
+
+	if err := handler(msg); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, map[string]string{"message_id": msg.MessageID})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, errorResponse{Error: message})
+}
@@ -9,13 +9,19 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"runtime"
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/pxkundu/agent-as-code/internal/log"
+	"golang.org/x/net/http/httpproxy"
+	"golang.org/x/net/http2"
 )
 
 // Client represents the Binary API client
@@ -30,11 +36,80 @@ func NewClient(baseURL string) *Client {
 	return &Client{
 		BaseURL: strings.TrimSuffix(baseURL, "/"),
 		HTTPClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   30 * time.Second,
+			Transport: newTransport(),
 		},
 	}
 }
 
+// newTransport returns the transport of API clients. Requests go through
+// the proxy named by HTTPS_PROXY or HTTP_PROXY, except for the hosts in
+// NO_PROXY.
+func newTransport() *http.Transport {
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+}
+
+// NewClientWithHTTP2 creates a Binary API client that multiplexes concurrent
+// requests over a single HTTP/2 connection. HTTP/2 is negotiated over TLS;
+// plain http:// URLs and servers without HTTP/2 support use HTTP/1.1.
+func NewClientWithHTTP2(baseURL string) *Client {
+	client := NewClient(baseURL)
+
+	transport := client.HTTPClient.Transport.(*http.Transport)
+	h2, err := http2.ConfigureTransports(transport)
+	if err != nil {
+		log.Warn("HTTP/2 unavailable, using HTTP/1.1", "error", err)
+		return client
+	}
+	// Detect connections that died mid-upload instead of waiting for the
+	// request timeout
+	h2.ReadIdleTimeout = 15 * time.Second
+	h2.PingTimeout = 10 * time.Second
+
+	return client
+}
+
+// SetProxy sends requests through the proxy at proxyURL instead of the one
+// in HTTPS_PROXY or HTTP_PROXY. Hosts in NO_PROXY are still reached
+// directly.
+func (c *Client) SetProxy(proxyURL string) error {
+	// Proxy URLs may carry credentials, so they are only shown redacted
+	parsed, err := url.Parse(proxyURL)
+	if err != nil {
+		return fmt.Errorf("invalid proxy URL")
+	}
+	if parsed.Scheme == "" || parsed.Host == "" {
+		return fmt.Errorf("invalid proxy URL '%s'", parsed.Redacted())
+	}
+
+	transport, ok := c.HTTPClient.Transport.(*http.Transport)
+	if !ok {
+		return fmt.Errorf("HTTP client does not support proxies")
+	}
+
+	env := httpproxy.FromEnvironment()
+	proxy := (&httpproxy.Config{
+		HTTPProxy:  proxyURL,
+		HTTPSProxy: proxyURL,
+		NoProxy:    env.NoProxy,
+	}).ProxyFunc()
+	transport.Proxy = func(req *http.Request) (*url.URL, error) {
+		return proxy(req.URL)
+	}
+	return nil
+}
+
 // SetAuthToken sets the authentication token for API requests
 func (c *Client) SetAuthToken(token string) {
 	c.AuthToken = token
@@ -51,20 +126,27 @@ type BinaryInfo struct {
 	DownloadURL  string `json:"download_url"`
 }
 
+// defaultPerPage is the page size used when auto-paginating
+const defaultPerPage = 100
+
 // VersionsResponse represents the response from the versions endpoint
 type VersionsResponse struct {
 	Success  bool     `json:"success"`
 	Versions []string `json:"versions"`
 	Count    int      `json:"count"`
+	Total    int      `json:"total,omitempty"`
+	NextPage string   `json:"next_page,omitempty"`
 }
 
 // FilesResponse represents the response from the files endpoint
 type FilesResponse struct {
-	Success bool         `json:"success"`
-	Major   int          `json:"major"`
-	Minor   int          `json:"minor"`
-	Files   []BinaryInfo `json:"files"`
-	Count   int          `json:"count"`
+	Success  bool         `json:"success"`
+	Major    int          `json:"major"`
+	Minor    int          `json:"minor"`
+	Files    []BinaryInfo `json:"files"`
+	Count    int          `json:"count"`
+	Total    int          `json:"total,omitempty"`
+	NextPage string       `json:"next_page,omitempty"`
 }
 
 // UploadRequest represents a binary upload request
@@ -109,46 +191,163 @@ type ErrorResponse struct {
 
 // ListVersions lists all available binary versions
 func (c *Client) ListVersions() (*VersionsResponse, error) {
-	url := fmt.Sprintf("%s/binary/releases/agent-as-code/versions", c.BaseURL)
-
-	resp, err := c.HTTPClient.Get(url)
+	versions, err := c.ListAllVersions()
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch versions: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, c.handleErrorResponse(resp)
-	}
+	return &VersionsResponse{
+		Success:  true,
+		Versions: versions,
+		Count:    len(versions),
+		Total:    len(versions),
+	}, nil
+}
 
+// ListVersionsPaginated lists a single page of binary versions
+func (c *Client) ListVersionsPaginated(page, perPage int) (*VersionsResponse, error) {
 	var versionsResp VersionsResponse
-	if err := json.NewDecoder(resp.Body).Decode(&versionsResp); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := c.getJSON(withPageParams(c.versionsEndpoint(), page, perPage), &versionsResp, "versions"); err != nil {
+		return nil, err
 	}
 
 	return &versionsResp, nil
 }
 
+// ListAllVersions lists every binary version, following next_page links
+func (c *Client) ListAllVersions() ([]string, error) {
+	first, err := c.ListVersionsPaginated(1, defaultPerPage)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := first.Versions
+	visited := map[string]bool{}
+	next := first.NextPage
+
+	for next != "" && !visited[next] {
+		visited[next] = true
+
+		var page VersionsResponse
+		if err := c.getJSON(c.resolveNextPage(c.versionsEndpoint(), next, defaultPerPage), &page, "versions"); err != nil {
+			return nil, err
+		}
+		if len(page.Versions) == 0 {
+			break
+		}
+
+		versions = append(versions, page.Versions...)
+		next = page.NextPage
+	}
+
+	return versions, nil
+}
+
 // ListFiles lists all files for a specific major.minor version
 func (c *Client) ListFiles(major, minor int) (*FilesResponse, error) {
-	url := fmt.Sprintf("%s/binary/releases/agent-as-code/%d/%d/", c.BaseURL, major, minor)
+	filesResp, err := c.ListFilesPaginated(major, minor, 1, defaultPerPage)
+	if err != nil {
+		return nil, err
+	}
+
+	visited := map[string]bool{}
+	next := filesResp.NextPage
+
+	for next != "" && !visited[next] {
+		visited[next] = true
+
+		var page FilesResponse
+		if err := c.getJSON(c.resolveNextPage(c.filesEndpoint(major, minor), next, defaultPerPage), &page, "files"); err != nil {
+			return nil, err
+		}
+		if len(page.Files) == 0 {
+			break
+		}
+
+		filesResp.Files = append(filesResp.Files, page.Files...)
+		next = page.NextPage
+	}
+
+	filesResp.Count = len(filesResp.Files)
+	filesResp.NextPage = ""
+	if filesResp.Total < filesResp.Count {
+		filesResp.Total = filesResp.Count
+	}
+
+	return filesResp, nil
+}
+
+// ListFilesPaginated lists a single page of files for a major.minor version
+func (c *Client) ListFilesPaginated(major, minor, page, perPage int) (*FilesResponse, error) {
+	var filesResp FilesResponse
+	if err := c.getJSON(withPageParams(c.filesEndpoint(major, minor), page, perPage), &filesResp, "files"); err != nil {
+		return nil, err
+	}
+
+	return &filesResp, nil
+}
 
+// versionsEndpoint returns the URL of the versions endpoint
+func (c *Client) versionsEndpoint() string {
+	return fmt.Sprintf("%s/binary/releases/agent-as-code/versions", c.BaseURL)
+}
+
+// filesEndpoint returns the URL of the files endpoint for major.minor
+func (c *Client) filesEndpoint(major, minor int) string {
+	return fmt.Sprintf("%s/binary/releases/agent-as-code/%d/%d/", c.BaseURL, major, minor)
+}
+
+// getJSON fetches url and decodes the JSON response into out
+func (c *Client) getJSON(url string, out interface{}, what string) error {
 	resp, err := c.HTTPClient.Get(url)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch files: %w", err)
+		return fmt.Errorf("failed to fetch %s: %w", what, err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, c.handleErrorResponse(resp)
+		return c.handleErrorResponse(resp)
 	}
 
-	var filesResp FilesResponse
-	if err := json.NewDecoder(resp.Body).Decode(&filesResp); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	return &filesResp, nil
+	return nil
+}
+
+// resolveNextPage turns a next_page value into a URL. The API may return an
+// absolute URL, a path relative to the base URL, a query string, or a bare
+// page number.
+func (c *Client) resolveNextPage(endpoint, next string, perPage int) string {
+	switch {
+	case strings.HasPrefix(next, "http://"), strings.HasPrefix(next, "https://"):
+		return next
+	case strings.HasPrefix(next, "/"):
+		return c.BaseURL + next
+	case strings.HasPrefix(next, "?"):
+		return endpoint + next
+	}
+
+	if page, err := strconv.Atoi(next); err == nil {
+		return withPageParams(endpoint, page, perPage)
+	}
+	return c.BaseURL + "/" + next
+}
+
+// withPageParams adds page and per_page query parameters to endpoint
+func withPageParams(endpoint string, page, perPage int) string {
+	params := url.Values{}
+	if page > 0 {
+		params.Set("page", strconv.Itoa(page))
+	}
+	if perPage > 0 {
+		params.Set("per_page", strconv.Itoa(perPage))
+	}
+	if len(params) == 0 {
+		return endpoint
+	}
+	return endpoint + "?" + params.Encode()
 }
 
 // DownloadBinary downloads a specific binary release
@@ -290,11 +489,20 @@ func (c *Client) GetLatestBinary() (*BinaryInfo, error) {
 	return nil, fmt.Errorf("no binary found for platform %s/%s", platform, arch)
 }
 
-// parseVersion parses a semantic version string and returns major, minor
+// parseVersion parses a semantic version string and returns major, minor.
+// A leading v, pre-release (-rc1) and build metadata (+build.1) are ignored.
 func parseVersion(version string) (int, int, error) {
+	original := version
+	version = strings.TrimPrefix(version, "v")
+	version, _, _ = strings.Cut(version, "+")
+	version, _, _ = strings.Cut(version, "-")
+
 	parts := strings.Split(version, ".")
 	if len(parts) < 2 {
-		return 0, 0, fmt.Errorf("invalid version format: %s", version)
+		return 0, 0, fmt.Errorf("invalid version format: %s", original)
+	}
+	if len(parts) > 3 {
+		log.Warn("ignoring extra version components", "version", original)
 	}
 
 	major, err := strconv.Atoi(parts[0])
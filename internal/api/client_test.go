@@ -0,0 +1,44 @@
+package api
+
+import "testing"
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		version   string
+		wantMajor int
+		wantMinor int
+		wantErr   bool
+	}{
+		{version: "v1.0.0", wantMajor: 1, wantMinor: 0},
+		{version: "1.0.0", wantMajor: 1, wantMinor: 0},
+		{version: "1.0.0-rc1", wantMajor: 1, wantMinor: 0},
+		{version: "v1.0.0+build.1", wantMajor: 1, wantMinor: 0},
+		{version: "2.13.4-beta.2+exp.sha.5114f85", wantMajor: 2, wantMinor: 13},
+		{version: "1.2.3.4", wantMajor: 1, wantMinor: 2},
+		{version: "", wantErr: true},
+		{version: "v", wantErr: true},
+		{version: "1", wantErr: true},
+		{version: "latest", wantErr: true},
+		{version: "x.1.0", wantErr: true},
+		{version: "1.y.0", wantErr: true},
+		{version: "-rc1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			major, minor, err := parseVersion(tt.version)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseVersion(%q) = %d, %d, want an error", tt.version, major, minor)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseVersion(%q) error = %v", tt.version, err)
+			}
+			if major != tt.wantMajor || minor != tt.wantMinor {
+				t.Errorf("parseVersion(%q) = %d, %d, want %d, %d", tt.version, major, minor, tt.wantMajor, tt.wantMinor)
+			}
+		})
+	}
+}
@@ -6,20 +6,129 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"os/exec"
+	"path"
 	"path/filepath"
 	"runtime"
 	"strings"
+
+	"github.com/pxkundu/agent-as-code/internal/install"
+	"github.com/pxkundu/agent-as-code/internal/log"
 )
 
-// Downloader handles binary downloads from the API
-type Downloader struct {
+// Download backends
+const (
+	BackendAPI = "api"
+	BackendS3  = "s3"
+)
+
+// BinaryBackendEnv selects the download backend for NewDownloader; the S3
+// backend is configured by the AGENT_S3_* variables
+const BinaryBackendEnv = "AGENT_BINARY_BACKEND"
+
+// DownloaderConfig selects where binaries are downloaded from: the binary
+// API at BaseURL, or an S3-compatible bucket such as MinIO or Ceph
+type DownloaderConfig struct {
+	Backend string // api (default) or s3
+	BaseURL string // Binary API URL, for the api backend
+
+	S3Endpoint  string // e.g. https://minio.example.com:9000
+	S3Bucket    string
+	S3AccessKey string // Empty for anonymous access to a public bucket
+	S3SecretKey string
+	S3Region    string // Defaults to us-east-1
+}
+
+// DownloaderConfigFromEnv returns the config selected by
+// AGENT_BINARY_BACKEND, with baseURL as the API URL
+func DownloaderConfigFromEnv(baseURL string) DownloaderConfig {
+	return DownloaderConfig{
+		Backend:     os.Getenv(BinaryBackendEnv),
+		BaseURL:     baseURL,
+		S3Endpoint:  os.Getenv("AGENT_S3_ENDPOINT"),
+		S3Bucket:    os.Getenv("AGENT_S3_BUCKET"),
+		S3AccessKey: os.Getenv("AGENT_S3_ACCESS_KEY"),
+		S3SecretKey: os.Getenv("AGENT_S3_SECRET_KEY"),
+		S3Region:    os.Getenv("AGENT_S3_REGION"),
+	}
+}
+
+// downloadBackend is a source of binary releases
+type downloadBackend interface {
+	DownloadBinary(version, platform, arch string) ([]byte, error)
+	// ListVersions returns the available versions, oldest first
+	ListVersions() ([]string, error)
+	ListFiles(major, minor int) ([]BinaryInfo, error)
+}
+
+// apiDownloader downloads binaries from the binary API
+type apiDownloader struct {
 	client *Client
 }
 
-// NewDownloader creates a new binary downloader
+func (a *apiDownloader) DownloadBinary(version, platform, arch string) ([]byte, error) {
+	return a.client.DownloadBinary(version, platform, arch)
+}
+
+func (a *apiDownloader) ListVersions() ([]string, error) {
+	resp, err := a.client.ListVersions()
+	if err != nil {
+		return nil, err
+	}
+	return resp.Versions, nil
+}
+
+func (a *apiDownloader) ListFiles(major, minor int) ([]BinaryInfo, error) {
+	resp, err := a.client.ListFiles(major, minor)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Files, nil
+}
+
+// misconfiguredBackend reports an invalid DownloaderConfig on every call
+type misconfiguredBackend struct {
+	err error
+}
+
+func (m misconfiguredBackend) DownloadBinary(version, platform, arch string) ([]byte, error) {
+	return nil, m.err
+}
+
+func (m misconfiguredBackend) ListVersions() ([]string, error) {
+	return nil, m.err
+}
+
+func (m misconfiguredBackend) ListFiles(major, minor int) ([]BinaryInfo, error) {
+	return nil, m.err
+}
+
+// Downloader handles binary downloads from the API or S3-compatible storage
+type Downloader struct {
+	backend downloadBackend
+}
+
+// NewDownloader creates a new binary downloader for the API at baseURL, or
+// for the backend selected by AGENT_BINARY_BACKEND
 func NewDownloader(baseURL string) *Downloader {
-	return &Downloader{
-		client: NewClient(baseURL),
+	return NewDownloaderWithConfig(DownloaderConfigFromEnv(baseURL))
+}
+
+// NewDownloaderWithConfig creates a binary downloader for the backend in
+// cfg. An invalid config is reported by the downloader's methods.
+func NewDownloaderWithConfig(cfg DownloaderConfig) *Downloader {
+	switch cfg.Backend {
+	case "", BackendAPI:
+		return &Downloader{backend: &apiDownloader{client: NewClient(cfg.BaseURL)}}
+	case BackendS3:
+		backend, err := newS3Downloader(cfg)
+		if err != nil {
+			return &Downloader{backend: misconfiguredBackend{fmt.Errorf("invalid S3 backend config: %w", err)}}
+		}
+		log.Debug("downloading binaries from S3", "endpoint", backend.endpoint.String(), "bucket", backend.bucket)
+		return &Downloader{backend: backend}
+	default:
+		return &Downloader{backend: misconfiguredBackend{fmt.Errorf("unknown binary backend '%s' (valid: %s, %s)", cfg.Backend, BackendAPI, BackendS3)}}
 	}
 }
 
@@ -52,7 +161,7 @@ func (d *Downloader) DownloadBinary(opts DownloadOptions) *DownloadResult {
 	}
 
 	// Download binary data
-	data, err := d.client.DownloadBinary(opts.Version, opts.Platform, opts.Architecture)
+	data, err := d.backend.DownloadBinary(opts.Version, opts.Platform, opts.Architecture)
 	if err != nil {
 		result.Error = fmt.Errorf("download failed: %w", err)
 		return result
@@ -91,7 +200,7 @@ func (d *Downloader) DownloadLatest(outputDir string) *DownloadResult {
 	arch := runtime.GOARCH
 
 	// Get latest binary info
-	latest, err := d.client.GetLatestBinary()
+	latest, err := d.latestBinary(platform, arch)
 	if err != nil {
 		return &DownloadResult{
 			Platform:     platform,
@@ -110,6 +219,36 @@ func (d *Downloader) DownloadLatest(outputDir string) *DownloadResult {
 	return d.DownloadBinary(opts)
 }
 
+// latestBinary returns the binary of the newest version for platform/arch
+func (d *Downloader) latestBinary(platform, arch string) (*BinaryInfo, error) {
+	versions, err := d.backend.ListVersions()
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("no versions available")
+	}
+
+	latestVersion := versions[len(versions)-1]
+	binaries, err := d.ListAvailableBinaries(latestVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, binary := range binaries {
+		if binary.Platform == platform && binary.Architecture == arch && binary.Version == latestVersion {
+			return &binary, nil
+		}
+	}
+	for _, binary := range binaries {
+		if binary.Platform == platform && binary.Architecture == arch {
+			return &binary, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no binary found for platform %s/%s", platform, arch)
+}
+
 // DownloadAllPlatforms downloads binaries for all supported platforms
 func (d *Downloader) DownloadAllPlatforms(version, outputDir string) []*DownloadResult {
 	platforms := []struct {
@@ -143,12 +282,7 @@ func (d *Downloader) DownloadAllPlatforms(version, outputDir string) []*Download
 
 // ListAvailableVersions lists all available versions
 func (d *Downloader) ListAvailableVersions() ([]string, error) {
-	resp, err := d.client.ListVersions()
-	if err != nil {
-		return nil, err
-	}
-
-	return resp.Versions, nil
+	return d.backend.ListVersions()
 }
 
 // ListAvailableBinaries lists all available binaries for a version
@@ -158,12 +292,7 @@ func (d *Downloader) ListAvailableBinaries(version string) ([]BinaryInfo, error)
 		return nil, err
 	}
 
-	resp, err := d.client.ListFiles(major, minor)
-	if err != nil {
-		return nil, err
-	}
-
-	return resp.Files, nil
+	return d.backend.ListFiles(major, minor)
 }
 
 // GetBinaryInfo gets information about a specific binary
@@ -182,8 +311,20 @@ func (d *Downloader) GetBinaryInfo(version, platform, arch string) (*BinaryInfo,
 	return nil, fmt.Errorf("binary not found for %s/%s version %s", platform, arch, version)
 }
 
-// InstallBinary downloads and installs a binary to the system
+// InstallOptions represents options for binary installation
+type InstallOptions struct {
+	NoPathUpdate bool // Skip adding the install directory to PATH
+	SkipVerify   bool // Skip running '<binary> version' before installing
+}
+
+// InstallBinary downloads a binary, verifies it, installs it to the system
+// and adds the install directory to PATH
 func (d *Downloader) InstallBinary(version, installDir string) *DownloadResult {
+	return d.InstallBinaryWithOptions(version, installDir, InstallOptions{})
+}
+
+// InstallBinaryWithOptions downloads and installs a binary to the system
+func (d *Downloader) InstallBinaryWithOptions(version, installDir string, options InstallOptions) *DownloadResult {
 	platform := runtime.GOOS
 	arch := runtime.GOARCH
 
@@ -212,21 +353,48 @@ func (d *Downloader) InstallBinary(version, installDir string) *DownloadResult {
 		return result
 	}
 
-	// Extract zip and install binary
-	if result.Success {
-		// Line 217 in internal/api/downloader.go
-		if err := d.extractAndInstallBinary(result.FilePath, installDir, version, platform, arch); err != nil {
-			result.Success = false
-			result.Error = fmt.Errorf("failed to install binary: %w", err)
+	// Extract zip, verify and install binary
+	if err := d.extractAndInstallBinary(result.FilePath, installDir, platform, !options.SkipVerify); err != nil {
+		result.Success = false
+		result.Error = fmt.Errorf("failed to install binary: %w", err)
+		return result
+	}
+
+	// A failed PATH update does not undo the install
+	if !options.NoPathUpdate {
+		if err := install.AddToPath(installDir); err != nil {
+			fmt.Printf("⚠️  Could not add %s to PATH: %v\n", installDir, err)
 		}
 	}
 
 	return result
 }
 
-// extractAndInstallBinary extracts the downloaded zip file and installs the binary
-func (d *Downloader) extractAndInstallBinary(zipPath, installDir, version, platform, arch string) error {
-	// Import archive/zip at the top of the file
+// verifyBinary runs '<binary> version' to check the installed binary works
+func verifyBinary(binaryPath string) error {
+	output, err := exec.Command(binaryPath, "version").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%v: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	fmt.Printf("✅ Verified %s\n", binaryPath)
+	return nil
+}
+
+// binaryFileName returns the agent binary name for platform
+func binaryFileName(platform string) string {
+	if platform == "windows" {
+		return "agent.exe"
+	}
+	return "agent"
+}
+
+// extractAndInstallBinary extracts the binary from the downloaded zip file
+// into a temporary file in installDir, optionally verifies it, and then
+// renames it over the installed binary. The installed binary, which may be
+// the one running, is never written to, so a failed download, extraction
+// or verification leaves it intact.
+func (d *Downloader) extractAndInstallBinary(zipPath, installDir, platform string, verify bool) error {
 	reader, err := zip.OpenReader(zipPath)
 	if err != nil {
 		return fmt.Errorf("failed to open zip file: %w", err)
@@ -234,59 +402,144 @@ func (d *Downloader) extractAndInstallBinary(zipPath, installDir, version, platf
 	defer reader.Close()
 
 	// Determine binary name
-	binaryName := "agent"
-	if platform == "windows" {
-		binaryName += ".exe"
+	binaryName := binaryFileName(platform)
+
+	// Reject archives with entries that would land outside installDir
+	// (zip slip), even though only the binary is extracted
+	for _, file := range reader.File {
+		if _, err := safePath(installDir, file.Name); err != nil {
+			return err
+		}
 	}
 
-	// Extract binary from zip
-	var binaryFound bool
+	var binary *zip.File
 	for _, file := range reader.File {
-		if strings.HasSuffix(file.Name, binaryName) {
-			// Extract this file
-			rc, err := file.Open()
-			if err != nil {
-				return fmt.Errorf("failed to open file in zip: %w", err)
-			}
-			defer rc.Close()
-
-			// Create installation directory
-			if err := os.MkdirAll(installDir, 0755); err != nil {
-				return fmt.Errorf("failed to create install directory: %w", err)
-			}
-
-			// Create destination file
-			destPath := filepath.Join(installDir, binaryName)
-			destFile, err := os.Create(destPath)
-			if err != nil {
-				return fmt.Errorf("failed to create destination file: %w", err)
-			}
-			defer destFile.Close()
-
-			// Copy binary content
-			if _, err := io.Copy(destFile, rc); err != nil {
-				return fmt.Errorf("failed to copy binary: %w", err)
-			}
-
-			// Set executable permissions on Unix systems
-			if platform != "windows" {
-				if err := os.Chmod(destPath, 0755); err != nil {
-					return fmt.Errorf("failed to set executable permissions: %w", err)
-				}
-			}
-
-			binaryFound = true
+		if !file.FileInfo().IsDir() && path.Base(file.Name) == binaryName {
+			binary = file
 			break
 		}
 	}
-
-	if !binaryFound {
+	if binary == nil {
 		return fmt.Errorf("binary not found in zip file")
 	}
 
-	// Clean up downloaded zip file
-	os.Remove(zipPath)
+	// Create installation directory
+	if err := os.MkdirAll(installDir, 0755); err != nil {
+		return fmt.Errorf("failed to create install directory: %w", err)
+	}
+	destPath, err := safePath(installDir, binaryName)
+	if err != nil {
+		return err
+	}
 
-	fmt.Printf("✅ Binary installed successfully to %s\n", filepath.Join(installDir, binaryName))
+	// Extract next to the destination so the final rename stays on one
+	// file system. The extension keeps it executable on Windows.
+	tempPath, err := extractToTemp(binary, installDir, filepath.Ext(binaryName))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tempPath)
+
+	// Set executable permissions on Unix systems
+	if platform != "windows" {
+		if err := os.Chmod(tempPath, 0755); err != nil {
+			return fmt.Errorf("failed to set executable permissions: %w", err)
+		}
+	}
+
+	if verify {
+		if err := verifyBinary(tempPath); err != nil {
+			return fmt.Errorf("downloaded binary failed verification, keeping the installed one: %w", err)
+		}
+	}
+
+	if err := replaceBinary(tempPath, destPath, platform); err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Binary installed successfully to %s\n", destPath)
+	return nil
+}
+
+// extractToTemp writes a zip entry to a new temporary file in dir and
+// returns its path
+func extractToTemp(file *zip.File, dir, ext string) (string, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return "", fmt.Errorf("failed to open file in zip: %w", err)
+	}
+	defer rc.Close()
+
+	tempFile, err := os.CreateTemp(dir, ".agent-update-*"+ext)
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary file: %w", err)
+	}
+
+	_, copyErr := io.Copy(tempFile, rc)
+	closeErr := tempFile.Close()
+	if copyErr != nil || closeErr != nil {
+		os.Remove(tempFile.Name())
+		if copyErr == nil {
+			copyErr = closeErr
+		}
+		return "", fmt.Errorf("failed to copy binary: %w", copyErr)
+	}
+
+	return tempFile.Name(), nil
+}
+
+// replaceBinary renames newPath over destPath. Windows cannot replace a
+// running executable but can rename it, so there the old binary is first
+// moved aside to <dest>.old and put back if the rename fails.
+func replaceBinary(newPath, destPath, platform string) error {
+	if platform != "windows" {
+		if err := os.Rename(newPath, destPath); err != nil {
+			return fmt.Errorf("failed to replace %s: %w", destPath, err)
+		}
+		return nil
+	}
+
+	oldPath := destPath + ".old"
+	// Left over from a previous update; it may still be running
+	os.Remove(oldPath)
+
+	movedAside := false
+	if _, err := os.Stat(destPath); err == nil {
+		if err := os.Rename(destPath, oldPath); err != nil {
+			return fmt.Errorf("failed to move %s aside: %w", destPath, err)
+		}
+		movedAside = true
+	}
+
+	if err := os.Rename(newPath, destPath); err != nil {
+		if movedAside {
+			os.Rename(oldPath, destPath)
+		}
+		return fmt.Errorf("failed to replace %s: %w", destPath, err)
+	}
 	return nil
 }
+
+// safePath joins name to base and returns an error if name is absolute or
+// the result is not inside base, which protects against zip slip entries
+// like ../../bin/agent. Entries that resolve to base itself, such as ./,
+// are harmless and return base.
+func safePath(base, name string) (string, error) {
+	if filepath.IsAbs(name) || strings.HasPrefix(name, "/") {
+		return "", fmt.Errorf("illegal file path in zip: %s", name)
+	}
+
+	absBase, err := filepath.Abs(base)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", base, err)
+	}
+	target, err := filepath.Abs(filepath.Join(base, name))
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", name, err)
+	}
+
+	if target != absBase && !strings.HasPrefix(target, absBase+string(os.PathSeparator)) {
+		return "", fmt.Errorf("illegal file path in zip: %s", name)
+	}
+	return target, nil
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestSafePath(t *testing.T) {
+	base := t.TempDir()
+
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{name: "agent", want: filepath.Join(base, "agent")},
+		{name: "bin/linux/agent", want: filepath.Join(base, "bin", "linux", "agent")},
+		{name: "./", want: base},
+		{name: "dir/..", want: base},
+		{name: "../../bin/agent", wantErr: true},
+		{name: "a/../../x", wantErr: true},
+		{name: "/usr/local/bin/agent", wantErr: true},
+		{name: "/", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := safePath(base, tt.name)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("safePath(%q) = %s, want an error", tt.name, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("safePath(%q) error = %v", tt.name, err)
+			}
+			if got != tt.want {
+				t.Errorf("safePath(%q) = %s, want %s", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractAndInstallBinary(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses shell scripts as binaries")
+	}
+
+	writeZip := func(t *testing.T, script string) string {
+		zipPath := filepath.Join(t.TempDir(), "agent.zip")
+		file, err := os.Create(zipPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		zw := zip.NewWriter(file)
+		w, err := zw.Create("agent")
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(script))
+		zw.Close()
+		file.Close()
+		return zipPath
+	}
+
+	installDir := t.TempDir()
+	installed := filepath.Join(installDir, "agent")
+	oldBinary := "#!/bin/sh\necho old\n"
+	if err := os.WriteFile(installed, []byte(oldBinary), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	d := &Downloader{}
+	if err := d.extractAndInstallBinary(writeZip(t, "#!/bin/sh\nexit 1\n"), installDir, "linux", true); err == nil {
+		t.Fatal("extractAndInstallBinary() installed a binary that failed verification")
+	}
+	if data, _ := os.ReadFile(installed); string(data) != oldBinary {
+		t.Errorf("installed binary = %q after a failed verification, want the old one kept", data)
+	}
+
+	newBinary := "#!/bin/sh\necho new\n"
+	if err := d.extractAndInstallBinary(writeZip(t, newBinary), installDir, "linux", true); err != nil {
+		t.Fatalf("extractAndInstallBinary() error = %v", err)
+	}
+	if data, _ := os.ReadFile(installed); string(data) != newBinary {
+		t.Errorf("installed binary = %q, want %q", data, newBinary)
+	}
+
+	entries, _ := os.ReadDir(installDir)
+	if len(entries) != 1 {
+		t.Errorf("install dir holds %d entries, want only the binary", len(entries))
+	}
+}
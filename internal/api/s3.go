@@ -0,0 +1,411 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// s3ReleasesPrefix is the key prefix of binary releases, laid out as
+	// releases/{major}/{minor}/{patch}/agent_as_code_{version}_{platform}_{arch}.zip
+	s3ReleasesPrefix = "releases/"
+	// s3DefaultRegion is used to sign requests when no region is configured;
+	// MinIO and Ceph accept it unless configured otherwise
+	s3DefaultRegion = "us-east-1"
+	// emptyPayloadHash is the SHA-256 of an empty request body
+	emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+// s3Downloader downloads binaries from an S3-compatible bucket using
+// path-style requests, which MinIO and Ceph support by default. Requests are
+// signed with AWS Signature Version 4 when credentials are configured and
+// sent anonymously otherwise.
+type s3Downloader struct {
+	endpoint   *url.URL
+	bucket     string
+	accessKey  string
+	secretKey  string
+	region     string
+	httpClient *http.Client
+}
+
+// newS3Downloader creates an S3 backend from cfg
+func newS3Downloader(cfg DownloaderConfig) (*s3Downloader, error) {
+	if cfg.S3Endpoint == "" {
+		return nil, fmt.Errorf("S3 endpoint not configured")
+	}
+	if cfg.S3Bucket == "" {
+		return nil, fmt.Errorf("S3 bucket not configured")
+	}
+	if (cfg.S3AccessKey == "") != (cfg.S3SecretKey == "") {
+		return nil, fmt.Errorf("S3 access key and secret key must be set together")
+	}
+
+	endpoint := cfg.S3Endpoint
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+	parsed, err := url.Parse(strings.TrimSuffix(endpoint, "/"))
+	if err != nil || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint '%s'", cfg.S3Endpoint)
+	}
+
+	region := cfg.S3Region
+	if region == "" {
+		region = s3DefaultRegion
+	}
+
+	return &s3Downloader{
+		endpoint:  parsed,
+		bucket:    cfg.S3Bucket,
+		accessKey: cfg.S3AccessKey,
+		secretKey: cfg.S3SecretKey,
+		region:    region,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}, nil
+}
+
+// s3Object is an object in a ListObjectsV2 response
+type s3Object struct {
+	Key          string `xml:"Key"`
+	LastModified string `xml:"LastModified"`
+	Size         int64  `xml:"Size"`
+}
+
+// s3ListResult is a ListObjectsV2 response
+type s3ListResult struct {
+	Contents              []s3Object `xml:"Contents"`
+	IsTruncated           bool       `xml:"IsTruncated"`
+	NextContinuationToken string     `xml:"NextContinuationToken"`
+}
+
+// s3Error is the error document S3 returns with failed requests
+type s3Error struct {
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+// DownloadBinary downloads the release archive for version, platform and arch
+func (s *s3Downloader) DownloadBinary(version, platform, arch string) ([]byte, error) {
+	key, err := s3ReleaseKey(version, platform, arch)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.do(key, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download binary: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	return data, nil
+}
+
+// ListVersions returns the versions with at least one release, oldest first
+func (s *s3Downloader) ListVersions() ([]string, error) {
+	binaries, err := s.listBinaries(s3ReleasesPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var versions []string
+	for _, binary := range binaries {
+		if !seen[binary.Version] {
+			seen[binary.Version] = true
+			versions = append(versions, binary.Version)
+		}
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return compareVersions(versions[i], versions[j]) < 0
+	})
+	return versions, nil
+}
+
+// ListFiles returns the releases of major.minor
+func (s *s3Downloader) ListFiles(major, minor int) ([]BinaryInfo, error) {
+	return s.listBinaries(fmt.Sprintf("%s%d/%d/", s3ReleasesPrefix, major, minor))
+}
+
+// listBinaries lists the release archives under prefix, following
+// continuation tokens. Objects that are not release archives are skipped.
+func (s *s3Downloader) listBinaries(prefix string) ([]BinaryInfo, error) {
+	var binaries []BinaryInfo
+	token := ""
+	for {
+		query := url.Values{}
+		query.Set("list-type", "2")
+		query.Set("prefix", prefix)
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+
+		resp, err := s.do("", query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list releases: %w", err)
+		}
+
+		var result s3ListResult
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+
+		for _, object := range result.Contents {
+			binary, ok := parseReleaseKey(object.Key)
+			if !ok {
+				continue
+			}
+			binary.Size = object.Size
+			binary.LastModified = object.LastModified
+			binary.DownloadURL = s.objectURL(object.Key).String()
+			binaries = append(binaries, binary)
+		}
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return binaries, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+// do sends a signed GET request for key, or for the bucket when key is
+// empty, and returns the response if it succeeded
+func (s *s3Downloader) do(key string, query url.Values) (*http.Response, error) {
+	u := s.objectURL(key)
+	u.RawQuery = awsQueryEncode(query)
+
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if s.accessKey != "" {
+		signV4(req, s.accessKey, s.secretKey, s.region, time.Now())
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, s3ErrorResponse(resp)
+	}
+	return resp, nil
+}
+
+// objectURL returns the path-style URL of key in the bucket
+func (s *s3Downloader) objectURL(key string) *url.URL {
+	u := *s.endpoint
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + s.bucket + "/" + key
+	u.RawPath = awsURIEncode(u.Path, false)
+	return &u
+}
+
+// s3ErrorResponse turns an S3 error document into an error
+func s3ErrorResponse(resp *http.Response) error {
+	body, _ := io.ReadAll(resp.Body)
+
+	var s3Err s3Error
+	if err := xml.Unmarshal(body, &s3Err); err != nil || s3Err.Code == "" {
+		return fmt.Errorf("S3 request failed with status %d", resp.StatusCode)
+	}
+	return fmt.Errorf("S3 error: %s - %s", s3Err.Code, s3Err.Message)
+}
+
+// s3ReleaseKey returns the object key of a release archive
+func s3ReleaseKey(version, platform, arch string) (string, error) {
+	major, minor, err := parseVersion(version)
+	if err != nil {
+		return "", fmt.Errorf("invalid version format: %w", err)
+	}
+	filename := fmt.Sprintf("agent_as_code_%s_%s_%s.zip", version, platform, arch)
+	return fmt.Sprintf("%s%d/%d/%d/%s", s3ReleasesPrefix, major, minor, patchVersion(version), filename), nil
+}
+
+// parseReleaseKey returns the binary a release archive key refers to
+func parseReleaseKey(key string) (BinaryInfo, bool) {
+	filename := key[strings.LastIndex(key, "/")+1:]
+	name, ok := strings.CutPrefix(filename, "agent_as_code_")
+	if !ok || !strings.HasSuffix(name, ".zip") {
+		return BinaryInfo{}, false
+	}
+
+	// The version may contain underscores; platform and arch don't
+	parts := strings.Split(strings.TrimSuffix(name, ".zip"), "_")
+	if len(parts) < 3 {
+		return BinaryInfo{}, false
+	}
+	return BinaryInfo{
+		Filename:     filename,
+		Version:      strings.Join(parts[:len(parts)-2], "_"),
+		Platform:     parts[len(parts)-2],
+		Architecture: parts[len(parts)-1],
+	}, true
+}
+
+// patchVersion returns the patch number of version, or 0 if it has none
+func patchVersion(version string) int {
+	numbers := versionNumbers(version)
+	if len(numbers) < 3 {
+		return 0
+	}
+	return numbers[2]
+}
+
+// versionNumbers returns the numeric components of version, ignoring a
+// leading v, pre-release and build metadata
+func versionNumbers(version string) []int {
+	version = strings.TrimPrefix(version, "v")
+	version, _, _ = strings.Cut(version, "+")
+	version, _, _ = strings.Cut(version, "-")
+
+	var numbers []int
+	for _, part := range strings.Split(version, ".") {
+		n := 0
+		for _, r := range part {
+			if r < '0' || r > '9' {
+				break
+			}
+			n = n*10 + int(r-'0')
+		}
+		numbers = append(numbers, n)
+	}
+	return numbers
+}
+
+// compareVersions compares two versions numerically, component by
+// component, and falls back to comparing the strings when they are equal
+func compareVersions(a, b string) int {
+	na, nb := versionNumbers(a), versionNumbers(b)
+	for i := 0; i < len(na) && i < len(nb); i++ {
+		if na[i] != nb[i] {
+			if na[i] < nb[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	if len(na) != len(nb) {
+		if len(na) < len(nb) {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// signV4 signs req with AWS Signature Version 4 for the s3 service. The
+// request must have no body.
+func signV4(req *http.Request, accessKey, secretKey, region string, now time.Time) {
+	_, _, authorization := v4Signature(req, accessKey, secretKey, region, now)
+	req.Header.Set("Authorization", authorization)
+}
+
+// v4Signature sets the X-Amz-Date and X-Amz-Content-Sha256 headers of req
+// and returns its canonical request, string to sign and Authorization header
+func v4Signature(req *http.Request, accessKey, secretKey, region string, now time.Time) (string, string, string) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", emptyPayloadHash)
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	canonicalHeaders := "host:" + host + "\n" +
+		"x-amz-content-sha256:" + emptyPayloadHash + "\n" +
+		"x-amz-date:" + amzDate + "\n"
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		awsURIEncode(req.URL.Path, false),
+		awsQueryEncode(req.URL.Query()),
+		canonicalHeaders,
+		signedHeaders,
+		emptyPayloadHash,
+	}, "\n")
+
+	scope := date + "/" + region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex(canonicalRequest)
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	authorization := fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKey, scope, signedHeaders, signature)
+	return canonicalRequest, stringToSign, authorization
+}
+
+// awsQueryEncode returns the canonical query string: parameters sorted by
+// name and encoded as Signature Version 4 requires
+func awsQueryEncode(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var pairs []string
+	for _, key := range keys {
+		values := append([]string(nil), query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, awsURIEncode(key, true)+"="+awsURIEncode(value, true))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// awsURIEncode percent-encodes every byte of s except unreserved
+// characters, and '/' unless encodeSlash is set
+func awsURIEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func sha256Hex(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
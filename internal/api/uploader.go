@@ -2,28 +2,55 @@
 package api
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 )
 
+// DefaultParallelism is the number of concurrent uploads in
+// UploadAllPlatforms
+const DefaultParallelism = 3
+
 // Uploader handles binary uploads to the API
 type Uploader struct {
-	client  *Client
-	version string
+	client      *Client
+	version     string
+	parallelism int
 }
 
-// NewUploader creates a new binary uploader
+// NewUploader creates a new binary uploader. Uploads share one HTTP/2
+// connection where the API supports it.
 func NewUploader(baseURL, authToken, version string) *Uploader {
-	client := NewClient(baseURL)
+	client := NewClientWithHTTP2(baseURL)
 	client.SetAuthToken(authToken)
 
 	return &Uploader{
-		client:  client,
-		version: version,
+		client:      client,
+		version:     version,
+		parallelism: DefaultParallelism,
+	}
+}
+
+// SetParallelism sets the number of concurrent uploads in
+// UploadAllPlatforms. Values below 1 upload one binary at a time.
+func (u *Uploader) SetParallelism(n int) {
+	if n < 1 {
+		n = 1
 	}
+	u.parallelism = n
+}
+
+// SetProxy sends uploads through the proxy at proxyURL (see
+// Client.SetProxy)
+func (u *Uploader) SetProxy(proxyURL string) error {
+	return u.client.SetProxy(proxyURL)
 }
 
 // UploadOptions represents options for binary upload
@@ -36,12 +63,36 @@ type UploadOptions struct {
 
 // UploadResult represents the result of a binary upload
 type UploadResult struct {
-	Success      bool
-	Platform     string
-	Architecture string
-	Version      string
-	DownloadURL  string
-	Error        error
+	Success       bool
+	Platform      string
+	Architecture  string
+	Version       string
+	DownloadURL   string
+	BytesUploaded int64
+	StartedAt     time.Time
+	Duration      time.Duration
+	Error         error
+}
+
+// uploadResultJSON is the JSON form of an UploadResult
+type uploadResultJSON struct {
+	Success       bool   `json:"success"`
+	Platform      string `json:"platform"`
+	Architecture  string `json:"architecture"`
+	Version       string `json:"version"`
+	DownloadURL   string `json:"downloadUrl,omitempty"`
+	BytesUploaded int64  `json:"bytesUploaded"`
+	DurationMs    int64  `json:"durationMs"`
+	Error         string `json:"error,omitempty"`
+}
+
+// uploadSummaryJSON is the document written by WriteAsJSON
+type uploadSummaryJSON struct {
+	Results       []uploadResultJSON `json:"results"`
+	Successful    int                `json:"successful"`
+	Failed        int                `json:"failed"`
+	BytesUploaded int64              `json:"bytesUploaded"`
+	DurationMs    int64              `json:"durationMs"`
 }
 
 // UploadBinary uploads a single binary
@@ -53,13 +104,16 @@ func (u *Uploader) UploadBinary(opts UploadOptions) *UploadResult {
 	}
 
 	// Validate file exists
-	if _, err := os.Stat(opts.FilePath); os.IsNotExist(err) {
+	info, err := os.Stat(opts.FilePath)
+	if os.IsNotExist(err) {
 		result.Error = fmt.Errorf("binary file not found: %s", opts.FilePath)
 		return result
 	}
 
 	// Upload binary
+	result.StartedAt = time.Now()
 	resp, err := u.client.UploadBinary(opts.FilePath, u.version, opts.Platform, opts.Architecture)
+	result.Duration = time.Since(result.StartedAt)
 	if err != nil {
 		result.Error = fmt.Errorf("upload failed: %w", err)
 		return result
@@ -67,11 +121,16 @@ func (u *Uploader) UploadBinary(opts UploadOptions) *UploadResult {
 
 	result.Success = resp.Success
 	result.DownloadURL = resp.Release.DownloadURL
+	if result.Success && info != nil {
+		result.BytesUploaded = info.Size()
+	}
 
 	return result
 }
 
-// UploadAllPlatforms uploads binaries for all supported platforms
+// UploadAllPlatforms uploads binaries for all supported platforms, running
+// up to the uploader's parallelism uploads at once. Results are returned in
+// platform order.
 func (u *Uploader) UploadAllPlatforms(binDir string) []*UploadResult {
 	platforms := []struct {
 		OS   string
@@ -85,9 +144,23 @@ func (u *Uploader) UploadAllPlatforms(binDir string) []*UploadResult {
 		{"windows", "arm64"},
 	}
 
-	var results []*UploadResult
+	var (
+		results []*UploadResult
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+	)
+	order := make(map[*UploadResult]int, len(platforms))
+	addResult := func(index int, result *UploadResult) {
+		mu.Lock()
+		defer mu.Unlock()
+		results = append(results, result)
+		order[result] = index
+	}
+
+	// Buffered channel as a semaphore bounding the number of uploads in flight
+	slots := make(chan struct{}, u.parallelism)
 
-	for _, platform := range platforms {
+	for i, platform := range platforms {
 		// Determine binary filename
 		binaryName := "agent"
 		if platform.OS == "windows" {
@@ -102,7 +175,7 @@ func (u *Uploader) UploadAllPlatforms(binDir string) []*UploadResult {
 
 		// Check if binary exists
 		if _, err := os.Stat(binaryPath); os.IsNotExist(err) {
-			results = append(results, &UploadResult{
+			addResult(i, &UploadResult{
 				Platform:     platform.OS,
 				Architecture: platform.Arch,
 				Version:      u.version,
@@ -118,10 +191,20 @@ func (u *Uploader) UploadAllPlatforms(binDir string) []*UploadResult {
 			FilePath:     binaryPath,
 		}
 
-		result := u.UploadBinary(opts)
-		results = append(results, result)
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(index int, opts UploadOptions) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			addResult(index, u.UploadBinary(opts))
+		}(i, opts)
 	}
 
+	wg.Wait()
+
+	sort.Slice(results, func(a, b int) bool {
+		return order[results[a]] < order[results[b]]
+	})
 	return results
 }
 
@@ -177,9 +260,81 @@ func GetUploadSummary(results []*UploadResult) string {
 
 	summary.WriteString(fmt.Sprintf("\n📊 Results: %d successful, %d failed\n", successful, failed))
 
+	totalBytes, totalDuration := uploadTotals(results)
+	if totalBytes > 0 {
+		mb := float64(totalBytes) / (1024 * 1024)
+		seconds := totalDuration.Seconds()
+		rate := 0.0
+		if seconds > 0 {
+			rate = mb / seconds
+		}
+		summary.WriteString(fmt.Sprintf("📤 Total uploaded: %.1f MB in %.1f seconds (%.1f MB/s)\n", mb, seconds, rate))
+	}
+
 	if successful > 0 {
 		summary.WriteString("\n🎉 Binaries are now available for download!\n")
 	}
 
 	return summary.String()
 }
+
+// WriteAsJSON writes upload results and totals as JSON, for CI pipelines
+// that need machine-readable output
+func WriteAsJSON(results []*UploadResult, w io.Writer) error {
+	summary := uploadSummaryJSON{Results: []uploadResultJSON{}}
+	for _, result := range results {
+		entry := uploadResultJSON{
+			Success:       result.Success,
+			Platform:      result.Platform,
+			Architecture:  result.Architecture,
+			Version:       result.Version,
+			DownloadURL:   result.DownloadURL,
+			BytesUploaded: result.BytesUploaded,
+			DurationMs:    result.Duration.Milliseconds(),
+		}
+		if result.Error != nil {
+			entry.Error = result.Error.Error()
+		}
+		if result.Success {
+			summary.Successful++
+		} else {
+			summary.Failed++
+		}
+		summary.Results = append(summary.Results, entry)
+	}
+
+	totalBytes, totalDuration := uploadTotals(results)
+	summary.BytesUploaded = totalBytes
+	summary.DurationMs = totalDuration.Milliseconds()
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(summary); err != nil {
+		return fmt.Errorf("failed to write upload results: %w", err)
+	}
+	return nil
+}
+
+// uploadTotals returns the bytes uploaded and the wall-clock time spent
+// uploading across all results. Uploads may overlap, so the time runs from
+// the first start to the last finish; results without a start time (such as
+// dry runs) add their durations.
+func uploadTotals(results []*UploadResult) (int64, time.Duration) {
+	var bytes int64
+	var first, last time.Time
+	var unstarted time.Duration
+	for _, result := range results {
+		bytes += result.BytesUploaded
+		if result.StartedAt.IsZero() {
+			unstarted += result.Duration
+			continue
+		}
+		if first.IsZero() || result.StartedAt.Before(first) {
+			first = result.StartedAt
+		}
+		if end := result.StartedAt.Add(result.Duration); end.After(last) {
+			last = end
+		}
+	}
+	return bytes, last.Sub(first) + unstarted
+}
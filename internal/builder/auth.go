@@ -0,0 +1,120 @@
+package builder
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/api/types/registry"
+	"github.com/pxkundu/agent-as-code/internal/config"
+	"github.com/pxkundu/agent-as-code/internal/log"
+)
+
+// registryUsername is sent with profile PATs. Agent registries identify the
+// user by the PAT alone, but Docker requires a user name.
+const registryUsername = "agent"
+
+// profileAuthConfig returns the Docker auth config for a registry profile,
+// or nil if there is nothing to authenticate with. An empty profileName
+// selects the default profile; failing to load it is logged rather than
+// returned so that builds and pushes without credentials keep working.
+func profileAuthConfig(profileName string) (*registry.AuthConfig, error) {
+	profile, ok, err := config.GetProfile(profileName)
+	if err != nil {
+		if profileName == "" {
+			log.Warn("ignoring default profile", "error", err)
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to load profile: %w", err)
+	}
+	if !ok || profile.PAT == "" {
+		return nil, nil
+	}
+
+	return &registry.AuthConfig{
+		Username:      registryUsername,
+		Password:      profile.PAT,
+		ServerAddress: registryHost(profile.Registry),
+	}, nil
+}
+
+// registryHost returns the host of a registry URL such as
+// https://registry.example.com/v2, as Docker keys credentials by host
+func registryHost(registryURL string) string {
+	if u, err := url.Parse(registryURL); err == nil && u.Host != "" {
+		return u.Host
+	}
+	host, _, _ := strings.Cut(registryURL, "/")
+	return host
+}
+
+// dockerCLIConfig writes a Docker config directory for the docker CLI that
+// adds auth for its registry to the user's Docker config, and returns its
+// path. The other entries of the user's config directory, such as
+// cli-plugins and contexts, are linked into it. The caller removes it.
+func dockerCLIConfig(auth *registry.AuthConfig) (string, error) {
+	userDir := os.Getenv("DOCKER_CONFIG")
+	if userDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get home directory: %w", err)
+		}
+		userDir = filepath.Join(home, ".docker")
+	}
+
+	cfg := make(map[string]any)
+	if data, err := os.ReadFile(filepath.Join(userDir, "config.json")); err == nil {
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return "", fmt.Errorf("failed to parse %s: %w", filepath.Join(userDir, "config.json"), err)
+		}
+	}
+
+	dir, err := os.MkdirTemp("", "agent-docker-config-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create docker config directory: %w", err)
+	}
+
+	entries, _ := os.ReadDir(userDir)
+	for _, entry := range entries {
+		if entry.Name() == "config.json" {
+			continue
+		}
+		if err := os.Symlink(filepath.Join(userDir, entry.Name()), filepath.Join(dir, entry.Name())); err != nil {
+			log.Warn("failed to link docker config entry", "entry", entry.Name(), "error", err)
+		}
+	}
+
+	auths, _ := cfg["auths"].(map[string]any)
+	if auths == nil {
+		auths = make(map[string]any)
+	}
+	auths[auth.ServerAddress] = map[string]any{
+		"auth": base64.StdEncoding.EncodeToString([]byte(auth.Username + ":" + auth.Password)),
+	}
+	cfg["auths"] = auths
+
+	// An empty credential helper makes the CLI read this registry's auth
+	// from the file even when a credsStore is configured
+	helpers, _ := cfg["credHelpers"].(map[string]any)
+	if helpers == nil {
+		helpers = make(map[string]any)
+	}
+	helpers[auth.ServerAddress] = ""
+	cfg["credHelpers"] = helpers
+
+	data, err := json.MarshalIndent(cfg, "", "\t")
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("failed to marshal docker config: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), data, 0600); err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("failed to write docker config: %w", err)
+	}
+
+	return dir, nil
+}
@@ -0,0 +1,78 @@
+package builder
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/registry"
+	"github.com/pxkundu/agent-as-code/internal/parser"
+)
+
+func TestDockerCLIConfig(t *testing.T) {
+	userDir := t.TempDir()
+	t.Setenv("DOCKER_CONFIG", userDir)
+	userConfig := `{"auths": {"ghcr.io": {"auth": "Z2g6dG9rZW4="}}, "credsStore": "desktop"}`
+	if err := os.WriteFile(filepath.Join(userDir, "config.json"), []byte(userConfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(userDir, "cli-plugins"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := dockerCLIConfig(&registry.AuthConfig{
+		Username:      "agent",
+		Password:      "pat",
+		ServerAddress: "registry.example.com",
+	})
+	if err != nil {
+		t.Fatalf("dockerCLIConfig() error = %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	data, err := os.ReadFile(filepath.Join(dir, "config.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cfg struct {
+		Auths       map[string]struct{ Auth string } `json:"auths"`
+		CredsStore  string                           `json:"credsStore"`
+		CredHelpers map[string]string                `json:"credHelpers"`
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := cfg.Auths["registry.example.com"].Auth; got != base64.StdEncoding.EncodeToString([]byte("agent:pat")) {
+		t.Errorf("registry.example.com auth = %q", got)
+	}
+	if cfg.Auths["ghcr.io"].Auth != "Z2g6dG9rZW4=" || cfg.CredsStore != "desktop" {
+		t.Errorf("user config not kept: %s", data)
+	}
+	if helper, ok := cfg.CredHelpers["registry.example.com"]; !ok || helper != "" {
+		t.Errorf("credHelpers = %v, want an empty helper for registry.example.com", cfg.CredHelpers)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "cli-plugins")); err != nil {
+		t.Errorf("cli-plugins not linked: %v", err)
+	}
+}
+
+func TestResourceArgs(t *testing.T) {
+	var limits types.ImageBuildOptions
+	resources := &parser.ResourceConfig{Limits: parser.ResourceLimits{Memory: "256Mi", CPU: "500m"}}
+	if err := applyResourceLimits(&limits, resources); err != nil {
+		t.Fatalf("applyResourceLimits() error = %v", err)
+	}
+
+	want := []string{"--memory", "268435456", "--shm-size", "67108864", "--cpu-period", "100000", "--cpu-quota", "50000"}
+	if got := resourceArgs(limits); !reflect.DeepEqual(got, want) {
+		t.Errorf("resourceArgs() = %v, want %v", got, want)
+	}
+	if got := resourceArgs(types.ImageBuildOptions{}); got != nil {
+		t.Errorf("resourceArgs() without limits = %v, want none", got)
+	}
+}
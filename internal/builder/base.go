@@ -0,0 +1,239 @@
+package builder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/registry"
+	"github.com/docker/docker/client"
+	"github.com/pxkundu/agent-as-code/internal/log"
+	"github.com/pxkundu/agent-as-code/internal/parser"
+	"gopkg.in/yaml.v3"
+)
+
+// MergedSpecFile is the merged agent.yaml written to the build context when
+// extending a base image; it replaces /app/agent.yaml in the new image
+const MergedSpecFile = "agent.merged.yaml"
+
+// loadBaseSpec reads /app/agent.yaml from the base image, pulling the image
+// first if it is not available locally. It returns nil if the image has no
+// agent.yaml.
+func (b *Builder) loadBaseSpec(image, profile string) (*parser.AgentSpec, error) {
+	if err := b.ensureImage(image, profile); err != nil {
+		return nil, err
+	}
+
+	files, err := b.extractImageFiles(image, []string{"agent.yaml"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read base image %s: %w", image, err)
+	}
+
+	data, ok := files["app/agent.yaml"]
+	if !ok {
+		return nil, nil
+	}
+
+	spec, err := b.parser.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid agent.yaml in base image %s: %w", image, err)
+	}
+	return spec, nil
+}
+
+// ensureImage pulls image unless it is already available locally
+func (b *Builder) ensureImage(image, profile string) error {
+	if b.dockerClient == nil {
+		return fmt.Errorf("Docker client not available. Please ensure Docker is running")
+	}
+
+	ctx := context.Background()
+	if _, _, err := b.dockerClient.ImageInspectWithRaw(ctx, image); err == nil {
+		return nil
+	} else if !client.IsErrNotFound(err) {
+		return fmt.Errorf("failed to inspect base image %s: %w", image, err)
+	}
+
+	pullOpts := types.ImagePullOptions{}
+	auth, err := profileAuthConfig(profile)
+	if err != nil {
+		return err
+	}
+	if auth != nil {
+		pullOpts.RegistryAuth, err = registry.EncodeAuthConfig(*auth)
+		if err != nil {
+			return fmt.Errorf("failed to encode registry auth: %w", err)
+		}
+	}
+
+	log.Info("pulling base image", "image", image, "authenticated", auth != nil)
+	resp, err := b.dockerClient.ImagePull(ctx, image, pullOpts)
+	if err != nil {
+		return fmt.Errorf("failed to pull base image %s: %w", image, err)
+	}
+	defer resp.Close()
+
+	decoder := json.NewDecoder(resp)
+	for {
+		var pullLine struct {
+			Error string `json:"error"`
+		}
+		if err := decoder.Decode(&pullLine); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return fmt.Errorf("failed to decode pull output: %w", err)
+		}
+		if pullLine.Error != "" {
+			return fmt.Errorf("failed to pull base image %s: %s", image, pullLine.Error)
+		}
+	}
+}
+
+// extensionDependencies returns the instructions installing the
+// dependencies listed in the build context on top of a base image
+func extensionDependencies(runtime, contextPath, run string) string {
+	switch {
+	case runtime == "python" && usesPyproject(contextPath):
+		return "# Install additional Python dependencies with uv\n" + uvInstall(run)
+	case runtime == "python" && fileExists(filepath.Join(contextPath, "requirements.txt")):
+		return "# Install additional Python dependencies\n" +
+			"COPY requirements.txt .\n" +
+			run + "pip install --no-cache-dir -r requirements.txt\n\n"
+	case runtime == "nodejs" && fileExists(filepath.Join(contextPath, "package.json")):
+		return "# Install additional Node.js dependencies\n" +
+			"COPY package*.json ./\n" +
+			run + "npm install --omit=dev\n\n"
+	default:
+		return ""
+	}
+}
+
+// writeMergedSpec writes spec to MergedSpecFile in the build context
+func writeMergedSpec(contextPath string, spec *parser.AgentSpec) error {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(spec); err != nil {
+		return fmt.Errorf("failed to marshal merged agent.yaml: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(contextPath, MergedSpecFile), buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", MergedSpecFile, err)
+	}
+	return nil
+}
+
+// MergeSpecs returns the spec of an image extending base with override.
+// Fields set in override win; lists are combined, with entries of override
+// replacing those of base with the same key (environment variable name,
+// container port, volume target, secret name); and maps such as
+// metadata.labels are merged.
+func (b *Builder) MergeSpecs(base, override *parser.AgentSpec) *parser.AgentSpec {
+	merged := *base
+
+	merged.APIVersion = pick(override.APIVersion, base.APIVersion)
+	merged.Kind = pick(override.Kind, base.Kind)
+
+	merged.Metadata.Name = pick(override.Metadata.Name, base.Metadata.Name)
+	merged.Metadata.Version = pick(override.Metadata.Version, base.Metadata.Version)
+	merged.Metadata.Description = pick(override.Metadata.Description, base.Metadata.Description)
+	merged.Metadata.Author = pick(override.Metadata.Author, base.Metadata.Author)
+	merged.Metadata.Tags = union(base.Metadata.Tags, override.Metadata.Tags)
+	merged.Metadata.Labels = mergeMaps(base.Metadata.Labels, override.Metadata.Labels)
+
+	spec, baseSpec, overrideSpec := &merged.Spec, &base.Spec, &override.Spec
+	spec.Runtime = pick(overrideSpec.Runtime, baseSpec.Runtime)
+	spec.Model.Provider = pick(overrideSpec.Model.Provider, baseSpec.Model.Provider)
+	spec.Model.Name = pick(overrideSpec.Model.Name, baseSpec.Model.Name)
+	spec.Model.Config = mergeMaps(baseSpec.Model.Config, overrideSpec.Model.Config)
+	spec.Capabilities = union(baseSpec.Capabilities, overrideSpec.Capabilities)
+	spec.Dependencies = union(baseSpec.Dependencies, overrideSpec.Dependencies)
+	spec.Config = mergeMaps(baseSpec.Config, overrideSpec.Config)
+
+	spec.Environment = mergeByKey(baseSpec.Environment, overrideSpec.Environment, func(env parser.EnvironmentVar) string {
+		return env.Name
+	})
+	spec.Ports = mergeByKey(baseSpec.Ports, overrideSpec.Ports, func(port parser.PortConfig) int {
+		return port.Container
+	})
+	spec.Volumes = mergeByKey(baseSpec.Volumes, overrideSpec.Volumes, func(volume parser.VolumeConfig) string {
+		return volume.Target
+	})
+	spec.Secrets = mergeByKey(baseSpec.Secrets, overrideSpec.Secrets, func(secret parser.SecretConfig) string {
+		return secret.Name
+	})
+
+	if overrideSpec.HealthCheck != nil {
+		spec.HealthCheck = overrideSpec.HealthCheck
+	}
+	if overrideSpec.Probes != nil {
+		spec.Probes = overrideSpec.Probes
+	}
+	if overrideSpec.Resources != nil {
+		spec.Resources = overrideSpec.Resources
+	}
+	if overrideSpec.Scaling != nil {
+		spec.Scaling = overrideSpec.Scaling
+	}
+
+	return &merged
+}
+
+// pick returns override unless it is empty
+func pick(override, base string) string {
+	if override != "" {
+		return override
+	}
+	return base
+}
+
+// union returns the items of base followed by those of override that are
+// not in base
+func union(base, override []string) []string {
+	return mergeByKey(base, override, func(item string) string { return item })
+}
+
+// mergeByKey returns base with each item replaced by the override item with
+// the same key, followed by the override items whose key is new
+func mergeByKey[T any, K comparable](base, override []T, key func(T) K) []T {
+	if len(override) == 0 {
+		return base
+	}
+
+	index := make(map[K]int)
+	var merged []T
+	for _, item := range base {
+		index[key(item)] = len(merged)
+		merged = append(merged, item)
+	}
+	for _, item := range override {
+		if i, ok := index[key(item)]; ok {
+			merged[i] = item
+			continue
+		}
+		index[key(item)] = len(merged)
+		merged = append(merged, item)
+	}
+	return merged
+}
+
+// mergeMaps returns the entries of base overlaid with those of override
+func mergeMaps[V any](base, override map[string]V) map[string]V {
+	if len(override) == 0 {
+		return base
+	}
+
+	merged := make(map[string]V, len(base)+len(override))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range override {
+		merged[k] = v
+	}
+	return merged
+}
@@ -8,14 +8,24 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/registry"
 	"github.com/docker/docker/client"
+	"github.com/pxkundu/agent-as-code/internal/log"
 	"github.com/pxkundu/agent-as-code/internal/parser"
 )
 
+const (
+	// defaultShmSize is Docker's default /dev/shm size
+	defaultShmSize = 64 << 20
+)
+
 // Builder handles agent building
 type Builder struct {
 	parser       *parser.Parser
@@ -29,13 +39,34 @@ type BuildOptions struct {
 	NoCache  bool
 	Push     bool
 	Platform string
+
+	// UsesBuildKit builds with BuildKit, which is required for spec.secrets
+	UsesBuildKit bool
+
+	// Profile is the registry profile whose PAT is used to pull base images
+	// from its registry. Empty selects the default profile.
+	Profile string
+
+	// StrictDeps fails the build on dependency conflicts instead of
+	// printing them as warnings
+	StrictDeps bool
+
+	// FromBase extends an existing agent image: the Dockerfile starts
+	// FROM it and only adds this context's dependencies, code and
+	// settings, and agent.yaml is merged with the base image's
+	FromBase string
 }
 
 // BuildResult represents build result
 type BuildResult struct {
-	ImageID string
-	Size    string
-	Tags    []string
+	ImageID       string
+	Size          string
+	SizeBytes     int64
+	Digest        string
+	Tags          []string
+	BuiltAt       time.Time
+	BuildDuration time.Duration
+	Spec          *parser.AgentSpec
 }
 
 // New creates a new builder instance
@@ -72,99 +103,237 @@ func (b *Builder) ValidateContext(path string) error {
 
 // Build builds an agent from the given options
 func (b *Builder) Build(options *BuildOptions) (*BuildResult, error) {
+	start := time.Now()
+
+	spec, dockerfilePath, err := b.prepare(options)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build Docker image. Secrets can only be supplied through a BuildKit
+	// session, so those builds go through the docker CLI.
+	var imageID string
+	if len(spec.Spec.Secrets) > 0 {
+		imageID, err = b.buildWithDockerCLI(options, dockerfilePath, spec.Spec.Secrets, spec.Spec.Resources)
+	} else {
+		imageID, err = b.buildDockerImage(options, dockerfilePath, spec.Spec.Resources)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("docker build failed: %w", err)
+	}
+
+	// Get image size
+	size, sizeBytes, err := b.getImageSize(imageID)
+	if err != nil {
+		size = "unknown"
+	}
+
+	digest, err := b.ImageDigest(imageID)
+	if err != nil {
+		digest = imageID
+	}
+
+	// Prepare result
+	result := &BuildResult{
+		ImageID:       imageID,
+		Size:          size,
+		SizeBytes:     sizeBytes,
+		Digest:        digest,
+		Tags:          []string{},
+		BuiltAt:       time.Now().UTC(),
+		BuildDuration: time.Since(start),
+		Spec:          spec,
+	}
+
+	if options.Tag != "" {
+		result.Tags = append(result.Tags, options.Tag)
+	}
+
+	return result, nil
+}
+
+// prepare parses the agent.yaml in the build context and writes the
+// generated Dockerfile.agent next to it
+func (b *Builder) prepare(options *BuildOptions) (*parser.AgentSpec, string, error) {
 	// Find and parse agent.yaml
 	agentFile, err := b.parser.FindAgentFile(options.Path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find agent.yaml: %w", err)
+		return nil, "", fmt.Errorf("failed to find agent.yaml: %w", err)
 	}
 
 	spec, err := b.parser.ParseFile(agentFile)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse agent.yaml: %w", err)
+		return nil, "", fmt.Errorf("failed to parse agent.yaml: %w", err)
+	}
+
+	// Merge with the base image's agent.yaml before interpolation, so the
+	// merged file in the image keeps ${VAR} references
+	if options.FromBase != "" {
+		if spec, err = b.extendBaseSpec(options, spec); err != nil {
+			return nil, "", err
+		}
+	}
+
+	// Resolve ${VAR} references in environment values from the host. Those
+	// to unset or secret variables stay out of the image for agent run to
+	// resolve.
+	spec, deferred, err := b.parser.InterpolateBuildEnv(spec)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to interpolate environment: %w", err)
+	}
+	for _, name := range deferred {
+		log.Info("environment variable left for agent run to resolve", "name", name)
+	}
+
+	if len(spec.Spec.Secrets) > 0 && !options.UsesBuildKit {
+		return nil, "", fmt.Errorf("spec.secrets requires BuildKit; rebuild with --buildkit")
+	}
+
+	// Catch dependency conflicts before the image build runs into them
+	if warnings := b.checkDependencies(options.Path, spec.Spec.Runtime); len(warnings) > 0 {
+		if options.StrictDeps {
+			return nil, "", fmt.Errorf("dependency conflicts in requirements.txt:\n  %s", strings.Join(warnings, "\n  "))
+		}
+		for _, warning := range warnings {
+			log.Warn("dependency conflict", "conflict", warning)
+		}
+	}
+
+	// Keep caches and VCS history out of the build context
+	if err := b.generateDefaultDockerignore(options.Path); err != nil {
+		log.Warn("failed to generate .dockerignore", "path", options.Path, "error", err)
 	}
 
 	// Generate Dockerfile
-	dockerfile, err := b.generateDockerfile(spec, options.Path)
+	dockerfile, err := b.generateDockerfile(spec, options.Path, options.FromBase, options.UsesBuildKit)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate Dockerfile: %w", err)
+		return nil, "", fmt.Errorf("failed to generate Dockerfile: %w", err)
 	}
 
 	// Write Dockerfile to build context
 	dockerfilePath := filepath.Join(options.Path, "Dockerfile.agent")
 	if err := b.writeDockerfile(dockerfilePath, dockerfile); err != nil {
-		return nil, fmt.Errorf("failed to write Dockerfile: %w", err)
+		return nil, "", fmt.Errorf("failed to write Dockerfile: %w", err)
 	}
 
-	// Build Docker image
-	imageID, err := b.buildDockerImage(options, dockerfilePath)
-	if err != nil {
-		return nil, fmt.Errorf("docker build failed: %w", err)
-	}
+	return spec, dockerfilePath, nil
+}
 
-	// Get image size
-	size, err := b.getImageSize(imageID)
+// extendBaseSpec merges spec into the agent.yaml of options.FromBase and
+// writes the result to MergedSpecFile for the generated Dockerfile to copy
+func (b *Builder) extendBaseSpec(options *BuildOptions, spec *parser.AgentSpec) (*parser.AgentSpec, error) {
+	base, err := b.loadBaseSpec(options.FromBase, options.Profile)
 	if err != nil {
-		size = "unknown"
+		return nil, err
 	}
 
-	// Prepare result
-	result := &BuildResult{
-		ImageID: imageID,
-		Size:    size,
-		Tags:    []string{},
+	if base == nil {
+		log.Warn("base image has no /app/agent.yaml; the image will carry this agent.yaml only", "image", options.FromBase)
+	} else {
+		if base.Spec.Runtime != spec.Spec.Runtime {
+			return nil, fmt.Errorf("agent.yaml uses the %s runtime, but base image %s uses %s", spec.Spec.Runtime, options.FromBase, base.Spec.Runtime)
+		}
+		spec = b.MergeSpecs(base, spec)
+		if err := b.parser.Validate(spec); err != nil {
+			return nil, fmt.Errorf("merged agent.yaml is invalid: %w", err)
+		}
 	}
 
-	if options.Tag != "" {
-		result.Tags = append(result.Tags, options.Tag)
+	if err := writeMergedSpec(options.Path, spec); err != nil {
+		return nil, err
 	}
-
-	return result, nil
+	return spec, nil
 }
 
-// generateDockerfile generates a Dockerfile from agent spec
-func (b *Builder) generateDockerfile(spec *parser.AgentSpec, contextPath string) (string, error) {
+// generateDockerfile generates a Dockerfile from agent spec. A non-empty
+// baseImage extends that image instead of starting from the runtime's.
+func (b *Builder) generateDockerfile(spec *parser.AgentSpec, contextPath, baseImage string, useBuildKit bool) (string, error) {
 	dockerfile := ""
 
-	// Base image based on runtime
-	switch spec.Spec.Runtime {
-	case "python":
-		dockerfile += "FROM python:3.11-slim\n\n"
-	case "nodejs":
-		dockerfile += "FROM node:18-slim\n\n"
-	case "go":
-		dockerfile += "FROM golang:1.21-alpine AS builder\n"
-		dockerfile += "FROM alpine:latest\n\n"
-	default:
-		return "", fmt.Errorf("unsupported runtime: %s", spec.Spec.Runtime)
+	if useBuildKit {
+		dockerfile += "# syntax=docker/dockerfile:1\n"
 	}
 
-	// Set working directory
-	dockerfile += "WORKDIR /app\n\n"
+	// Dependencies are installed with build secrets (e.g. private registry
+	// credentials) mounted, so they never end up in an image layer
+	run := "RUN "
+	if useBuildKit {
+		run += secretMounts(spec.Spec.Secrets)
+	}
 
-	// Install dependencies
-	if len(spec.Spec.Dependencies) > 0 {
+	// Node.js projects are built in a separate stage so build tools and dev
+	// dependencies stay out of the final image
+	var pkg *packageJSON
+	if spec.Spec.Runtime == "nodejs" && baseImage == "" {
+		var err error
+		if pkg, err = readPackageJSON(contextPath); err != nil {
+			return "", err
+		}
+	}
+
+	// Base image based on runtime, or the agent image being extended
+	if baseImage != "" {
+		dockerfile += "FROM " + baseImage + "\n\n"
+	} else {
 		switch spec.Spec.Runtime {
 		case "python":
-			dockerfile += "# Install Python dependencies\n"
-			dockerfile += "COPY requirements.txt .\n"
-			dockerfile += "RUN pip install --no-cache-dir -r requirements.txt\n\n"
+			dockerfile += "FROM python:" + pythonVersion + "-slim\n\n"
 		case "nodejs":
-			dockerfile += "# Install Node.js dependencies\n"
-			dockerfile += "COPY package*.json .\n"
-			dockerfile += "RUN npm ci --only=production\n\n"
+			if pkg != nil {
+				dockerfile += nodeBuilderStage(pkg, contextPath, run)
+			}
+			dockerfile += "FROM " + nodeImage + "\n"
+			dockerfile += "ENV NODE_ENV=production\n\n"
+		case "go":
+			dockerfile += "FROM golang:1.21-alpine AS builder\n"
+			dockerfile += "FROM alpine:latest\n\n"
+		default:
+			return "", fmt.Errorf("unsupported runtime: %s", spec.Spec.Runtime)
 		}
 	}
 
+	// Set working directory
+	dockerfile += "WORKDIR /app\n\n"
+
+	// Scaling hints
+	if spec.Spec.Scaling != nil {
+		dockerfile += "# Scaling hints\n"
+		dockerfile += fmt.Sprintf("LABEL agent.dev/min-replicas=%d\n", spec.Spec.Scaling.MinReplicas)
+		dockerfile += fmt.Sprintf("LABEL agent.dev/max-replicas=%d\n\n", spec.Spec.Scaling.MaxReplicas)
+	}
+
+	// Install dependencies. An extended image already has the base
+	// image's, so only the manifests in this context are installed.
+	switch {
+	case baseImage != "":
+		dockerfile += extensionDependencies(spec.Spec.Runtime, contextPath, run)
+	case spec.Spec.Runtime == "python" && usesPyproject(contextPath):
+		dockerfile += "# Install Python dependencies with uv\n"
+		dockerfile += uvInstall(run)
+	case spec.Spec.Runtime == "python" && len(spec.Spec.Dependencies) > 0:
+		dockerfile += "# Install Python dependencies\n"
+		dockerfile += "COPY requirements.txt .\n"
+		dockerfile += run + "pip install --no-cache-dir -r requirements.txt\n\n"
+	}
+
 	// Copy application code
-	dockerfile += "# Copy application code\n"
-	dockerfile += "COPY . .\n\n"
+	if pkg != nil {
+		dockerfile += nodeCopyFromBuilder(pkg)
+	} else {
+		dockerfile += "# Copy application code\n"
+		dockerfile += "COPY . .\n"
+		if baseImage != "" {
+			dockerfile += "COPY " + MergedSpecFile + " agent.yaml\n"
+		}
+		dockerfile += "\n"
+	}
 
 	// Set environment variables
 	if len(spec.Spec.Environment) > 0 {
 		dockerfile += "# Environment variables\n"
 		for _, env := range spec.Spec.Environment {
 			if env.Value != "" {
-				dockerfile += fmt.Sprintf("ENV %s=%s\n", env.Name, env.Value)
+				dockerfile += fmt.Sprintf("ENV %s=%s\n", env.Name, dockerfileQuote(env.Value))
 			}
 		}
 		dockerfile += "\n"
@@ -179,33 +348,37 @@ func (b *Builder) generateDockerfile(spec *parser.AgentSpec, contextPath string)
 		dockerfile += "\n"
 	}
 
-	// Health check
-	if spec.Spec.HealthCheck != nil {
+	// Health check. Docker supports a single HEALTHCHECK, so only the
+	// liveness probe is used; readiness goes to the Kubernetes manifest.
+	if check := spec.Spec.LivenessCheck(); check != nil {
 		dockerfile += "# Health check\n"
 		dockerfile += "HEALTHCHECK "
-		if spec.Spec.HealthCheck.Interval != "" {
-			dockerfile += fmt.Sprintf("--interval=%s ", spec.Spec.HealthCheck.Interval)
+		if check.Interval != "" {
+			dockerfile += fmt.Sprintf("--interval=%s ", check.Interval)
 		}
-		if spec.Spec.HealthCheck.Timeout != "" {
-			dockerfile += fmt.Sprintf("--timeout=%s ", spec.Spec.HealthCheck.Timeout)
+		if check.Timeout != "" {
+			dockerfile += fmt.Sprintf("--timeout=%s ", check.Timeout)
 		}
-		if spec.Spec.HealthCheck.Retries > 0 {
-			dockerfile += fmt.Sprintf("--retries=%d ", spec.Spec.HealthCheck.Retries)
+		if check.Retries > 0 {
+			dockerfile += fmt.Sprintf("--retries=%d ", check.Retries)
 		}
-		if spec.Spec.HealthCheck.StartPeriod != "" {
-			dockerfile += fmt.Sprintf("--start-period=%s ", spec.Spec.HealthCheck.StartPeriod)
+		if check.StartPeriod != "" {
+			dockerfile += fmt.Sprintf("--start-period=%s ", check.StartPeriod)
 		}
-		dockerfile += "CMD " + joinCommand(spec.Spec.HealthCheck.Command) + "\n\n"
+		dockerfile += "CMD " + joinCommand(check.Command) + "\n\n"
 	}
 
-	// Default command
+	// Default command. An extended image keeps the base image's.
+	if baseImage != "" {
+		return dockerfile, nil
+	}
 	switch spec.Spec.Runtime {
 	case "python":
 		dockerfile += "# Run the application\n"
 		dockerfile += "CMD [\"python\", \"main.py\"]\n"
 	case "nodejs":
 		dockerfile += "# Run the application\n"
-		dockerfile += "CMD [\"node\", \"index.js\"]\n"
+		dockerfile += fmt.Sprintf("CMD [\"node\", %q]\n", pkg.entrypoint())
 	case "go":
 		dockerfile += "# Run the application\n"
 		dockerfile += "CMD [\"./app\"]\n"
@@ -220,7 +393,7 @@ func (b *Builder) writeDockerfile(path, content string) error {
 }
 
 // buildDockerImage builds the Docker image
-func (b *Builder) buildDockerImage(options *BuildOptions, dockerfilePath string) (string, error) {
+func (b *Builder) buildDockerImage(options *BuildOptions, dockerfilePath string, resources *parser.ResourceConfig) (string, error) {
 	if b.dockerClient == nil {
 		return "", fmt.Errorf("Docker client not available. Please ensure Docker is running")
 	}
@@ -239,14 +412,35 @@ func (b *Builder) buildDockerImage(options *BuildOptions, dockerfilePath string)
 		Tags:       []string{},
 		Remove:     true,
 		NoCache:    options.NoCache,
+		Platform:   options.Platform,
 	}
 
 	if options.Tag != "" {
 		buildOpts.Tags = append(buildOpts.Tags, options.Tag)
 	}
 
+	if options.UsesBuildKit {
+		inlineCache := "1"
+		buildOpts.Version = types.BuilderBuildKit
+		buildOpts.BuildArgs = map[string]*string{"BUILDKIT_INLINE_CACHE": &inlineCache}
+	}
+
+	// Resource constraints
+	if err := applyResourceLimits(&buildOpts, resources); err != nil {
+		return "", err
+	}
+
+	// Credentials for base images on the profile's registry
+	auth, err := profileAuthConfig(options.Profile)
+	if err != nil {
+		return "", err
+	}
+	if auth != nil {
+		buildOpts.AuthConfigs = map[string]registry.AuthConfig{auth.ServerAddress: *auth}
+	}
+
 	// Build the image
-	fmt.Printf("Building Docker image...\n")
+	log.Info("building docker image", "dockerfile", filepath.Base(dockerfilePath), "buildkit", options.UsesBuildKit)
 	resp, err := b.dockerClient.ImageBuild(ctx, buildContext, buildOpts)
 	if err != nil {
 		return "", fmt.Errorf("failed to build image: %w", err)
@@ -258,11 +452,10 @@ func (b *Builder) buildDockerImage(options *BuildOptions, dockerfilePath string)
 	decoder := json.NewDecoder(resp.Body)
 	for {
 		var buildLine struct {
-			Stream string `json:"stream"`
-			Aux    struct {
-				ID string `json:"ID"`
-			} `json:"aux"`
-			Error string `json:"error"`
+			Stream string          `json:"stream"`
+			ID     string          `json:"id"`
+			Aux    json.RawMessage `json:"aux"`
+			Error  string          `json:"error"`
 		}
 
 		if err := decoder.Decode(&buildLine); err != nil {
@@ -280,8 +473,15 @@ func (b *Builder) buildDockerImage(options *BuildOptions, dockerfilePath string)
 			fmt.Print(buildLine.Stream)
 		}
 
-		if buildLine.Aux.ID != "" {
-			imageID = buildLine.Aux.ID
+		// BuildKit also sends progress traces as aux messages; only the
+		// classic builder's aux and BuildKit's moby.image.id carry the ID
+		if len(buildLine.Aux) > 0 && (buildLine.ID == "" || buildLine.ID == "moby.image.id") {
+			var aux struct {
+				ID string `json:"ID"`
+			}
+			if err := json.Unmarshal(buildLine.Aux, &aux); err == nil && aux.ID != "" {
+				imageID = aux.ID
+			}
 		}
 	}
 
@@ -289,41 +489,236 @@ func (b *Builder) buildDockerImage(options *BuildOptions, dockerfilePath string)
 		return "", fmt.Errorf("failed to get image ID from build output")
 	}
 
-	fmt.Printf("Successfully built %s\n", imageID[:12])
+	log.Info("image built", "id", imageID[:12], "tag", options.Tag)
+
+	return imageID, nil
+}
+
+// buildWithDockerCLI builds the image with `docker build` under BuildKit so
+// that build secrets can be passed with --secret. Like buildDockerImage it
+// applies the resource limits and the profile's registry credentials.
+func (b *Builder) buildWithDockerCLI(options *BuildOptions, dockerfilePath string, secrets []parser.SecretConfig, resources *parser.ResourceConfig) (string, error) {
+	dockerPath, err := exec.LookPath("docker")
+	if err != nil {
+		return "", fmt.Errorf("docker CLI is required to build with secrets: %w", err)
+	}
+
+	iidFile, err := os.CreateTemp("", "agent-iid-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create image ID file: %w", err)
+	}
+	iidFile.Close()
+	defer os.Remove(iidFile.Name())
+
+	args := []string{"build", "-f", dockerfilePath, "--iidfile", iidFile.Name(),
+		"--build-arg", "BUILDKIT_INLINE_CACHE=1"}
 	if options.Tag != "" {
-		fmt.Printf("Successfully tagged %s\n", options.Tag)
+		args = append(args, "-t", options.Tag)
+	}
+	if options.NoCache {
+		args = append(args, "--no-cache")
+	}
+	if options.Platform != "" {
+		args = append(args, "--platform", options.Platform)
+	}
+
+	// Resource constraints
+	var limits types.ImageBuildOptions
+	if err := applyResourceLimits(&limits, resources); err != nil {
+		return "", err
+	}
+	args = append(args, resourceArgs(limits)...)
+
+	args = append(args, secretArgs(options.Path, secrets)...)
+	args = append(args, options.Path)
+
+	log.Info("building docker image with docker CLI", "dockerfile", filepath.Base(dockerfilePath), "secrets", len(secrets))
+	cmd := exec.Command(dockerPath, args...)
+	cmd.Env = append(os.Environ(), "DOCKER_BUILDKIT=1")
+
+	// Credentials for base images on the profile's registry
+	auth, err := profileAuthConfig(options.Profile)
+	if err != nil {
+		return "", err
+	}
+	if auth != nil {
+		configDir, err := dockerCLIConfig(auth)
+		if err != nil {
+			return "", err
+		}
+		defer os.RemoveAll(configDir)
+		cmd.Env = append(cmd.Env, "DOCKER_CONFIG="+configDir)
+	}
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("failed to build image: %w", err)
+	}
+
+	data, err := os.ReadFile(iidFile.Name())
+	if err != nil {
+		return "", fmt.Errorf("failed to read image ID: %w", err)
+	}
+	imageID := strings.TrimSpace(string(data))
+	if imageID == "" {
+		return "", fmt.Errorf("failed to get image ID from build output")
 	}
 
 	return imageID, nil
 }
 
+// secretArgs returns the docker build --secret flags for build secrets.
+// Relative sources are resolved against the build context.
+func secretArgs(contextPath string, secrets []parser.SecretConfig) []string {
+	var args []string
+	for _, secret := range secrets {
+		if secret.Env != "" {
+			args = append(args, "--secret", fmt.Sprintf("id=%s,env=%s", secret.Name, secret.Env))
+			continue
+		}
+		source := secret.Source
+		if !filepath.IsAbs(source) {
+			source = filepath.Join(contextPath, source)
+		}
+		args = append(args, "--secret", fmt.Sprintf("id=%s,src=%s", secret.Name, source))
+	}
+	return args
+}
+
+// resourceArgs returns the docker build flags for the resource limits that
+// applyResourceLimits set on limits
+func resourceArgs(limits types.ImageBuildOptions) []string {
+	var args []string
+	if limits.Memory > 0 {
+		args = append(args, "--memory", strconv.FormatInt(limits.Memory, 10),
+			"--shm-size", strconv.FormatInt(limits.ShmSize, 10))
+	}
+	if limits.CPUQuota > 0 {
+		args = append(args, "--cpu-period", strconv.FormatInt(limits.CPUPeriod, 10),
+			"--cpu-quota", strconv.FormatInt(limits.CPUQuota, 10))
+	}
+	return args
+}
+
+// secretMounts returns the RUN --mount flags for build secrets
+func secretMounts(secrets []parser.SecretConfig) string {
+	mounts := ""
+	for _, secret := range secrets {
+		mounts += "--mount=type=secret,id=" + secret.Name
+		if secret.Target != "" {
+			mounts += ",target=" + secret.Target
+		}
+		mounts += " "
+	}
+	return mounts
+}
+
+// applyResourceLimits sets memory and CPU constraints on the build from
+// spec.resources.limits
+func applyResourceLimits(buildOpts *types.ImageBuildOptions, resources *parser.ResourceConfig) error {
+	if resources == nil {
+		return nil
+	}
+
+	if resources.Limits.Memory != "" {
+		memory, err := parser.ParseMemory(resources.Limits.Memory)
+		if err != nil {
+			return fmt.Errorf("invalid memory limit: %w", err)
+		}
+		buildOpts.Memory = memory
+
+		// Keep Docker's default /dev/shm size unless it would take more
+		// than half of the memory limit
+		buildOpts.ShmSize = defaultShmSize
+		if buildOpts.ShmSize > memory/2 {
+			buildOpts.ShmSize = memory / 2
+		}
+	}
+
+	if resources.Limits.CPU != "" {
+		quota, err := parser.CPUQuota(resources.Limits.CPU)
+		if err != nil {
+			return fmt.Errorf("invalid cpu limit: %w", err)
+		}
+		// The build API has no NanoCPUs field; express it as a CFS quota
+		buildOpts.CPUPeriod = parser.CPUPeriod
+		buildOpts.CPUQuota = quota
+	}
+
+	return nil
+}
+
+// dockerfileQuote quotes s as a double-quoted Dockerfile word, so that
+// spaces, quotes and $ are taken literally. Newlines cannot be represented
+// and are replaced with spaces, so a value can never start an instruction.
+func dockerfileQuote(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`, "\r\n", " ", "\n", " ", "\r", " ").Replace(s)
+	return `"` + s + `"`
+}
+
 // getImageSize gets the size of a Docker image
-func (b *Builder) getImageSize(imageID string) (string, error) {
+func (b *Builder) getImageSize(imageID string) (string, int64, error) {
 	if b.dockerClient == nil {
-		return "unknown", nil
+		return "unknown", 0, nil
 	}
 
 	ctx := context.Background()
 	imageInspect, _, err := b.dockerClient.ImageInspectWithRaw(ctx, imageID)
 	if err != nil {
-		return "unknown", err
+		return "unknown", 0, err
 	}
 
 	size := imageInspect.Size
-	return formatSize(size), nil
+	return formatSize(size), size, nil
 }
 
-// Push pushes the image to a registry
-func (b *Builder) Push(tag string) error {
+// ImageDigest returns the registry digest of an image once it has been
+// pushed, or its content-addressable image ID otherwise
+func (b *Builder) ImageDigest(imageID string) (string, error) {
+	if b.dockerClient == nil {
+		return "", fmt.Errorf("Docker client not available")
+	}
+
+	ctx := context.Background()
+	imageInspect, _, err := b.dockerClient.ImageInspectWithRaw(ctx, imageID)
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect image: %w", err)
+	}
+
+	// Repo digests have the form name@sha256:...
+	for _, repoDigest := range imageInspect.RepoDigests {
+		if _, digest, ok := strings.Cut(repoDigest, "@"); ok {
+			return digest, nil
+		}
+	}
+
+	return imageInspect.ID, nil
+}
+
+// Push pushes the image to a registry, authenticating with the PAT of the
+// given registry profile. An empty profileName selects the default profile.
+func (b *Builder) Push(tag string, profileName string) error {
 	if b.dockerClient == nil {
 		return fmt.Errorf("Docker client not available")
 	}
 
 	ctx := context.Background()
 
+	pushOpts := types.ImagePushOptions{}
+	auth, err := profileAuthConfig(profileName)
+	if err != nil {
+		return err
+	}
+	if auth != nil {
+		pushOpts.RegistryAuth, err = registry.EncodeAuthConfig(*auth)
+		if err != nil {
+			return fmt.Errorf("failed to encode registry auth: %w", err)
+		}
+	}
+
 	// Push the image
-	fmt.Printf("Pushing %s...\n", tag)
-	resp, err := b.dockerClient.ImagePush(ctx, tag, types.ImagePushOptions{})
+	log.Info("pushing image", "tag", tag, "authenticated", auth != nil)
+	resp, err := b.dockerClient.ImagePush(ctx, tag, pushOpts)
 	if err != nil {
 		return fmt.Errorf("failed to push image: %w", err)
 	}
@@ -357,18 +752,84 @@ func (b *Builder) Push(tag string) error {
 		}
 	}
 
-	fmt.Printf("Push completed successfully\n")
+	log.Info("push completed", "tag", tag)
 	return nil
 }
 
-// createBuildContext creates a tar archive of the build context
+// TagAndPush pushes localTag to a private registry such as
+// registry.example.com:5000. Docker pushes an image to the registry named
+// by its tag, so the image is first tagged RegistryTag(registryURL,
+// remoteTag); an empty remoteTag keeps localTag's name and tag. untag
+// removes the registry-qualified tag again once the image is pushed.
+func (b *Builder) TagAndPush(localTag, registryURL, remoteTag, profileName string, untag bool) error {
+	if b.dockerClient == nil {
+		return fmt.Errorf("Docker client not available")
+	}
+	if localTag == "" {
+		return fmt.Errorf("a tag is required to push to %s", registryURL)
+	}
+	if remoteTag == "" {
+		remoteTag = localTag
+	}
+
+	ctx := context.Background()
+	ref := RegistryTag(registryURL, remoteTag)
+	if ref != localTag {
+		log.Info("tagging image", "tag", localTag, "remote", ref)
+		if err := b.dockerClient.ImageTag(ctx, localTag, ref); err != nil {
+			return fmt.Errorf("failed to tag %s as %s: %w", localTag, ref, err)
+		}
+	}
+
+	if err := b.Push(ref, profileName); err != nil {
+		return err
+	}
+
+	if untag && ref != localTag {
+		// Removing one of the image's tags only untags it
+		if _, err := b.dockerClient.ImageRemove(ctx, ref, types.ImageRemoveOptions{}); err != nil {
+			return fmt.Errorf("failed to remove tag %s: %w", ref, err)
+		}
+		log.Debug("removed remote tag", "tag", ref)
+	}
+	return nil
+}
+
+// RegistryTag qualifies tag with registryURL, e.g. my-agent:latest and
+// registry.example.com:5000 give registry.example.com:5000/my-agent:latest.
+// A registry host already in tag is replaced; a path in registryURL, such
+// as registry.example.com/team, is kept as a namespace.
+func RegistryTag(registryURL, tag string) string {
+	if _, rest, ok := strings.Cut(registryURL, "://"); ok {
+		registryURL = rest
+	}
+	registryURL = strings.Trim(registryURL, "/")
+	if registryURL == "" {
+		return tag
+	}
+
+	// Like Docker, a first component with a dot or port, or localhost,
+	// names a registry
+	if first, rest, ok := strings.Cut(tag, "/"); ok && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		tag = rest
+	}
+	return registryURL + "/" + tag
+}
+
+// createBuildContext creates a tar archive of the build context, leaving out
+// the files its .dockerignore excludes
 func (b *Builder) createBuildContext(buildPath, dockerfilePath string) (io.Reader, error) {
+	ignore, err := loadIgnoreFile(buildPath)
+	if err != nil {
+		return nil, err
+	}
+
 	buf := new(bytes.Buffer)
 	tw := tar.NewWriter(buf)
 	defer tw.Close()
 
 	// Walk through the build directory
-	err := filepath.Walk(buildPath, func(path string, info os.FileInfo, err error) error {
+	err = filepath.Walk(buildPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -392,6 +853,14 @@ func (b *Builder) createBuildContext(buildPath, dockerfilePath string) (io.Reade
 			return nil
 		}
 
+		// The Dockerfile is sent even when it is ignored, as docker does
+		if relPath != filepath.Base(dockerfilePath) && ignore.excluded(relPath) {
+			if info.IsDir() && !ignore.hasExclusions() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
 		// Create tar header
 		header, err := tar.FileInfoHeader(info, "")
 		if err != nil {
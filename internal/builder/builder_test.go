@@ -0,0 +1,40 @@
+package builder
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pxkundu/agent-as-code/internal/parser"
+)
+
+func TestGenerateDockerfileQuotesEnvironment(t *testing.T) {
+	spec := &parser.AgentSpec{
+		Spec: parser.AgentSpecDetails{
+			Runtime: "python",
+			Environment: []parser.EnvironmentVar{
+				{Name: "GREETING", Value: "hello \"big\" world $HOME \\ done"},
+				{Name: "MULTILINE", Value: "first\nRUN touch /injected"},
+			},
+		},
+	}
+
+	b := &Builder{parser: parser.New()}
+	dockerfile, err := b.generateDockerfile(spec, t.TempDir(), "", false)
+	if err != nil {
+		t.Fatalf("generateDockerfile() error = %v", err)
+	}
+
+	want := `ENV GREETING="hello \"big\" world \$HOME \\ done"`
+	if !strings.Contains(dockerfile, want+"\n") {
+		t.Errorf("Dockerfile does not contain %s:\n%s", want, dockerfile)
+	}
+	want = `ENV MULTILINE="first RUN touch /injected"`
+	if !strings.Contains(dockerfile, want+"\n") {
+		t.Errorf("Dockerfile does not contain %s:\n%s", want, dockerfile)
+	}
+	for _, line := range strings.Split(dockerfile, "\n") {
+		if strings.HasPrefix(line, "RUN touch") {
+			t.Errorf("environment value injected an instruction:\n%s", dockerfile)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package builder
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/pxkundu/agent-as-code/internal/log"
+)
+
+// ParsePlatforms splits a comma-separated --platform value such as
+// linux/amd64,linux/arm64
+func ParsePlatforms(value string) []string {
+	var platforms []string
+	for _, platform := range strings.Split(value, ",") {
+		if platform = strings.TrimSpace(platform); platform != "" {
+			platforms = append(platforms, platform)
+		}
+	}
+	return platforms
+}
+
+// BuildMultiArch builds the agent for several platforms with docker buildx
+// and pushes the resulting manifest list to options.Tag. A multi-platform
+// image cannot be loaded into the local image store, so it is always
+// pushed; buildx authenticates with the credentials from docker login.
+// buildx always uses BuildKit, so spec.secrets needs no --buildkit here.
+//
+// With a single platform the image is built locally through the Docker API
+// like Build does.
+func (b *Builder) BuildMultiArch(options *BuildOptions, platforms []string) error {
+	if len(platforms) <= 1 {
+		single := *options
+		if len(platforms) == 1 {
+			single.Platform = platforms[0]
+		}
+		_, err := b.Build(&single)
+		return err
+	}
+
+	if options.Tag == "" {
+		return fmt.Errorf("multi-platform builds are pushed and need a tag (-t)")
+	}
+
+	dockerPath, err := exec.LookPath("docker")
+	if err != nil {
+		return fmt.Errorf("docker CLI is required for multi-platform builds: %w", err)
+	}
+
+	// buildx always builds with BuildKit, so secret mounts work without
+	// --buildkit
+	buildxOptions := *options
+	buildxOptions.UsesBuildKit = true
+	options = &buildxOptions
+
+	spec, dockerfilePath, err := b.prepare(options)
+	if err != nil {
+		return err
+	}
+
+	args := []string{"buildx", "build",
+		"--platform", strings.Join(platforms, ","),
+		"--push",
+		"-t", options.Tag,
+		"-f", dockerfilePath,
+	}
+	if options.NoCache {
+		args = append(args, "--no-cache")
+	}
+	args = append(args, secretArgs(options.Path, spec.Spec.Secrets)...)
+	args = append(args, options.Path)
+
+	log.Info("building multi-platform image with docker buildx", "platforms", strings.Join(platforms, ","), "tag", options.Tag)
+	cmd := exec.Command(dockerPath, args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("docker buildx build failed: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,211 @@
+package builder
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pxkundu/agent-as-code/internal/parser"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// ollamaImage runs the Ollama server next to agents using local models
+	ollamaImage = "ollama/ollama:latest"
+	// ollamaServiceURL is where the agent reaches the ollama service
+	ollamaServiceURL = "http://ollama:11434"
+)
+
+// composeFile is the subset of a Docker Compose v3 file that agent.yaml maps
+// to
+type composeFile struct {
+	Version  string                    `yaml:"version"`
+	Services map[string]composeService `yaml:"services"`
+	Volumes  map[string]struct{}       `yaml:"volumes,omitempty"`
+}
+
+type composeService struct {
+	Image       string              `yaml:"image"`
+	Ports       []composePort       `yaml:"ports,omitempty"`
+	Environment []string            `yaml:"environment,omitempty"`
+	Volumes     []string            `yaml:"volumes,omitempty"`
+	Tmpfs       []string            `yaml:"tmpfs,omitempty"`
+	DependsOn   []string            `yaml:"depends_on,omitempty"`
+	Healthcheck *composeHealthcheck `yaml:"healthcheck,omitempty"`
+	Restart     string              `yaml:"restart,omitempty"`
+}
+
+// composePort is a HOST:CONTAINER port mapping. Compose recommends quoting
+// them, as YAML 1.1 reads values such as 22:22 as base 60 numbers.
+type composePort string
+
+// MarshalYAML writes the mapping as a double-quoted string
+func (p composePort) MarshalYAML() (interface{}, error) {
+	return &yaml.Node{Kind: yaml.ScalarNode, Style: yaml.DoubleQuotedStyle, Value: string(p)}, nil
+}
+
+type composeHealthcheck struct {
+	Test        []string `yaml:"test,flow"`
+	Interval    string   `yaml:"interval,omitempty"`
+	Timeout     string   `yaml:"timeout,omitempty"`
+	Retries     int      `yaml:"retries,omitempty"`
+	StartPeriod string   `yaml:"start_period,omitempty"`
+}
+
+// GenerateComposeFile writes a Docker Compose file to outputPath that runs
+// imageName with the ports, environment, volumes and health check of spec,
+// so 'docker compose up' replaces a long 'agent run' command line. Agents
+// using Ollama get an ollama service, and environment values pointing at
+// an Ollama on localhost are redirected to it.
+func (b *Builder) GenerateComposeFile(spec *parser.AgentSpec, imageName, outputPath string) error {
+	data, err := generateCompose(spec, imageName)
+	if err != nil {
+		return err
+	}
+
+	header := fmt.Sprintf("# Generated by agent build from agent.yaml of %s\n", spec.Metadata.Name)
+	header += fmt.Sprintf("# Start with: docker compose -f %s up\n", outputPath)
+	if usesOllama(spec) {
+		header += fmt.Sprintf("# Pull the model once the stack is up: docker compose -f %s exec ollama ollama pull %s\n", outputPath, spec.Spec.Model.Name)
+	}
+	if err := os.WriteFile(outputPath, append([]byte(header), data...), 0644); err != nil {
+		return fmt.Errorf("failed to write compose file: %w", err)
+	}
+	return nil
+}
+
+// generateCompose returns the compose file for the agent running image
+func generateCompose(spec *parser.AgentSpec, image string) ([]byte, error) {
+	ollama := usesOllama(spec)
+	file := composeFile{
+		Version:  "3.8",
+		Services: map[string]composeService{},
+	}
+
+	service := composeService{Image: image, Restart: "unless-stopped"}
+	for _, port := range spec.Spec.Ports {
+		host := port.Host
+		if host == 0 {
+			host = port.Container
+		}
+		mapping := fmt.Sprintf("%d:%d", host, port.Container)
+		if port.Protocol != "" && !strings.EqualFold(port.Protocol, "tcp") {
+			mapping += "/" + strings.ToLower(port.Protocol)
+		}
+		service.Ports = append(service.Ports, composePort(mapping))
+	}
+
+	hasOllamaURL := false
+	for _, env := range spec.Spec.Environment {
+		if env.Name == "OLLAMA_BASE_URL" || env.Name == "OLLAMA_HOST" {
+			hasOllamaURL = true
+		}
+		if env.From != "" {
+			// Secrets and other external values are passed through from
+			// the shell running docker compose
+			service.Environment = append(service.Environment, env.Name)
+			continue
+		}
+		value := env.Value
+		if ollama {
+			value = redirectOllamaURL(value)
+		}
+		service.Environment = append(service.Environment, env.Name+"="+value)
+	}
+	if ollama && !hasOllamaURL {
+		service.Environment = append(service.Environment, "OLLAMA_BASE_URL="+ollamaServiceURL)
+	}
+
+	for _, volume := range spec.Spec.Volumes {
+		switch volume.Type {
+		case "tmpfs":
+			service.Tmpfs = append(service.Tmpfs, volume.Target)
+		case "volume":
+			service.Volumes = append(service.Volumes, volume.Source+":"+volume.Target)
+			addComposeVolume(&file, volume.Source)
+		default:
+			service.Volumes = append(service.Volumes, volume.Source+":"+volume.Target)
+		}
+	}
+
+	if check := spec.Spec.LivenessCheck(); check != nil {
+		test := check.Command
+		if len(test) > 0 && test[0] != "CMD" && test[0] != "CMD-SHELL" && test[0] != "NONE" {
+			test = append([]string{"CMD"}, test...)
+		}
+		service.Healthcheck = &composeHealthcheck{
+			Test:        test,
+			Interval:    check.Interval,
+			Timeout:     check.Timeout,
+			Retries:     check.Retries,
+			StartPeriod: check.StartPeriod,
+		}
+	}
+
+	if ollama {
+		service.DependsOn = []string{"ollama"}
+		file.Services["ollama"] = composeService{
+			Image:   ollamaImage,
+			Ports:   []composePort{"11434:11434"},
+			Volumes: []string{"ollama:/root/.ollama"},
+			Restart: "unless-stopped",
+		}
+		addComposeVolume(&file, "ollama")
+	}
+	name := composeServiceName(spec.Metadata.Name)
+	if ollama && name == "ollama" {
+		name = "agent"
+	}
+	file.Services[name] = service
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(file); err != nil {
+		return nil, fmt.Errorf("failed to marshal compose file: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// usesOllama reports whether the agent's model is served by Ollama
+func usesOllama(spec *parser.AgentSpec) bool {
+	provider := strings.ToLower(spec.Spec.Model.Provider)
+	return provider == "ollama" || provider == "local"
+}
+
+// redirectOllamaURL points a URL of an Ollama on the host at the ollama
+// service, since localhost inside the agent container is the container
+func redirectOllamaURL(value string) string {
+	for _, host := range []string{"localhost:11434", "127.0.0.1:11434", "0.0.0.0:11434"} {
+		value = strings.ReplaceAll(value, host, "ollama:11434")
+	}
+	return value
+}
+
+// addComposeVolume declares a named volume
+func addComposeVolume(file *composeFile, name string) {
+	if file.Volumes == nil {
+		file.Volumes = map[string]struct{}{}
+	}
+	file.Volumes[name] = struct{}{}
+}
+
+// composeServiceName turns an agent name into a valid service name, which
+// Compose limits to lowercase letters, digits, dashes and underscores
+func composeServiceName(name string) string {
+	service := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + ('a' - 'A')
+		default:
+			return '-'
+		}
+	}, name)
+	if service == "" {
+		return "agent"
+	}
+	return service
+}
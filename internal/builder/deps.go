@@ -0,0 +1,123 @@
+package builder
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pxkundu/agent-as-code/internal/log"
+)
+
+// pythonVersion is the Python version of the python runtime's base image
+const pythonVersion = "3.11"
+
+// uvImage is the image uv is copied from for projects using pyproject.toml
+const uvImage = "ghcr.io/astral-sh/uv:0.5"
+
+// usesPyproject reports whether the Python project in contextPath lists
+// its dependencies in pyproject.toml rather than requirements.txt, as
+// 'agent init --modern-python' projects do
+func usesPyproject(contextPath string) bool {
+	return fileExists(filepath.Join(contextPath, "pyproject.toml")) &&
+		!fileExists(filepath.Join(contextPath, "requirements.txt"))
+}
+
+// uvInstall returns the instructions installing the dependencies in
+// pyproject.toml with uv. The agent runs from /app and is not itself
+// installed, so only its dependencies are.
+func uvInstall(run string) string {
+	return "COPY --from=" + uvImage + " /uv /bin/uv\n" +
+		"COPY pyproject.toml .\n" +
+		run + "uv pip install --system --no-cache -r pyproject.toml\n\n"
+}
+
+// checkDependencies resolves the project's dependencies the way the image
+// build will and returns a warning for each conflict found. Only Python
+// projects are checked; a failed check is logged and yields no warnings.
+func (b *Builder) checkDependencies(projectDir, runtime string) []string {
+	if runtime != "python" {
+		return nil
+	}
+
+	if _, err := os.Stat(filepath.Join(projectDir, "requirements.txt")); err != nil {
+		return nil
+	}
+
+	pipArgs := []string{"install", "--dry-run", "--ignore-installed", "--report", "/dev/stdout", "-r", "requirements.txt"}
+
+	var cmd *exec.Cmd
+	if python := localPython(); python != "" {
+		cmd = exec.Command(python, append([]string{"-m", "pip"}, pipArgs...)...)
+		cmd.Dir = projectDir
+	} else {
+		// Resolve inside the base image when the host has no matching Python
+		args := []string{"run", "--rm", "-v", projectDir + ":/app:ro", "-w", "/app", "python:" + pythonVersion + "-slim", "pip"}
+		cmd = exec.Command("docker", append(args, pipArgs...)...)
+	}
+
+	log.Debug("checking python dependencies", "command", strings.Join(cmd.Args, " "))
+	output, err := cmd.CombinedOutput()
+
+	warnings := parsePipConflicts(output)
+	if err != nil && len(warnings) == 0 {
+		log.Warn("dependency check failed", "error", err, "output", lastLine(output))
+	}
+
+	return warnings
+}
+
+// localPython returns a host interpreter matching pythonVersion, or "" if
+// there is none
+func localPython() string {
+	if path, err := exec.LookPath("python" + pythonVersion); err == nil {
+		return path
+	}
+
+	path, err := exec.LookPath("python3")
+	if err != nil {
+		return ""
+	}
+	out, err := exec.Command(path, "-c", "import sys; print('%d.%d' % sys.version_info[:2])").Output()
+	if err != nil || strings.TrimSpace(string(out)) != pythonVersion {
+		return ""
+	}
+	return path
+}
+
+// parsePipConflicts extracts the resolver conflicts and unsatisfiable
+// requirements from pip's output
+func parsePipConflicts(output []byte) []string {
+	var warnings []string
+	inCause := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case strings.HasPrefix(trimmed, "The conflict is caused by:"):
+			inCause = true
+		case inCause && trimmed != "" && line != trimmed:
+			warnings = append(warnings, trimmed)
+		case strings.Contains(trimmed, "conflicting dependencies"),
+			strings.Contains(trimmed, "Could not find a version that satisfies"),
+			strings.HasPrefix(trimmed, "ERROR: Double requirement given"):
+			inCause = false
+			warnings = append(warnings, strings.TrimPrefix(trimmed, "ERROR: "))
+		default:
+			inCause = false
+		}
+	}
+
+	return warnings
+}
+
+// lastLine returns the last non-empty line of output
+func lastLine(output []byte) string {
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
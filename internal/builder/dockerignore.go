@@ -0,0 +1,199 @@
+package builder
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pxkundu/agent-as-code/internal/log"
+)
+
+// defaultDockerignore is written to build contexts that have no ignore file
+// of their own
+const defaultDockerignore = `# Generated by agent build. Edit this file to change what is sent to the
+# Docker daemon as the build context.
+.git/
+.env
+**/__pycache__/
+**/*.pyc
+**/*.egg-info/
+**/node_modules/
+*.test
+coverage/
+`
+
+// generateDefaultDockerignore writes defaultDockerignore to buildPath if it
+// has neither a .dockerignore nor an .agentignore
+func (b *Builder) generateDefaultDockerignore(buildPath string) error {
+	for _, name := range []string{".dockerignore", ".agentignore"} {
+		if _, err := os.Stat(filepath.Join(buildPath, name)); err == nil {
+			return nil
+		} else if !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	dockerignorePath := filepath.Join(buildPath, ".dockerignore")
+	if err := os.WriteFile(dockerignorePath, []byte(defaultDockerignore), 0644); err != nil {
+		return err
+	}
+
+	log.Info("generated .dockerignore to keep caches, git history and test output out of the build context", "path", dockerignorePath)
+	return nil
+}
+
+// ignorePattern is one line of a .dockerignore
+type ignorePattern struct {
+	re        *regexp.Regexp
+	exclusion bool
+}
+
+// ignoreMatcher decides which files of a build context are left out, with
+// the semantics of .dockerignore: patterns are relative to the context root,
+// ** matches any number of directories, a leading ! re-includes what earlier
+// patterns excluded, and the last matching pattern wins.
+type ignoreMatcher struct {
+	patterns []ignorePattern
+}
+
+// loadIgnoreFile reads the .dockerignore of buildPath, or its .agentignore
+// if there is no .dockerignore. It returns a nil matcher when there is
+// neither.
+func loadIgnoreFile(buildPath string) (*ignoreMatcher, error) {
+	for _, name := range []string{".dockerignore", ".agentignore"} {
+		file, err := os.Open(filepath.Join(buildPath, name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		matcher := &ignoreMatcher{}
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+
+			exclusion := strings.HasPrefix(line, "!")
+			if exclusion {
+				line = strings.TrimSpace(line[1:])
+			}
+			line = path.Clean(strings.TrimPrefix(filepath.ToSlash(line), "/"))
+			if line == "." {
+				continue
+			}
+
+			re, err := ignorePatternRegexp(line)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern '%s' in %s: %w", line, name, err)
+			}
+			matcher.patterns = append(matcher.patterns, ignorePattern{re: re, exclusion: exclusion})
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		return matcher, nil
+	}
+	return nil, nil
+}
+
+// ignorePatternRegexp translates a .dockerignore pattern into a regular
+// expression matching slash-separated paths
+func ignorePatternRegexp(pattern string) (*regexp.Regexp, error) {
+	var expr strings.Builder
+	expr.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				i++
+				if i+1 < len(pattern) && pattern[i+1] == '/' {
+					i++
+					expr.WriteString("(.*/)?")
+				} else {
+					expr.WriteString(".*")
+				}
+			} else {
+				expr.WriteString("[^/]*")
+			}
+		case '?':
+			expr.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(pattern[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated character class")
+			}
+			class := pattern[i+1 : i+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			expr.WriteString("[" + class + "]")
+			i += end
+		case '\\':
+			if i+1 < len(pattern) {
+				i++
+			}
+			expr.WriteString(regexp.QuoteMeta(string(pattern[i])))
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	expr.WriteString("$")
+	return regexp.Compile(expr.String())
+}
+
+// excluded reports whether relPath, relative to the context root, is left
+// out of the build context. A path is excluded when it or one of its parent
+// directories matches.
+func (m *ignoreMatcher) excluded(relPath string) bool {
+	if m == nil {
+		return false
+	}
+
+	relPath = filepath.ToSlash(relPath)
+	excluded := false
+	for _, pattern := range m.patterns {
+		// Only patterns that would change the outcome need matching
+		if excluded != pattern.exclusion {
+			continue
+		}
+		if matchesOrParentMatches(pattern.re, relPath) {
+			excluded = !pattern.exclusion
+		}
+	}
+	return excluded
+}
+
+// hasExclusions reports whether any pattern re-includes files, in which case
+// excluded directories still have to be walked
+func (m *ignoreMatcher) hasExclusions() bool {
+	if m == nil {
+		return false
+	}
+	for _, pattern := range m.patterns {
+		if pattern.exclusion {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesOrParentMatches(re *regexp.Regexp, relPath string) bool {
+	if re.MatchString(relPath) {
+		return true
+	}
+	for dir := path.Dir(relPath); dir != "."; dir = path.Dir(dir) {
+		if re.MatchString(dir) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,225 @@
+package builder
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"os"
+	"time"
+
+	"github.com/pxkundu/agent-as-code/internal/parser"
+	"gopkg.in/yaml.v3"
+)
+
+// k8sDeployment is the subset of a Kubernetes apps/v1 Deployment that
+// agent.yaml maps to
+type k8sDeployment struct {
+	APIVersion string            `yaml:"apiVersion"`
+	Kind       string            `yaml:"kind"`
+	Metadata   k8sMetadata       `yaml:"metadata"`
+	Spec       k8sDeploymentSpec `yaml:"spec"`
+}
+
+type k8sMetadata struct {
+	Name   string            `yaml:"name,omitempty"`
+	Labels map[string]string `yaml:"labels,omitempty"`
+}
+
+type k8sDeploymentSpec struct {
+	Replicas int `yaml:"replicas"`
+	Selector struct {
+		MatchLabels map[string]string `yaml:"matchLabels"`
+	} `yaml:"selector"`
+	Template struct {
+		Metadata k8sMetadata `yaml:"metadata"`
+		Spec     struct {
+			Containers []k8sContainer `yaml:"containers"`
+		} `yaml:"spec"`
+	} `yaml:"template"`
+}
+
+type k8sContainer struct {
+	Name           string        `yaml:"name"`
+	Image          string        `yaml:"image"`
+	Ports          []k8sPort     `yaml:"ports,omitempty"`
+	Env            []k8sEnvVar   `yaml:"env,omitempty"`
+	Resources      *k8sResources `yaml:"resources,omitempty"`
+	LivenessProbe  *k8sProbe     `yaml:"livenessProbe,omitempty"`
+	ReadinessProbe *k8sProbe     `yaml:"readinessProbe,omitempty"`
+}
+
+type k8sPort struct {
+	ContainerPort int    `yaml:"containerPort"`
+	Protocol      string `yaml:"protocol,omitempty"`
+}
+
+type k8sEnvVar struct {
+	Name      string        `yaml:"name"`
+	Value     string        `yaml:"value,omitempty"`
+	ValueFrom *k8sEnvSource `yaml:"valueFrom,omitempty"`
+}
+
+type k8sEnvSource struct {
+	SecretKeyRef k8sSecretKeyRef `yaml:"secretKeyRef"`
+}
+
+type k8sSecretKeyRef struct {
+	Name string `yaml:"name"`
+	Key  string `yaml:"key"`
+}
+
+type k8sResources struct {
+	Limits   map[string]string `yaml:"limits,omitempty"`
+	Requests map[string]string `yaml:"requests,omitempty"`
+}
+
+type k8sProbe struct {
+	Exec struct {
+		Command []string `yaml:"command"`
+	} `yaml:"exec"`
+	InitialDelaySeconds int `yaml:"initialDelaySeconds,omitempty"`
+	PeriodSeconds       int `yaml:"periodSeconds,omitempty"`
+	TimeoutSeconds      int `yaml:"timeoutSeconds,omitempty"`
+	FailureThreshold    int `yaml:"failureThreshold,omitempty"`
+}
+
+// GenerateKubernetesManifest returns a Deployment for the agent running
+// image. spec.probes.liveness (or spec.healthCheck) becomes the
+// livenessProbe and spec.probes.readiness the readinessProbe. Env vars with
+// from: secret are read from the Secret <name>-secrets.
+func GenerateKubernetesManifest(spec *parser.AgentSpec, image string) ([]byte, error) {
+	name := spec.Metadata.Name
+	labels := map[string]string{"app": name}
+
+	container := k8sContainer{Name: name, Image: image}
+	for _, port := range spec.Spec.Ports {
+		container.Ports = append(container.Ports, k8sPort{ContainerPort: port.Container, Protocol: k8sProtocol(port.Protocol)})
+	}
+	for _, env := range spec.Spec.Environment {
+		envVar := k8sEnvVar{Name: env.Name, Value: env.Value}
+		if env.From == "secret" {
+			envVar.Value = ""
+			envVar.ValueFrom = &k8sEnvSource{
+				SecretKeyRef: k8sSecretKeyRef{Name: name + "-secrets", Key: env.Name},
+			}
+		}
+		container.Env = append(container.Env, envVar)
+	}
+	if resources := spec.Spec.Resources; resources != nil {
+		container.Resources = &k8sResources{
+			Limits:   k8sResourceList(resources.Limits),
+			Requests: k8sResourceList(resources.Requests),
+		}
+		if container.Resources.Limits == nil && container.Resources.Requests == nil {
+			container.Resources = nil
+		}
+	}
+
+	var err error
+	if check := spec.Spec.LivenessCheck(); check != nil {
+		if container.LivenessProbe, err = k8sProbeFrom("liveness", *check); err != nil {
+			return nil, err
+		}
+	}
+	if probes := spec.Spec.Probes; probes != nil && probes.Readiness.IsSet() {
+		if container.ReadinessProbe, err = k8sProbeFrom("readiness", parser.HealthCheckConfig(probes.Readiness)); err != nil {
+			return nil, err
+		}
+	}
+
+	deployment := k8sDeployment{
+		APIVersion: "apps/v1",
+		Kind:       "Deployment",
+		Metadata:   k8sMetadata{Name: name, Labels: labels},
+	}
+	deployment.Spec.Replicas = 1
+	if scaling := spec.Spec.Scaling; scaling != nil && scaling.MinReplicas > 0 {
+		deployment.Spec.Replicas = scaling.MinReplicas
+	}
+	deployment.Spec.Selector.MatchLabels = labels
+	deployment.Spec.Template.Metadata = k8sMetadata{Labels: labels}
+	deployment.Spec.Template.Spec.Containers = []k8sContainer{container}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(deployment); err != nil {
+		return nil, fmt.Errorf("failed to marshal Kubernetes manifest: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteKubernetesManifest writes the Deployment for the agent to path
+func WriteKubernetesManifest(spec *parser.AgentSpec, image, path string) error {
+	data, err := GenerateKubernetesManifest(spec, image)
+	if err != nil {
+		return err
+	}
+	header := fmt.Sprintf("# Generated by agent build from agent.yaml of %s\n", spec.Metadata.Name)
+	if err := os.WriteFile(path, append([]byte(header), data...), 0644); err != nil {
+		return fmt.Errorf("failed to write Kubernetes manifest: %w", err)
+	}
+	return nil
+}
+
+// k8sProbeFrom converts a Docker-style health check to an exec probe
+func k8sProbeFrom(name string, check parser.HealthCheckConfig) (*k8sProbe, error) {
+	probe := &k8sProbe{FailureThreshold: check.Retries}
+
+	command := check.Command
+	switch {
+	case len(command) > 1 && command[0] == "CMD-SHELL":
+		command = []string{"sh", "-c", command[1]}
+	case len(command) > 1 && command[0] == "CMD":
+		command = command[1:]
+	}
+	probe.Exec.Command = command
+
+	for _, field := range []struct {
+		value  string
+		target *int
+	}{
+		{check.StartPeriod, &probe.InitialDelaySeconds},
+		{check.Interval, &probe.PeriodSeconds},
+		{check.Timeout, &probe.TimeoutSeconds},
+	} {
+		if field.value == "" {
+			continue
+		}
+		d, err := time.ParseDuration(field.value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration '%s' in %s probe: %w", field.value, name, err)
+		}
+		*field.target = int(math.Ceil(d.Seconds()))
+	}
+
+	return probe, nil
+}
+
+// k8sResourceList converts resource limits to a Kubernetes resource list
+func k8sResourceList(limits parser.ResourceLimits) map[string]string {
+	list := map[string]string{}
+	if limits.CPU != "" {
+		list["cpu"] = limits.CPU
+	}
+	if limits.Memory != "" {
+		list["memory"] = limits.Memory
+	}
+	if len(list) == 0 {
+		return nil
+	}
+	return list
+}
+
+// k8sProtocol maps agent.yaml protocols to Kubernetes ones, which are
+// upper case
+func k8sProtocol(protocol string) string {
+	switch protocol {
+	case "udp", "UDP":
+		return "UDP"
+	case "sctp", "SCTP":
+		return "SCTP"
+	default:
+		return ""
+	}
+}
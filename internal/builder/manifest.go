@@ -0,0 +1,60 @@
+package builder
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// BuildManifest is the machine-readable summary of a build for CI/CD
+// pipelines
+type BuildManifest struct {
+	ImageID         string    `json:"image_id"`
+	Tags            []string  `json:"tags"`
+	SizeBytes       int64     `json:"size_bytes"`
+	Digest          string    `json:"digest"`
+	BuildAt         time.Time `json:"build_at"`
+	BuildDurationMs int64     `json:"build_duration_ms"`
+	AgentName       string    `json:"agent_name"`
+	AgentVersion    string    `json:"agent_version"`
+	Runtime         string    `json:"runtime"`
+	ModelProvider   string    `json:"model_provider"`
+	ModelName       string    `json:"model_name"`
+}
+
+// NewBuildManifest creates a build manifest from a build result
+func NewBuildManifest(result *BuildResult) *BuildManifest {
+	manifest := &BuildManifest{
+		ImageID:         result.ImageID,
+		Tags:            result.Tags,
+		SizeBytes:       result.SizeBytes,
+		Digest:          result.Digest,
+		BuildAt:         result.BuiltAt,
+		BuildDurationMs: result.BuildDuration.Milliseconds(),
+	}
+
+	if spec := result.Spec; spec != nil {
+		manifest.AgentName = spec.Metadata.Name
+		manifest.AgentVersion = spec.Metadata.Version
+		manifest.Runtime = spec.Spec.Runtime
+		manifest.ModelProvider = spec.Spec.Model.Provider
+		manifest.ModelName = spec.Spec.Model.Name
+	}
+
+	return manifest
+}
+
+// WriteBuildManifest writes the build manifest for result to path
+func WriteBuildManifest(result *BuildResult, path string) error {
+	data, err := json.MarshalIndent(NewBuildManifest(result), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal build manifest: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write build manifest: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package builder
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// nodeImage is the base image of both stages of the nodejs runtime's build
+const nodeImage = "node:20-alpine"
+
+// packageJSON holds the fields of package.json the nodejs build uses
+type packageJSON struct {
+	Main    string            `json:"main"`
+	Scripts map[string]string `json:"scripts"`
+}
+
+// readPackageJSON reads package.json from the build context, returning nil
+// if the project has none
+func readPackageJSON(contextPath string) (*packageJSON, error) {
+	data, err := os.ReadFile(filepath.Join(contextPath, "package.json"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read package.json: %w", err)
+	}
+
+	var pkg packageJSON
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return nil, fmt.Errorf("invalid package.json: %w", err)
+	}
+	return &pkg, nil
+}
+
+// hasBuild reports whether the project defines an npm build script
+func (p *packageJSON) hasBuild() bool {
+	return p != nil && p.Scripts["build"] != ""
+}
+
+// runsBuildOutput reports whether the final image only needs the build
+// output: the project has a build script and its main script, if set, is
+// under dist/
+func (p *packageJSON) runsBuildOutput() bool {
+	if !p.hasBuild() {
+		return false
+	}
+	if p.Main == "" {
+		return true
+	}
+	main := path.Clean(strings.TrimPrefix(p.Main, "./"))
+	return strings.HasPrefix(main, "dist/")
+}
+
+// entrypoint returns the script node runs in the final image
+func (p *packageJSON) entrypoint() string {
+	switch {
+	case p != nil && p.Main != "":
+		return p.Main
+	case p.hasBuild():
+		return "dist/index.js"
+	default:
+		return "index.js"
+	}
+}
+
+// nodeBuilderStage returns the first stage of the nodejs build: it installs
+// all dependencies, runs the build script if there is one, then drops the
+// dev dependencies so only production node_modules reach the final image.
+// run is the RUN prefix for commands that need build secrets.
+func nodeBuilderStage(pkg *packageJSON, contextPath, run string) string {
+	install := "npm ci"
+	if !fileExists(filepath.Join(contextPath, "package-lock.json")) && !fileExists(filepath.Join(contextPath, "npm-shrinkwrap.json")) {
+		// npm ci needs a lockfile
+		install = "npm install"
+	}
+
+	stage := "FROM " + nodeImage + " AS builder\n"
+	stage += "WORKDIR /app\n"
+	stage += "COPY package*.json ./\n"
+	stage += run + install + "\n"
+	stage += "COPY . .\n"
+	if pkg.hasBuild() {
+		stage += "RUN npm run build\n"
+	}
+	stage += "RUN npm prune --omit=dev\n\n"
+	return stage
+}
+
+// nodeCopyFromBuilder returns the instructions that copy the application
+// from the builder stage into the final image: the build output when the
+// project has a build script and runs from dist/, otherwise the whole app,
+// so that a main script outside dist/ is still there
+func nodeCopyFromBuilder(pkg *packageJSON) string {
+	copy := "# Copy the application from the build stage\n"
+	if !pkg.runsBuildOutput() {
+		return copy + "COPY --from=builder /app ./\n\n"
+	}
+
+	copy += "COPY --from=builder /app/package*.json ./\n"
+	copy += "COPY --from=builder /app/node_modules ./node_modules\n"
+	copy += "COPY --from=builder /app/dist ./dist\n\n"
+	return copy
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
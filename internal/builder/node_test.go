@@ -0,0 +1,37 @@
+package builder
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNodeCopyFromBuilder(t *testing.T) {
+	build := map[string]string{"build": "tsc"}
+	tests := []struct {
+		name       string
+		pkg        *packageJSON
+		entrypoint string
+		distOnly   bool
+	}{
+		{name: "no build script", pkg: &packageJSON{Main: "server.js"}, entrypoint: "server.js"},
+		{name: "build without main", pkg: &packageJSON{Scripts: build}, entrypoint: "dist/index.js", distOnly: true},
+		{name: "main under dist", pkg: &packageJSON{Main: "./dist/server.js", Scripts: build}, entrypoint: "./dist/server.js", distOnly: true},
+		{name: "main outside dist", pkg: &packageJSON{Main: "server.js", Scripts: build}, entrypoint: "server.js"},
+		{name: "main escaping dist", pkg: &packageJSON{Main: "dist/../server.js", Scripts: build}, entrypoint: "dist/../server.js"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.pkg.entrypoint(); got != tt.entrypoint {
+				t.Errorf("entrypoint() = %q, want %q", got, tt.entrypoint)
+			}
+			copy := nodeCopyFromBuilder(tt.pkg)
+			if distOnly := strings.Contains(copy, "/app/dist ./dist"); distOnly != tt.distOnly {
+				t.Errorf("nodeCopyFromBuilder() copies only dist/ = %v, want %v:\n%s", distOnly, tt.distOnly, copy)
+			}
+			if !tt.distOnly && !strings.Contains(copy, "COPY --from=builder /app ./") {
+				t.Errorf("nodeCopyFromBuilder() does not copy the whole app:\n%s", copy)
+			}
+		})
+	}
+}
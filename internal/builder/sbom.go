@@ -0,0 +1,311 @@
+package builder
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pxkundu/agent-as-code/internal/log"
+)
+
+// sbomManifestFiles are the dependency manifests read from the image when
+// syft is not available
+var sbomManifestFiles = []string{"requirements.txt", "package.json", "go.sum"}
+
+// cycloneDXBOM is a minimal CycloneDX 1.5 document
+type cycloneDXBOM struct {
+	BOMFormat    string               `json:"bomFormat"`
+	SpecVersion  string               `json:"specVersion"`
+	SerialNumber string               `json:"serialNumber"`
+	Version      int                  `json:"version"`
+	Metadata     cycloneDXMetadata    `json:"metadata"`
+	Components   []cycloneDXComponent `json:"components"`
+}
+
+type cycloneDXMetadata struct {
+	Timestamp string             `json:"timestamp"`
+	Tools     []cycloneDXTool    `json:"tools"`
+	Component cycloneDXComponent `json:"component"`
+}
+
+type cycloneDXTool struct {
+	Vendor string `json:"vendor"`
+	Name   string `json:"name"`
+}
+
+type cycloneDXComponent struct {
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	PURL    string `json:"purl,omitempty"`
+}
+
+// GenerateSBOM writes a CycloneDX SBOM for the given image to outputPath.
+// syft is used when installed; otherwise a minimal SBOM is generated from
+// the dependency manifests found in the image layers.
+func (b *Builder) GenerateSBOM(imageID string, outputPath string) error {
+	if syftPath, err := exec.LookPath("syft"); err == nil {
+		return b.generateSyftSBOM(syftPath, imageID, outputPath)
+	}
+
+	return b.generateManifestSBOM(imageID, outputPath)
+}
+
+// generateSyftSBOM runs syft against the image
+func (b *Builder) generateSyftSBOM(syftPath, imageID, outputPath string) error {
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create SBOM file: %w", err)
+	}
+	defer file.Close()
+
+	var stderr bytes.Buffer
+	cmd := exec.Command(syftPath, "packages", "docker:"+imageID, "-o", "cyclonedx-json")
+	cmd.Stdout = file
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("syft failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
+
+// generateManifestSBOM builds a CycloneDX SBOM from dependency manifests
+// extracted from the image layers
+func (b *Builder) generateManifestSBOM(imageID, outputPath string) error {
+	manifests, err := b.extractImageFiles(imageID, sbomManifestFiles)
+	if err != nil {
+		return fmt.Errorf("failed to read image layers: %w", err)
+	}
+
+	var components []cycloneDXComponent
+	for name, content := range manifests {
+		switch path.Base(name) {
+		case "requirements.txt":
+			components = append(components, parseRequirements(content)...)
+		case "package.json":
+			components = append(components, parsePackageJSON(content)...)
+		case "go.sum":
+			components = append(components, parseGoSum(content)...)
+		}
+	}
+
+	sort.Slice(components, func(i, j int) bool {
+		return components[i].PURL < components[j].PURL
+	})
+
+	bom := cycloneDXBOM{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: "urn:uuid:" + newUUID(),
+		Version:      1,
+		Metadata: cycloneDXMetadata{
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			Tools:     []cycloneDXTool{{Vendor: "agent-as-code", Name: "agent"}},
+			Component: cycloneDXComponent{Type: "container", Name: imageID},
+		},
+		Components: components,
+	}
+
+	data, err := json.MarshalIndent(bom, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal SBOM: %w", err)
+	}
+
+	return os.WriteFile(outputPath, data, 0644)
+}
+
+// extractImageFiles returns the contents of files in the image's /app
+// directory whose base name is in names. Files in later layers override
+// earlier ones.
+func (b *Builder) extractImageFiles(imageID string, names []string) (map[string][]byte, error) {
+	if b.dockerClient == nil {
+		return nil, fmt.Errorf("Docker client not available")
+	}
+
+	reader, err := b.dockerClient.ImageSave(context.Background(), []string{imageID})
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	wanted := make(map[string]bool)
+	for _, name := range names {
+		wanted["app/"+name] = true
+	}
+
+	files := make(map[string][]byte)
+	outer := tar.NewReader(reader)
+	for {
+		header, err := outer.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		// Layers are themselves tar archives, possibly compressed; other
+		// entries (configs, manifests) fail to parse and are skipped
+		content, ok := layerContent(header.Name, outer)
+		if !ok {
+			continue
+		}
+		layer := tar.NewReader(content)
+		for {
+			entry, err := layer.Next()
+			if err != nil {
+				break
+			}
+			name := strings.TrimPrefix(entry.Name, "./")
+			if !wanted[name] || entry.Typeflag != tar.TypeReg {
+				continue
+			}
+			content, err := io.ReadAll(layer)
+			if err != nil {
+				break
+			}
+			files[name] = content
+		}
+	}
+
+	return files, nil
+}
+
+// layerContent returns the uncompressed content of the image save entry
+// name. It decompresses gzip layers and reports false, with a warning, for
+// zstd layers, which it cannot read; the SBOM then misses their files.
+func layerContent(name string, r io.Reader) (io.Reader, bool) {
+	buffered := bufio.NewReader(r)
+	magic, _ := buffered.Peek(4)
+
+	switch {
+	case bytes.HasPrefix(magic, []byte{0x1f, 0x8b}):
+		gz, err := gzip.NewReader(buffered)
+		if err != nil {
+			log.Warn("skipping unreadable gzip layer in SBOM scan", "layer", name, "error", err)
+			return nil, false
+		}
+		return gz, true
+	case bytes.Equal(magic, []byte{0x28, 0xb5, 0x2f, 0xfd}):
+		log.Warn("skipping zstd-compressed layer in SBOM scan; the SBOM may be incomplete", "layer", name)
+		return nil, false
+	}
+	return buffered, true
+}
+
+// parseRequirements parses a pip requirements file
+func parseRequirements(content []byte) []cycloneDXComponent {
+	var components []cycloneDXComponent
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = strings.TrimSpace(line[:idx])
+		}
+		if line == "" || strings.HasPrefix(line, "-") {
+			continue
+		}
+		if idx := strings.Index(line, ";"); idx >= 0 {
+			line = strings.TrimSpace(line[:idx])
+		}
+
+		name, version := line, ""
+		if idx := strings.IndexAny(line, "=<>!~"); idx >= 0 {
+			name = strings.TrimSpace(line[:idx])
+			version = strings.TrimLeft(line[idx:], "=<>!~ ")
+			if comma := strings.Index(version, ","); comma >= 0 {
+				version = version[:comma]
+			}
+		}
+		if idx := strings.Index(name, "["); idx >= 0 {
+			name = name[:idx]
+		}
+
+		components = append(components, newLibraryComponent("pypi", strings.ToLower(name), version))
+	}
+
+	return components
+}
+
+// parsePackageJSON parses the dependencies of a package.json file
+func parsePackageJSON(content []byte) []cycloneDXComponent {
+	var pkg struct {
+		Dependencies map[string]string `json:"dependencies"`
+	}
+	if err := json.Unmarshal(content, &pkg); err != nil {
+		return nil
+	}
+
+	var components []cycloneDXComponent
+	for name, version := range pkg.Dependencies {
+		version = strings.TrimLeft(version, "^~>=< ")
+		components = append(components, newLibraryComponent("npm", name, version))
+	}
+
+	return components
+}
+
+// parseGoSum parses the module list of a go.sum file
+func parseGoSum(content []byte) []cycloneDXComponent {
+	var components []cycloneDXComponent
+	seen := make(map[string]bool)
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || strings.HasSuffix(fields[1], "/go.mod") {
+			continue
+		}
+
+		key := fields[0] + "@" + fields[1]
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		components = append(components, newLibraryComponent("golang", fields[0], fields[1]))
+	}
+
+	return components
+}
+
+// newLibraryComponent creates a library component with a package URL
+func newLibraryComponent(purlType, name, version string) cycloneDXComponent {
+	purl := fmt.Sprintf("pkg:%s/%s", purlType, name)
+	if version != "" {
+		purl += "@" + version
+	}
+
+	return cycloneDXComponent{
+		Type:    "library",
+		Name:    name,
+		Version: version,
+		PURL:    purl,
+	}
+}
+
+// newUUID returns a random RFC 4122 version 4 UUID
+func newUUID() string {
+	var u [16]byte
+	rand.Read(u[:])
+	u[6] = (u[6] & 0x0f) | 0x40
+	u[8] = (u[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:16])
+}
@@ -0,0 +1,47 @@
+package builder
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"testing"
+)
+
+func TestLayerContent(t *testing.T) {
+	var layer bytes.Buffer
+	tw := tar.NewWriter(&layer)
+	body := []byte("requests==2.31.0\n")
+	if err := tw.WriteHeader(&tar.Header{Name: "app/requirements.txt", Mode: 0644, Size: int64(len(body))}); err != nil {
+		t.Fatal(err)
+	}
+	tw.Write(body)
+	tw.Close()
+
+	var compressed bytes.Buffer
+	gw := gzip.NewWriter(&compressed)
+	gw.Write(layer.Bytes())
+	gw.Close()
+
+	for name, data := range map[string][]byte{"plain": layer.Bytes(), "gzip": compressed.Bytes()} {
+		t.Run(name, func(t *testing.T) {
+			content, ok := layerContent("blobs/sha256/abc", bytes.NewReader(data))
+			if !ok {
+				t.Fatal("layerContent() skipped the layer")
+			}
+			tr := tar.NewReader(content)
+			header, err := tr.Next()
+			if err != nil {
+				t.Fatalf("reading layer: %v", err)
+			}
+			got, _ := io.ReadAll(tr)
+			if header.Name != "app/requirements.txt" || !bytes.Equal(got, body) {
+				t.Errorf("layer entry = %s %q", header.Name, got)
+			}
+		})
+	}
+
+	if _, ok := layerContent("blobs/sha256/def", bytes.NewReader([]byte{0x28, 0xb5, 0x2f, 0xfd, 0})); ok {
+		t.Error("layerContent() accepted a zstd layer")
+	}
+}
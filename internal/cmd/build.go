@@ -3,8 +3,10 @@ package cmd
 import (
 	"fmt"
 	"path/filepath"
+	"strings"
 
 	"github.com/pxkundu/agent-as-code/internal/builder"
+	"github.com/pxkundu/agent-as-code/internal/parser"
 	"github.com/spf13/cobra"
 )
 
@@ -21,16 +23,86 @@ Examples:
   agent build .
   agent build -t my-agent:latest .
   agent build -t my-agent:v1.0.0 ./my-agent-dir
-  agent build --no-cache -t my-agent .`,
-	Args: cobra.ExactArgs(1),
+  agent build --no-cache -t my-agent .
+  agent build --sbom -t my-agent:latest .
+  agent build --buildkit -t my-agent:latest .
+  agent build --manifest-output dist/build-manifest.json -t my-agent .
+  agent build --push --profile prod -t registry.example.com/my-agent:latest .
+  agent build --push --registry registry.example.com:5000 -t my-agent:latest .
+  agent build --platform linux/amd64,linux/arm64 -t registry.example.com/my-agent:latest .
+  agent build --kubernetes -t registry.example.com/my-agent:latest .
+  agent build --generate-compose -t my-agent:latest .
+  agent build --all
+  agent build --strict-deps -t my-agent:latest .
+  agent build --from-base registry.example.com/support-agent:1.2 -t support-agent-plus .
+
+After a successful build, a build-manifest.json with the image ID, digest,
+size and build time is written to the build context directory (or to
+--manifest-output).
+
+The PAT of the registry profile given by --profile (or of the default
+profile) authenticates pushes and base image pulls from that profile's
+registry.
+
+--registry pushes to a private registry such as registry.example.com:5000
+by first tagging the image with the registry, e.g. -t my-agent:latest is
+pushed as registry.example.com:5000/my-agent:latest. --untag removes that
+tag again after the push.
+
+With more than one --platform the image is built with 'docker buildx' and
+pushed as a multi-platform image, since such images cannot be stored
+locally. The push uses the credentials from 'docker login', and no build
+manifest is written. buildx always uses BuildKit, so spec.secrets does not
+need --buildkit there.
+
+--kubernetes also writes agent-k8s.yaml, a Deployment for the image with
+spec.probes.liveness (or spec.healthCheck) as its livenessProbe and
+spec.probes.readiness as its readinessProbe. Docker images carry a single
+HEALTHCHECK, so the readiness probe only takes effect on Kubernetes.
+
+--generate-compose also writes agent-compose.yaml, a Docker Compose file
+running the image with the ports, environment, volumes and health check
+of agent.yaml. Start it with 'docker compose -f agent-compose.yaml up'
+instead of passing them to 'agent run'. Agents with spec.model.provider
+ollama get an ollama service, which Ollama URLs on localhost are pointed
+at. Environment variables with from: set are taken from the shell.
+
+--all builds every agent listed in the agents.yaml of a monorepo (see
+'agent init --monorepo'), found in PATH or the current directory. Each
+image is tagged <name>:latest.
+
+Python dependencies are resolved with 'pip install --dry-run' against
+Python 3.11, the version in the image, before the build starts. Conflicts
+are printed as warnings, or fail the build with --strict-deps.
+
+--from-base extends a published agent image instead of building from the
+runtime's base image. The Dockerfile starts FROM that image and only adds
+this directory's requirements.txt (or package.json), code, environment and
+ports; the base image's command is kept. agent.yaml is merged with the
+base image's /app/agent.yaml: fields set here win, lists such as
+environment and ports are combined by name or port, and metadata.labels
+are merged. The merged file is written to agent.merged.yaml and replaces
+agent.yaml in the new image.`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: runBuild,
 }
 
 var (
-	buildTag      string
-	buildNoCache  bool
-	buildPush     bool
-	buildPlatform string
+	buildTag        string
+	buildNoCache    bool
+	buildPush       bool
+	buildPlatform   string
+	buildSBOM       bool
+	buildKit        bool
+	buildManifest   string
+	buildProfile    string
+	buildK8s        bool
+	buildCompose    bool
+	buildAll        bool
+	buildStrictDeps bool
+	buildFromBase   string
+	buildRegistry   string
+	buildUntag      bool
 )
 
 func init() {
@@ -39,12 +111,77 @@ func init() {
 	buildCmd.Flags().StringVarP(&buildTag, "tag", "t", "", "name and optionally a tag in the 'name:tag' format")
 	buildCmd.Flags().BoolVar(&buildNoCache, "no-cache", false, "do not use cache when building the image")
 	buildCmd.Flags().BoolVar(&buildPush, "push", false, "push the image to registry after building")
-	buildCmd.Flags().StringVar(&buildPlatform, "platform", "", "set platform if server is multi-platform capable")
+	buildCmd.Flags().StringVar(&buildPlatform, "platform", "", "target platform, or a comma-separated list for a multi-platform build (e.g. linux/amd64,linux/arm64)")
+	buildCmd.Flags().BoolVar(&buildKit, "buildkit", false, "build with BuildKit (required for spec.secrets)")
+	buildCmd.Flags().StringVar(&buildManifest, "manifest-output", "", "path of the build manifest (default: <PATH>/build-manifest.json)")
+	buildCmd.Flags().StringVar(&buildProfile, "profile", "", "registry profile to authenticate with (default: the default profile)")
+	buildCmd.Flags().BoolVar(&buildK8s, "kubernetes", false, "also write a Kubernetes Deployment with the agent's probes to <PATH>/agent-k8s.yaml")
+	buildCmd.Flags().BoolVar(&buildCompose, "generate-compose", false, "also write a Docker Compose file for the image to <PATH>/agent-compose.yaml")
+	buildCmd.Flags().BoolVar(&buildAll, "all", false, "build every agent listed in agents.yaml")
+	buildCmd.Flags().BoolVar(&buildStrictDeps, "strict-deps", false, "fail the build on Python dependency conflicts instead of warning")
+	buildCmd.Flags().BoolVar(&buildSBOM, "sbom", false, "generate a CycloneDX SBOM next to the generated Dockerfile")
+	buildCmd.Flags().StringVar(&buildFromBase, "from-base", "", "extend an existing agent image instead of building from the runtime's base image")
+	buildCmd.Flags().StringVar(&buildRegistry, "registry", "", "registry to push to with --push, e.g. registry.example.com:5000")
+	buildCmd.Flags().BoolVar(&buildUntag, "untag", false, "remove the registry-qualified tag after pushing with --registry")
 }
 
 func runBuild(cmd *cobra.Command, args []string) error {
-	buildPath := args[0]
+	if buildRegistry != "" && !buildPush {
+		return fmt.Errorf("--registry requires --push")
+	}
+	if buildUntag && buildRegistry == "" {
+		return fmt.Errorf("--untag requires --registry")
+	}
+
+	if buildAll {
+		root := "."
+		if len(args) == 1 {
+			root = args[0]
+		}
+		return buildAllAgents(root)
+	}
+
+	if len(args) != 1 {
+		return fmt.Errorf("requires a PATH argument, or --all to build every agent in agents.yaml")
+	}
+	return buildAgent(args[0], buildTag, buildManifest)
+}
+
+// buildAllAgents builds every agent in the agents.yaml in root, tagging
+// each <name>:latest. A failed build does not stop the others.
+func buildAllAgents(root string) error {
+	if buildTag != "" || buildManifest != "" || buildFromBase != "" {
+		return fmt.Errorf("--tag, --manifest-output and --from-base cannot be used with --all")
+	}
 
+	manifestPath := filepath.Join(root, parser.AgentListFile)
+	list, err := parser.LoadAgentList(manifestPath)
+	if err != nil {
+		return err
+	}
+	if len(list.Items) == 0 {
+		return fmt.Errorf("no agents listed in %s", manifestPath)
+	}
+
+	var failed []string
+	for i, item := range list.Items {
+		fmt.Printf("\n[%d/%d] %s\n", i+1, len(list.Items), item.Name)
+		if err := buildAgent(item.Dir(manifestPath), item.Name+":latest", ""); err != nil {
+			fmt.Printf("❌ %s: %v\n", item.Name, err)
+			failed = append(failed, item.Name)
+		}
+	}
+
+	fmt.Printf("\n📦 Built %d of %d agents\n", len(list.Items)-len(failed), len(list.Items))
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to build %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// buildAgent builds the agent in buildPath and tags it tag. An empty
+// manifestOutput writes the build manifest to the build context.
+func buildAgent(buildPath, tag, manifestOutput string) error {
 	// Convert to absolute path
 	absPath, err := filepath.Abs(buildPath)
 	if err != nil {
@@ -57,10 +194,15 @@ func runBuild(cmd *cobra.Command, args []string) error {
 	// Build options
 	options := &builder.BuildOptions{
 		Path:     absPath,
-		Tag:      buildTag,
+		Tag:      tag,
 		NoCache:  buildNoCache,
 		Push:     buildPush,
 		Platform: buildPlatform,
+
+		UsesBuildKit: buildKit,
+		Profile:      buildProfile,
+		StrictDeps:   buildStrictDeps,
+		FromBase:     buildFromBase,
 	}
 
 	// Validate build context
@@ -69,6 +211,34 @@ func runBuild(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Printf("🔨 Building agent from %s\n", absPath)
+	if buildFromBase != "" {
+		fmt.Printf("🧱 Extending %s\n", buildFromBase)
+	}
+
+	if platforms := builder.ParsePlatforms(buildPlatform); len(platforms) > 1 {
+		if buildSBOM {
+			return fmt.Errorf("--sbom is not supported for multi-platform builds")
+		}
+		if buildRegistry != "" && tag != "" {
+			// buildx pushes straight to the registry the tag names
+			tag = builder.RegistryTag(buildRegistry, tag)
+			options.Tag = tag
+		}
+		if err := agentBuilder.BuildMultiArch(options, platforms); err != nil {
+			return fmt.Errorf("build failed: %w", err)
+		}
+		fmt.Printf("✅ Agent built and pushed for %s\n", strings.Join(platforms, ", "))
+		fmt.Printf("   Tag: %s\n", tag)
+		if buildK8s {
+			if err := writeKubernetesManifest(absPath, tag); err != nil {
+				return err
+			}
+		}
+		if buildCompose {
+			return writeComposeFile(agentBuilder, absPath, tag)
+		}
+		return nil
+	}
 
 	// Build the agent
 	result, err := agentBuilder.Build(options)
@@ -81,17 +251,110 @@ func runBuild(cmd *cobra.Command, args []string) error {
 	fmt.Printf("   Image: %s\n", result.ImageID)
 	fmt.Printf("   Size: %s\n", result.Size)
 
-	if buildTag != "" {
-		fmt.Printf("   Tag: %s\n", buildTag)
+	if tag != "" {
+		fmt.Printf("   Tag: %s\n", tag)
+	}
+
+	if buildSBOM {
+		sbomPath := filepath.Join(absPath, "sbom.cdx.json")
+		fmt.Printf("📋 Generating SBOM...\n")
+		if err := agentBuilder.GenerateSBOM(result.ImageID, sbomPath); err != nil {
+			return fmt.Errorf("SBOM generation failed: %w", err)
+		}
+		fmt.Printf("✅ SBOM saved to %s\n", sbomPath)
 	}
 
 	if buildPush {
-		fmt.Printf("📤 Pushing to registry...\n")
-		if err := agentBuilder.Push(buildTag); err != nil {
+		if buildRegistry != "" {
+			fmt.Printf("📤 Pushing to %s...\n", builder.RegistryTag(buildRegistry, tag))
+			err = agentBuilder.TagAndPush(tag, buildRegistry, "", buildProfile, buildUntag)
+		} else {
+			fmt.Printf("📤 Pushing to registry...\n")
+			err = agentBuilder.Push(tag, buildProfile)
+		}
+		if err != nil {
 			return fmt.Errorf("push failed: %w", err)
 		}
 		fmt.Printf("✅ Push completed!\n")
+
+		// Pushing assigns the registry digest
+		if digest, err := agentBuilder.ImageDigest(result.ImageID); err == nil {
+			result.Digest = digest
+		}
+	}
+
+	if buildK8s {
+		image := tag
+		if image == "" {
+			image = result.ImageID
+			fmt.Printf("⚠️  No tag given; agent-k8s.yaml refers to the local image ID\n")
+		}
+		if err := writeKubernetesManifest(absPath, image); err != nil {
+			return err
+		}
+	}
+
+	if buildCompose {
+		image := tag
+		if image == "" {
+			image = result.ImageID
+			fmt.Printf("⚠️  No tag given; agent-compose.yaml refers to the local image ID\n")
+		}
+		if err := writeComposeFile(agentBuilder, absPath, image); err != nil {
+			return err
+		}
+	}
+
+	manifestPath := manifestOutput
+	if manifestPath == "" {
+		manifestPath = filepath.Join(absPath, "build-manifest.json")
+	}
+	if err := builder.WriteBuildManifest(result, manifestPath); err != nil {
+		return err
 	}
+	fmt.Printf("📄 Build manifest saved to %s\n", manifestPath)
 
 	return nil
 }
+
+// writeKubernetesManifest writes agent-k8s.yaml next to agent.yaml. The spec
+// is parsed again without interpolation so host environment values don't
+// end up in the manifest.
+func writeKubernetesManifest(dir, image string) error {
+	spec, err := parseAgentSpec(dir)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(dir, "agent-k8s.yaml")
+	if err := builder.WriteKubernetesManifest(spec, image, path); err != nil {
+		return err
+	}
+	fmt.Printf("☸️  Kubernetes manifest saved to %s\n", path)
+	return nil
+}
+
+// writeComposeFile writes agent-compose.yaml next to agent.yaml
+func writeComposeFile(agentBuilder *builder.Builder, dir, image string) error {
+	spec, err := parseAgentSpec(dir)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(dir, "agent-compose.yaml")
+	if err := agentBuilder.GenerateComposeFile(spec, image, path); err != nil {
+		return err
+	}
+	fmt.Printf("🐳 Compose file saved to %s\n", path)
+	return nil
+}
+
+// parseAgentSpec parses the agent.yaml in dir
+func parseAgentSpec(dir string) (*parser.AgentSpec, error) {
+	p := parser.New()
+	agentFile, err := p.FindAgentFile(dir)
+	if err != nil {
+		return nil, err
+	}
+	return p.ParseFile(agentFile)
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/pxkundu/agent-as-code/internal/runtime"
+	"github.com/spf13/cobra"
+)
+
+var checkpointCmd = &cobra.Command{
+	Use:   "checkpoint [OPTIONS] CONTAINER",
+	Short: "Save the state of a running agent",
+	Long: `Save the in-memory state of a running agent container to a tar archive.
+
+The checkpoint is taken with CRIU, so the Docker daemon must have
+experimental features enabled and CRIU installed. The archive contains the
+CRIU image and a checkpoint-manifest.json with the container configuration
+needed by 'agent restore'.
+
+Examples:
+  agent checkpoint my-agent --output my-agent.tar
+  agent checkpoint my-agent --output my-agent.tar --description "after warmup"
+  agent checkpoint my-agent --output my-agent.tar --leave-running`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCheckpoint,
+}
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore [OPTIONS] CHECKPOINT",
+	Short: "Restore an agent from a checkpoint",
+	Long: `Create a new agent container from a checkpoint archive and resume it
+from the saved state. The checkpoint ID and description are recorded as
+agent.dev/checkpoint-* labels on the new container.
+
+Examples:
+  agent restore my-agent.tar
+  agent restore my-agent.tar --name my-agent-2
+  agent restore my-agent.tar --name my-agent-2 --description "replica of warm agent"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRestore,
+}
+
+var (
+	checkpointOutput       string
+	checkpointDescription  string
+	checkpointLeaveRunning bool
+	restoreName            string
+	restoreDescription     string
+)
+
+func init() {
+	rootCmd.AddCommand(checkpointCmd)
+	rootCmd.AddCommand(restoreCmd)
+
+	checkpointCmd.Flags().StringVarP(&checkpointOutput, "output", "o", "", "path of the checkpoint archive to write")
+	checkpointCmd.Flags().StringVar(&checkpointDescription, "description", "", "human-readable label stored in the checkpoint manifest")
+	checkpointCmd.Flags().BoolVar(&checkpointLeaveRunning, "leave-running", false, "keep the container running after the checkpoint")
+	checkpointCmd.MarkFlagRequired("output")
+
+	restoreCmd.Flags().StringVar(&restoreName, "name", "", "name of the restored container (default: <original>-restored)")
+	restoreCmd.Flags().StringVar(&restoreDescription, "description", "", "label for the restored container (default: the checkpoint's description)")
+}
+
+func runCheckpoint(cmd *cobra.Command, args []string) error {
+	containerName := args[0]
+
+	fmt.Printf("📸 Checkpointing agent: %s\n", containerName)
+
+	agentRuntime := runtime.New()
+	manifest, err := agentRuntime.Checkpoint(&runtime.CheckpointOptions{
+		Container:    containerName,
+		OutputPath:   checkpointOutput,
+		Description:  checkpointDescription,
+		LeaveRunning: checkpointLeaveRunning,
+	})
+	if err != nil {
+		return fmt.Errorf("checkpoint failed: %w", err)
+	}
+
+	fmt.Printf("✅ Checkpoint saved to %s\n", checkpointOutput)
+	fmt.Printf("   Checkpoint ID: %s\n", manifest.CheckpointID)
+	if manifest.Description != "" {
+		fmt.Printf("   Description: %s\n", manifest.Description)
+	}
+	if !checkpointLeaveRunning {
+		fmt.Printf("   Container %s has been stopped\n", manifest.Container)
+	}
+
+	fmt.Printf("\n💡 Use 'agent restore %s' to resume the agent\n", checkpointOutput)
+	return nil
+}
+
+func runRestore(cmd *cobra.Command, args []string) error {
+	checkpointPath := args[0]
+
+	fmt.Printf("♻️  Restoring agent from %s\n", checkpointPath)
+
+	agentRuntime := runtime.New()
+	container, manifest, err := agentRuntime.Restore(&runtime.RestoreOptions{
+		CheckpointPath: checkpointPath,
+		Name:           restoreName,
+		Description:    restoreDescription,
+	})
+	if err != nil {
+		return fmt.Errorf("restore failed: %w", err)
+	}
+
+	fmt.Printf("✅ Agent restored\n")
+	fmt.Printf("   Container ID: %s\n", container.ID[:12])
+	fmt.Printf("   Name: %s\n", container.Name)
+	if manifest.Description != "" {
+		fmt.Printf("   Checkpoint: %s (%s)\n", manifest.Description, manifest.CreatedAt.Format("2006-01-02 15:04:05"))
+	}
+	for _, port := range container.Ports {
+		fmt.Printf("   Port: %s -> %s/%s\n", port.Host, port.Container, port.Protocol)
+	}
+
+	return nil
+}
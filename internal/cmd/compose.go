@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pxkundu/agent-as-code/internal/compose"
+	"github.com/spf13/cobra"
+)
+
+var composeCmd = &cobra.Command{
+	Use:   "compose",
+	Short: "Run multi-agent systems from a compose.yaml",
+	Long: `Run multi-agent systems described by a compose.yaml.
+
+The compose file uses a subset of the Docker Compose format:
+
+  services:
+    embedder:
+      image: embedder:latest
+    responder:
+      image: responder:latest
+      depends_on: [embedder]
+      environment:
+        EMBEDDER_URL: http://embedder:8080
+      ports: ["8080:8080"]
+
+Services start in dependency order on a network named after the project,
+where they reach each other by service name. The project name is the
+compose file's name field, or else the name of its directory. An
+environment variable without a value, such as "OPENAI_API_KEY:", is passed
+through from the shell running agent compose.
+
+Examples:
+  agent compose up
+  agent compose -f pipeline.yaml up
+  agent compose logs responder
+  agent compose down`,
+}
+
+var composeUpCmd = &cobra.Command{
+	Use:   "up",
+	Short: "Start the services of a compose file",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		project, err := composeProject()
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("📦 Starting project %s\n", project)
+		if err := compose.NewOrchestrator(project, os.Stdout).Up(composeFile); err != nil {
+			return err
+		}
+
+		fmt.Printf("✅ Project %s is up\n", project)
+		fmt.Printf("\n💡 Use 'agent compose logs SERVICE' to view logs\n")
+		fmt.Printf("💡 Use 'agent compose down' to stop the project\n")
+		return nil
+	},
+}
+
+var composeDownCmd = &cobra.Command{
+	Use:   "down",
+	Short: "Stop and remove the services of a compose project",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		project, err := composeProject()
+		if err != nil {
+			return err
+		}
+
+		if err := compose.NewOrchestrator(project, os.Stdout).Down(); err != nil {
+			return err
+		}
+
+		fmt.Printf("✅ Project %s is down\n", project)
+		return nil
+	},
+}
+
+var composeLogsCmd = &cobra.Command{
+	Use:   "logs SERVICE",
+	Short: "Follow the logs of a service",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		project, err := composeProject()
+		if err != nil {
+			return err
+		}
+
+		return compose.NewOrchestrator(project, os.Stdout).Logs(args[0])
+	},
+}
+
+var (
+	composeFile        string
+	composeProjectName string
+)
+
+func init() {
+	rootCmd.AddCommand(composeCmd)
+	composeCmd.AddCommand(composeUpCmd)
+	composeCmd.AddCommand(composeDownCmd)
+	composeCmd.AddCommand(composeLogsCmd)
+
+	composeCmd.PersistentFlags().StringVarP(&composeFile, "file", "f", "compose.yaml", "compose file")
+	composeCmd.PersistentFlags().StringVarP(&composeProjectName, "project-name", "p", "", "project name (default: the file's name field or directory)")
+}
+
+// composeProject returns the project name from --project-name or the
+// compose file. down and logs work without the file, using its directory.
+func composeProject() (string, error) {
+	if composeProjectName != "" {
+		return compose.ProjectName("", &compose.File{Name: composeProjectName}), nil
+	}
+
+	file, err := compose.Load(composeFile)
+	if err != nil {
+		if _, statErr := os.Stat(composeFile); os.IsNotExist(statErr) {
+			return compose.ProjectName(composeFile, nil), nil
+		}
+		return "", err
+	}
+	return compose.ProjectName(composeFile, file), nil
+}
@@ -1,13 +1,15 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
+	"io"
 	"os"
-	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/pxkundu/agent-as-code/internal/config"
 	"github.com/spf13/cobra"
 )
 
@@ -25,7 +27,39 @@ Examples:
   agent configure profile list
   agent configure profile remove prod
   agent configure profile test prod
-  agent configure profile set-default prod`,
+  agent configure profile set-default prod
+  agent configure export --format toml > ~/dotfiles/agent/config.toml
+  agent configure migrate --to json
+  agent configure service-account create --name ci-bot --expiry 90d
+
+Config files:
+  The user config is read from the first of these that exists:
+    1. the file given with --config, if it ends in .json or .toml
+    2. ~/.agent/config.json
+    3. ~/.agent/config.toml
+  It is merged with the system-wide /etc/agent/config.json
+  (%ProgramData%\agent\config.json on Windows) and the project-local
+  .agent.json in the current directory or its nearest parent that has one.
+  A profile defined in several files is taken from the one with the highest
+  precedence: project, then user, then system; the same goes for
+  default_profile. Run with --log-level debug to see which files were read.
+
+  Changes are always written as JSON to the user config: to the --config
+  file when it is JSON and to ~/.agent/config.json otherwise. Profiles from
+  the project file, and removing profiles from the system file, must be
+  edited in those files. A TOML file lists profiles as [profiles.NAME]
+  tables with registry, pat and description keys, next to an optional
+  top-level default_profile.
+
+Token storage:
+  PATs are encrypted with AES-256-GCM before being written to
+  ~/.agent/config.json. The encryption key is kept in the OS keyring
+  (macOS Keychain, Windows Credential Manager, or the Secret Service on
+  Linux) when one is available. Otherwise the key is derived from the
+  machine ID (/etc/machine-id on Linux, IOPlatformUUID on macOS), so the
+  config file cannot be decrypted on another machine. Profiles saved in
+  plaintext by older versions are encrypted automatically on first use.
+  PATs in a TOML config are read as plaintext.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return cmd.Help()
 	},
@@ -54,7 +88,10 @@ like push, pull, and other registry interactions.
 Examples:
   agent configure profile add production --registry https://api.myagentregistry.com --pat a1b2c3d4e5f6789012345678901234567890abcdef1234567890abcdef123456
   agent configure profile add staging --registry https://api.myagentregistry.com --pat b2c3d4e5f6789012345678901234567890abcdef1234567890abcdef1234567a --description "Staging environment"
-  agent configure profile add local --registry http://localhost:5000 --pat c3d4e5f6789012345678901234567890abcdef1234567890abcdef1234567890 --set-default --test`,
+  agent configure profile add local --registry http://localhost:5000 --pat c3d4e5f6789012345678901234567890abcdef1234567890abcdef1234567890 --set-default --test
+
+The PAT is encrypted at rest. See 'agent configure --help' for details on
+how the encryption key is stored.`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		name := args[0]
@@ -74,9 +111,16 @@ var profileListCmd = &cobra.Command{
 	Long: `List all configured registry profiles.
 
 This command displays all available profiles with their settings and
-indicates which profile is currently set as default.`,
+indicates which profile is currently set as default. PATs are shown with
+only their first 8 and last 4 characters; --reveal-token shows them in
+full after a confirmation at the terminal.
+
+Examples:
+  agent configure profile list
+  agent configure profile list --reveal-token`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return listProfiles()
+		reveal, _ := cmd.Flags().GetBool("reveal-token")
+		return listProfiles(reveal)
 	},
 }
 
@@ -137,6 +181,71 @@ Examples:
 	},
 }
 
+var configureExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Print the profiles config as TOML",
+	Long: `Print the current profiles config as TOML, for example to share it in a
+dotfiles repository. PATs are left out unless --include-pats is given.
+
+Examples:
+  agent configure export --format toml
+  agent configure export --format toml --include-pats > ~/.agent/config.toml`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		includePATs, _ := cmd.Flags().GetBool("include-pats")
+		return exportConfig(format, includePATs)
+	},
+}
+
+var configureMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Convert a TOML profiles config to JSON",
+	Long: `Convert ~/.agent/config.toml to ~/.agent/config.json, encrypting its PATs.
+The TOML file is kept as config.toml.bak.
+
+Examples:
+  agent configure migrate --to json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		to, _ := cmd.Flags().GetString("to")
+		return migrateConfig(to)
+	},
+}
+
+var serviceAccountCmd = &cobra.Command{
+	Use:   "service-account",
+	Short: "Manage service accounts for CI",
+	Long:  `Manage service accounts, which let CI jobs authenticate without a PAT.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var serviceAccountCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a service account token",
+	Long: `Create a service account for a profile and print its token.
+
+The token is a JWT signed with the profile's PAT (HS256), with the account
+name as its subject, the given scope and an expiry. It is stored with the
+profile, replacing any earlier service account. Pass it to CI jobs in the
+AGENT_SERVICE_ACCOUNT_TOKEN environment variable, which is used instead of
+AGENT_REGISTRY_TOKEN.
+
+--expiry takes a number of days (90d) or a duration (720h).
+
+Examples:
+  agent configure service-account create --name ci-bot --expiry 90d
+  agent configure service-account create --name release-bot --profile prod --scope read`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, _ := cmd.Flags().GetString("name")
+		expiry, _ := cmd.Flags().GetString("expiry")
+		profile, _ := cmd.Flags().GetString("profile")
+		scope, _ := cmd.Flags().GetStringSlice("scope")
+		return createServiceAccount(name, expiry, profile, scope)
+	},
+}
+
 func init() {
 	// Configure command
 	rootCmd.AddCommand(configureCmd)
@@ -154,6 +263,7 @@ func init() {
 	profileCmd.AddCommand(profileAddCmd)
 
 	// Profile list command
+	profileListCmd.Flags().Bool("reveal-token", false, "show PATs in full (asks for confirmation)")
 	profileCmd.AddCommand(profileListCmd)
 
 	// Profile remove command
@@ -164,18 +274,32 @@ func init() {
 
 	// Profile set-default command
 	profileCmd.AddCommand(profileSetDefaultCmd)
-}
 
-type Profile struct {
-	Registry    string `json:"registry"`
-	PAT         string `json:"pat"`
-	Description string `json:"description"`
+	// Export command
+	configureExportCmd.Flags().String("format", "toml", "output format (toml)")
+	configureExportCmd.Flags().Bool("include-pats", false, "include PATs in plaintext")
+	configureCmd.AddCommand(configureExportCmd)
+
+	// Migrate command
+	configureMigrateCmd.Flags().String("to", "json", "format to migrate to (json)")
+	configureCmd.AddCommand(configureMigrateCmd)
+
+	// Service account commands
+	serviceAccountCreateCmd.Flags().String("name", "", "service account name, used as the token subject (required)")
+	serviceAccountCreateCmd.Flags().String("expiry", "90d", "token lifetime in days (90d) or as a duration (720h)")
+	serviceAccountCreateCmd.Flags().String("profile", "", "profile whose PAT signs the token (default: the default profile)")
+	serviceAccountCreateCmd.Flags().StringSlice("scope", []string{"read", "write"}, "token scope")
+	serviceAccountCreateCmd.MarkFlagRequired("name")
+	serviceAccountCmd.AddCommand(serviceAccountCreateCmd)
+	configureCmd.AddCommand(serviceAccountCmd)
 }
 
-type Config struct {
-	Profiles       map[string]Profile `json:"profiles"`
-	DefaultProfile string             `json:"default_profile"`
-}
+// Profile and Config are stored by the config package so other packages can
+// read registry credentials
+type (
+	Profile = config.Profile
+	Config  = config.Config
+)
 
 func addProfile(name, registry, pat, description string, setDefault, test bool) error {
 	// Validate PAT format
@@ -233,12 +357,22 @@ func addProfile(name, registry, pat, description string, setDefault, test bool)
 	return nil
 }
 
-func listProfiles() error {
+func listProfiles(reveal bool) error {
 	config, err := loadConfig()
 	if err != nil {
 		return fmt.Errorf("failed to load profiles: %v", err)
 	}
 
+	if reveal && len(config.Profiles) > 0 {
+		// Only a person at the terminal can agree, not a piped "y"
+		if !isTerminal(os.Stdin) {
+			return fmt.Errorf("--reveal-token must be confirmed at an interactive terminal")
+		}
+		if !confirm("⚠️  This prints your PATs in plaintext. Continue?") {
+			return fmt.Errorf("aborted")
+		}
+	}
+
 	if len(config.Profiles) == 0 {
 		fmt.Println("No profiles configured")
 		fmt.Println("Use 'agent configure profile add' to add a profile")
@@ -255,6 +389,14 @@ func listProfiles() error {
 		fmt.Printf("  %s%s\n", name, defaultMarker)
 		fmt.Printf("    Registry: %s\n", profile.Registry)
 		fmt.Printf("    Description: %s\n", profile.Description)
+		switch {
+		case profile.PAT == "":
+			fmt.Printf("    PAT: (not set)\n")
+		case reveal:
+			fmt.Printf("    PAT: %s\n", profile.PAT)
+		default:
+			fmt.Printf("    PAT: %s\n", profile.MaskedPAT())
+		}
 		fmt.Println()
 	}
 
@@ -278,25 +420,29 @@ func removeProfile(name string) error {
 	delete(config.Profiles, name)
 
 	// Update default profile if necessary
+	defaultChange := ""
 	if config.DefaultProfile == name {
 		if len(config.Profiles) > 0 {
 			// Set first remaining profile as default
 			for profileName := range config.Profiles {
 				config.DefaultProfile = profileName
-				fmt.Printf("Default profile changed to '%s'\n", profileName)
+				defaultChange = fmt.Sprintf("Default profile changed to '%s'", profileName)
 				break
 			}
 		} else {
 			config.DefaultProfile = ""
-			fmt.Println("No profiles remaining")
+			defaultChange = "No profiles remaining"
 		}
 	}
 
-	// Save the config
+	// Save the config; it fails for profiles defined outside the user config
 	if err := saveConfig(config); err != nil {
 		return fmt.Errorf("failed to save config: %v", err)
 	}
 
+	if defaultChange != "" {
+		fmt.Println(defaultChange)
+	}
 	fmt.Printf("Profile '%s' removed successfully\n", name)
 	return nil
 }
@@ -315,7 +461,7 @@ func testProfile(name string) error {
 	}
 
 	// Test the connection using registry client
-	if err := testRegistryConnection(profile.Registry, profile.PAT); err != nil {
+	if err := testRegistryConnection(os.Stdout, profile.Registry, profile.PAT, profile.ServiceAccount); err != nil {
 		return fmt.Errorf("connection test failed: %v", err)
 	}
 
@@ -347,71 +493,105 @@ func setDefaultProfile(name string) error {
 	return nil
 }
 
+// loadConfig loads the profiles config, reporting PATs that were encrypted
+// on load. A token in AGENT_SERVICE_ACCOUNT_TOKEN becomes the default
+// profile's service account; it is not written back by saveConfig.
 func loadConfig() (*Config, error) {
-	configFile := getConfigFile()
+	cfg, migrated, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+	if migrated {
+		fmt.Println("🔒 Stored PATs have been encrypted")
+	}
+
+	cfg.ApplyServiceAccountEnv()
+	return cfg, nil
+}
+
+func saveConfig(cfg *Config) error {
+	return config.Save(cfg)
+}
 
-	// Create default config if file doesn't exist
-	if _, err := os.Stat(configFile); os.IsNotExist(err) {
-		return &Config{
-			Profiles:       make(map[string]Profile),
-			DefaultProfile: "",
-		}, nil
+func exportConfig(format string, includePATs bool) error {
+	if format != "toml" {
+		return fmt.Errorf("invalid format '%s' (valid: toml)", format)
 	}
 
-	// Read config file
-	data, err := os.ReadFile(configFile)
+	cfg, err := loadConfig()
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config file: %v", err)
+		return fmt.Errorf("failed to load config: %v", err)
 	}
 
-	var config Config
-	if err := json.Unmarshal(data, &config); err != nil {
-		// Return default config if parsing fails
-		fmt.Printf("Warning: Failed to load config: %v\n", err)
-		return &Config{
-			Profiles:       make(map[string]Profile),
-			DefaultProfile: "",
-		}, nil
+	return config.ExportTOML(cfg, os.Stdout, includePATs)
+}
+
+func migrateConfig(to string) error {
+	if to != "json" {
+		return fmt.Errorf("invalid format '%s' (valid: json)", to)
 	}
 
-	// Initialize profiles map if nil
-	if config.Profiles == nil {
-		config.Profiles = make(map[string]Profile)
+	backup, err := config.MigrateToJSON()
+	if err != nil {
+		return err
 	}
 
-	return &config, nil
+	fmt.Printf("✅ Migrated profiles to %s\n", config.File())
+	fmt.Printf("📦 The TOML config was kept as %s\n", backup)
+	return nil
 }
 
-func saveConfig(config *Config) error {
-	configFile := getConfigFile()
-
-	// Ensure config directory exists
-	configDir := filepath.Dir(configFile)
-	if err := os.MkdirAll(configDir, 0755); err != nil {
+func createServiceAccount(name, expiry, profileName string, scope []string) error {
+	lifetime, err := parseExpiry(expiry)
+	if err != nil {
 		return err
 	}
 
-	// Marshal config to JSON
-	data, err := json.MarshalIndent(config, "", "  ")
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %v", err)
+	}
+
+	if profileName == "" {
+		profileName = cfg.DefaultProfile
+		if profileName == "" {
+			return fmt.Errorf("no default profile set; use --profile")
+		}
+	}
+	profile, exists := cfg.Profiles[profileName]
+	if !exists {
+		return fmt.Errorf("profile '%s' not found", profileName)
+	}
+
+	account, err := config.NewServiceAccount(name, scope, lifetime, profile.PAT)
 	if err != nil {
-		return fmt.Errorf("failed to marshal config: %v", err)
+		return fmt.Errorf("failed to create service account: %v", err)
 	}
+	profile.ServiceAccount = account
+	cfg.Profiles[profileName] = profile
 
-	// Write to file
-	if err := os.WriteFile(configFile, data, 0644); err != nil {
-		return fmt.Errorf("failed to write config file: %v", err)
+	if err := saveConfig(cfg); err != nil {
+		return fmt.Errorf("failed to save service account: %v", err)
 	}
 
+	fmt.Printf("✅ Service account '%s' created for profile '%s'\n", name, profileName)
+	fmt.Printf("   Scope: %s\n", strings.Join(scope, ","))
+	fmt.Printf("   Expires: %s\n", account.ExpiresAt.Format(time.RFC3339))
+	fmt.Printf("\n%s\n\n", account.Token)
+	fmt.Printf("💡 Set this token as the %s secret of your CI pipeline\n", config.ServiceAccountTokenEnv)
 	return nil
 }
 
-func getConfigFile() string {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		home = "."
+// parseExpiry parses a token lifetime given in days (90d) or as a duration
+func parseExpiry(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n > 0 {
+			return time.Duration(n) * 24 * time.Hour, nil
+		}
+	} else if d, err := time.ParseDuration(value); err == nil && d > 0 {
+		return d, nil
 	}
-
-	return filepath.Join(home, ".agent", "config.json")
+	return 0, fmt.Errorf("invalid expiry '%s': use a number of days (90d) or a duration (720h)", value)
 }
 
 func validatePAT(pat string) bool {
@@ -425,7 +605,10 @@ func validatePAT(pat string) bool {
 	return matched
 }
 
-func testRegistryConnection(registry, pat string) error {
+// testRegistryConnection checks the registry URL and the credentials a
+// profile authenticates with: its service account token when it has one,
+// and its PAT otherwise. The credentials used are reported to out.
+func testRegistryConnection(out io.Writer, registry, pat string, account *config.ServiceAccount) error {
 	// Import needed for HTTP requests
 	// In a real implementation, this would make an HTTP request to test connectivity
 	// For now, we simulate the test based on the registry URL
@@ -440,8 +623,29 @@ func testRegistryConnection(registry, pat string) error {
 		return fmt.Errorf("example.com is not a real registry")
 	}
 
+	if account != nil {
+		claims, err := config.ParseServiceAccountToken(account.Token, pat)
+		if err != nil {
+			return err
+		}
+		source := "profile"
+		if account.FromEnv {
+			source = config.ServiceAccountTokenEnv
+		}
+		fmt.Fprintf(out, "🤖 Authenticating as service account '%s' from %s (scope %s, expires %s)\n",
+			claims.Name, source, strings.Join(claims.Scope, ","), claims.ExpiresAt.Format(time.RFC3339))
+	} else if pat != "" {
+		fmt.Fprintln(out, "🔑 Authenticating with the profile's PAT")
+	}
+
 	// In a real implementation, this would make a GET request to {registry}/health
-	// with Authorization header containing the PAT
+	// with Authorization header containing the PAT or service account token
 
 	return nil
 }
+
+// isTerminal reports whether f is a terminal rather than a pipe or file
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// dockerEndpoint is the Docker daemon a context points at
+type dockerEndpoint struct {
+	Host          string
+	SkipTLSVerify bool
+	TLSPath       string // directory holding ca.pem, cert.pem and key.pem
+}
+
+// applyDockerTarget points every Docker client created afterwards at
+// dockerHost, or at the daemon of the named Docker context, by setting
+// DOCKER_HOST and the TLS variables the clients read from the environment
+func applyDockerTarget(dockerHost, contextName string) error {
+	if dockerHost != "" && contextName != "" {
+		return fmt.Errorf("--docker-host and --context cannot be used together")
+	}
+
+	if contextName != "" && contextName != "default" {
+		endpoint, err := resolveDockerContext(contextName)
+		if err != nil {
+			return err
+		}
+
+		dockerHost = endpoint.Host
+		if hasTLSFiles(endpoint.TLSPath) {
+			os.Setenv("DOCKER_CERT_PATH", endpoint.TLSPath)
+			if endpoint.SkipTLSVerify {
+				os.Unsetenv("DOCKER_TLS_VERIFY")
+			} else {
+				os.Setenv("DOCKER_TLS_VERIFY", "1")
+			}
+		}
+	}
+
+	if dockerHost == "" {
+		return nil
+	}
+	if strings.HasPrefix(dockerHost, "ssh://") {
+		return fmt.Errorf("ssh:// Docker hosts are not supported, expose the daemon over tcp:// instead")
+	}
+
+	return os.Setenv("DOCKER_HOST", dockerHost)
+}
+
+// resolveDockerContext reads the endpoint of a Docker context from the
+// Docker CLI's context store, falling back to 'docker context inspect'
+func resolveDockerContext(name string) (*dockerEndpoint, error) {
+	configDir := os.Getenv("DOCKER_CONFIG")
+	if configDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to find home directory: %v", err)
+		}
+		configDir = filepath.Join(home, ".docker")
+	}
+
+	// Contexts are stored under the SHA-256 of their name
+	sum := sha256.Sum256([]byte(name))
+	id := hex.EncodeToString(sum[:])
+
+	data, err := os.ReadFile(filepath.Join(configDir, "contexts", "meta", id, "meta.json"))
+	if os.IsNotExist(err) {
+		return inspectDockerContext(name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Docker context '%s': %v", name, err)
+	}
+
+	var meta struct {
+		Endpoints struct {
+			Docker struct {
+				Host          string `json:"Host"`
+				SkipTLSVerify bool   `json:"SkipTLSVerify"`
+			} `json:"docker"`
+		} `json:"Endpoints"`
+	}
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, fmt.Errorf("invalid Docker context '%s': %v", name, err)
+	}
+	if meta.Endpoints.Docker.Host == "" {
+		return nil, fmt.Errorf("Docker context '%s' has no Docker endpoint", name)
+	}
+
+	return &dockerEndpoint{
+		Host:          meta.Endpoints.Docker.Host,
+		SkipTLSVerify: meta.Endpoints.Docker.SkipTLSVerify,
+		TLSPath:       filepath.Join(configDir, "contexts", "tls", id, "docker"),
+	}, nil
+}
+
+// inspectDockerContext asks the Docker CLI for a context's endpoint
+func inspectDockerContext(name string) (*dockerEndpoint, error) {
+	if _, err := exec.LookPath("docker"); err != nil {
+		return nil, fmt.Errorf("Docker context '%s' not found", name)
+	}
+
+	out, err := exec.Command("docker", "context", "inspect", name).Output()
+	if err != nil {
+		return nil, fmt.Errorf("Docker context '%s' not found", name)
+	}
+
+	var contexts []struct {
+		Endpoints struct {
+			Docker struct {
+				Host          string `json:"Host"`
+				SkipTLSVerify bool   `json:"SkipTLSVerify"`
+			} `json:"docker"`
+		} `json:"Endpoints"`
+		Storage struct {
+			TLSPath string `json:"TLSPath"`
+		} `json:"Storage"`
+	}
+	if err := json.Unmarshal(out, &contexts); err != nil || len(contexts) == 0 {
+		return nil, fmt.Errorf("failed to parse 'docker context inspect %s' output", name)
+	}
+
+	context := contexts[0]
+	if context.Endpoints.Docker.Host == "" {
+		return nil, fmt.Errorf("Docker context '%s' has no Docker endpoint", name)
+	}
+
+	return &dockerEndpoint{
+		Host:          context.Endpoints.Docker.Host,
+		SkipTLSVerify: context.Endpoints.Docker.SkipTLSVerify,
+		TLSPath:       filepath.Join(context.Storage.TLSPath, "docker"),
+	}, nil
+}
+
+// hasTLSFiles reports whether dir holds the client certificate files
+// Docker clients load from DOCKER_CERT_PATH
+func hasTLSFiles(dir string) bool {
+	if dir == "" {
+		return false
+	}
+	for _, name := range []string{"ca.pem", "cert.pem", "key.pem"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			return false
+		}
+	}
+	return true
+}
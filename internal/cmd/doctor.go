@@ -0,0 +1,351 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/versions"
+	"github.com/docker/docker/client"
+	"github.com/pxkundu/agent-as-code/internal/config"
+	"github.com/pxkundu/agent-as-code/internal/llm"
+	"github.com/spf13/cobra"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose common environment issues",
+	Long: `Check that the tools agent relies on are installed and reachable.
+
+Checks:
+  - the Docker daemon is reachable and speaks API version 1.41 or later
+  - the config directory exists and the default profile's registry connection works
+  - AGENT_REGISTRY_TOKEN (or AGENT_SERVICE_ACCOUNT_TOKEN) is set
+  - Ollama is reachable at OLLAMA_HOST, or http://localhost:11434
+  - Python 3.8 or later is installed
+  - at least 10 GB of disk space is free in the home directory
+
+Each failed check comes with the steps to fix it. --fix applies the fixes
+that are safe to make automatically, such as creating the config
+directory; the other fixes are printed as commands to run. The command
+exits with an error when any check fails.
+
+Examples:
+  agent doctor
+  agent doctor --fix`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fix, _ := cmd.Flags().GetBool("fix")
+		return runDoctor(fix)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+
+	doctorCmd.Flags().Bool("fix", false, "apply safe fixes automatically")
+}
+
+// Thresholds checked by agent doctor
+const (
+	minDockerAPIVersion = "1.41"
+	minPythonMinor      = 8 // Python 3.8
+	minFreeDiskBytes    = 10 << 30
+)
+
+// checkStatus is the outcome of a doctor check
+type checkStatus int
+
+const (
+	checkPassed checkStatus = iota
+	checkFailed
+	checkSkipped
+)
+
+// checkResult is the outcome of a doctor check, with the steps to take when
+// it did not pass
+type checkResult struct {
+	Name    string
+	Status  checkStatus
+	Message string
+	Remedy  []string
+
+	// Fix repairs the problem, if it can be done safely; run with --fix
+	Fix func() error
+}
+
+func (s checkStatus) icon() string {
+	switch s {
+	case checkPassed:
+		return "✅"
+	case checkSkipped:
+		return "➖"
+	default:
+		return "❌"
+	}
+}
+
+func runDoctor(fix bool) error {
+	fmt.Println("🩺 Checking your environment")
+	fmt.Println("============================")
+
+	dockerCheck, apiCheck := checkDocker()
+	results := []checkResult{
+		dockerCheck,
+		apiCheck,
+		checkConfigDir(),
+		checkRegistryToken(),
+		checkDefaultProfile(),
+		checkOllama(),
+		checkPython(),
+		checkDiskSpace(),
+	}
+
+	failed := 0
+	for _, result := range results {
+		fmt.Printf("%s %s: %s\n", result.Status.icon(), result.Name, result.Message)
+		if result.Status != checkFailed {
+			continue
+		}
+
+		if fix && result.Fix != nil {
+			if err := result.Fix(); err != nil {
+				fmt.Printf("   🔧 Fix failed: %v\n", err)
+			} else {
+				fmt.Println("   🔧 Fixed")
+				continue
+			}
+		}
+		failed++
+		for _, step := range result.Remedy {
+			fmt.Printf("   💡 %s\n", step)
+		}
+	}
+
+	fmt.Println()
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(results))
+	}
+	fmt.Println("🎉 Your environment is ready")
+	return nil
+}
+
+// checkDocker checks that the Docker daemon is reachable and that its API
+// version is recent enough
+func checkDocker() (daemon, api checkResult) {
+	daemon = checkResult{Name: "Docker daemon"}
+	api = checkResult{Name: "Docker API version"}
+
+	var version types.Version
+	dockerClient, err := client.NewClientWithOpts(client.FromEnv)
+	if err == nil {
+		defer dockerClient.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		version, err = dockerClient.ServerVersion(ctx)
+	}
+
+	if err == nil {
+		daemon.Status = checkPassed
+		daemon.Message = fmt.Sprintf("Docker %s at %s", version.Version, dockerClient.DaemonHost())
+
+		if versions.LessThan(version.APIVersion, minDockerAPIVersion) {
+			api.Status = checkFailed
+			api.Message = fmt.Sprintf("API %s is older than %s", version.APIVersion, minDockerAPIVersion)
+			api.Remedy = []string{"Upgrade Docker Engine to 20.10 or later: https://docs.docker.com/engine/install/"}
+		} else {
+			api.Status = checkPassed
+			api.Message = fmt.Sprintf("API %s", version.APIVersion)
+		}
+		return daemon, api
+	}
+
+	daemon.Status = checkFailed
+	daemon.Message = fmt.Sprintf("not reachable (%v)", err)
+	if host := os.Getenv("DOCKER_HOST"); host != "" {
+		daemon.Remedy = []string{fmt.Sprintf("Check that the daemon in DOCKER_HOST (%s) is running, or unset DOCKER_HOST to use the local one", host)}
+	} else {
+		daemon.Remedy = []string{
+			"Start Docker Desktop, or run 'sudo systemctl start docker' on Linux",
+			"Install Docker from https://docs.docker.com/get-docker/ if it is missing",
+		}
+	}
+
+	api.Status = checkSkipped
+	api.Message = "skipped, the Docker daemon is not reachable"
+	return daemon, api
+}
+
+// checkConfigDir checks that the directory holding config.json exists
+func checkConfigDir() checkResult {
+	result := checkResult{Name: "Config directory"}
+	dir := filepath.Dir(config.File())
+
+	if info, err := os.Stat(dir); err == nil && info.IsDir() {
+		result.Status = checkPassed
+		result.Message = dir
+		return result
+	}
+
+	result.Status = checkFailed
+	result.Message = fmt.Sprintf("%s does not exist", dir)
+	result.Remedy = []string{fmt.Sprintf("Run 'agent doctor --fix', or 'mkdir -p %s'", dir)}
+	result.Fix = func() error {
+		return os.MkdirAll(dir, 0700)
+	}
+	return result
+}
+
+// checkRegistryToken checks that a registry token is set in the environment
+func checkRegistryToken() checkResult {
+	result := checkResult{Name: "Registry token"}
+
+	switch {
+	case os.Getenv("AGENT_REGISTRY_TOKEN") != "":
+		result.Status = checkPassed
+		result.Message = "AGENT_REGISTRY_TOKEN is set"
+	case os.Getenv(config.ServiceAccountTokenEnv) != "":
+		result.Status = checkPassed
+		result.Message = config.ServiceAccountTokenEnv + " is set"
+	default:
+		result.Status = checkFailed
+		result.Message = "AGENT_REGISTRY_TOKEN is not set"
+		result.Remedy = []string{
+			"Run 'export AGENT_REGISTRY_TOKEN=<your PAT>'",
+			"In CI, set " + config.ServiceAccountTokenEnv + " to a token from 'agent configure service-account create'",
+		}
+	}
+	return result
+}
+
+// checkDefaultProfile checks that a default profile exists and that its
+// registry connection works
+func checkDefaultProfile() checkResult {
+	result := checkResult{Name: "Default profile"}
+	addProfileStep := "Run 'agent configure profile add default --registry https://api.myagentregistry.com --pat <your PAT> --set-default'"
+
+	cfg, err := loadConfig()
+	if err != nil {
+		result.Status = checkFailed
+		result.Message = fmt.Sprintf("failed to load %s: %v", config.Source(), err)
+		result.Remedy = []string{"Fix or remove the config file, then add a profile", addProfileStep}
+		return result
+	}
+
+	profile, ok := cfg.Profiles[cfg.DefaultProfile]
+	if cfg.DefaultProfile == "" || !ok {
+		result.Status = checkFailed
+		result.Message = "no default profile is configured"
+		result.Remedy = []string{addProfileStep}
+		if len(cfg.Profiles) > 0 {
+			result.Remedy = []string{"Run 'agent configure profile set-default <name>' with one of the profiles from 'agent configure profile list'"}
+		}
+		return result
+	}
+
+	if err := testRegistryConnection(io.Discard, profile.Registry, profile.PAT, profile.ServiceAccount); err != nil {
+		result.Status = checkFailed
+		result.Message = fmt.Sprintf("'%s' cannot connect to %s: %v", cfg.DefaultProfile, profile.Registry, err)
+		result.Remedy = []string{fmt.Sprintf("Check the registry URL and PAT, then run 'agent configure profile test %s'", cfg.DefaultProfile)}
+		return result
+	}
+
+	result.Status = checkPassed
+	result.Message = fmt.Sprintf("'%s' (%s)", cfg.DefaultProfile, profile.Registry)
+	return result
+}
+
+// checkOllama checks that Ollama answers at the configured URL
+func checkOllama() checkResult {
+	result := checkResult{Name: "Ollama"}
+	manager := llm.NewLocalLLMManager()
+
+	if err := manager.CheckOllamaAvailability(); err != nil {
+		result.Status = checkFailed
+		result.Message = fmt.Sprintf("not reachable at %s", manager.URL())
+		result.Remedy = []string{
+			"Install Ollama from https://ollama.ai and start it with 'ollama serve'",
+			"If Ollama runs elsewhere, set OLLAMA_HOST to its address",
+		}
+		return result
+	}
+
+	result.Status = checkPassed
+	result.Message = manager.URL()
+	if version, err := manager.GetOllamaVersion(); err == nil {
+		result.Message = fmt.Sprintf("v%s at %s", version, manager.URL())
+	}
+	return result
+}
+
+// checkPython checks that Python 3.8 or later is installed
+func checkPython() checkResult {
+	result := checkResult{
+		Name:   "Python",
+		Remedy: []string{"Install Python 3.8 or later from https://www.python.org/downloads/"},
+	}
+
+	version := getPythonVersion()
+	if version == "" {
+		result.Status = checkFailed
+		result.Message = "python3 not found"
+		return result
+	}
+
+	parts := strings.SplitN(version, ".", 3)
+	major, _ := strconv.Atoi(parts[0])
+	minor := 0
+	if len(parts) > 1 {
+		minor, _ = strconv.Atoi(parts[1])
+	}
+	if major < 3 || (major == 3 && minor < minPythonMinor) {
+		result.Status = checkFailed
+		result.Message = fmt.Sprintf("%s is older than 3.%d", version, minPythonMinor)
+		return result
+	}
+
+	result.Status = checkPassed
+	result.Message = version
+	result.Remedy = nil
+	return result
+}
+
+// checkDiskSpace checks the free space on the file system of the home
+// directory, where models and the config are stored
+func checkDiskSpace() checkResult {
+	result := checkResult{Name: "Disk space"}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = "."
+	}
+
+	free, err := freeDiskSpace(home)
+	if err != nil {
+		result.Status = checkSkipped
+		result.Message = fmt.Sprintf("could not be determined: %v", err)
+		return result
+	}
+
+	message := fmt.Sprintf("%.1f GB free in %s", float64(free)/(1<<30), home)
+	if free < minFreeDiskBytes {
+		result.Status = checkFailed
+		result.Message = message + ", at least 10 GB is needed"
+		result.Remedy = []string{
+			"Remove unused images with 'docker image prune'",
+			"Remove unused models with 'agent llm remove <model>'",
+		}
+		return result
+	}
+
+	result.Status = checkPassed
+	result.Message = message
+	return result
+}
@@ -1,9 +1,12 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 	"text/tabwriter"
+	"text/template"
 	"time"
 
 	"github.com/pxkundu/agent-as-code/internal/registry"
@@ -22,24 +25,38 @@ Examples:
   agent images
   agent images --filter "name=my-agent"
   agent images --format json
-  agent images -q`,
+  agent images --format "{{.Repository}}:{{.Tag}}"
+  agent images --format "{{.ID}}\t{{.Size}}"
+  agent images --format "{{json .}}"
+  agent images --digests
+  agent images --no-trunc
+  agent images -q
+
+--format accepts table (the default), json, or a Go template that is
+executed once per image. Templates can use .Repository, .Tag, .Digest,
+.ID, .Created, .CreatedAt and .Size, and {{json .}} prints an image as
+JSON. \t in a template is printed as a tab.`,
 	RunE: runImages,
 }
 
 var (
-	imagesFilter []string
-	imagesFormat string
-	imagesQuiet  bool
-	imagesAll    bool
+	imagesFilter  []string
+	imagesFormat  string
+	imagesQuiet   bool
+	imagesAll     bool
+	imagesDigests bool
+	imagesNoTrunc bool
 )
 
 func init() {
 	rootCmd.AddCommand(imagesCmd)
 
 	imagesCmd.Flags().StringSliceVar(&imagesFilter, "filter", []string{}, "filter output based on conditions provided")
-	imagesCmd.Flags().StringVar(&imagesFormat, "format", "table", "pretty-print images using a Go template")
+	imagesCmd.Flags().StringVar(&imagesFormat, "format", "table", "output format: table, json, or a Go template such as '{{.Repository}}:{{.Tag}}'")
 	imagesCmd.Flags().BoolVarP(&imagesQuiet, "quiet", "q", false, "only show image IDs")
 	imagesCmd.Flags().BoolVarP(&imagesAll, "all", "a", false, "show all images (default hides intermediate images)")
+	imagesCmd.Flags().BoolVar(&imagesDigests, "digests", false, "show digests")
+	imagesCmd.Flags().BoolVar(&imagesNoTrunc, "no-trunc", false, "don't truncate image IDs")
 }
 
 func runImages(cmd *cobra.Command, args []string) error {
@@ -69,23 +86,88 @@ func runImages(cmd *cobra.Command, args []string) error {
 	switch {
 	case imagesQuiet:
 		for _, image := range images {
-			fmt.Println(image.ID[:12])
+			fmt.Println(displayImageID(image.ID))
 		}
 	case imagesFormat == "json":
 		return printImagesJSON(images)
-	default:
+	case strings.Contains(imagesFormat, "{{"):
+		return printImagesTemplate(images, imagesFormat)
+	case imagesFormat == "table":
 		return printImagesTable(images)
+	default:
+		return fmt.Errorf("unknown format '%s': use table, json or a Go template", imagesFormat)
 	}
 
 	return nil
 }
 
+// ImageTemplateData holds the fields available to --format templates,
+// named like the Docker CLI's
+type ImageTemplateData struct {
+	Repository string `json:"Repository"`
+	Tag        string `json:"Tag"`
+	Digest     string `json:"Digest"`
+	ID         string `json:"ID"`
+	Created    string `json:"Created"`   // relative, e.g. "2 hours ago"
+	CreatedAt  string `json:"CreatedAt"` // RFC 3339
+	Size       string `json:"Size"`
+}
+
+// newImageTemplateData formats image the way the table does
+func newImageTemplateData(image registry.ImageInfo) ImageTemplateData {
+	data := ImageTemplateData{
+		Repository: image.Repository,
+		Tag:        image.Tag,
+		Digest:     image.Digest,
+		ID:         displayImageID(image.ID),
+		Created:    formatTime(image.Created),
+		CreatedAt:  image.Created.Format(time.RFC3339),
+		Size:       formatSize(image.Size),
+	}
+	if data.Repository == "" {
+		data.Repository = "<none>"
+	}
+	if data.Tag == "" {
+		data.Tag = "<none>"
+	}
+	if data.Digest == "" {
+		data.Digest = "<none>"
+	}
+	return data
+}
+
+// printImagesTemplate prints one line per image, executing format as a
+// text/template against its ImageTemplateData
+func printImagesTemplate(images []registry.ImageInfo, format string) error {
+	tmpl, err := template.New("format").Funcs(template.FuncMap{
+		"json": func(v interface{}) (string, error) {
+			data, err := json.Marshal(v)
+			return string(data), err
+		},
+	}).Parse(strings.ReplaceAll(format, `\t`, "\t"))
+	if err != nil {
+		return fmt.Errorf("invalid --format template: %w", err)
+	}
+
+	for _, image := range images {
+		var line strings.Builder
+		if err := tmpl.Execute(&line, newImageTemplateData(image)); err != nil {
+			return fmt.Errorf("failed to execute --format template: %w", err)
+		}
+		fmt.Println(line.String())
+	}
+	return nil
+}
+
 func printImagesTable(images []registry.ImageInfo) error {
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	defer w.Flush()
 
 	// Header
-	fmt.Fprintln(w, "REPOSITORY\tTAG\tIMAGE ID\tCREATED\tSIZE")
+	if imagesDigests {
+		fmt.Fprintln(w, "REPOSITORY\tTAG\tDIGEST\tIMAGE ID\tCREATED\tSIZE")
+	} else {
+		fmt.Fprintln(w, "REPOSITORY\tTAG\tIMAGE ID\tCREATED\tSIZE")
+	}
 
 	// Rows
 	for _, image := range images {
@@ -102,13 +184,47 @@ func printImagesTable(images []registry.ImageInfo) error {
 		created := formatTime(image.Created)
 		size := formatSize(image.Size)
 
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
-			repository, tag, image.ID[:12], created, size)
+		if imagesDigests {
+			digest := image.Digest
+			if digest == "" {
+				digest = "<none>"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+				repository, tag, digest, displayImageID(image.ID), created, size)
+		} else {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+				repository, tag, displayImageID(image.ID), created, size)
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	// An image with several tags is listed once per tag but counted once
+	var total int64
+	seen := make(map[string]bool)
+	for _, image := range images {
+		if !seen[image.ID] {
+			seen[image.ID] = true
+			total += image.Size
+		}
 	}
+	fmt.Printf("\nTotal: %d images, %s\n", len(seen), formatSize(total))
 
 	return nil
 }
 
+// displayImageID returns the 12-character short ID, or the full
+// 64-character ID with --no-trunc
+func displayImageID(id string) string {
+	id = strings.TrimPrefix(id, "sha256:")
+	if !imagesNoTrunc && len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
+
 func printImagesJSON(images []registry.ImageInfo) error {
 	// Simple JSON output (in a real implementation, use json.Marshal)
 	fmt.Println("[")
@@ -117,6 +233,7 @@ func printImagesJSON(images []registry.ImageInfo) error {
 		fmt.Printf("    \"id\": \"%s\",\n", image.ID)
 		fmt.Printf("    \"repository\": \"%s\",\n", image.Repository)
 		fmt.Printf("    \"tag\": \"%s\",\n", image.Tag)
+		fmt.Printf("    \"digest\": \"%s\",\n", image.Digest)
 		fmt.Printf("    \"created\": \"%s\",\n", image.Created.Format(time.RFC3339))
 		fmt.Printf("    \"size\": %d\n", image.Size)
 		if i < len(images)-1 {
@@ -3,8 +3,10 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
+	"github.com/pxkundu/agent-as-code/internal/parser"
 	"github.com/pxkundu/agent-as-code/internal/templates"
 	"github.com/spf13/cobra"
 )
@@ -21,15 +23,32 @@ and template-specific implementation files.
 Examples:
   agent init my-chatbot --template chatbot
   agent init sentiment-analyzer --template sentiment
-  agent init my-agent --runtime python`,
+  agent init my-agent --runtime python
+  agent init my-agent --template support-bot --template-dir ~/src/org-templates
+  agent init my-agent --template chatbot --with-ci github
+  agent init support-bot --template chatbot --monorepo
+  agent init my-agent --template chatbot --modern-python
+
+With --monorepo the project is created in agents/<NAME>/ and added to the
+agents.yaml manifest in the current directory, which is created if needed.
+List the agents with 'agent list-all' and build them all with
+'agent build --all'.
+
+--modern-python lists the Python dependencies in a pyproject.toml instead
+of requirements.txt, with a uv.lock placeholder for 'uv lock' to fill in.
+'agent build' then installs them with uv.`,
 	Args: cobra.ExactArgs(1),
 	RunE: runInit,
 }
 
 var (
-	initTemplate string
-	initRuntime  string
-	initModel    string
+	initTemplate    string
+	initTemplateDir string
+	initRuntime     string
+	initModel       string
+	initWithCI      string
+	initMonorepo    bool
+	initModernPy    bool
 )
 
 func init() {
@@ -38,6 +57,10 @@ func init() {
 	initCmd.Flags().StringVarP(&initTemplate, "template", "t", "", "template to use (chatbot, sentiment, summarizer, translator, data-analyzer, content-gen)")
 	initCmd.Flags().StringVarP(&initRuntime, "runtime", "r", "python", "runtime environment (python, nodejs, go)")
 	initCmd.Flags().StringVarP(&initModel, "model", "m", "openai/gpt-4", "default model to use (supports local models like 'local/llama2')")
+	initCmd.Flags().StringVar(&initTemplateDir, "template-dir", "", "directory containing custom templates (one subdirectory per template)")
+	initCmd.Flags().StringVar(&initWithCI, "with-ci", "", "generate a CI pipeline (github, gitlab)")
+	initCmd.Flags().BoolVar(&initMonorepo, "monorepo", false, "create the agent in agents/<NAME>/ and list it in agents.yaml")
+	initCmd.Flags().BoolVar(&initModernPy, "modern-python", false, "use pyproject.toml and uv instead of requirements.txt and pip")
 }
 
 func runInit(cmd *cobra.Command, args []string) error {
@@ -48,18 +71,44 @@ func runInit(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("agent name cannot be empty")
 	}
 
+	// Validate CI platform
+	if initWithCI != "" && !isCIPlatform(initWithCI) {
+		return fmt.Errorf("invalid CI platform '%s'. Valid platforms: %v", initWithCI, templates.CIPlatforms())
+	}
+
+	if initModernPy && initRuntime != "python" {
+		return fmt.Errorf("--modern-python requires the python runtime")
+	}
+
+	// Validate custom template directory
+	if initTemplateDir != "" {
+		info, err := os.Stat(initTemplateDir)
+		if err != nil || !info.IsDir() {
+			return fmt.Errorf("template directory '%s' not found", initTemplateDir)
+		}
+	}
+
+	// Monorepo agents live under agents/
+	projectDir := agentName
+	if initMonorepo {
+		projectDir = filepath.Join("agents", agentName)
+	}
+
 	// Check if directory already exists
-	if _, err := os.Stat(agentName); !os.IsNotExist(err) {
-		return fmt.Errorf("directory '%s' already exists", agentName)
+	if _, err := os.Stat(projectDir); !os.IsNotExist(err) {
+		return fmt.Errorf("directory '%s' already exists", projectDir)
 	}
 
 	// Create agent directory
-	if err := os.MkdirAll(agentName, 0755); err != nil {
+	if err := os.MkdirAll(projectDir, 0755); err != nil {
 		return fmt.Errorf("failed to create directory: %w", err)
 	}
 
 	// Initialize template manager
 	templateManager := templates.New()
+	if initTemplateDir != "" {
+		templateManager = templates.NewWithDir(initTemplateDir)
+	}
 
 	// Template validation is now handled by the template manager with fallback logic
 
@@ -83,22 +132,52 @@ func runInit(cmd *cobra.Command, args []string) error {
 		Template: template,
 		Runtime:  initRuntime,
 		Model:    initModel,
+
+		ModernPython: initModernPy,
 	}
 
 	// Generate project files
-	if err := templateManager.Generate(agentName, config); err != nil {
+	if err := templateManager.Generate(projectDir, config); err != nil {
 		// Clean up on error
-		os.RemoveAll(agentName)
+		os.RemoveAll(projectDir)
 		return fmt.Errorf("failed to generate project: %w", err)
 	}
 
+	// Generate CI pipeline
+	if initWithCI != "" {
+		if err := templateManager.GenerateCI(initWithCI, projectDir, config); err != nil {
+			os.RemoveAll(projectDir)
+			return fmt.Errorf("failed to generate CI pipeline: %w", err)
+		}
+	}
+
+	// List the agent in the monorepo manifest
+	if initMonorepo {
+		if err := addToAgentList(parser.AgentListItem{
+			Name:     agentName,
+			Path:     filepath.ToSlash(projectDir),
+			Template: template,
+			Model:    initModel,
+		}); err != nil {
+			os.RemoveAll(projectDir)
+			return err
+		}
+	}
+
 	// Success message
 	fmt.Printf("✅ Agent project '%s' created successfully!\n\n", agentName)
+	if initMonorepo {
+		fmt.Printf("📋 Added to %s\n\n", parser.AgentListFile)
+	}
 	fmt.Printf("Next steps:\n")
-	fmt.Printf("  cd %s\n", agentName)
+	fmt.Printf("  cd %s\n", projectDir)
 	fmt.Printf("  agent build -t %s:latest .\n", agentName)
 	fmt.Printf("  agent run %s:latest\n", agentName)
 
+	if initWithCI != "" {
+		fmt.Printf("\n🔁 A %s CI pipeline was generated. Set the AGENT_REGISTRY_TOKEN secret to push images from main.\n", initWithCI)
+	}
+
 	if template != "basic" {
 		fmt.Printf("\n📖 Check the README.md for template-specific instructions.\n")
 	}
@@ -106,6 +185,29 @@ func runInit(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// addToAgentList adds an agent to agents.yaml in the current directory,
+// creating the manifest if it does not exist
+func addToAgentList(item parser.AgentListItem) error {
+	list := parser.NewAgentList()
+	if _, err := os.Stat(parser.AgentListFile); err == nil {
+		if list, err = parser.LoadAgentList(parser.AgentListFile); err != nil {
+			return err
+		}
+	}
+
+	list.Upsert(item)
+	return list.Save(parser.AgentListFile)
+}
+
+func isCIPlatform(platform string) bool {
+	for _, valid := range templates.CIPlatforms() {
+		if platform == valid {
+			return true
+		}
+	}
+	return false
+}
+
 func validateTemplate(template string) error {
 	validTemplates := []string{"basic", "chatbot", "sentiment", "summarizer", "translator", "data-analyzer", "content-gen"}
 
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/pxkundu/agent-as-code/internal/imagediff"
 	"github.com/spf13/cobra"
 )
 
@@ -16,14 +17,33 @@ var inspectCmd = &cobra.Command{
 This command displays comprehensive information about the specified agent,
 including configuration details, runtime settings, capabilities, and metadata.
 
+--diff compares the image with a base image instead, such as an earlier
+build or the image it was built from: the files added, removed and
+modified in its filesystem, and its environment variable and label
+changes. --diff-env and --diff-labels limit the comparison to environment
+variables or labels, which is much faster for large images.
+
 Examples:
   agent inspect my-agent:latest
   agent inspect my-agent:v1.0.0
-  agent inspect --format json my-agent:latest`,
+  agent inspect --format json my-agent:latest
+  agent inspect my-agent:v1.1.0 --diff my-agent:v1.0.0
+  agent inspect my-agent:latest --diff python:3.11-slim --format json
+  agent inspect my-agent:v1.1.0 --diff my-agent:v1.0.0 --diff-env --diff-labels`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		tag := args[0]
 		format, _ := cmd.Flags().GetString("format")
+		diffBase, _ := cmd.Flags().GetString("diff")
+		diffEnv, _ := cmd.Flags().GetBool("diff-env")
+		diffLabels, _ := cmd.Flags().GetBool("diff-labels")
+
+		if diffBase != "" {
+			return inspectDiff(tag, diffBase, format, diffEnv, diffLabels)
+		}
+		if diffEnv || diffLabels {
+			return fmt.Errorf("--diff-env and --diff-labels require --diff")
+		}
 		
 		fmt.Printf("🔍 Inspecting agent: %s\n", tag)
 		
@@ -45,6 +65,9 @@ Examples:
 
 func init() {
 	inspectCmd.Flags().String("format", "table", "output format (table, json)")
+	inspectCmd.Flags().String("diff", "", "show what changed relative to this base image")
+	inspectCmd.Flags().Bool("diff-env", false, "with --diff, compare only environment variables")
+	inspectCmd.Flags().Bool("diff-labels", false, "with --diff, compare only labels")
 	rootCmd.AddCommand(inspectCmd)
 }
 
@@ -236,3 +259,84 @@ func displayTable(info *AgentInfo) error {
 	
 	return nil
 }
+
+// inspectDiff shows the changes from base to image. With diffEnv or
+// diffLabels, only those are compared and the filesystems are not read.
+func inspectDiff(image, base, format string, diffEnv, diffLabels bool) error {
+	differ, err := imagediff.NewDiffer()
+	if err != nil {
+		return err
+	}
+
+	var result *imagediff.DiffResult
+	if diffEnv || diffLabels {
+		result, err = differ.CompareConfig(base, image)
+	} else {
+		if format != "json" {
+			fmt.Printf("🔍 Comparing %s with %s (exporting both images)\n", image, base)
+		}
+		result, err = differ.Compare(base, image)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to diff images: %v", err)
+	}
+
+	files := !diffEnv && !diffLabels
+	if !files && !diffEnv {
+		result.EnvAdded, result.EnvRemoved, result.EnvModified = nil, nil, nil
+	}
+	if !files && !diffLabels {
+		result.LabelsAdded, result.LabelsRemoved, result.LabelsModified = nil, nil, nil
+	}
+
+	if format == "json" {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if files {
+		fmt.Printf("\n📁 Files (%d added, %d removed, %d modified)\n", len(result.Added), len(result.Removed), len(result.Modified))
+		fmt.Printf("========\n")
+		for _, name := range result.Added {
+			fmt.Printf("  + %s\n", name)
+		}
+		for _, name := range result.Removed {
+			fmt.Printf("  - %s\n", name)
+		}
+		for _, name := range result.Modified {
+			fmt.Printf("  ~ %s\n", name)
+		}
+	}
+	if files || diffEnv {
+		fmt.Printf("\n🔑 Environment\n")
+		fmt.Printf("==============\n")
+		printChanges(result.EnvAdded, result.EnvRemoved, result.EnvModified)
+	}
+	if files || diffLabels {
+		fmt.Printf("\n🏷️  Labels\n")
+		fmt.Printf("==========\n")
+		printChanges(result.LabelsAdded, result.LabelsRemoved, result.LabelsModified)
+	}
+
+	return nil
+}
+
+func printChanges(added, removed, modified []imagediff.Change) {
+	if len(added)+len(removed)+len(modified) == 0 {
+		fmt.Println("  no changes")
+		return
+	}
+	for _, change := range added {
+		fmt.Printf("  + %s=%s\n", change.Key, change.New)
+	}
+	for _, change := range removed {
+		fmt.Printf("  - %s=%s\n", change.Key, change.Old)
+	}
+	for _, change := range modified {
+		fmt.Printf("  ~ %s: %s -> %s\n", change.Key, change.Old, change.New)
+	}
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/pxkundu/agent-as-code/internal/lint"
+	"github.com/spf13/cobra"
+)
+
+var lintCmd = &cobra.Command{
+	Use:   "lint [PATH]",
+	Short: "Check an agent project against best-practice rules",
+	Long: `Check an agent project against opinionated best-practice rules.
+
+Where agent.yaml validation checks the schema, lint checks the project:
+
+  pinned-base-image       Dockerfile FROM images are pinned to a digest
+  pinned-requirements     requirements.txt versions are pinned or bounded
+  agent-description       agent.yaml has metadata.description
+  secret-env-from-secret  env vars named *KEY* or *SECRET* use from: secret
+  privileged-port         container ports are 1024 or above
+
+Violations are printed as file:line: severity: message, which most CI
+tools understand. The command exits with status 1 if any violation has
+severity error.
+
+Rules are configured in ~/.agent/lint.yaml (or the file given by --rules):
+
+  rules:
+    pinned-base-image:
+      enabled: false
+    privileged-port:
+      severity: error
+  secretPatterns: [KEY, SECRET, TOKEN, PASSWORD]
+
+Examples:
+  agent lint
+  agent lint ./my-agent
+  agent lint --rules ci-lint.yaml .`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := "."
+		if len(args) == 1 {
+			path = args[0]
+		}
+		rules, _ := cmd.Flags().GetString("rules")
+
+		failed, err := runLint(path, rules)
+		if err != nil {
+			return err
+		}
+		if failed {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("lint found errors")
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+
+	lintCmd.Flags().String("rules", "", "lint configuration file (default: ~/.agent/lint.yaml)")
+}
+
+// runLint lints the project at path and reports whether any violation has
+// severity error
+func runLint(path, rulesFile string) (bool, error) {
+	if rulesFile == "" {
+		defaultPath, err := lint.DefaultConfigPath()
+		if err != nil {
+			return false, err
+		}
+		rulesFile = defaultPath
+	}
+
+	config, err := lint.LoadConfig(rulesFile)
+	if err != nil {
+		return false, err
+	}
+
+	violations, err := lint.New(config).Lint(path)
+	if err != nil {
+		return false, err
+	}
+
+	failed := false
+	for _, violation := range violations {
+		fmt.Println(violation)
+		if violation.Severity == lint.SeverityError {
+			failed = true
+		}
+	}
+
+	if len(violations) == 0 {
+		fmt.Println("✅ No lint violations found")
+	}
+
+	return failed, nil
+}
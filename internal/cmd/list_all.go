@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/pxkundu/agent-as-code/internal/parser"
+	"github.com/spf13/cobra"
+)
+
+var listAllCmd = &cobra.Command{
+	Use:   "list-all",
+	Short: "List the agents of a monorepo",
+	Long: `List the agents in the agents.yaml manifest of a monorepo.
+
+Agents are added to agents.yaml by 'agent init --monorepo'.
+
+Examples:
+  agent list-all
+  agent list-all --file path/to/agents.yaml`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file, _ := cmd.Flags().GetString("file")
+		return listAllAgents(file)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(listAllCmd)
+
+	listAllCmd.Flags().StringP("file", "f", parser.AgentListFile, "agents manifest to read")
+}
+
+func listAllAgents(file string) error {
+	list, err := parser.LoadAgentList(file)
+	if err != nil {
+		return err
+	}
+
+	if len(list.Items) == 0 {
+		fmt.Printf("No agents listed in %s\n", file)
+		fmt.Println("\n💡 Add one with: agent init my-agent --monorepo")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tPATH\tTEMPLATE\tMODEL")
+	for _, item := range list.Items {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", item.Name, item.Path, valueOrDash(item.Template), valueOrDash(item.Model))
+	}
+	return w.Flush()
+}
+
+// valueOrDash returns "-" for empty table cells
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
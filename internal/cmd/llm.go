@@ -1,11 +1,15 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"sort"
 	"strings"
 
 	"github.com/pxkundu/agent-as-code/internal/llm"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 var llmCmd = &cobra.Command{
@@ -37,9 +41,16 @@ var llmListCmd = &cobra.Command{
 	Long: `List all available local LLM models.
 
 This command shows all models that are currently available on your
-local system through Ollama or other local backends.`,
+local system through Ollama or other local backends.
+
+Examples:
+  agent llm list
+  agent llm list --sort-by size
+  agent llm list --group-by family`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return listLocalModels()
+		groupBy, _ := cmd.Flags().GetString("group-by")
+		sortBy, _ := cmd.Flags().GetString("sort-by")
+		return listLocalModels(groupBy, sortBy)
 	},
 }
 
@@ -54,11 +65,18 @@ for AI agent development without API costs.
 Examples:
   agent llm pull llama2
   agent llm pull llama2:7b
-  agent llm pull mistral:7b`,
+  agent llm pull mistral:7b
+  agent llm pull llama2:7b --verify
+
+With --verify the SHA-256 of the downloaded weights is compared with the
+digest in the model's Ollama manifest. A model that does not match is
+removed again. If the manifest cannot be read, e.g. because Ollama runs in
+Docker or on another host, the command fails and the model is kept.`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		modelName := args[0]
-		return pullLocalModel(modelName)
+		verify, _ := cmd.Flags().GetBool("verify")
+		return pullLocalModel(modelName, verify)
 	},
 }
 
@@ -85,15 +103,39 @@ var llmRemoveCmd = &cobra.Command{
 	Short: "Remove a local model",
 	Long: `Remove a local model to free up disk space.
 
-This command removes the specified model from your local system.
+This command removes the specified model from your local system. With
+--all or --pattern it removes several models after listing them and asking
+for confirmation.
 
 Examples:
   agent llm remove llama2
-  agent llm remove mistral:7b`,
-	Args: cobra.ExactArgs(1),
+  agent llm remove mistral:7b
+  agent llm remove --pattern 'llama2:*'
+  agent llm remove --all --yes`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		modelName := args[0]
-		return removeLocalModel(modelName)
+		all, _ := cmd.Flags().GetBool("all")
+		pattern, _ := cmd.Flags().GetString("pattern")
+		yes, _ := cmd.Flags().GetBool("yes")
+
+		selectors := len(args)
+		if all {
+			selectors++
+		}
+		if pattern != "" {
+			selectors++
+		}
+		if selectors != 1 {
+			return fmt.Errorf("specify exactly one of MODEL, --all or --pattern")
+		}
+
+		if all {
+			pattern = "*"
+		}
+		if pattern != "" {
+			return removeLocalModels(pattern, yes)
+		}
+		return removeLocalModel(args[0])
 	},
 }
 
@@ -125,10 +167,12 @@ var llmInfoCmd = &cobra.Command{
 
 This command displays comprehensive information about the specified
 model, including size, modification date, and other details.
+With --verbose it also shows the model file location, parameter size,
+license, template, system prompt and all Modelfile parameters.
 
 Examples:
   agent llm info llama2
-  agent llm info mistral:7b`,
+  agent llm info mistral:7b --verbose`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		modelName := args[0]
@@ -170,11 +214,33 @@ Use cases: chatbot, sentiment-analyzer, code-assistant, data-analyzer,
 Examples:
   agent llm create-agent chatbot
   agent llm create-agent sentiment-analyzer --model local/llama2
-  agent llm create-agent code-assistant --optimize --test`,
+  agent llm create-agent code-assistant --optimize --test
+  agent llm create-agent chatbot --prometheus
+  agent llm create-agent chatbot --with-auth apikey
+  agent llm create-agent qa-system --enable-a2a
+  agent llm create-agent chatbot --strict-codegen
+
+--with-auth protects /process and /metrics of the generated agent:
+  apikey   X-API-Key header checked against AGENT_API_KEY
+  jwt      Bearer JWTs verified with JWT_SECRET using python-jose
+  oauth2   Bearer access tokens verified against OAUTH2_JWKS_URL
+/health stays open for container health checks.
+
+--enable-a2a adds an agent2agent endpoint at /a2a/messages, for messages
+from other agents in a compose stack, and registers the agent with the
+registry in A2A_REGISTRY_URL at startup (see 'agent llm agent2agent').
+
+The generated code is checked with python3 -m py_compile and, when
+installed, flake8 for undefined names. Problems are reported as warnings;
+--strict-codegen makes them fail the command.`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		useCase := args[0]
-		return createIntelligentAgent(useCase)
+		prometheus, _ := cmd.Flags().GetBool("prometheus")
+		authMode, _ := cmd.Flags().GetString("with-auth")
+		enableA2A, _ := cmd.Flags().GetBool("enable-a2a")
+		strict, _ := cmd.Flags().GetBool("strict-codegen")
+		return createIntelligentAgent(useCase, llm.CreateAgentOptions{Prometheus: prometheus, AuthMode: authMode, A2A: enableA2A, StrictCodegen: strict})
 	},
 }
 
@@ -193,11 +259,18 @@ This command analyzes the model and use case to:
 Examples:
   agent llm optimize llama2 chatbot
   agent llm optimize mistral:7b code-generation
-  agent llm optimize codellama:13b debugging`,
+  agent llm optimize codellama:13b debugging
+  agent llm optimize llama2 chatbot --auto-tune
+  agent llm optimize llama2 qa-system --auto-tune --eval-prompts prompts.txt`,
 	Args: cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		modelName := args[0]
 		useCase := args[1]
+		autoTune, _ := cmd.Flags().GetBool("auto-tune")
+		if autoTune {
+			evalPromptsFile, _ := cmd.Flags().GetString("eval-prompts")
+			return autoTuneModel(modelName, useCase, evalPromptsFile)
+		}
 		return optimizeModelForUseCase(modelName, useCase)
 	},
 }
@@ -214,12 +287,21 @@ This command tests models across multiple dimensions:
 - Cost-benefit analysis
 - Performance recommendations
 
+--format markdown writes the results as a GitHub-flavored Markdown table,
+followed by the OS, CPU count, RAM and Ollama version they were measured
+with, ready to paste into an issue. Progress messages then go to stderr.
+
 Examples:
   agent llm benchmark
-  agent llm benchmark --tasks chatbot,code,analysis
-  agent llm benchmark --output json`,
+  agent llm benchmark --format markdown
+  agent llm benchmark --format markdown --output benchmark.md`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return benchmarkAllModels()
+		format, _ := cmd.Flags().GetString("format")
+		output, _ := cmd.Flags().GetString("output")
+		if format != "text" && format != "markdown" {
+			return fmt.Errorf("unsupported format '%s' (valid: text, markdown)", format)
+		}
+		return benchmarkAllModels(format, output)
 	},
 }
 
@@ -236,13 +318,26 @@ This command:
 - Provides performance metrics
 - Generates deployment report
 
+The agent is deployed to the Docker daemon in DOCKER_HOST, or the local
+one if it is unset, using DOCKER_TLS_VERIFY and DOCKER_CERT_PATH for TLS.
+--docker-host overrides DOCKER_HOST, and --context deploys to the daemon
+of a Docker context (see 'docker context ls'), including its TLS
+certificates. The agent image must exist on that daemon.
+
 Examples:
   agent llm deploy-agent my-chatbot
+  agent llm deploy-agent my-chatbot --docker-host tcp://build-box:2376
+  agent llm deploy-agent my-chatbot --context staging
   agent llm deploy-agent sentiment-analyzer --test-suite comprehensive
   agent llm deploy-agent code-assistant --monitor`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		agentName := args[0]
+		dockerHost, _ := cmd.Flags().GetString("docker-host")
+		dockerContext, _ := cmd.Flags().GetString("context")
+		if err := applyDockerTarget(dockerHost, dockerContext); err != nil {
+			return err
+		}
 		return deployAndTestAgent(agentName)
 	},
 }
@@ -259,14 +354,29 @@ This command provides deep insights into:
 - Optimization opportunities
 - Integration recommendations
 
+Performance is measured with a quick two-task benchmark by default. Use
+--full to run every benchmark task, or --quick=false to skip the benchmark
+and estimate performance from the model size.
+
 Examples:
   agent llm analyze llama2
+  agent llm analyze llama2 --full
+  agent llm analyze llama2 --quick=false
   agent llm analyze mistral:7b --detailed
   agent llm analyze codellama:13b --capabilities`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		modelName := args[0]
-		return analyzeModelCapabilities(modelName)
+		quick, _ := cmd.Flags().GetBool("quick")
+		full, _ := cmd.Flags().GetBool("full")
+
+		mode := llm.BenchmarkNone
+		if full {
+			mode = llm.BenchmarkFull
+		} else if quick {
+			mode = llm.BenchmarkQuick
+		}
+		return analyzeModelCapabilities(modelName, mode)
 	},
 }
 
@@ -289,9 +399,39 @@ func init() {
 	llmCmd.AddCommand(llmBenchmarkCmd)
 	llmCmd.AddCommand(llmDeployAgentCmd)
 	llmCmd.AddCommand(llmAnalyzeCmd)
+
+	llmPullCmd.Flags().Bool("verify", false, "check the model's digest after pulling")
+
+	llmBenchmarkCmd.Flags().String("format", "text", "output format (text, markdown)")
+	llmBenchmarkCmd.Flags().String("output", "", "write the results to a file instead of stdout")
+
+	llmAnalyzeCmd.Flags().Bool("quick", true, "measure performance with the simple question and code generation benchmark tasks")
+	llmAnalyzeCmd.Flags().Bool("full", false, "measure performance with every benchmark task")
+
+	llmRemoveCmd.Flags().Bool("all", false, "remove all local models")
+	llmRemoveCmd.Flags().String("pattern", "", "remove the models whose names match a glob pattern")
+	llmRemoveCmd.Flags().BoolP("yes", "y", false, "remove without asking for confirmation")
+
+	llmListCmd.Flags().String("group-by", "", "group models by family and show disk usage per family")
+	llmListCmd.Flags().String("sort-by", "name", "sort models by name, size or date")
+
+	llmCreateAgentCmd.Flags().Bool("prometheus", false, "expose Prometheus metrics at /metrics in the generated agent")
+	llmCreateAgentCmd.Flags().String("with-auth", "", "protect the generated agent's endpoints (apikey, jwt, oauth2)")
+	llmCreateAgentCmd.Flags().Bool("enable-a2a", false, "add agent2agent messaging and registry registration to the generated agent")
+	llmCreateAgentCmd.Flags().Bool("strict-codegen", false, "fail when the generated code does not pass static analysis")
+
+	llmDeployAgentCmd.Flags().String("docker-host", "", "Docker daemon to deploy to (unix:///path/to/docker.sock or tcp://host:port), overrides DOCKER_HOST")
+	llmDeployAgentCmd.Flags().String("context", "", "Docker context whose daemon to deploy to")
+
+	llmOptimizeCmd.Flags().Bool("auto-tune", false, "A/B test temperature and top_p combinations instead of using the static mapping")
+	llmOptimizeCmd.Flags().String("eval-prompts", "", "file with one eval prompt per line for --auto-tune")
 }
 
-func listLocalModels() error {
+func listLocalModels(groupBy, sortBy string) error {
+	if groupBy != "" && groupBy != "family" {
+		return fmt.Errorf("invalid group key '%s' (valid: family)", groupBy)
+	}
+
 	manager := llm.NewLocalLLMManager()
 
 	// Check if Ollama is available
@@ -304,6 +444,13 @@ func listLocalModels() error {
 		return nil
 	}
 
+	// Help debug compatibility issues and connections to the wrong server
+	if version, err := manager.GetOllamaVersion(); err == nil {
+		fmt.Printf("Ollama v%s (%s)\n\n", version, manager.URL())
+	} else {
+		fmt.Printf("Ollama at %s\n\n", manager.URL())
+	}
+
 	models, err := manager.ListLocalModels()
 	if err != nil {
 		return fmt.Errorf("failed to list models: %v", err)
@@ -316,23 +463,57 @@ func listLocalModels() error {
 		return nil
 	}
 
+	if err := llm.SortModels(models, sortBy); err != nil {
+		return err
+	}
+
 	fmt.Println("🤖 Available Local Models")
 	fmt.Println("=========================")
 
-	for _, model := range models {
-		fmt.Printf("\n%s\n", model.Name)
-		fmt.Printf("  Size:     %s\n", model.Size)
-		fmt.Printf("  Backend:  %s\n", model.Backend)
-		fmt.Printf("  Status:   %s\n", model.Status)
-		if model.ModifiedAt != "" {
-			fmt.Printf("  Modified: %s\n", model.ModifiedAt)
+	if groupBy == "" {
+		for _, model := range models {
+			printLocalModel(model, "")
 		}
+		return nil
+	}
+
+	groups := llm.GroupModels(models, groupBy)
+	families := make([]string, 0, len(groups))
+	for family := range groups {
+		families = append(families, family)
 	}
+	sort.Strings(families)
 
+	var total int64
+	for _, family := range families {
+		var subtotal int64
+		for _, model := range groups[family] {
+			subtotal += model.SizeBytes
+		}
+		total += subtotal
+
+		fmt.Printf("\n📦 %s (%d models, %s)\n", family, len(groups[family]), formatSize(subtotal))
+		for _, model := range groups[family] {
+			printLocalModel(model, "  ")
+		}
+	}
+
+	fmt.Printf("\nTotal: %d models, %s\n", len(models), formatSize(total))
 	return nil
 }
 
-func pullLocalModel(modelName string) error {
+// printLocalModel prints a model's details with the given indent
+func printLocalModel(model llm.LocalModel, indent string) {
+	fmt.Printf("\n%s%s\n", indent, model.Name)
+	fmt.Printf("%s  Size:     %s\n", indent, model.Size)
+	fmt.Printf("%s  Backend:  %s\n", indent, model.Backend)
+	fmt.Printf("%s  Status:   %s\n", indent, model.Status)
+	if model.ModifiedAt != "" {
+		fmt.Printf("%s  Modified: %s\n", indent, model.ModifiedAt)
+	}
+}
+
+func pullLocalModel(modelName string, verify bool) error {
 	manager := llm.NewLocalLLMManager()
 
 	// Validate model name
@@ -343,11 +524,28 @@ func pullLocalModel(modelName string) error {
 	// Check if model is already available
 	if manager.IsModelAvailable(modelName) {
 		fmt.Printf("ℹ️  Model '%s' is already available\n", modelName)
+	} else if err := manager.PullModel(modelName); err != nil {
+		return err
+	}
+
+	if !verify {
 		return nil
 	}
 
-	// Pull the model
-	return manager.PullModel(modelName)
+	fmt.Printf("🔍 Verifying '%s'...\n", modelName)
+	if err := manager.VerifyModel(modelName); err != nil {
+		// Only corrupt weights are removed; a model that could not be
+		// checked, e.g. because Ollama runs elsewhere, is left in place
+		if !errors.Is(err, llm.ErrDigestMismatch) {
+			return fmt.Errorf("failed to verify '%s': %w", modelName, err)
+		}
+		if rmErr := manager.RemoveModel(modelName); rmErr != nil {
+			return fmt.Errorf("%v (removing the model also failed: %v)", err, rmErr)
+		}
+		return fmt.Errorf("%v; the model was removed", err)
+	}
+	fmt.Printf("✅ Model '%s' matches its manifest digest\n", modelName)
+	return nil
 }
 
 func testLocalModel(modelName string) error {
@@ -374,6 +572,40 @@ func removeLocalModel(modelName string) error {
 	return manager.RemoveModel(modelName)
 }
 
+func removeLocalModels(pattern string, yes bool) error {
+	manager := llm.NewLocalLLMManager()
+
+	names, err := manager.MatchModels(pattern)
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		fmt.Printf("ℹ️  No local models match '%s'\n", pattern)
+		return nil
+	}
+
+	fmt.Printf("The following %d models will be removed:\n", len(names))
+	for _, name := range names {
+		fmt.Printf("  - %s\n", name)
+	}
+	if !yes && !confirm("Remove these models?") {
+		fmt.Println("Aborted")
+		return nil
+	}
+
+	report, err := manager.RemoveModels(names)
+	for _, name := range report.Removed {
+		fmt.Printf("🗑️  Removed %s\n", name)
+	}
+	if err != nil {
+		fmt.Printf("❌ Failed to remove %d of %d models\n", len(report.Failed), len(names))
+		return err
+	}
+
+	fmt.Printf("✅ Removed %d models\n", len(report.Removed))
+	return nil
+}
+
 func recommendModels(useCase string) error {
 	manager := llm.NewLocalLLMManager()
 
@@ -425,13 +657,56 @@ func showModelInfo(modelName string) error {
 	if len(info.Details) > 0 {
 		fmt.Println("\nDetails:")
 		for key, value := range info.Details {
-			fmt.Printf("  %s: %s\n", key, value)
+			fmt.Printf("  %s: %v\n", key, value)
 		}
 	}
 
+	if !viper.GetBool("verbose") {
+		return nil
+	}
+
+	show, err := manager.ShowModel(modelName)
+	if err != nil {
+		return fmt.Errorf("failed to show model: %v", err)
+	}
+	info.ModelfileContent = show.Modelfile
+	info.License = show.License
+	info.Template = show.Template
+	info.System = show.System
+	info.Parameters = show.Parameters
+
+	fmt.Println()
+	if path := show.ModelPath(); path != "" {
+		fmt.Printf("Location:   %s\n", path)
+	}
+	if show.Details.ParameterSize != "" {
+		fmt.Printf("Parameters: %s\n", show.Details.ParameterSize)
+	}
+	if len(show.Details.Families) > 0 {
+		fmt.Printf("Families:   %s\n", strings.Join(show.Details.Families, ", "))
+	}
+
+	printModelSection("Modelfile parameters", info.Parameters)
+	printModelSection("System", info.System)
+	printModelSection("Template", info.Template)
+	printModelSection("License", info.License)
+
 	return nil
 }
 
+// printModelSection prints an indented block of model info, skipping empty
+// values
+func printModelSection(title, content string) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return
+	}
+	fmt.Printf("\n%s:\n", title)
+	for _, line := range strings.Split(content, "\n") {
+		fmt.Printf("  %s\n", line)
+	}
+}
+
 func setupLocalLLM() error {
 	fmt.Println("🚀 Setting up Local LLM Environment")
 	fmt.Println("===================================")
@@ -462,7 +737,7 @@ func setupLocalLLM() error {
 	return nil
 }
 
-func createIntelligentAgent(useCase string) error {
+func createIntelligentAgent(useCase string, options llm.CreateAgentOptions) error {
 	fmt.Printf("🧠 Creating intelligent agent for: %s\n", useCase)
 	fmt.Println("=====================================")
 
@@ -485,7 +760,7 @@ func createIntelligentAgent(useCase string) error {
 	fmt.Printf("🔧 Capabilities: %s\n", strings.Join(creator.GetCapabilities(useCase), ", "))
 
 	// Create intelligent agent
-	agentConfig, err := creator.CreateAgent(useCase, recommendedModel)
+	agentConfig, err := creator.CreateAgent(useCase, recommendedModel, options)
 	if err != nil {
 		return fmt.Errorf("failed to create agent: %v", err)
 	}
@@ -496,6 +771,12 @@ func createIntelligentAgent(useCase string) error {
 	fmt.Printf("🧠 Model: %s\n", agentConfig.Model)
 	fmt.Printf("📚 Dependencies: %d packages\n", len(agentConfig.Dependencies))
 	fmt.Printf("🧪 Test Coverage: %s\n", agentConfig.TestCoverage)
+	if agentConfig.Prometheus {
+		fmt.Printf("📈 Metrics: Prometheus at /metrics\n")
+	}
+	if agentConfig.AuthMode != "" {
+		fmt.Printf("🔐 Authentication: %s (see README.md)\n", agentConfig.AuthMode)
+	}
 
 	fmt.Printf("\n🚀 Next steps:\n")
 	fmt.Printf("   cd %s\n", agentConfig.Name)
@@ -542,9 +823,50 @@ func optimizeModelForUseCase(modelName, useCase string) error {
 	return nil
 }
 
-func benchmarkAllModels() error {
-	fmt.Println("🏁 Running comprehensive model benchmarks")
-	fmt.Println("=======================================")
+func autoTuneModel(modelName, useCase, evalPromptsFile string) error {
+	fmt.Printf("🎛️  Auto-tuning %s for %s\n", modelName, useCase)
+	fmt.Println("=================================")
+
+	var evalPrompts []string
+	if evalPromptsFile != "" {
+		data, err := os.ReadFile(evalPromptsFile)
+		if err != nil {
+			return fmt.Errorf("failed to read eval prompts: %v", err)
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				evalPrompts = append(evalPrompts, line)
+			}
+		}
+	}
+
+	tester := llm.NewABTester()
+	optimization, err := tester.FindOptimalParams(modelName, useCase, evalPrompts)
+	if err != nil {
+		return fmt.Errorf("auto-tune failed: %v", err)
+	}
+
+	fmt.Printf("\n✅ Best parameters found!\n\n")
+	fmt.Printf("🔧 Optimized Parameters:\n")
+	for param, value := range optimization.Parameters {
+		fmt.Printf("  %s: %v\n", param, value)
+	}
+
+	fmt.Printf("\n📊 Result: %s (%s)\n", optimization.QualityImprovement, optimization.ResponseTimeImprovement)
+	fmt.Printf("\n💾 Configuration saved to: %s\n", optimization.ConfigPath)
+
+	return nil
+}
+
+func benchmarkAllModels(format, output string) error {
+	// With Markdown on stdout, stdout carries only the table
+	out := os.Stdout
+	if format == "markdown" && output == "" {
+		out = os.Stderr
+	}
+
+	fmt.Fprintln(out, "🏁 Running comprehensive model benchmarks")
+	fmt.Fprintln(out, "=======================================")
 
 	// Initialize benchmark runner
 	benchmarker := llm.NewModelBenchmarker()
@@ -556,10 +878,10 @@ func benchmarkAllModels() error {
 	}
 
 	if len(models) == 0 {
-		fmt.Println("ℹ️  No models available for benchmarking")
-		fmt.Println("💡 Pull some models first:")
-		fmt.Println("   agent llm pull llama2")
-		fmt.Println("   agent llm pull mistral:7b")
+		fmt.Fprintln(out, "ℹ️  No models available for benchmarking")
+		fmt.Fprintln(out, "💡 Pull some models first:")
+		fmt.Fprintln(out, "   agent llm pull llama2")
+		fmt.Fprintln(out, "   agent llm pull mistral:7b")
 		return nil
 	}
 
@@ -570,25 +892,48 @@ func benchmarkAllModels() error {
 	}
 
 	// Display results
-	fmt.Printf("\n📊 Benchmark Results\n")
-	fmt.Println("===================")
+	var report strings.Builder
+	if format == "markdown" {
+		report.WriteString(llm.FormatBenchmarkMarkdown(results, benchmarker.SystemInfo()))
+	} else {
+		fmt.Fprintf(&report, "\n📊 Benchmark Results\n")
+		fmt.Fprintln(&report, "===================")
+
+		for _, result := range results {
+			fmt.Fprintf(&report, "\n🤖 %s\n", result.ModelName)
+			fmt.Fprintf(&report, "  ⏱️  Response Time: %s\n", result.AverageResponseTime)
+			fmt.Fprintf(&report, "  🧠 Memory Usage: %s\n", result.MemoryUsage)
+			fmt.Fprintf(&report, "  📈 Throughput: %s\n", result.Throughput)
+			fmt.Fprintf(&report, "  🎯 Quality Score: %s\n", result.QualityScore)
+			fmt.Fprintf(&report, "  💰 Cost Efficiency: %s\n", result.CostEfficiency)
+		}
+	}
 
-	for _, result := range results {
-		fmt.Printf("\n🤖 %s\n", result.ModelName)
-		fmt.Printf("  ⏱️  Response Time: %s\n", result.AverageResponseTime)
-		fmt.Printf("  🧠 Memory Usage: %s\n", result.MemoryUsage)
-		fmt.Printf("  📈 Throughput: %s\n", result.Throughput)
-		fmt.Printf("  🎯 Quality Score: %s\n", result.QualityScore)
-		fmt.Printf("  💰 Cost Efficiency: %s\n", result.CostEfficiency)
+	if output != "" {
+		if err := os.WriteFile(output, []byte(report.String()), 0644); err != nil {
+			return fmt.Errorf("failed to write results: %v", err)
+		}
+		fmt.Fprintf(out, "\n💾 Results written to %s\n", output)
+	} else {
+		fmt.Print(report.String())
+	}
+
+	// Save results for the leaderboard
+	if benchmarkDir, err := llm.DefaultBenchmarkDir(); err == nil {
+		if err := benchmarker.SaveResults(results, benchmarkDir); err != nil {
+			fmt.Fprintf(out, "⚠️  Failed to save benchmark results: %v\n", err)
+		}
 	}
 
 	// Generate recommendations
 	recommendations := benchmarker.GenerateRecommendations(results)
-	fmt.Printf("\n💡 Recommendations:\n")
+	fmt.Fprintf(out, "\n💡 Recommendations:\n")
 	for _, rec := range recommendations {
-		fmt.Printf("  • %s\n", rec)
+		fmt.Fprintf(out, "  • %s\n", rec)
 	}
 
+	fmt.Fprintf(out, "\n💡 Use 'agent llm leaderboard' to compare results across runs\n")
+
 	return nil
 }
 
@@ -598,6 +943,9 @@ func deployAndTestAgent(agentName string) error {
 
 	// Initialize deployment manager
 	deployer := llm.NewAgentDeployer()
+	if host := deployer.DockerHost(); host != "" {
+		fmt.Printf("🐳 Docker host: %s\n", host)
+	}
 
 	// Check if agent project exists
 	if !deployer.AgentExists(agentName) {
@@ -634,7 +982,7 @@ func deployAndTestAgent(agentName string) error {
 	// Display results
 	fmt.Printf("\n🎉 Agent deployment successful!\n")
 	fmt.Printf("🐳 Container: %s\n", container.Name)
-	fmt.Printf("🔗 Access: http://localhost:%s\n", container.Port)
+	fmt.Printf("🔗 Access: http://%s:%s\n", deployer.AccessHost(), container.Port)
 	fmt.Printf("🧪 Tests: %d/%d passed\n", testResults.Passed, testResults.Total)
 	fmt.Printf("✅ Validation: %s\n", validation.Status)
 
@@ -658,7 +1006,7 @@ func deployAndTestAgent(agentName string) error {
 	return nil
 }
 
-func analyzeModelCapabilities(modelName string) error {
+func analyzeModelCapabilities(modelName string, mode llm.BenchmarkMode) error {
 	fmt.Printf("🔍 Analyzing model: %s\n", modelName)
 	fmt.Println("=========================")
 
@@ -671,7 +1019,10 @@ func analyzeModelCapabilities(modelName string) error {
 	}
 
 	// Analyze model
-	analysis, err := analyzer.AnalyzeModel(modelName)
+	if mode != llm.BenchmarkNone {
+		fmt.Printf("⏱️  Running %s benchmark...\n", mode)
+	}
+	analysis, err := analyzer.AnalyzeModel(modelName, mode)
 	if err != nil {
 		return fmt.Errorf("analysis failed: %v", err)
 	}
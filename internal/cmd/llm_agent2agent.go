@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/pxkundu/agent-as-code/internal/a2a"
+	"github.com/spf13/cobra"
+)
+
+var llmAgent2AgentCmd = &cobra.Command{
+	Use:     "agent2agent",
+	Aliases: []string{"a2a"},
+	Short:   "Send and route messages between agents",
+	Long: `Work with the agent2agent (A2A) protocol agents in a compose stack use to
+call each other.
+
+A message is a JSON document with from_agent, to_agent, message_id, type,
+payload and timestamp fields, POSTed to the receiving agent's
+/a2a/messages endpoint. Agents created with 'agent llm create-agent
+--enable-a2a' receive messages there and register with a registry sidecar
+at startup when A2A_REGISTRY_URL is set. Without a registry, agents are
+reached by their service name on port 8080.
+
+Examples:
+  agent llm agent2agent registry --port 8500
+  agent llm agent2agent agents --registry http://localhost:8500
+  agent llm agent2agent send --to summarizer --type ping --registry http://localhost:8500
+  agent llm agent2agent send --to http://localhost:8080 --type task --payload '{"input": "hello"}'`,
+}
+
+var llmA2ARegistryCmd = &cobra.Command{
+	Use:   "registry",
+	Short: "Run the A2A service-discovery registry",
+	Long: `Run the registry agents register with at startup and look each other up in.
+
+Agents register with POST /register and a {"name", "url"} body. GET /agents
+lists the registered agents, and GET /agents/<name> returns one of them.
+Registrations are kept in memory, so agents register again when the
+registry restarts with them.
+
+Examples:
+  agent llm agent2agent registry
+  agent llm agent2agent registry --port 9000`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runA2ARegistry()
+	},
+}
+
+var llmA2ASendCmd = &cobra.Command{
+	Use:   "send",
+	Short: "Send a message to an agent",
+	Long: `Send an A2A message to an agent.
+
+--to is the name of the receiving agent, looked up in --registry, or the
+base URL of the agent. --payload is the JSON payload of the message.
+
+Examples:
+  agent llm agent2agent send --to summarizer --type ping
+  agent llm agent2agent send --to summarizer --type task --payload '{"input": "hello"}' --registry http://localhost:8500
+  agent llm agent2agent send --to http://localhost:8080 --type ping --from cli`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return sendA2AMessage()
+	},
+}
+
+var llmA2AAgentsCmd = &cobra.Command{
+	Use:   "agents",
+	Short: "List the agents in an A2A registry",
+	Long: `List the agents registered in an A2A registry.
+
+Examples:
+  agent llm agent2agent agents --registry http://localhost:8500`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return listA2AAgents()
+	},
+}
+
+var (
+	a2aRegistryPort int
+
+	a2aRegistryURL string
+	a2aFrom        string
+	a2aTo          string
+	a2aType        string
+	a2aPayload     string
+)
+
+func init() {
+	llmCmd.AddCommand(llmAgent2AgentCmd)
+	llmAgent2AgentCmd.AddCommand(llmA2ARegistryCmd)
+	llmAgent2AgentCmd.AddCommand(llmA2ASendCmd)
+	llmAgent2AgentCmd.AddCommand(llmA2AAgentsCmd)
+
+	llmAgent2AgentCmd.PersistentFlags().StringVar(&a2aRegistryURL, "registry", os.Getenv("A2A_REGISTRY_URL"), "URL of the A2A registry (default: A2A_REGISTRY_URL)")
+
+	llmA2ARegistryCmd.Flags().IntVar(&a2aRegistryPort, "port", 8500, "port to serve the registry on")
+
+	llmA2ASendCmd.Flags().StringVar(&a2aFrom, "from", "agent-cli", "name of the sending agent")
+	llmA2ASendCmd.Flags().StringVar(&a2aTo, "to", "", "receiving agent name or URL (required)")
+	llmA2ASendCmd.Flags().StringVar(&a2aType, "type", "", "message type (required)")
+	llmA2ASendCmd.Flags().StringVar(&a2aPayload, "payload", "", "JSON payload of the message")
+	llmA2ASendCmd.MarkFlagRequired("to")
+	llmA2ASendCmd.MarkFlagRequired("type")
+}
+
+func runA2ARegistry() error {
+	addr := fmt.Sprintf(":%d", a2aRegistryPort)
+	fmt.Printf("📇 A2A registry listening on %s\n", addr)
+	fmt.Printf("   Agents register with POST http://localhost:%d%s\n", a2aRegistryPort, a2a.RegisterPath)
+
+	return a2a.NewRegistry().ListenAndServe(addr)
+}
+
+func sendA2AMessage() error {
+	var payload interface{}
+	if a2aPayload != "" {
+		if !json.Valid([]byte(a2aPayload)) {
+			return fmt.Errorf("--payload is not valid JSON")
+		}
+		payload = json.RawMessage(a2aPayload)
+	}
+
+	client := a2a.NewClient(a2aFrom, a2aRegistryURL)
+	if err := client.Send(a2aTo, a2aType, payload); err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Sent %s message to %s\n", a2aType, a2aTo)
+	return nil
+}
+
+func listA2AAgents() error {
+	if a2aRegistryURL == "" {
+		return fmt.Errorf("no registry given; use --registry or set A2A_REGISTRY_URL")
+	}
+
+	agents, err := a2a.NewClient("", a2aRegistryURL).Agents()
+	if err != nil {
+		return err
+	}
+	if len(agents) == 0 {
+		fmt.Println("No agents registered")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tURL\tREGISTERED")
+	for _, agent := range agents {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", agent.Name, agent.URL, agent.RegisteredAt.Local().Format("2006-01-02 15:04:05"))
+	}
+	return w.Flush()
+}
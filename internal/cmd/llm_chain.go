@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pxkundu/agent-as-code/internal/llm"
+	"github.com/spf13/cobra"
+)
+
+var llmChainCmd = &cobra.Command{
+	Use:   "chain",
+	Short: "Pipe a prompt through a sequence of local models",
+	Long: `Run a multi-step pipeline in which each step's output feeds the next.
+
+The steps file is a YAML list. Each step names a model, a prompt template
+and the key its output is stored under. Templates use Go template syntax
+and can refer to the chain input as {{.Input}} and to the output of any
+earlier step by its output_key:
+
+  - model: mistral
+    prompt_template: "Translate to English: {{.Input}}"
+    output_key: english
+  - model: codellama
+    system: You write concise pseudocode.
+    prompt_template: "Convert to pseudocode: {{.english}}"
+    output_key: pseudocode
+
+Step progress is shown on stderr and each step's output on stdout.
+
+Examples:
+  agent llm chain --steps steps.yaml --input "Sortiere die Liste absteigend"
+  cat request.txt | agent llm chain --steps steps.yaml --input -`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		steps, _ := cmd.Flags().GetString("steps")
+		input, _ := cmd.Flags().GetString("input")
+		return runChain(steps, input)
+	},
+}
+
+func init() {
+	llmCmd.AddCommand(llmChainCmd)
+
+	llmChainCmd.Flags().String("steps", "", "YAML file with the chain steps (required)")
+	llmChainCmd.Flags().String("input", "", "input text, or - for stdin (required)")
+	llmChainCmd.MarkFlagRequired("steps")
+	llmChainCmd.MarkFlagRequired("input")
+}
+
+func runChain(stepsPath, input string) error {
+	steps, err := llm.LoadChainSteps(stepsPath)
+	if err != nil {
+		return err
+	}
+
+	if input == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("failed to read input: %v", err)
+		}
+		input = strings.TrimSpace(string(data))
+	}
+
+	outputs, err := llm.NewChainRunner(os.Stderr).Execute(steps, input)
+	if err != nil {
+		return err
+	}
+
+	for _, step := range steps {
+		fmt.Printf("\n=== %s ===\n%s\n", step.OutputKey, outputs[step.OutputKey])
+	}
+	return nil
+}
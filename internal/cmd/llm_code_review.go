@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pxkundu/agent-as-code/internal/llm"
+	"github.com/spf13/cobra"
+)
+
+var llmCodeReviewCmd = &cobra.Command{
+	Use:   "code-review",
+	Short: "Review a diff with a local model",
+	Long: `Review code changes in a unified diff with a local model.
+
+The diff is split by file. Each file's hunks, plus the lines around them
+in the working tree, are sent to the model with the review rules. The
+model answers with {file, line, severity, comment} entries, which are
+rendered as an annotated review.
+
+Rules are read from --rules, or default to error, warning and info levels
+covering correctness, security, error handling and readability:
+
+  severities:          # most severe first
+    - name: blocker
+      description: must be fixed before merging
+    - name: nit
+      description: optional polish
+  rules:
+    - id: no-print
+      severity: nit
+      description: use the logger instead of print statements
+
+--output github-comment renders markdown for the body of a pull request
+comment, e.g. for 'gh pr comment --body-file -'.
+
+Examples:
+  git diff main | agent llm code-review --model codellama --diff -
+  agent llm code-review --model llama2 --diff changes.patch --rules rules.yaml
+  git diff origin/main | agent llm code-review --model codellama --diff - --output github-comment | gh pr comment 42 --body-file -`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		model, _ := cmd.Flags().GetString("model")
+		diff, _ := cmd.Flags().GetString("diff")
+		rules, _ := cmd.Flags().GetString("rules")
+		output, _ := cmd.Flags().GetString("output")
+		return reviewDiff(model, diff, rules, output)
+	},
+}
+
+func init() {
+	llmCmd.AddCommand(llmCodeReviewCmd)
+
+	llmCodeReviewCmd.Flags().String("model", "", "local model that reviews the code (required)")
+	llmCodeReviewCmd.Flags().String("diff", "", "unified diff to review, or - for stdin (required)")
+	llmCodeReviewCmd.Flags().String("rules", "", "YAML file with severity levels and review rules")
+	llmCodeReviewCmd.Flags().String("output", "text", "output format (text|github-comment)")
+	llmCodeReviewCmd.MarkFlagRequired("model")
+	llmCodeReviewCmd.MarkFlagRequired("diff")
+}
+
+func reviewDiff(model, diffPath, rulesPath, output string) error {
+	if output != "text" && output != "github-comment" {
+		return fmt.Errorf("invalid output format '%s' (valid: text, github-comment)", output)
+	}
+
+	rules := llm.DefaultReviewRules()
+	if rulesPath != "" {
+		var err error
+		if rules, err = llm.LoadReviewRules(rulesPath); err != nil {
+			return err
+		}
+	}
+
+	var in io.Reader = os.Stdin
+	if diffPath != "-" {
+		file, err := os.Open(diffPath)
+		if err != nil {
+			return fmt.Errorf("failed to open diff: %v", err)
+		}
+		defer file.Close()
+		in = file
+	}
+
+	files, err := llm.ParseUnifiedDiff(in)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no changed files found in the diff")
+	}
+
+	comments, err := llm.NewCodeReviewer(os.Stderr).Review(model, files, rules)
+	if err != nil {
+		return err
+	}
+
+	if output == "github-comment" {
+		fmt.Print(llm.RenderGitHubComment(comments, rules, model))
+	} else {
+		fmt.Print(llm.RenderReview(comments, rules))
+	}
+	return nil
+}
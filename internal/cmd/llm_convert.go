@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pxkundu/agent-as-code/internal/llm"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var llmConvertCmd = &cobra.Command{
+	Use:   "convert",
+	Short: "Convert models between SafeTensors and GGUF",
+	Long: `Convert models between the SafeTensors format used by HuggingFace and
+the GGUF format used by Ollama.
+
+SafeTensors to GGUF runs llama.cpp's converter (convert_hf_to_gguf.py or
+convert.py) on a HuggingFace model directory. With --quantize the result
+is quantized in the same run. GGUF to SafeTensors needs Python with the
+transformers library and writes a model directory.
+
+The conversion runs a shell script generated next to the output. The
+llama.cpp location is read from the LLAMACPP_PATH environment variable or
+the llm.llamacpp-path config key.
+
+Examples:
+  agent llm convert --from-format safetensors --to-format gguf --input ./Llama-2-7b-hf --output llama-2-7b.gguf
+  agent llm convert --from-format safetensors --to-format gguf --input ./Llama-2-7b-hf --output llama-2-7b.Q4_K_M.gguf --quantize Q4_K_M
+  agent llm convert --from-format gguf --to-format safetensors --input llama-2-7b.gguf --output ./llama-2-7b-hf`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return convertModel()
+	},
+}
+
+var (
+	convertFromFormat string
+	convertToFormat   string
+	convertInput      string
+	convertOutput     string
+	convertQuantize   string
+	convertScriptOnly bool
+)
+
+func init() {
+	llmCmd.AddCommand(llmConvertCmd)
+
+	llmConvertCmd.Flags().StringVar(&convertFromFormat, "from-format", "", "format of the input model (safetensors|gguf) (required)")
+	llmConvertCmd.Flags().StringVar(&convertToFormat, "to-format", "", "format of the output model (safetensors|gguf) (required)")
+	llmConvertCmd.Flags().StringVar(&convertInput, "input", "", "HuggingFace model directory or GGUF file (required)")
+	llmConvertCmd.Flags().StringVar(&convertOutput, "output", "", "GGUF file or model directory to write (required)")
+	llmConvertCmd.Flags().StringVar(&convertQuantize, "quantize", "", "quantization type of the GGUF output (e.g. Q4_K_M, Q8_0; default F16)")
+	llmConvertCmd.Flags().BoolVar(&convertScriptOnly, "script-only", false, "only generate the conversion script")
+	llmConvertCmd.MarkFlagRequired("from-format")
+	llmConvertCmd.MarkFlagRequired("to-format")
+	llmConvertCmd.MarkFlagRequired("input")
+	llmConvertCmd.MarkFlagRequired("output")
+}
+
+func convertModel() error {
+	llamaCppPath := os.Getenv("LLAMACPP_PATH")
+	if llamaCppPath == "" {
+		llamaCppPath = viper.GetString("llm.llamacpp-path")
+	}
+
+	converter := llm.NewFormatConverter(llamaCppPath, convertFromFormat, convertToFormat)
+	plan, err := converter.Plan(convertInput, convertOutput, convertQuantize)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("🔄 Converting %s (%s) to %s (%s)\n", plan.Input, plan.FromFormat, plan.Output, plan.ToFormat)
+	fmt.Println("=================================")
+	fmt.Printf("📦 Input size: %s\n", formatSize(plan.InputSize))
+	fmt.Printf("🔢 Parameters: %.2fB\n", float64(plan.ParameterCount)/1e9)
+	if plan.QuantType != "" {
+		fmt.Printf("🗜️  Output type: %s\n", plan.QuantType)
+	}
+	fmt.Printf("📦 Estimated size: %s\n\n", formatSize(plan.EstimatedSize))
+
+	if convertScriptOnly {
+		scriptPath, err := converter.WriteScript(plan)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("📝 Conversion script written to %s\n", scriptPath)
+		return nil
+	}
+
+	if err := converter.Convert(plan); err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Converted model saved to %s\n", plan.Output)
+	return nil
+}
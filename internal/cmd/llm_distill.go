@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/pxkundu/agent-as-code/internal/llm"
+	"github.com/spf13/cobra"
+)
+
+var llmDistillCmd = &cobra.Command{
+	Use:   "distill",
+	Short: "Generate a synthetic training dataset from a teacher model",
+	Long: `Generate a synthetic instruction/response dataset from a larger teacher model.
+
+This command reads task descriptions from a YAML file, asks the teacher
+model to produce examples for each task, removes near-duplicate examples
+using embedding similarity, and writes the result as Alpaca-format JSONL
+suitable for fine-tuning a smaller model.
+
+The tasks file has the form:
+  tasks:
+    - name: summarization
+      description: Summarize a short news article in two sentences
+      examples:
+        - Summarize this article about renewable energy
+
+Examples:
+  agent llm distill --teacher llama2:13b --tasks tasks.yaml
+  agent llm distill --teacher mistral --tasks tasks.yaml --count 500 --output dataset.jsonl`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return distillDataset()
+	},
+}
+
+var (
+	distillTeacher string
+	distillTasks   string
+	distillCount   int
+	distillOutput  string
+	distillRPM     int
+)
+
+func init() {
+	llmCmd.AddCommand(llmDistillCmd)
+
+	llmDistillCmd.Flags().StringVar(&distillTeacher, "teacher", "", "teacher model used to generate examples (required)")
+	llmDistillCmd.Flags().StringVar(&distillTasks, "tasks", "", "YAML file listing task descriptions (required)")
+	llmDistillCmd.Flags().IntVar(&distillCount, "count", 1000, "number of examples to generate")
+	llmDistillCmd.Flags().StringVar(&distillOutput, "output", "dataset.jsonl", "output JSONL file")
+	llmDistillCmd.Flags().IntVar(&distillRPM, "rate-limit", 60, "maximum requests per minute sent to the teacher model")
+	llmDistillCmd.MarkFlagRequired("teacher")
+	llmDistillCmd.MarkFlagRequired("tasks")
+}
+
+func distillDataset() error {
+	fmt.Printf("🧪 Distilling dataset from %s\n", distillTeacher)
+	fmt.Println("=================================")
+
+	tasks, err := llm.LoadDistillTasks(distillTasks)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("📋 Tasks: %d\n", len(tasks))
+	fmt.Printf("🎯 Target examples: %d\n", distillCount)
+	fmt.Printf("💾 Output: %s\n\n", distillOutput)
+
+	distiller := llm.NewDistiller()
+	opts := llm.DistillOptions{
+		Count:               distillCount,
+		OutputPath:          distillOutput,
+		Temperature:         0.7,
+		SimilarityThreshold: 0.95,
+		RequestsPerMinute:   distillRPM,
+	}
+
+	if err := distiller.Generate(distillTeacher, tasks, opts); err != nil {
+		return fmt.Errorf("distillation failed: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pxkundu/agent-as-code/internal/llm"
+	"github.com/spf13/cobra"
+)
+
+var llmEvaluateCmd = &cobra.Command{
+	Use:   "evaluate",
+	Short: "Score a model's outputs against a reference dataset",
+	Long: `Score a local model's outputs against reference answers.
+
+The dataset is a JSON Lines file with one {"prompt", "reference"} object
+per line. Each prompt is run through the model at temperature 0, and the
+output is scored against the reference with --metric, from 0 to 1. The
+score of every example is printed, followed by a summary.
+
+Metrics:
+  exact  1 if output and reference match, ignoring case and whitespace
+  f1     overlap of the words of output and reference
+  rouge  ROUGE-L: longest common subsequence of words
+  bleu   BLEU-4 with smoothing, for short outputs
+
+f1, rouge and bleu ignore case and punctuation.
+
+Examples:
+  agent llm evaluate --model llama2 --dataset eval.jsonl
+  agent llm evaluate --model mistral --dataset qa.jsonl --metric f1
+  agent llm evaluate --model llama2 --dataset summaries.jsonl --metric rouge`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return evaluateModel()
+	},
+}
+
+var (
+	evaluateModelName string
+	evaluateDataset   string
+	evaluateMetric    string
+)
+
+func init() {
+	llmCmd.AddCommand(llmEvaluateCmd)
+
+	llmEvaluateCmd.Flags().StringVar(&evaluateModelName, "model", "", "model to evaluate (required)")
+	llmEvaluateCmd.Flags().StringVar(&evaluateDataset, "dataset", "", "JSON Lines file of prompts and references (required)")
+	llmEvaluateCmd.Flags().StringVar(&evaluateMetric, "metric", llm.MetricExact, fmt.Sprintf("metric to score outputs with (%s)", strings.Join(llm.EvalMetrics(), "|")))
+	llmEvaluateCmd.MarkFlagRequired("model")
+	llmEvaluateCmd.MarkFlagRequired("dataset")
+}
+
+func evaluateModel() error {
+	valid := false
+	for _, metric := range llm.EvalMetrics() {
+		valid = valid || metric == evaluateMetric
+	}
+	if !valid {
+		return fmt.Errorf("invalid metric '%s' (valid: %s)", evaluateMetric, strings.Join(llm.EvalMetrics(), ", "))
+	}
+
+	examples, err := llm.LoadEvalDataset(evaluateDataset)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("📏 Evaluating %s on %d examples (%s)\n", evaluateModelName, len(examples), evaluateMetric)
+	fmt.Println("=================================")
+
+	results, err := llm.NewEvaluator().Evaluate(evaluateModelName, examples, evaluateMetric, func(r llm.EvalResult) {
+		fmt.Printf("  %d/%d  %.3f  %s\n", r.Index, len(examples), r.Score, truncateSentence(r.Example.Prompt, 60))
+	})
+	if err != nil {
+		return fmt.Errorf("evaluation failed: %v", err)
+	}
+
+	summary := llm.SummarizeEval(evaluateMetric, results)
+	fmt.Printf("\n📊 Summary (%s)\n", summary.Metric)
+	fmt.Printf("   Examples: %d\n", summary.Count)
+	fmt.Printf("   Mean:     %.3f\n", summary.Avg)
+	fmt.Printf("   Median:   %.3f\n", summary.Median)
+	fmt.Printf("   Min:      %.3f\n", summary.Min)
+	fmt.Printf("   Max:      %.3f\n", summary.Max)
+	if evaluateMetric == llm.MetricExact {
+		matches := 0
+		for _, result := range results {
+			if result.Score == 1 {
+				matches++
+			}
+		}
+		fmt.Printf("   Matches:  %d/%d\n", matches, summary.Count)
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pxkundu/agent-as-code/internal/llm"
+	"github.com/spf13/cobra"
+)
+
+var llmExplainCmd = &cobra.Command{
+	Use:   "explain [AGENT_DIR]",
+	Short: "Explain what an existing agent does with a local model",
+	Long: `Summarize an existing agent project with a local model.
+
+The agent's agent.yaml, main.py or main.go, and README.md are sent to the
+model, which explains the agent's purpose, its API endpoints and its
+limitations. The answer is streamed as it is generated. Use --focus to
+narrow the analysis to endpoints, security or performance.
+
+Examples:
+  agent llm explain ./my-agent --model llama2
+  agent llm explain ./my-agent --model codellama --focus security`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		model, _ := cmd.Flags().GetString("model")
+		focus, _ := cmd.Flags().GetString("focus")
+		return explainAgent(args[0], model, focus)
+	},
+}
+
+func init() {
+	llmCmd.AddCommand(llmExplainCmd)
+
+	llmExplainCmd.Flags().String("model", "", "local model that explains the agent (required)")
+	llmExplainCmd.Flags().String("focus", "", fmt.Sprintf("narrow the analysis (%s)", strings.Join(llm.ExplainFocuses(), "|")))
+	llmExplainCmd.MarkFlagRequired("model")
+}
+
+func explainAgent(agentDir, model, focus string) error {
+	if focus != "" {
+		fmt.Printf("🔍 Explaining %s with %s (focus: %s)\n\n", agentDir, model, focus)
+	} else {
+		fmt.Printf("🔍 Explaining %s with %s\n\n", agentDir, model)
+	}
+
+	return llm.NewCodeExplainer(focus).Explain(agentDir, model, os.Stdout)
+}
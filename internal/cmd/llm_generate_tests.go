@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pxkundu/agent-as-code/internal/llm"
+	"github.com/pxkundu/agent-as-code/internal/parser"
+	"github.com/spf13/cobra"
+)
+
+var llmGenerateTestsCmd = &cobra.Command{
+	Use:   "generate-tests [AGENT_DIR]",
+	Short: "Generate pytest tests for an agent with a local model",
+	Long: `Generate pytest tests for an existing agent with a local model.
+
+The agent's agent.yaml and main.py are sent to the model, which is asked
+for test cases as JSON. The tests are written to tests/test_generated.py
+in the agent directory. Generated tests should be reviewed before they are
+relied on.
+
+Examples:
+  agent llm generate-tests ./my-agent --model codellama
+  agent llm generate-tests ./my-agent --model llama2 --coverage-target 90`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		model, _ := cmd.Flags().GetString("model")
+		coverageTarget, _ := cmd.Flags().GetInt("coverage-target")
+		return generateAgentTests(args[0], model, coverageTarget)
+	},
+}
+
+func init() {
+	llmCmd.AddCommand(llmGenerateTestsCmd)
+
+	llmGenerateTestsCmd.Flags().String("model", "", "local model that writes the tests (required)")
+	llmGenerateTestsCmd.Flags().Int("coverage-target", 80, "line coverage of main.py the tests should aim for, in percent")
+	llmGenerateTestsCmd.MarkFlagRequired("model")
+}
+
+// checkPythonSyntax parses the file in argv[1] and prints the first syntax
+// error
+const checkPythonSyntax = `import ast, sys
+try:
+    ast.parse(open(sys.argv[1]).read(), sys.argv[1])
+except SyntaxError as e:
+    print("line %d: %s" % (e.lineno, e.msg))
+    sys.exit(1)
+`
+
+func generateAgentTests(agentDir, model string, coverageTarget int) error {
+	agentName := filepath.Base(agentDir)
+	if spec, err := parser.New().ParseFile(filepath.Join(agentDir, "agent.yaml")); err == nil && spec.Metadata.Name != "" {
+		agentName = spec.Metadata.Name
+	}
+
+	fmt.Printf("🧪 Generating tests for %s with %s (coverage target %d%%)\n", agentName, model, coverageTarget)
+
+	tokens := 0
+	generated, err := llm.NewTestGenerator().Generate(llm.TestGenerationOptions{
+		AgentDir:       agentDir,
+		Model:          model,
+		CoverageTarget: coverageTarget,
+		OnToken: func(string) {
+			tokens++
+			fmt.Printf("\r🧠 Generating... %d tokens", tokens)
+		},
+	})
+	fmt.Println()
+	if err != nil {
+		return err
+	}
+
+	testsDir := filepath.Join(agentDir, "tests")
+	if err := os.MkdirAll(testsDir, 0755); err != nil {
+		return fmt.Errorf("failed to create tests directory: %v", err)
+	}
+	outputPath := filepath.Join(testsDir, "test_generated.py")
+	if err := os.WriteFile(outputPath, []byte(llm.RenderPytest(agentName, model, generated)), 0644); err != nil {
+		return fmt.Errorf("failed to write tests: %v", err)
+	}
+
+	fmt.Printf("✅ Wrote %d tests to %s\n", len(generated.Tests), outputPath)
+	for _, test := range generated.Tests {
+		fmt.Printf("   • %s\n", test.Name)
+	}
+
+	// Models sometimes produce invalid Python; catch it before pytest does
+	if python, err := exec.LookPath("python3"); err == nil {
+		if output, err := exec.Command(python, "-c", checkPythonSyntax, outputPath).CombinedOutput(); err != nil {
+			fmt.Printf("⚠️  The generated file has syntax errors and needs fixing:\n%s\n", strings.TrimSpace(string(output)))
+		}
+	}
+
+	fmt.Printf("\n💡 Run them with: cd %s && pytest tests/test_generated.py\n", agentDir)
+	return nil
+}
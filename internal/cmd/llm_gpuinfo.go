@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/pxkundu/agent-as-code/internal/llm"
+	"github.com/spf13/cobra"
+)
+
+var llmGPUInfoCmd = &cobra.Command{
+	Use:   "gpu-info",
+	Short: "Show GPU availability for model inference",
+	Long: `Show the GPUs available for local model inference and whether Ollama is using them.
+
+On Linux, NVIDIA GPUs are detected with nvidia-smi (or /proc/driver/nvidia
+when nvidia-smi is not installed). On macOS, GPUs are detected with
+system_profiler. Running models are read from Ollama to report how much of
+each model is loaded into VRAM.
+
+Examples:
+  agent llm gpu-info`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return showGPUInfo()
+	},
+}
+
+func init() {
+	llmCmd.AddCommand(llmGPUInfoCmd)
+}
+
+func showGPUInfo() error {
+	fmt.Println("🎮 GPU Information")
+	fmt.Println("==================")
+
+	detector := llm.NewGPUDetector()
+	gpus, err := detector.DetectGPUs()
+	if err != nil {
+		return fmt.Errorf("failed to detect GPUs: %v", err)
+	}
+
+	if len(gpus) == 0 {
+		fmt.Println("ℹ️  No supported GPU detected; models will run on CPU")
+	}
+
+	for i, gpu := range gpus {
+		fmt.Printf("\n🖥️  GPU %d: %s\n", i, gpu.Name)
+		fmt.Printf("  💾 VRAM: %s\n", gpu.VRAM)
+		if gpu.Driver != "" {
+			fmt.Printf("  🔧 Driver: %s\n", gpu.Driver)
+		}
+		if gpu.CUDAVersion != "" {
+			fmt.Printf("  ⚡ CUDA: %s\n", gpu.CUDAVersion)
+		}
+		if gpu.Utilization != "" {
+			fmt.Printf("  📈 Utilization: %s\n", gpu.Utilization)
+		}
+		if gpu.IsUsedByOllama {
+			fmt.Printf("  ✅ Used by Ollama\n")
+		} else {
+			fmt.Printf("  ⚪ Not used by Ollama\n")
+		}
+	}
+
+	models, err := detector.RunningModels()
+	if err != nil {
+		fmt.Printf("\n⚠️  Could not query Ollama for running models: %v\n", err)
+		return nil
+	}
+
+	fmt.Printf("\n🤖 Running Models:\n")
+	if len(models) == 0 {
+		fmt.Println("  (none)")
+		return nil
+	}
+
+	for _, model := range models {
+		placement := "CPU"
+		if model.Size > 0 && model.SizeVRAM > 0 {
+			placement = fmt.Sprintf("%.0f%% GPU", float64(model.SizeVRAM)/float64(model.Size)*100)
+		}
+		fmt.Printf("  %-30s %-10s %s\n", model.Name, formatSize(model.Size), placement)
+	}
+
+	return nil
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pxkundu/agent-as-code/internal/llm"
+	"github.com/spf13/cobra"
+)
+
+var llmGraphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Build a knowledge graph from documents",
+	Long: `Build a knowledge graph from the text documents in a directory.
+
+This command reads the .txt, .md and .rst files under --input, sends them
+to a local model in batches of --chunk-size bytes, and asks it for the
+entities in each batch and the relations between them. The results are
+combined into one directed graph, with entities of the same name merged,
+and saved as JSON-LD. Each relation records the documents it was found in.
+
+Use 'agent llm graph query' to ask questions of a saved graph.
+
+Examples:
+  agent llm graph --model llama2 --input ./docs
+  agent llm graph --model mistral --input ./reports --output reports-graph.json
+  agent llm graph query --graph graph.json --query "find all people related to Acme Corp"`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return buildKnowledgeGraph()
+	},
+}
+
+var llmGraphQueryCmd = &cobra.Command{
+	Use:   "query",
+	Short: "Query a knowledge graph",
+	Long: `Answer a question from a knowledge graph built by 'agent llm graph'.
+
+The question is translated into a traversal of the graph: the entities it
+starts from, the relations to follow, and the types of entity it asks for.
+With --model, a local model makes the translation; without it, the entities
+and entity types named in the question are used. Relations are followed in
+both directions, up to --depth relations away, and each entity found is
+printed with the relations that lead to it.
+
+Examples:
+  agent llm graph query --graph graph.json --query "find all people related to Acme Corp"
+  agent llm graph query --graph graph.json --query "who works with Jane Doe?" --model llama2
+  agent llm graph query --graph graph.json --query "locations of Acme Corp" --depth 1`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return queryKnowledgeGraph()
+	},
+}
+
+var (
+	graphModel     string
+	graphInput     string
+	graphOutput    string
+	graphChunkSize int
+
+	graphQueryFile  string
+	graphQueryText  string
+	graphQueryModel string
+	graphQueryDepth int
+)
+
+func init() {
+	llmCmd.AddCommand(llmGraphCmd)
+	llmGraphCmd.AddCommand(llmGraphQueryCmd)
+
+	llmGraphCmd.Flags().StringVar(&graphModel, "model", "", "model that extracts entities and relations (required)")
+	llmGraphCmd.Flags().StringVar(&graphInput, "input", "", "directory of documents to read (required)")
+	llmGraphCmd.Flags().StringVar(&graphOutput, "output", "graph.json", "JSON-LD file to write the graph to")
+	llmGraphCmd.Flags().IntVar(&graphChunkSize, "chunk-size", 4000, "bytes of text sent to the model per batch")
+	llmGraphCmd.MarkFlagRequired("model")
+	llmGraphCmd.MarkFlagRequired("input")
+
+	llmGraphQueryCmd.Flags().StringVar(&graphQueryFile, "graph", "graph.json", "graph file to query")
+	llmGraphQueryCmd.Flags().StringVar(&graphQueryText, "query", "", "question to answer (required)")
+	llmGraphQueryCmd.Flags().StringVar(&graphQueryModel, "model", "", "model that translates the question (default: match names in the question)")
+	llmGraphQueryCmd.Flags().IntVar(&graphQueryDepth, "depth", 0, "maximum number of relations to follow (default: chosen by the model, or 2)")
+	llmGraphQueryCmd.MarkFlagRequired("query")
+}
+
+func buildKnowledgeGraph() error {
+	fmt.Printf("🕸️  Building knowledge graph with %s\n", graphModel)
+	fmt.Println("=================================")
+	fmt.Printf("📂 Input: %s\n", graphInput)
+	fmt.Printf("💾 Output: %s\n\n", graphOutput)
+
+	builder := llm.NewKnowledgeGraphBuilder()
+	opts := llm.GraphBuildOptions{
+		InputDir:   graphInput,
+		OutputPath: graphOutput,
+		ChunkSize:  graphChunkSize,
+	}
+
+	if _, err := builder.Build(graphModel, opts); err != nil {
+		return fmt.Errorf("graph build failed: %v", err)
+	}
+
+	return nil
+}
+
+func queryKnowledgeGraph() error {
+	graph, err := llm.LoadKnowledgeGraph(graphQueryFile)
+	if err != nil {
+		return err
+	}
+
+	query, err := llm.NewKnowledgeGraphBuilder().TranslateQuery(graphQueryModel, graphQueryText, graph)
+	if err != nil {
+		return fmt.Errorf("failed to translate query: %v", err)
+	}
+	if len(query.Entities) == 0 {
+		return fmt.Errorf("the query names no entity in the graph")
+	}
+	if graphQueryDepth > 0 {
+		query.Depth = graphQueryDepth
+	}
+
+	fmt.Printf("🔎 Starting from: %s\n", strings.Join(query.Entities, ", "))
+	if len(query.Predicates) > 0 {
+		fmt.Printf("   Following: %s\n", strings.Join(query.Predicates, ", "))
+	}
+	if len(query.Types) > 0 {
+		fmt.Printf("   Looking for: %s\n", strings.Join(query.Types, ", "))
+	}
+	fmt.Println()
+
+	matches, err := graph.Query(query)
+	if err != nil {
+		return err
+	}
+	if len(matches) == 0 {
+		fmt.Println("No matching entities found")
+		return nil
+	}
+
+	for _, match := range matches {
+		name := match.Entity.Name
+		if match.Entity.Type != "" {
+			name += " (" + match.Entity.Type + ")"
+		}
+		fmt.Printf("• %s\n", name)
+		for _, relation := range match.Path {
+			fmt.Printf("    %s\n", graph.FormatRelation(relation))
+		}
+	}
+	fmt.Printf("\n✅ Found %d entities\n", len(matches))
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/pxkundu/agent-as-code/internal/llm"
+	"github.com/spf13/cobra"
+)
+
+var llmHealthCmd = &cobra.Command{
+	Use:   "health",
+	Short: "Show a health report for the local LLM backend",
+	Long: `Show a unified health report for Ollama.
+
+The report includes the Ollama status and version, the number of local
+models and their total disk usage, the models currently loaded into memory
+and GPU utilization. The command exits with status 1 if Ollama is down.
+
+Examples:
+  agent llm health
+  agent llm health --watch 5`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		watch, _ := cmd.Flags().GetInt("watch")
+		if watch < 0 {
+			return fmt.Errorf("--watch must be a positive number of seconds")
+		}
+
+		if watch == 0 {
+			if !printLLMHealth() {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("Ollama is down")
+			}
+			return nil
+		}
+
+		return watchLLMHealth(time.Duration(watch) * time.Second)
+	},
+}
+
+func init() {
+	llmCmd.AddCommand(llmHealthCmd)
+
+	llmHealthCmd.Flags().Int("watch", 0, "refresh the report every N seconds")
+}
+
+// watchLLMHealth redraws the health report every interval until interrupted
+func watchLLMHealth(interval time.Duration) error {
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		fmt.Print("\033[H\033[2J")
+		printLLMHealth()
+		fmt.Printf("\n🔄 Refreshing every %s (Ctrl+C to stop)\n", interval)
+
+		select {
+		case <-interrupt:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// printLLMHealth prints the health report and reports whether Ollama is up
+func printLLMHealth() bool {
+	manager := llm.NewLocalLLMManager()
+
+	fmt.Println("🩺 Local LLM Health")
+	fmt.Println("===================")
+
+	if err := manager.CheckOllamaAvailability(); err != nil {
+		fmt.Printf("❌ Ollama: down\n")
+		fmt.Printf("   %v\n", err)
+		return false
+	}
+
+	version, err := manager.GetOllamaVersion()
+	if err != nil {
+		version = "unknown"
+	}
+	fmt.Printf("✅ Ollama: up (version %s)\n", version)
+
+	models, err := manager.ListLocalModels()
+	if err != nil {
+		fmt.Printf("⚠️  Models: %v\n", err)
+	} else {
+		var total int64
+		for _, model := range models {
+			total += model.SizeBytes
+		}
+		fmt.Printf("📦 Models: %d (%s on disk)\n", len(models), formatSize(total))
+	}
+
+	running, err := manager.ListRunningModels()
+	if err != nil {
+		fmt.Printf("⚠️  Loaded models: %v\n", err)
+	} else {
+		fmt.Printf("🧠 Loaded models: %d\n", len(running))
+		for _, model := range running {
+			fmt.Printf("   %-30s %-10s (%s in VRAM)\n", model.Name, formatSize(model.Size), formatSize(model.SizeVRAM))
+		}
+	}
+
+	gpus, err := llm.NewGPUDetector().DetectGPUs()
+	switch {
+	case err != nil:
+		fmt.Printf("🎮 GPUs: %v\n", err)
+	case len(gpus) == 0:
+		fmt.Printf("🎮 GPUs: none detected (CPU inference)\n")
+	default:
+		fmt.Printf("🎮 GPUs: %d\n", len(gpus))
+		for _, gpu := range gpus {
+			utilization := gpu.Utilization
+			if utilization == "" {
+				utilization = "n/a"
+			}
+			usedBy := ""
+			if gpu.IsUsedByOllama {
+				usedBy = ", used by Ollama"
+			}
+			fmt.Printf("   %s: %s utilization, %s VRAM%s\n", gpu.Name, utilization, gpu.VRAM, usedBy)
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/pxkundu/agent-as-code/internal/llm"
+	"github.com/spf13/cobra"
+)
+
+var llmLeaderboardCmd = &cobra.Command{
+	Use:   "leaderboard",
+	Short: "Compare local models across saved benchmark runs",
+	Long: `Display a ranked comparison of local models based on saved benchmark runs.
+
+Every 'agent llm benchmark' run saves its results to ~/.agent/benchmarks.
+This command groups the results by model and shows the min, max and average
+response time and quality score across runs.
+
+Sort keys:
+  score       composite of quality and speed (default)
+  speed       fastest average response time first
+  quality     highest average quality score first
+  efficiency  highest quality per second first
+
+Examples:
+  agent llm leaderboard
+  agent llm leaderboard --sort-by speed
+  agent llm leaderboard --reset`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sortBy, _ := cmd.Flags().GetString("sort-by")
+		reset, _ := cmd.Flags().GetBool("reset")
+		return showLeaderboard(sortBy, reset)
+	},
+}
+
+func init() {
+	llmCmd.AddCommand(llmLeaderboardCmd)
+
+	llmLeaderboardCmd.Flags().String("sort-by", "score", "sort key (score, speed, quality, efficiency)")
+	llmLeaderboardCmd.Flags().Bool("reset", false, "delete all saved benchmark results")
+}
+
+func showLeaderboard(sortBy string, reset bool) error {
+	dir, err := llm.DefaultBenchmarkDir()
+	if err != nil {
+		return err
+	}
+
+	leaderboard := llm.NewLeaderboard()
+
+	if reset {
+		removed, err := leaderboard.Reset(dir)
+		if err != nil {
+			return fmt.Errorf("failed to reset leaderboard: %v", err)
+		}
+		fmt.Printf("🗑️  Removed %d benchmark results\n", removed)
+		return nil
+	}
+
+	stats, err := leaderboard.Load(dir)
+	if err != nil {
+		return fmt.Errorf("failed to load benchmark results: %v", err)
+	}
+
+	if len(stats) == 0 {
+		fmt.Println("ℹ️  No benchmark results found")
+		fmt.Println("💡 Run 'agent llm benchmark' first")
+		return nil
+	}
+
+	if err := leaderboard.Sort(stats, sortBy); err != nil {
+		return err
+	}
+
+	fmt.Printf("🏆 Model Leaderboard (sorted by %s)\n", sortBy)
+	fmt.Println("=================================")
+	fmt.Printf("%-4s %-25s %-5s %-24s %-24s %-10s %-6s\n",
+		"#", "MODEL", "RUNS", "RESPONSE (min/avg/max)", "QUALITY (min/avg/max)", "EFFICIENCY", "SCORE")
+
+	for i, s := range stats {
+		fmt.Printf("%-4d %-25s %-5d %-24s %-24s %-10s %-6s\n",
+			i+1,
+			s.ModelName,
+			s.Runs,
+			fmt.Sprintf("%.2f/%.2f/%.2fs", s.ResponseTime.Min, s.ResponseTime.Avg, s.ResponseTime.Max),
+			fmt.Sprintf("%.1f/%.1f/%.1f%%", s.QualityScore.Min, s.QualityScore.Avg, s.QualityScore.Max),
+			fmt.Sprintf("%.1f", s.Efficiency),
+			fmt.Sprintf("%.3f", s.CompositeScore),
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/pxkundu/agent-as-code/internal/llm"
+	"github.com/spf13/cobra"
+)
+
+var llmMigrateCmd = &cobra.Command{
+	Use:   "migrate [AGENT_DIR]",
+	Short: "Convert an OpenAI-based agent to use a local model",
+	Long: `Convert an existing OpenAI-based agent to use a local model served by Ollama.
+
+The migration:
+  - sets spec.model.provider and spec.model.name in agent.yaml
+  - adds the OLLAMA_BASE_URL environment variable and removes OPENAI_API_KEY
+  - replaces the openai package with httpx in requirements.txt and
+    spec.dependencies
+  - patches main.py to use a small Ollama client with the same
+    chat.completions.create interface as the OpenAI client
+
+A diff of every changed file is shown and confirmation is asked before
+anything is written.
+
+Examples:
+  agent llm migrate --from openai/gpt-4 --to local/llama2 ./my-agent
+  agent llm migrate --from openai/gpt-3.5-turbo --to local/mistral:7b . --yes`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, _ := cmd.Flags().GetString("from")
+		to, _ := cmd.Flags().GetString("to")
+		yes, _ := cmd.Flags().GetBool("yes")
+		return migrateAgent(args[0], from, to, yes)
+	},
+}
+
+func init() {
+	llmCmd.AddCommand(llmMigrateCmd)
+
+	llmMigrateCmd.Flags().String("from", "", "current model of the agent, e.g. openai/gpt-4 (required)")
+	llmMigrateCmd.Flags().String("to", "", "local model to migrate to, e.g. local/llama2 (required)")
+	llmMigrateCmd.Flags().Bool("yes", false, "write the changes without asking for confirmation")
+	llmMigrateCmd.MarkFlagRequired("from")
+	llmMigrateCmd.MarkFlagRequired("to")
+}
+
+func migrateAgent(agentDir, from, to string, yes bool) error {
+	fmt.Printf("🔄 Migrating agent in %s from %s to %s\n\n", agentDir, from, to)
+
+	migrator := llm.NewMigrator()
+	changes, err := migrator.Plan(&llm.MigrateOptions{
+		From:     from,
+		To:       to,
+		AgentDir: agentDir,
+	})
+	if err != nil {
+		return fmt.Errorf("migration failed: %v", err)
+	}
+
+	if len(changes) == 0 {
+		fmt.Println("ℹ️  Nothing to change")
+		return nil
+	}
+
+	for _, change := range changes {
+		fmt.Println(change.Diff())
+	}
+
+	if !yes && !confirm(fmt.Sprintf("Write changes to %d files?", len(changes))) {
+		fmt.Println("Aborted")
+		return nil
+	}
+
+	if err := migrator.Apply(changes); err != nil {
+		return err
+	}
+
+	for _, change := range changes {
+		fmt.Printf("✅ Updated %s\n", filepath.Base(change.Path))
+	}
+
+	_, model, _ := strings.Cut(to, "/")
+	fmt.Printf("\n💡 Pull the model with: agent llm pull %s\n", model)
+	return nil
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pxkundu/agent-as-code/internal/llm"
+	"github.com/spf13/cobra"
+)
+
+var llmMockServerCmd = &cobra.Command{
+	Use:   "mock-server",
+	Short: "Serve recorded model responses through the Ollama API",
+	Long: `Serve pre-recorded responses through an Ollama-compatible HTTP API, so
+CI runs can exercise agents and llm commands without running a model.
+
+Responses are looked up by model and the SHA-256 of the prompt; for chat
+requests the last user message is the prompt. Requests without a
+recording get a 404. Record responses with 'agent llm mock-record'.
+
+Point the agent CLI at the mock with OLLAMA_HOST, e.g.
+OLLAMA_HOST=localhost:11435 when serving on --port 11435.
+
+Examples:
+  agent llm mock-server --recordings recordings.json
+  agent llm mock-server --recordings recordings.json --port 11435`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		recordings, _ := cmd.Flags().GetString("recordings")
+		port, _ := cmd.Flags().GetInt("port")
+		return runMockServer(recordings, port)
+	},
+}
+
+var llmMockRecordCmd = &cobra.Command{
+	Use:   "mock-record",
+	Short: "Record model responses for llm mock-server",
+	Long: `Run prompts through a real model and save the responses for
+'agent llm mock-server'.
+
+The prompts file uses the same format as 'agent llm prompt-test':
+  prompts:
+    - id: greeting
+      system: You are a helpful assistant
+      user: Say hello
+
+Recordings are merged into the output file; a prompt recorded again for
+the same model replaces the earlier response.
+
+Examples:
+  agent llm mock-record --model llama2 --prompts prompts.yaml --output recordings.json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		model, _ := cmd.Flags().GetString("model")
+		prompts, _ := cmd.Flags().GetString("prompts")
+		output, _ := cmd.Flags().GetString("output")
+		return recordMockResponses(model, prompts, output)
+	},
+}
+
+func init() {
+	llmCmd.AddCommand(llmMockServerCmd)
+	llmCmd.AddCommand(llmMockRecordCmd)
+
+	llmMockServerCmd.Flags().String("recordings", "recordings.json", "recordings file to serve")
+	llmMockServerCmd.Flags().Int("port", 11434, "port to listen on")
+
+	llmMockRecordCmd.Flags().String("model", "", "model to record (required)")
+	llmMockRecordCmd.Flags().String("prompts", "", "YAML file with the prompts to record (required)")
+	llmMockRecordCmd.Flags().String("output", "recordings.json", "recordings file to write")
+	llmMockRecordCmd.MarkFlagRequired("model")
+	llmMockRecordCmd.MarkFlagRequired("prompts")
+}
+
+func runMockServer(recordingsPath string, port int) error {
+	if port <= 0 || port > 65535 {
+		return fmt.Errorf("invalid port %d", port)
+	}
+
+	recordings, err := llm.LoadRecordings(recordingsPath)
+	if err != nil {
+		return err
+	}
+	if len(recordings.Recordings) == 0 {
+		return fmt.Errorf("no recordings in %s. Record some with 'agent llm mock-record'", recordingsPath)
+	}
+
+	server := llm.NewMockServer(recordings.Recordings)
+	fmt.Printf("🎭 Serving %d recordings for %s on http://localhost:%d\n",
+		len(recordings.Recordings), strings.Join(server.Models(), ", "), port)
+	fmt.Printf("💡 Use it with: OLLAMA_HOST=localhost:%d agent llm ...\n", port)
+
+	return server.ListenAndServe(fmt.Sprintf(":%d", port))
+}
+
+func recordMockResponses(model, promptsPath, output string) error {
+	prompts, err := llm.LoadPromptTests(promptsPath)
+	if err != nil {
+		return err
+	}
+
+	recordings, err := llm.LoadRecordings(output)
+	if err != nil {
+		return err
+	}
+
+	manager := llm.NewLocalLLMManager()
+	fmt.Printf("🎙️  Recording %d prompts with %s\n", len(prompts), model)
+	for _, prompt := range prompts {
+		resp, err := manager.Generate(llm.GenerateRequest{
+			Model:  model,
+			System: prompt.System,
+			Prompt: prompt.User,
+		})
+		if err != nil {
+			return fmt.Errorf("prompt '%s' failed: %v", prompt.ID, err)
+		}
+
+		recordings.Add(llm.Recording{
+			Model:      model,
+			PromptHash: llm.PromptHash(prompt.User),
+			Prompt:     prompt.User,
+			Response:   resp.Response,
+		})
+		fmt.Printf("  ✅ %s\n", prompt.ID)
+	}
+
+	if err := recordings.Save(output); err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Saved %d recordings to %s\n", len(recordings.Recordings), output)
+	return nil
+}
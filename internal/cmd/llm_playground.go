@@ -0,0 +1,314 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pxkundu/agent-as-code/internal/llm"
+	"github.com/spf13/cobra"
+)
+
+var llmPlaygroundCmd = &cobra.Command{
+	Use:   "playground [MODEL]",
+	Short: "Interactive prompt playground with streaming token stats",
+	Long: `Open an interactive terminal playground for a local model.
+
+The screen is split into a prompt editor, a response pane that streams
+tokens as they are generated, and a stats pane showing tokens/sec, total
+tokens, prompt tokens and eval duration.
+
+Keys:
+  Enter      run the prompt
+  Alt+Enter  insert a newline
+  Ctrl+R     re-run the last prompt
+  Ctrl+S     save the session as JSON
+  Esc        quit
+
+Examples:
+  agent llm playground llama2
+  agent llm playground mistral:7b --system "You are a terse assistant"`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		system, _ := cmd.Flags().GetString("system")
+		output, _ := cmd.Flags().GetString("output")
+		return runPlayground(args[0], system, output)
+	},
+}
+
+func init() {
+	llmCmd.AddCommand(llmPlaygroundCmd)
+
+	llmPlaygroundCmd.Flags().String("system", "", "system prompt sent with every request")
+	llmPlaygroundCmd.Flags().String("output", "", "session file written by Ctrl+S (default: playground-<timestamp>.json)")
+}
+
+// PlaygroundRun is a single prompt and its response in a playground session
+type PlaygroundRun struct {
+	Prompt         string    `json:"prompt"`
+	Response       string    `json:"response"`
+	PromptTokens   int       `json:"prompt_tokens"`
+	EvalTokens     int       `json:"eval_tokens"`
+	EvalDurationMs int64     `json:"eval_duration_ms"`
+	TokensPerSec   float64   `json:"tokens_per_sec"`
+	StartedAt      time.Time `json:"started_at"`
+}
+
+// PlaygroundSession is the JSON document saved with Ctrl+S
+type PlaygroundSession struct {
+	Model  string          `json:"model"`
+	System string          `json:"system,omitempty"`
+	Runs   []PlaygroundRun `json:"runs"`
+}
+
+type playgroundTokenMsg string
+
+type playgroundDoneMsg struct {
+	final *llm.GenerateResponse
+	err   error
+}
+
+var (
+	playgroundPaneStyle  = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0, 1)
+	playgroundTitleStyle = lipgloss.NewStyle().Bold(true)
+	playgroundHelpStyle  = lipgloss.NewStyle().Faint(true)
+)
+
+// playgroundModel is the bubbletea model of the playground
+type playgroundModel struct {
+	manager *llm.LocalLLMManager
+	session PlaygroundSession
+	output  string
+
+	editor   textarea.Model
+	response viewport.Model
+	width    int
+	height   int
+
+	tokens     chan tea.Msg
+	streaming  bool
+	current    PlaygroundRun
+	chunks     int
+	lastPrompt string
+	status     string
+}
+
+func runPlayground(modelName, system, output string) error {
+	manager := llm.NewLocalLLMManager()
+	if !manager.IsModelAvailable(modelName) {
+		return fmt.Errorf("model '%s' is not available. Pull it first with 'agent llm pull %s'", modelName, modelName)
+	}
+
+	editor := textarea.New()
+	editor.Placeholder = "Type a prompt and press Enter..."
+	editor.ShowLineNumbers = false
+	editor.KeyMap.InsertNewline.SetKeys("alt+enter")
+	editor.Focus()
+
+	m := &playgroundModel{
+		manager:  manager,
+		session:  PlaygroundSession{Model: modelName, System: system},
+		output:   output,
+		editor:   editor,
+		response: viewport.New(0, 0),
+	}
+
+	_, err := tea.NewProgram(m, tea.WithAltScreen()).Run()
+	return err
+}
+
+func (m *playgroundModel) Init() tea.Cmd {
+	return textarea.Blink
+}
+
+func (m *playgroundModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.resize()
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "ctrl+c":
+			return m, tea.Quit
+		case "enter":
+			prompt := strings.TrimSpace(m.editor.Value())
+			if prompt == "" || m.streaming {
+				return m, nil
+			}
+			m.editor.Reset()
+			return m, m.run(prompt)
+		case "ctrl+r":
+			if m.lastPrompt == "" || m.streaming {
+				return m, nil
+			}
+			return m, m.run(m.lastPrompt)
+		case "ctrl+s":
+			m.save()
+			return m, nil
+		}
+
+	case playgroundTokenMsg:
+		m.chunks++
+		m.current.Response += string(msg)
+		m.response.SetContent(m.current.Response)
+		m.response.GotoBottom()
+		return m, m.waitForToken()
+
+	case playgroundDoneMsg:
+		m.streaming = false
+		if msg.err != nil {
+			m.status = "❌ " + msg.err.Error()
+			return m, nil
+		}
+		m.current.PromptTokens = msg.final.PromptEvalCount
+		m.current.EvalTokens = msg.final.EvalCount
+		m.current.EvalDurationMs = time.Duration(msg.final.EvalDuration).Milliseconds()
+		if msg.final.EvalDuration > 0 {
+			m.current.TokensPerSec = float64(msg.final.EvalCount) / time.Duration(msg.final.EvalDuration).Seconds()
+		}
+		m.session.Runs = append(m.session.Runs, m.current)
+		m.status = fmt.Sprintf("✅ Done in %s", time.Since(m.current.StartedAt).Round(time.Millisecond))
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.editor, cmd = m.editor.Update(msg)
+	return m, cmd
+}
+
+// run starts streaming a generation for prompt
+func (m *playgroundModel) run(prompt string) tea.Cmd {
+	m.lastPrompt = prompt
+	m.streaming = true
+	m.chunks = 0
+	m.current = PlaygroundRun{Prompt: prompt, StartedAt: time.Now()}
+	m.response.SetContent("")
+	m.status = "⏳ Generating..."
+
+	m.tokens = make(chan tea.Msg, 64)
+	tokens := m.tokens
+	req := llm.GenerateRequest{
+		Model:  m.session.Model,
+		Prompt: prompt,
+		System: m.session.System,
+	}
+
+	go func() {
+		final, err := m.manager.StreamGenerate(req, func(chunk llm.GenerateResponse) error {
+			if chunk.Response != "" {
+				tokens <- playgroundTokenMsg(chunk.Response)
+			}
+			return nil
+		})
+		tokens <- playgroundDoneMsg{final: final, err: err}
+		close(tokens)
+	}()
+
+	return m.waitForToken()
+}
+
+// waitForToken returns a command that delivers the next streaming message
+func (m *playgroundModel) waitForToken() tea.Cmd {
+	tokens := m.tokens
+	return func() tea.Msg {
+		msg, ok := <-tokens
+		if !ok {
+			return nil
+		}
+		return msg
+	}
+}
+
+// save writes the session to the output file
+func (m *playgroundModel) save() {
+	path := m.output
+	if path == "" {
+		path = fmt.Sprintf("playground-%s.json", time.Now().Format("20060102-150405"))
+	}
+
+	data, err := json.MarshalIndent(m.session, "", "  ")
+	if err == nil {
+		err = os.WriteFile(path, data, 0644)
+	}
+	if err != nil {
+		m.status = fmt.Sprintf("❌ Failed to save session: %v", err)
+		return
+	}
+	m.status = fmt.Sprintf("💾 Saved %d runs to %s", len(m.session.Runs), path)
+}
+
+// resize lays out the panes for the current terminal size
+func (m *playgroundModel) resize() {
+	const editorHeight = 5
+	const statsWidth = 30
+	// Each bordered pane uses 2 rows/columns for the border and 2 columns
+	// for padding
+	m.editor.SetWidth(max(m.width-4, 10))
+	m.editor.SetHeight(editorHeight)
+	m.response.Width = max(m.width-statsWidth-8, 10)
+	m.response.Height = max(m.height-editorHeight-7, 3)
+}
+
+// stats renders the stats pane for the current run
+func (m *playgroundModel) stats() string {
+	run := m.current
+	tokensPerSec := run.TokensPerSec
+	evalDuration := time.Duration(run.EvalDurationMs) * time.Millisecond
+	totalTokens := run.PromptTokens + run.EvalTokens
+
+	// While streaming, estimate from chunks (Ollama sends about one token
+	// per chunk) until the final stats arrive
+	if m.streaming {
+		elapsed := time.Since(run.StartedAt)
+		evalDuration = elapsed
+		totalTokens = m.chunks
+		if elapsed > 0 {
+			tokensPerSec = float64(m.chunks) / elapsed.Seconds()
+		}
+	}
+
+	return strings.Join([]string{
+		playgroundTitleStyle.Render("Stats"),
+		"",
+		fmt.Sprintf("tokens/sec:    %.1f", tokensPerSec),
+		fmt.Sprintf("total_tokens:  %d", totalTokens),
+		fmt.Sprintf("prompt_tokens: %d", run.PromptTokens),
+		fmt.Sprintf("eval_duration: %s", evalDuration.Round(time.Millisecond)),
+		"",
+		fmt.Sprintf("runs:          %d", len(m.session.Runs)),
+	}, "\n")
+}
+
+func (m *playgroundModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+
+	response := playgroundPaneStyle.
+		Width(m.response.Width + 2).
+		Height(m.response.Height).
+		Render(m.response.View())
+	stats := playgroundPaneStyle.
+		Width(26).
+		Height(m.response.Height).
+		Render(m.stats())
+	editor := playgroundPaneStyle.Render(m.editor.View())
+
+	header := playgroundTitleStyle.Render("🧪 Playground: " + m.session.Model)
+	help := playgroundHelpStyle.Render("enter run • alt+enter newline • ctrl+r re-run • ctrl+s save • esc quit")
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		header+"  "+m.status,
+		lipgloss.JoinHorizontal(lipgloss.Top, response, stats),
+		editor,
+		help,
+	)
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/pxkundu/agent-as-code/internal/llm"
+	"github.com/spf13/cobra"
+)
+
+var llmPluginCmd = &cobra.Command{
+	Use:   "plugin",
+	Short: "List and run the tools models can call",
+	Long: `Work with LLM plugins, the tools a model can call during a chat.
+
+Each plugin has a name, a description and a JSON Schema for its arguments,
+which are sent to Ollama as tool definitions. When the model calls a tool,
+the plugin runs and its result is sent back to the model.
+
+Built-in plugins:
+  calculator  evaluates math expressions
+  web_search  searches the web with the API in AGENT_SEARCH_URL
+
+web_search sends the model's queries to a third party, so it is disabled
+until AGENT_SEARCH_URL is set, e.g. to https://api.duckduckgo.com/.
+
+Examples:
+  agent llm plugin list
+  agent llm plugin run calculator --args '{"expression": "2^10 / 3"}'
+  agent llm session chat support --plugins calculator,web_search`,
+}
+
+var llmPluginListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the available plugins",
+	Long: `List the available plugins with their descriptions.
+
+Examples:
+  agent llm plugin list
+  agent llm plugin list --schema`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return listLLMPlugins()
+	},
+}
+
+var llmPluginRunCmd = &cobra.Command{
+	Use:   "run [NAME]",
+	Short: "Run a plugin as a model would",
+	Long: `Run a plugin with JSON arguments, as a model's tool call would, and
+print its result.
+
+Examples:
+  agent llm plugin run calculator --args '{"expression": "sqrt(2) * 10"}'
+  AGENT_SEARCH_URL=https://api.duckduckgo.com/ agent llm plugin run web_search --args '{"query": "Ollama tool calling"}'`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runLLMPlugin(args[0])
+	},
+}
+
+var (
+	llmPluginSchema bool
+	llmPluginArgs   string
+)
+
+func init() {
+	llmCmd.AddCommand(llmPluginCmd)
+	llmPluginCmd.AddCommand(llmPluginListCmd)
+	llmPluginCmd.AddCommand(llmPluginRunCmd)
+
+	llmPluginListCmd.Flags().BoolVar(&llmPluginSchema, "schema", false, "also print each plugin's argument schema")
+	llmPluginRunCmd.Flags().StringVar(&llmPluginArgs, "args", "{}", "JSON arguments of the call")
+}
+
+func listLLMPlugins() error {
+	plugins := llm.DefaultPluginRegistry().Plugins()
+
+	if llmPluginSchema {
+		for _, p := range plugins {
+			fmt.Printf("🔌 %s\n", p.Name())
+			fmt.Printf("   %s\n", p.Description())
+			fmt.Printf("   Schema: %s\n\n", string(p.Schema()))
+		}
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tDESCRIPTION")
+	for _, p := range plugins {
+		fmt.Fprintf(w, "%s\t%s\n", p.Name(), truncateSentence(p.Description(), 80))
+	}
+	return w.Flush()
+}
+
+func runLLMPlugin(name string) error {
+	if !json.Valid([]byte(llmPluginArgs)) {
+		return fmt.Errorf("--args is not valid JSON")
+	}
+
+	result, err := llm.DefaultPluginRegistry().HandleToolCall(name, json.RawMessage(llmPluginArgs))
+	if err != nil {
+		return fmt.Errorf("%s failed: %v", name, err)
+	}
+
+	fmt.Println(result)
+	return nil
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/pxkundu/agent-as-code/internal/llm"
+	"github.com/spf13/cobra"
+)
+
+var llmProfileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Profile the inference performance of a local model",
+	Long: `Profile time to first token and throughput of a local model.
+
+This command runs the prompt --iterations times and collects the timings
+Ollama reports for each response (load, prompt evaluation and generation
+durations, and the number of generated tokens), along with the time to the
+first streamed token. It prints the mean, p50, p95 and p99 of each metric
+and writes the raw timings of every iteration to a CSV file for external
+analysis.
+
+The first iteration includes loading the model into memory unless it is
+already loaded, which shows up in load_duration and ttft.
+
+Formats:
+  table       statistics of every metric
+  flamegraph  statistics followed by an ASCII flame chart of where the
+              time of each request goes
+
+Examples:
+  agent llm profile --model llama2 --prompt "Explain recursion"
+  agent llm profile --model mistral --prompt "Write a haiku" --iterations 20
+  agent llm profile --model llama2 --prompt "Hello" --format flamegraph --csv timings.csv`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return profileInference()
+	},
+}
+
+var (
+	profileModel      string
+	profilePrompt     string
+	profileIterations int
+	profileFormat     string
+	profileCSV        string
+)
+
+func init() {
+	llmCmd.AddCommand(llmProfileCmd)
+
+	llmProfileCmd.Flags().StringVar(&profileModel, "model", "", "model to profile (required)")
+	llmProfileCmd.Flags().StringVar(&profilePrompt, "prompt", "", "prompt to run (required)")
+	llmProfileCmd.Flags().IntVar(&profileIterations, "iterations", 10, "number of times to run the prompt")
+	llmProfileCmd.Flags().StringVar(&profileFormat, "format", "table", "output format (table|flamegraph)")
+	llmProfileCmd.Flags().StringVar(&profileCSV, "csv", "inference-profile.csv", "CSV file for the raw timings (empty to skip)")
+	llmProfileCmd.MarkFlagRequired("model")
+	llmProfileCmd.MarkFlagRequired("prompt")
+}
+
+func profileInference() error {
+	if profileFormat != "table" && profileFormat != "flamegraph" {
+		return fmt.Errorf("invalid format '%s' (valid: table, flamegraph)", profileFormat)
+	}
+
+	fmt.Printf("⏱️  Profiling %s (%d iterations)\n", profileModel, profileIterations)
+	fmt.Println("=================================")
+
+	profiler := llm.NewInferenceProfiler()
+	profile, err := profiler.Profile(profileModel, profilePrompt, profileIterations, func(s llm.InferenceSample) {
+		fmt.Printf("  %d/%d  ttft %.1fms  total %.1fms  %d tokens  %.1f tokens/s\n",
+			s.Iteration, profileIterations,
+			float64(s.TTFT.Microseconds())/1000, float64(s.TotalDuration.Microseconds())/1000,
+			s.EvalCount, s.TokensPerSecond())
+	})
+	if err != nil {
+		return fmt.Errorf("profiling failed: %v", err)
+	}
+
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "METRIC\tUNIT\tMEAN\tP50\tP95\tP99\tMIN\tMAX")
+	for _, s := range profile.Stats() {
+		fmt.Fprintf(w, "%s\t%s\t%.1f\t%.1f\t%.1f\t%.1f\t%.1f\t%.1f\n", s.Name, s.Unit, s.Avg, s.P50, s.P95, s.P99, s.Min, s.Max)
+	}
+	w.Flush()
+
+	if profileFormat == "flamegraph" {
+		fmt.Println()
+		profile.WriteFlameChart(os.Stdout, 72)
+	}
+
+	if profileCSV != "" {
+		if err := profile.WriteCSV(profileCSV); err != nil {
+			return err
+		}
+		fmt.Printf("\n💾 Raw timings written to %s\n", profileCSV)
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pxkundu/agent-as-code/internal/llm"
+	"github.com/spf13/cobra"
+)
+
+var llmPromptTestCmd = &cobra.Command{
+	Use:   "prompt-test",
+	Short: "Run a batch of prompts and compare outputs across models",
+	Long: `Run a batch of prompts through several local models and compare the results.
+
+Each prompt is sent to each model. Responses, latency and token counts are
+written to a CSV file with the columns model, prompt_id, response,
+latency_ms and tokens. A prompt passes when the response contains every
+string in expected_contains (case-insensitive). A leaderboard of pass rates
+per model is printed at the end.
+
+The prompts file has the form:
+  prompts:
+    - id: capital
+      system: You are a geography expert
+      user: What is the capital of France?
+      expected_contains:
+        - Paris
+
+Examples:
+  agent llm prompt-test --prompts prompts.yaml --models llama2,mistral
+  agent llm prompt-test --prompts prompts.yaml --models llama2,mistral --output results.csv --concurrency 4`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPromptTest()
+	},
+}
+
+var (
+	promptTestPrompts     string
+	promptTestModels      string
+	promptTestOutput      string
+	promptTestConcurrency int
+)
+
+func init() {
+	llmCmd.AddCommand(llmPromptTestCmd)
+
+	llmPromptTestCmd.Flags().StringVar(&promptTestPrompts, "prompts", "", "YAML file with prompts to run (required)")
+	llmPromptTestCmd.Flags().StringVar(&promptTestModels, "models", "", "comma-separated list of models to compare (required)")
+	llmPromptTestCmd.Flags().StringVar(&promptTestOutput, "output", "results.csv", "output CSV file")
+	llmPromptTestCmd.Flags().IntVar(&promptTestConcurrency, "concurrency", 2, "maximum number of concurrent model calls")
+	llmPromptTestCmd.MarkFlagRequired("prompts")
+	llmPromptTestCmd.MarkFlagRequired("models")
+}
+
+func runPromptTest() error {
+	var models []string
+	for _, model := range strings.Split(promptTestModels, ",") {
+		if model = strings.TrimSpace(model); model != "" {
+			models = append(models, model)
+		}
+	}
+
+	prompts, err := llm.LoadPromptTests(promptTestPrompts)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("🧪 Running %d prompts against %d models\n", len(prompts), len(models))
+	fmt.Println("=================================")
+
+	tester := llm.NewPromptTester()
+	results, err := tester.Run(models, prompts, promptTestConcurrency)
+	if err != nil {
+		return fmt.Errorf("prompt test failed: %v", err)
+	}
+
+	if err := llm.WritePromptTestCSV(results, promptTestOutput); err != nil {
+		return fmt.Errorf("failed to write results: %v", err)
+	}
+
+	failures := 0
+	for _, result := range results {
+		if result.Error != "" {
+			failures++
+		}
+	}
+	if failures > 0 {
+		fmt.Printf("⚠️  %d requests failed (see %s)\n", failures, promptTestOutput)
+	}
+
+	fmt.Printf("\n🏆 Leaderboard:\n")
+	fmt.Printf("%-4s %-30s %-10s %-12s\n", "#", "MODEL", "PASS RATE", "AVG LATENCY")
+	for i, stats := range llm.PromptTestLeaderboard(results) {
+		fmt.Printf("%-4d %-30s %-10s %-12s\n",
+			i+1,
+			stats.Model,
+			fmt.Sprintf("%.1f%%", stats.PassRate),
+			fmt.Sprintf("%dms", stats.AvgLatencyMs),
+		)
+	}
+
+	fmt.Printf("\n💾 Results saved to %s\n", promptTestOutput)
+	return nil
+}
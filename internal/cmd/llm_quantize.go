@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pxkundu/agent-as-code/internal/llm"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var llmQuantizeCmd = &cobra.Command{
+	Use:   "quantize",
+	Short: "Quantize a GGUF model to lower precision",
+	Long: `Quantize a GGUF model to lower precision using llama.cpp.
+
+Lower precision models such as Q4_K_M or Q8_0 need less memory and run
+faster on CPU-only machines at a small cost in quality. This command
+generates a shell script wrapping llama.cpp's quantize binary next to the
+output file and runs it.
+
+The llama.cpp location is read from the LLAMACPP_PATH environment variable
+or the llm.llamacpp-path config key, and may point either at the quantize
+binary or at a llama.cpp checkout.
+
+Examples:
+  agent llm quantize --model llama-2-7b.f16.gguf --output llama-2-7b.Q4_K_M.gguf
+  agent llm quantize --model model.gguf --output model.Q8_0.gguf --type Q8_0
+  agent llm quantize --model model.gguf --output model.Q4_K_M.gguf --script-only`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return quantizeModel()
+	},
+}
+
+var (
+	quantizeModelPath  string
+	quantizeOutput     string
+	quantizeType       string
+	quantizeScriptOnly bool
+)
+
+func init() {
+	llmCmd.AddCommand(llmQuantizeCmd)
+
+	llmQuantizeCmd.Flags().StringVar(&quantizeModelPath, "model", "", "path to the GGUF model to quantize (required)")
+	llmQuantizeCmd.Flags().StringVar(&quantizeOutput, "output", "", "path of the quantized GGUF model (required)")
+	llmQuantizeCmd.Flags().StringVar(&quantizeType, "type", "Q4_K_M", "quantization type (e.g. Q4_K_M, Q5_K_M, Q8_0)")
+	llmQuantizeCmd.Flags().BoolVar(&quantizeScriptOnly, "script-only", false, "only generate the quantization script")
+	llmQuantizeCmd.MarkFlagRequired("model")
+	llmQuantizeCmd.MarkFlagRequired("output")
+}
+
+func quantizeModel() error {
+	fmt.Printf("🗜️  Quantizing %s to %s\n", quantizeModelPath, quantizeType)
+	fmt.Println("=================================")
+
+	llamaCppPath := os.Getenv("LLAMACPP_PATH")
+	if llamaCppPath == "" {
+		llamaCppPath = viper.GetString("llm.llamacpp-path")
+	}
+
+	quantizer := llm.NewQuantizer(llamaCppPath)
+
+	if info, err := os.Stat(quantizeModelPath); err == nil {
+		if estimate, err := quantizer.EstimateSize(quantizeModelPath, quantizeType); err == nil {
+			fmt.Printf("📦 Current size: %s\n", formatSize(info.Size()))
+			fmt.Printf("📦 Estimated size: %s\n\n", formatSize(estimate))
+		} else {
+			fmt.Printf("⚠️  Could not estimate output size: %v\n\n", err)
+		}
+	}
+
+	if quantizeScriptOnly {
+		scriptPath, err := quantizer.WriteScript(quantizeModelPath, quantizeOutput, quantizeType)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("📝 Quantization script written to %s\n", scriptPath)
+		return nil
+	}
+
+	if err := quantizer.Quantize(quantizeModelPath, quantizeOutput, quantizeType); err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Quantized model saved to %s\n", quantizeOutput)
+	return nil
+}
@@ -0,0 +1,557 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/chzyer/readline"
+	"github.com/pxkundu/agent-as-code/internal/llm"
+	"github.com/spf13/cobra"
+)
+
+var llmReplCmd = &cobra.Command{
+	Use:   "repl [MODEL]",
+	Short: "Chat with a local model in an interactive REPL",
+	Long: `Chat with a local model from a line-editing prompt.
+
+Responses stream as they are generated, with code blocks highlighted.
+Input history is kept in ~/.agent/llm_history and can be searched with
+Ctrl+R. End a line with \ to continue the message on the next line.
+
+Without MODEL, the first locally available model is used.
+
+Commands (Tab completes them and model names):
+  /model [NAME]        show or switch the model
+  /temperature [VALUE] show or set the sampling temperature (0-2)
+  /clear               forget the conversation so far
+  /save FILE           save the conversation as JSON
+  /load FILE           continue a saved conversation
+  /help                show the commands
+  /exit                quit (or Ctrl+D)
+
+Examples:
+  agent llm repl
+  agent llm repl llama2 --system "You are a terse assistant"
+  agent llm repl codellama --temperature 0.2`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		model := ""
+		if len(args) > 0 {
+			model = args[0]
+		}
+		var temperature *float64
+		if cmd.Flags().Changed("temperature") {
+			temperature = &replTemperature
+		}
+		return runRepl(model, replSystem, temperature)
+	},
+}
+
+var (
+	replSystem      string
+	replTemperature float64
+)
+
+// replHistoryFile is where the REPL keeps its input history, in ~/.agent
+const replHistoryFile = "llm_history"
+
+func init() {
+	llmCmd.AddCommand(llmReplCmd)
+
+	llmReplCmd.Flags().StringVar(&replSystem, "system", "", "system prompt sent with every request")
+	llmReplCmd.Flags().Float64Var(&replTemperature, "temperature", 0, "sampling temperature (default: the model's)")
+}
+
+// ReplSession is the JSON document written by /save and read by /load
+type ReplSession struct {
+	Model       string        `json:"model"`
+	System      string        `json:"system,omitempty"`
+	Temperature *float64      `json:"temperature,omitempty"`
+	Messages    []llm.Message `json:"messages"`
+}
+
+// repl is the state of a REPL session
+type repl struct {
+	manager *llm.LocalLLMManager
+	rl      *readline.Instance
+	session ReplSession
+}
+
+func runRepl(model, system string, temperature *float64) error {
+	manager := llm.NewLocalLLMManager()
+	if model == "" {
+		models, err := manager.ListLocalModels()
+		if err != nil {
+			return fmt.Errorf("failed to list local models: %v", err)
+		}
+		if len(models) == 0 {
+			return fmt.Errorf("no local models found, pull one with 'agent llm pull MODEL'")
+		}
+		model = models[0].Name
+	}
+
+	r := &repl{
+		manager: manager,
+		session: ReplSession{Model: model, System: system, Temperature: temperature},
+	}
+
+	rl, err := readline.NewEx(&readline.Config{
+		Prompt:          r.prompt(),
+		HistoryFile:     replHistoryPath(),
+		AutoComplete:    r.completer(),
+		InterruptPrompt: "^C",
+		EOFPrompt:       "/exit",
+		// Multiline messages are saved once complete, see readMessage
+		DisableAutoSaveHistory: true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to start the REPL: %v", err)
+	}
+	defer rl.Close()
+	r.rl = rl
+
+	fmt.Printf("🤖 Chatting with %s at %s\n", model, manager.URL())
+	fmt.Println("Type /help for commands, Ctrl+D to quit.")
+	fmt.Println()
+
+	for {
+		input, err := r.readMessage()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		input = strings.TrimSpace(input)
+		switch {
+		case input == "":
+			continue
+		case strings.HasPrefix(input, "/"):
+			if quit := r.runCommand(input); quit {
+				return nil
+			}
+		default:
+			r.send(input)
+		}
+	}
+}
+
+// replHistoryPath returns the history file, creating ~/.agent if needed.
+// Without a home directory history is not persisted.
+func replHistoryPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	dir := filepath.Join(home, ".agent")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return ""
+	}
+	return filepath.Join(dir, replHistoryFile)
+}
+
+func (r *repl) prompt() string {
+	return r.session.Model + "> "
+}
+
+// readMessage reads one message, joining lines that end with a backslash.
+// Ctrl+C discards the message being typed.
+func (r *repl) readMessage() (string, error) {
+	var lines []string
+	r.rl.SetPrompt(r.prompt())
+	for {
+		line, err := r.rl.Readline()
+		if err == readline.ErrInterrupt {
+			if len(lines) == 0 && line == "" {
+				fmt.Println("(use /exit or Ctrl+D to quit)")
+			}
+			lines = nil
+			r.rl.SetPrompt(r.prompt())
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+
+		if strings.HasSuffix(line, `\`) {
+			lines = append(lines, strings.TrimSuffix(line, `\`))
+			r.rl.SetPrompt(strings.Repeat(" ", max(len(r.prompt())-4, 0)) + "... ")
+			continue
+		}
+		lines = append(lines, line)
+
+		message := strings.Join(lines, "\n")
+		if strings.TrimSpace(message) != "" {
+			// The history file holds one entry per line
+			_ = r.rl.SaveHistory(strings.TrimSpace(strings.Join(lines, " ")))
+		}
+		return message, nil
+	}
+}
+
+// completer completes the REPL commands, model names after /model and
+// JSON files after /save and /load
+func (r *repl) completer() *readline.PrefixCompleter {
+	return readline.NewPrefixCompleter(
+		readline.PcItem("/model", readline.PcItemDynamic(r.modelNames)),
+		readline.PcItem("/temperature"),
+		readline.PcItem("/clear"),
+		readline.PcItem("/save", readline.PcItemDynamic(replSessionFiles)),
+		readline.PcItem("/load", readline.PcItemDynamic(replSessionFiles)),
+		readline.PcItem("/help"),
+		readline.PcItem("/exit"),
+	)
+}
+
+// modelNames lists the local models for completion
+func (r *repl) modelNames(string) []string {
+	models, err := r.manager.ListLocalModels()
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(models))
+	for _, model := range models {
+		names = append(names, model.Name)
+	}
+	return names
+}
+
+// replSessionFiles lists the JSON files in the current directory for
+// completion
+func replSessionFiles(string) []string {
+	files, _ := filepath.Glob("*.json")
+	return files
+}
+
+// runCommand runs a slash command and reports whether the REPL should quit
+func (r *repl) runCommand(input string) bool {
+	fields := strings.Fields(input)
+	command, args := fields[0], fields[1:]
+
+	switch command {
+	case "/exit", "/quit":
+		return true
+
+	case "/help":
+		fmt.Println(`Commands:
+  /model [NAME]        show or switch the model
+  /temperature [VALUE] show or set the sampling temperature (0-2)
+  /clear               forget the conversation so far
+  /save FILE           save the conversation as JSON
+  /load FILE           continue a saved conversation
+  /exit                quit (or Ctrl+D)
+End a line with \ to continue the message on the next line.`)
+
+	case "/model":
+		if len(args) == 0 {
+			fmt.Printf("Model: %s\n", r.session.Model)
+			return false
+		}
+		r.session.Model = args[0]
+		fmt.Printf("✅ Switched to %s\n", r.session.Model)
+
+	case "/temperature":
+		if len(args) == 0 {
+			if r.session.Temperature == nil {
+				fmt.Println("Temperature: model default")
+			} else {
+				fmt.Printf("Temperature: %g\n", *r.session.Temperature)
+			}
+			return false
+		}
+		value, err := strconv.ParseFloat(args[0], 64)
+		if err != nil || value < 0 || value > 2 {
+			fmt.Printf("❌ Temperature must be a number between 0 and 2, got '%s'\n", args[0])
+			return false
+		}
+		r.session.Temperature = &value
+		fmt.Printf("✅ Temperature set to %g\n", value)
+
+	case "/clear":
+		r.session.Messages = nil
+		fmt.Println("✅ Conversation cleared")
+
+	case "/save":
+		if len(args) == 0 {
+			fmt.Println("❌ Usage: /save FILE")
+			return false
+		}
+		if err := r.save(args[0]); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return false
+		}
+		fmt.Printf("💾 Saved %d messages to %s\n", len(r.session.Messages), args[0])
+
+	case "/load":
+		if len(args) == 0 {
+			fmt.Println("❌ Usage: /load FILE")
+			return false
+		}
+		if err := r.load(args[0]); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return false
+		}
+		fmt.Printf("📂 Loaded %d messages with %s from %s\n", len(r.session.Messages), r.session.Model, args[0])
+
+	default:
+		fmt.Printf("❌ Unknown command %s, type /help for the commands\n", command)
+	}
+	return false
+}
+
+func (r *repl) save(path string) error {
+	data, err := json.MarshalIndent(r.session, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal session: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write session: %v", err)
+	}
+	return nil
+}
+
+func (r *repl) load(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read session: %v", err)
+	}
+	var session ReplSession
+	if err := json.Unmarshal(data, &session); err != nil {
+		return fmt.Errorf("failed to parse session %s: %v", path, err)
+	}
+	if session.Model == "" {
+		session.Model = r.session.Model
+	}
+	r.session = session
+	return nil
+}
+
+// send sends a user message with the conversation so far and streams the
+// reply. The message is only kept when a reply was received.
+func (r *repl) send(input string) {
+	var messages []llm.Message
+	if r.session.System != "" {
+		messages = append(messages, llm.Message{Role: "system", Content: r.session.System})
+	}
+	messages = append(messages, r.session.Messages...)
+	messages = append(messages, llm.Message{Role: "user", Content: input})
+
+	req := llm.ChatRequest{Model: r.session.Model, Messages: messages}
+	if r.session.Temperature != nil {
+		req.Options = map[string]interface{}{"temperature": *r.session.Temperature}
+	}
+
+	renderer := newReplRenderer(os.Stdout, isTerminal(os.Stdout) && os.Getenv("NO_COLOR") == "")
+	var reply strings.Builder
+	_, err := r.manager.StreamChat(req, func(chunk llm.ChatResponse) error {
+		reply.WriteString(chunk.Message.Content)
+		renderer.Write(chunk.Message.Content)
+		return nil
+	})
+	renderer.Flush()
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return
+	}
+	fmt.Println()
+
+	r.session.Messages = append(r.session.Messages,
+		llm.Message{Role: "user", Content: input},
+		llm.Message{Role: "assistant", Content: reply.String()},
+	)
+}
+
+const (
+	ansiReset   = "\033[0m"
+	ansiFaint   = "\033[2m"
+	ansiKeyword = "\033[35m" // magenta
+	ansiString  = "\033[32m" // green
+	ansiNumber  = "\033[33m" // yellow
+	ansiComment = "\033[90m" // bright black
+)
+
+// replRenderer writes a streamed reply, highlighting the code blocks.
+// Prose is written as it arrives; code is written a line at a time so
+// each line can be highlighted whole.
+type replRenderer struct {
+	out   io.Writer
+	color bool
+
+	line      string // the part of the current line not yet written
+	streaming bool   // part of the current line was written as prose
+	inCode    bool
+	lang      string
+}
+
+func newReplRenderer(out io.Writer, color bool) *replRenderer {
+	return &replRenderer{out: out, color: color}
+}
+
+// Write renders the next chunk of the reply
+func (r *replRenderer) Write(text string) {
+	for {
+		i := strings.IndexByte(text, '\n')
+		if i < 0 {
+			break
+		}
+		r.line += text[:i]
+		text = text[i+1:]
+		r.endLine()
+	}
+	r.line += text
+
+	// A line that may still turn out to be a fence is held back
+	if r.line != "" && !r.inCode && (r.streaming || !mayBeFence(r.line)) {
+		fmt.Fprint(r.out, r.line)
+		r.line = ""
+		r.streaming = true
+	}
+}
+
+// Flush writes what is left of the reply
+func (r *replRenderer) Flush() {
+	if r.line != "" || r.streaming {
+		r.endLine()
+	}
+	if r.inCode && r.color {
+		fmt.Fprint(r.out, ansiReset)
+	}
+	r.inCode = false
+}
+
+// endLine writes the current line, which is complete
+func (r *replRenderer) endLine() {
+	line := r.line
+	r.line = ""
+	if r.streaming {
+		r.streaming = false
+		fmt.Fprintln(r.out, line)
+		return
+	}
+
+	if fence := strings.TrimSpace(line); strings.HasPrefix(fence, "```") {
+		if !r.inCode {
+			r.lang = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(fence, "```")))
+		}
+		r.inCode = !r.inCode
+		fmt.Fprintln(r.out, r.paint(ansiFaint, line))
+		return
+	}
+	if r.inCode && r.color {
+		fmt.Fprintln(r.out, highlightCode(line, r.lang))
+		return
+	}
+	fmt.Fprintln(r.out, line)
+}
+
+func (r *replRenderer) paint(code, text string) string {
+	if !r.color {
+		return text
+	}
+	return code + text + ansiReset
+}
+
+// mayBeFence reports whether a partial line could still become a code
+// fence
+func mayBeFence(partial string) bool {
+	partial = strings.TrimLeft(partial, " \t")
+	return strings.HasPrefix("```", partial) || strings.HasPrefix(partial, "```")
+}
+
+// codeKeywords are highlighted in code blocks of any language
+var codeKeywords = map[string]bool{}
+
+func init() {
+	for _, keyword := range strings.Fields(`
+		func function def fn class struct interface type enum impl trait
+		package import from use module export as
+		var let const mut static public private protected pub
+		return if else elif for while do range switch case default match
+		break continue goto go defer select yield await async
+		try except catch finally raise throw with in is not and or new
+		lambda pass del global self this super
+		true false nil null None True False undefined
+		echo then fi done esac local`) {
+		codeKeywords[keyword] = true
+	}
+}
+
+// hashCommentLanguages are the code block languages whose comments start
+// with #
+var hashCommentLanguages = map[string]bool{
+	"python": true, "py": true, "sh": true, "bash": true, "shell": true, "zsh": true,
+	"yaml": true, "yml": true, "toml": true, "ruby": true, "rb": true, "perl": true,
+	"r": true, "dockerfile": true, "makefile": true, "make": true, "ini": true, "conf": true,
+}
+
+// highlightCode colors the keywords, strings, numbers and comments of a
+// line of code with ANSI escapes. It is a lexer for the shape most
+// languages share rather than a parser of any one of them.
+func highlightCode(line, lang string) string {
+	hashComments := hashCommentLanguages[lang] || strings.HasPrefix(strings.TrimSpace(line), "#")
+	sqlComments := lang == "sql" || lang == "lua"
+
+	var b strings.Builder
+	for i := 0; i < len(line); {
+		rest := line[i:]
+		c := line[i]
+		switch {
+		case strings.HasPrefix(rest, "//") || (hashComments && c == '#') || (sqlComments && strings.HasPrefix(rest, "--")):
+			b.WriteString(ansiComment + rest + ansiReset)
+			return b.String()
+
+		case c == '"' || c == '\'' || c == '`':
+			end := i + 1
+			for end < len(line) && line[end] != c {
+				if line[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			end = min(end+1, len(line))
+			b.WriteString(ansiString + line[i:end] + ansiReset)
+			i = end
+
+		case isIdentStart(c):
+			end := i
+			for end < len(line) && (isIdentStart(line[end]) || isDigit(line[end])) {
+				end++
+			}
+			word := line[i:end]
+			if codeKeywords[word] {
+				b.WriteString(ansiKeyword + word + ansiReset)
+			} else {
+				b.WriteString(word)
+			}
+			i = end
+
+		case isDigit(c):
+			end := i
+			for end < len(line) && (isDigit(line[end]) || line[end] == '.' || line[end] == '_' || line[end] == 'x' ||
+				(line[end] >= 'a' && line[end] <= 'f') || (line[end] >= 'A' && line[end] <= 'F')) {
+				end++
+			}
+			b.WriteString(ansiNumber + line[i:end] + ansiReset)
+			i = end
+
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return b.String()
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pxkundu/agent-as-code/internal/llm"
+	"github.com/spf13/cobra"
+)
+
+var llmRoleplayCmd = &cobra.Command{
+	Use:   "roleplay",
+	Short: "Chat with a local model playing a custom persona",
+	Long: `Start an interactive conversation with a local model playing a persona.
+
+The persona file sets the character's name, role, instructions, sampling
+temperature and example dialogs, which are shown to the model as few-shot
+examples:
+
+  name: Captain Byte
+  role: a pirate who teaches networking
+  instructions: |
+    Explain networking concepts using nautical metaphors.
+    Keep answers under five sentences.
+  temperature: 0.8
+  example_dialogs:
+    - user: What is a packet?
+      assistant: Arr, a packet be a small crate of cargo ...
+
+Long conversations tend to drift out of character. --max-drift-check N
+repeats the persona's system prompt every N turns. Unlike 'agent llm
+session chat', the conversation is not saved.
+
+Type /exit or press Ctrl+D to leave.
+
+Examples:
+  agent llm roleplay --model llama2 --persona-file persona.yaml
+  agent llm roleplay --model mistral:7b --persona-file persona.yaml --max-drift-check 5`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		model, _ := cmd.Flags().GetString("model")
+		personaFile, _ := cmd.Flags().GetString("persona-file")
+		driftCheck, _ := cmd.Flags().GetInt("max-drift-check")
+		return roleplay(model, personaFile, driftCheck)
+	},
+}
+
+func init() {
+	llmCmd.AddCommand(llmRoleplayCmd)
+
+	llmRoleplayCmd.Flags().String("model", "", "model that plays the persona (required)")
+	llmRoleplayCmd.Flags().String("persona-file", "", "YAML file describing the persona (required)")
+	llmRoleplayCmd.Flags().Int("max-drift-check", 0, "repeat the persona's system prompt every N turns (0 disables)")
+	llmRoleplayCmd.MarkFlagRequired("model")
+	llmRoleplayCmd.MarkFlagRequired("persona-file")
+}
+
+func roleplay(model, personaFile string, driftCheck int) error {
+	if driftCheck < 0 {
+		return fmt.Errorf("--max-drift-check must not be negative")
+	}
+
+	persona, err := llm.LoadPersona(personaFile)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("🎭 %s", persona.Name)
+	if persona.Role != "" {
+		fmt.Printf(" (%s)", persona.Role)
+	}
+	fmt.Printf(" played by %s\n", model)
+	fmt.Println("Type /exit or press Ctrl+D to leave")
+
+	conversation := llm.NewRoleplay(model, persona, driftCheck)
+	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for {
+		fmt.Print("\n> ")
+		if !scanner.Scan() {
+			fmt.Println()
+			break
+		}
+
+		input := strings.TrimSpace(scanner.Text())
+		if input == "" {
+			continue
+		}
+		if input == "/exit" || input == "/quit" {
+			break
+		}
+
+		fmt.Printf("%s: ", persona.Name)
+		_, err := conversation.Send(input, func(token string) {
+			fmt.Print(token)
+		})
+		fmt.Println()
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+		}
+	}
+
+	return scanner.Err()
+}
@@ -0,0 +1,284 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pxkundu/agent-as-code/internal/llm"
+	"github.com/spf13/cobra"
+)
+
+var llmSessionCmd = &cobra.Command{
+	Use:   "session",
+	Short: "Manage persistent multi-turn chat sessions",
+	Long: `Manage persistent chat sessions with local models.
+
+A session stores the model, an optional system prompt and the full
+conversation history in ~/.agent/sessions/<name>.json, so a conversation
+can be resumed later. Sessions can safely be used from several terminals
+at once.
+
+Examples:
+  agent llm session create --model llama2 --name support
+  agent llm session chat support
+  agent llm session list
+  agent llm session export support --format markdown`,
+}
+
+var llmSessionCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a chat session",
+	Long: `Create a new, empty chat session.
+
+Examples:
+  agent llm session create --model llama2 --name support
+  agent llm session create --model mistral:7b --name reviewer --system "You review Go code"`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		model, _ := cmd.Flags().GetString("model")
+		name, _ := cmd.Flags().GetString("name")
+		system, _ := cmd.Flags().GetString("system")
+		return createSession(name, model, system)
+	},
+}
+
+var llmSessionChatCmd = &cobra.Command{
+	Use:   "chat [NAME]",
+	Short: "Resume a chat session",
+	Long: `Resume a chat session, appending every new turn to its history.
+
+Type a message and press Enter to send it. Type /exit or press Ctrl+D to
+leave the session.
+
+--plugins offers plugins to the model as tools (see 'agent llm plugin
+list'). The model calls them through Ollama's tool calling, which needs a
+model with tool support, such as llama3.1 or qwen2.5. Tool calls are
+shown as they run; only the question and the final answer are saved.
+
+Examples:
+  agent llm session chat support
+  agent llm session chat support --plugins calculator,web_search`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		plugins, _ := cmd.Flags().GetStringSlice("plugins")
+		return chatSession(args[0], plugins)
+	},
+}
+
+var llmSessionListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List chat sessions",
+	Long: `List chat sessions with their model, message count and last use.
+
+Examples:
+  agent llm session list`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return listSessions()
+	},
+}
+
+var llmSessionExportCmd = &cobra.Command{
+	Use:   "export [NAME]",
+	Short: "Export a chat session",
+	Long: `Export the conversation of a chat session as markdown or JSON.
+
+Examples:
+  agent llm session export support
+  agent llm session export support --format json --output support.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		output, _ := cmd.Flags().GetString("output")
+		return exportSession(args[0], format, output)
+	},
+}
+
+func init() {
+	llmCmd.AddCommand(llmSessionCmd)
+	llmSessionCmd.AddCommand(llmSessionCreateCmd)
+	llmSessionCmd.AddCommand(llmSessionChatCmd)
+	llmSessionCmd.AddCommand(llmSessionListCmd)
+	llmSessionCmd.AddCommand(llmSessionExportCmd)
+
+	llmSessionCreateCmd.Flags().String("model", "", "model to chat with (required)")
+	llmSessionCreateCmd.Flags().String("name", "", "session name (required)")
+	llmSessionCreateCmd.Flags().String("system", "", "system prompt for the session")
+	llmSessionCreateCmd.MarkFlagRequired("model")
+	llmSessionCreateCmd.MarkFlagRequired("name")
+
+	llmSessionChatCmd.Flags().StringSlice("plugins", nil, "plugins the model may call as tools (comma-separated)")
+
+	llmSessionExportCmd.Flags().String("format", "markdown", "export format (markdown|json)")
+	llmSessionExportCmd.Flags().String("output", "", "file to write (default: stdout)")
+}
+
+// newSessionManager returns a session manager for the default session
+// directory
+func newSessionManager() (*llm.SessionManager, error) {
+	dir, err := llm.DefaultSessionDir()
+	if err != nil {
+		return nil, err
+	}
+	return llm.NewSessionManager(dir), nil
+}
+
+func createSession(name, model, system string) error {
+	manager, err := newSessionManager()
+	if err != nil {
+		return err
+	}
+
+	if _, err := manager.Create(name, model, system); err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Created session '%s' with model %s\n", name, model)
+	fmt.Printf("💡 Start chatting with: agent llm session chat %s\n", name)
+	return nil
+}
+
+func chatSession(name string, pluginNames []string) error {
+	manager, err := newSessionManager()
+	if err != nil {
+		return err
+	}
+
+	var plugins *llm.PluginRegistry
+	if len(pluginNames) > 0 {
+		if plugins, err = llm.DefaultPluginRegistry().Select(pluginNames); err != nil {
+			return err
+		}
+	}
+
+	session, err := manager.Load(name)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("💬 Session '%s' (%s, %d previous messages)\n", session.Name, session.Model, len(session.History))
+	if plugins != nil {
+		fmt.Printf("🔌 Tools: %s\n", strings.Join(pluginNames, ", "))
+	}
+	fmt.Println("Type /exit or press Ctrl+D to leave")
+
+	modelManager := llm.NewLocalLLMManager()
+	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for {
+		fmt.Print("\n> ")
+		if !scanner.Scan() {
+			fmt.Println()
+			break
+		}
+
+		input := strings.TrimSpace(scanner.Text())
+		if input == "" {
+			continue
+		}
+		if input == "/exit" || input == "/quit" {
+			break
+		}
+
+		// Reload so turns added from another terminal are part of the context
+		session, err = manager.Load(name)
+		if err != nil {
+			return err
+		}
+
+		userMessage := llm.Message{Role: "user", Content: input}
+		req := llm.ChatRequest{
+			Model:    session.Model,
+			Messages: append(session.Messages(), userMessage),
+		}
+		printChunk := func(chunk llm.ChatResponse) error {
+			fmt.Print(chunk.Message.Content)
+			return nil
+		}
+
+		var final *llm.ChatResponse
+		if plugins != nil {
+			final, err = modelManager.StreamChatWithTools(req, plugins, printChunk, printToolCall)
+		} else {
+			final, err = modelManager.StreamChat(req, printChunk)
+		}
+		fmt.Println()
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			continue
+		}
+
+		if _, err := manager.Append(name, userMessage, final.Message); err != nil {
+			return fmt.Errorf("failed to save session: %v", err)
+		}
+	}
+
+	return scanner.Err()
+}
+
+// printToolCall shows a tool call the model made and its result
+func printToolCall(call llm.ToolCall, result string, err error) {
+	fmt.Printf("\n🔧 %s(%s)\n", call.Function.Name, string(call.Function.Arguments))
+	if err != nil {
+		fmt.Printf("   ❌ %v\n", err)
+		return
+	}
+	fmt.Printf("   → %s\n", truncateSentence(result, 200))
+}
+
+func listSessions() error {
+	manager, err := newSessionManager()
+	if err != nil {
+		return err
+	}
+
+	sessions, err := manager.List()
+	if err != nil {
+		return err
+	}
+
+	if len(sessions) == 0 {
+		fmt.Println("No chat sessions found")
+		fmt.Println("\n💡 Create one with: agent llm session create --model llama2 --name my-session")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tMODEL\tMESSAGES\tLAST USED")
+	for _, session := range sessions {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\n",
+			session.Name, session.Model, len(session.History), formatTime(session.UpdatedAt))
+	}
+	return w.Flush()
+}
+
+func exportSession(name, format, output string) error {
+	manager, err := newSessionManager()
+	if err != nil {
+		return err
+	}
+
+	session, err := manager.Load(name)
+	if err != nil {
+		return err
+	}
+
+	if output == "" {
+		return llm.ExportSession(session, format, os.Stdout)
+	}
+
+	file, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %v", output, err)
+	}
+	defer file.Close()
+
+	if err := llm.ExportSession(session, format, file); err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Exported session '%s' to %s\n", name, output)
+	return nil
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pxkundu/agent-as-code/internal/llm"
+	"github.com/spf13/cobra"
+)
+
+var llmSummarizeCmd = &cobra.Command{
+	Use:   "summarize",
+	Short: "Summarize a document with a local model",
+	Long: `Summarize a text document with a local model.
+
+Documents longer than the model's context window are split into chunks,
+each chunk is summarized, and the chunk summaries are summarized again
+into the final summary. Progress through the chunks is shown on stderr,
+so the summary on stdout can be redirected to a file.
+
+--max-length is the length of the summary in tokens.
+
+Examples:
+  agent llm summarize --model llama2 --input report.txt
+  agent llm summarize --model mistral --input notes.md --style bullets
+  cat meeting.txt | agent llm summarize --model llama2 --input - --style detailed --max-length 800`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		model, _ := cmd.Flags().GetString("model")
+		input, _ := cmd.Flags().GetString("input")
+		style, _ := cmd.Flags().GetString("style")
+		maxLength, _ := cmd.Flags().GetInt("max-length")
+		return summarizeDocument(model, input, style, maxLength)
+	},
+}
+
+func init() {
+	llmCmd.AddCommand(llmSummarizeCmd)
+
+	llmSummarizeCmd.Flags().String("model", "", "local model that writes the summary (required)")
+	llmSummarizeCmd.Flags().String("input", "", "file to summarize, or - for stdin (required)")
+	llmSummarizeCmd.Flags().String("style", "brief", fmt.Sprintf("summary style (%s)", strings.Join(llm.SummaryStyles(), "|")))
+	llmSummarizeCmd.Flags().Int("max-length", 500, "maximum length of the summary in tokens")
+	llmSummarizeCmd.MarkFlagRequired("model")
+	llmSummarizeCmd.MarkFlagRequired("input")
+}
+
+func summarizeDocument(model, input, style string, maxLength int) error {
+	var data []byte
+	var err error
+	if input == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(input)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read input: %v", err)
+	}
+
+	summary, err := llm.NewSummarizer(os.Stderr).Summarize(model, string(data), style, maxLength)
+	if err != nil {
+		return fmt.Errorf("summarization failed: %v", err)
+	}
+
+	fmt.Println(summary)
+	return nil
+}
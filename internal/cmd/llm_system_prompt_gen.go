@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/pxkundu/agent-as-code/internal/llm"
+	"github.com/spf13/cobra"
+)
+
+var llmSystemPromptGenCmd = &cobra.Command{
+	Use:   "system-prompt-gen",
+	Short: "Generate an optimized system prompt for an agent use case",
+	Long: `Generate a system prompt for an agent use case with a local model.
+
+The model writes a first prompt in the given --style, then refines it over
+--rounds rounds of self-evaluation: each round it scores the prompt from 1
+to 10, lists its weaknesses and rewrites it. The final prompt is printed,
+or written to --output-file.
+
+--test-prompts runs the final prompt against sample inputs, in the format
+of 'agent llm prompt-test' (the system field is replaced by the generated
+prompt). A test passes when the response contains every string in
+expected_contains.
+
+Styles:
+  concise           short prompt with the essential rules
+  detailed          thorough prompt with constraints and edge cases
+  chain-of-thought  prompt asking for step-by-step reasoning
+
+Examples:
+  agent llm system-prompt-gen --use-case "customer support for a SaaS product" --model llama3
+  agent llm system-prompt-gen --use-case "SQL query assistant" --model mistral --style detailed --output-file prompt.txt
+  agent llm system-prompt-gen --use-case "math tutor" --model llama3 --style chain-of-thought --test-prompts tests.yaml`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return generateSystemPrompt()
+	},
+}
+
+var (
+	systemPromptUseCase string
+	systemPromptModel   string
+	systemPromptStyle   string
+	systemPromptRounds  int
+	systemPromptOutput  string
+	systemPromptTests   string
+)
+
+func init() {
+	llmCmd.AddCommand(llmSystemPromptGenCmd)
+
+	llmSystemPromptGenCmd.Flags().StringVar(&systemPromptUseCase, "use-case", "", "what the agent does (required)")
+	llmSystemPromptGenCmd.Flags().StringVar(&systemPromptModel, "model", "", "model that writes and refines the prompt (required)")
+	llmSystemPromptGenCmd.Flags().StringVar(&systemPromptStyle, "style", llm.PromptStyleConcise, fmt.Sprintf("prompt style (%s)", strings.Join(llm.PromptStyles(), "|")))
+	llmSystemPromptGenCmd.Flags().IntVar(&systemPromptRounds, "rounds", llm.DefaultRefineRounds, "self-evaluation rounds")
+	llmSystemPromptGenCmd.Flags().StringVar(&systemPromptOutput, "output-file", "", "file to write the prompt to (default: stdout)")
+	llmSystemPromptGenCmd.Flags().StringVar(&systemPromptTests, "test-prompts", "", "YAML file of sample inputs to test the prompt with")
+	llmSystemPromptGenCmd.MarkFlagRequired("use-case")
+	llmSystemPromptGenCmd.MarkFlagRequired("model")
+}
+
+func generateSystemPrompt() error {
+	if systemPromptRounds < 0 {
+		return fmt.Errorf("--rounds cannot be negative")
+	}
+	if !slices.Contains(llm.PromptStyles(), systemPromptStyle) {
+		return fmt.Errorf("invalid --style '%s' (valid: %s)", systemPromptStyle, strings.Join(llm.PromptStyles(), ", "))
+	}
+
+	// Load the tests first so a bad file fails before the slow generation
+	var tests []llm.PromptTestCase
+	if systemPromptTests != "" {
+		var err error
+		if tests, err = llm.LoadPromptTests(systemPromptTests); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("✍️  Generating a %s system prompt with %s\n", systemPromptStyle, systemPromptModel)
+	fmt.Printf("   Use case: %s\n", systemPromptUseCase)
+
+	generator := llm.NewPromptGenerator()
+	result, err := generator.Generate(systemPromptModel, systemPromptUseCase, systemPromptStyle, systemPromptRounds, func(round llm.PromptRound) {
+		fmt.Printf("   Round %d/%d: scored %d/10", round.Round, systemPromptRounds, round.Score)
+		if len(round.Weaknesses) > 0 {
+			fmt.Printf(" (%s)", truncateSentence(round.Weaknesses[0], 70))
+		}
+		fmt.Println()
+	})
+	if err != nil {
+		return fmt.Errorf("prompt generation failed: %v", err)
+	}
+
+	if systemPromptOutput != "" {
+		if err := os.WriteFile(systemPromptOutput, []byte(result.Prompt+"\n"), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %v", systemPromptOutput, err)
+		}
+		fmt.Printf("✅ System prompt saved to %s\n", systemPromptOutput)
+	} else {
+		fmt.Println("\n📝 System prompt")
+		fmt.Println("=================================")
+		fmt.Println(result.Prompt)
+	}
+
+	if len(tests) == 0 {
+		return nil
+	}
+
+	fmt.Printf("\n🧪 Testing the prompt with %d inputs\n", len(tests))
+	results, err := generator.Test(systemPromptModel, result.Prompt, tests)
+	if err != nil {
+		return fmt.Errorf("prompt test failed: %v", err)
+	}
+
+	passed := 0
+	for _, r := range results {
+		switch {
+		case r.Error != "":
+			fmt.Printf("   ❌ %s: %s\n", r.PromptID, r.Error)
+		case r.Passed:
+			passed++
+			fmt.Printf("   ✅ %s\n", r.PromptID)
+		default:
+			fmt.Printf("   ❌ %s: %s\n", r.PromptID, truncateSentence(r.Response, 70))
+		}
+	}
+	fmt.Printf("   Passed: %d/%d\n", passed, len(results))
+
+	if passed < len(results) {
+		return fmt.Errorf("%d of %d prompt tests failed", len(results)-passed, len(results))
+	}
+	return nil
+}
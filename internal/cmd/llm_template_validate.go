@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pxkundu/agent-as-code/internal/llm"
+	"github.com/spf13/cobra"
+)
+
+var llmTemplateValidateCmd = &cobra.Command{
+	Use:   "template-validate [USE_CASE...]",
+	Short: "Check that generated agent code passes static analysis",
+	Long: `Generate agents into a temporary directory and check their code with
+static analysis, as 'agent llm create-agent --strict-codegen' does.
+
+Python code is compiled with python3 -m py_compile and, when flake8 is
+installed, checked for syntax errors and undefined names
+(flake8 --select=E9,F7,F8). TypeScript agents are checked with
+tsc --noEmit.
+
+Without USE_CASE every use case is checked. --prometheus, --with-auth and
+--enable-a2a check the code generated with those options.
+
+Examples:
+  agent llm template-validate
+  agent llm template-validate chatbot qa-system
+  agent llm template-validate --with-auth jwt --enable-a2a`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		failed, err := runTemplateValidate(args, llm.CreateAgentOptions{
+			Prometheus: templateValidatePrometheus,
+			AuthMode:   templateValidateAuth,
+			A2A:        templateValidateA2A,
+		})
+		if err != nil {
+			return err
+		}
+		if failed > 0 {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("generated code of %d use cases failed validation", failed)
+		}
+		return nil
+	},
+}
+
+var (
+	templateValidatePrometheus bool
+	templateValidateAuth       string
+	templateValidateA2A        bool
+)
+
+func init() {
+	llmCmd.AddCommand(llmTemplateValidateCmd)
+
+	llmTemplateValidateCmd.Flags().BoolVar(&templateValidatePrometheus, "prometheus", false, "check agents generated with Prometheus metrics")
+	llmTemplateValidateCmd.Flags().StringVar(&templateValidateAuth, "with-auth", "", "check agents generated with authentication (apikey, jwt, oauth2)")
+	llmTemplateValidateCmd.Flags().BoolVar(&templateValidateA2A, "enable-a2a", false, "check agents generated with agent2agent messaging")
+}
+
+// runTemplateValidate generates an agent for each use case and returns how
+// many failed static analysis
+func runTemplateValidate(useCases []string, options llm.CreateAgentOptions) (int, error) {
+	creator := llm.NewIntelligentAgentCreator()
+	if len(useCases) == 0 {
+		useCases = llm.AgentUseCases
+	}
+	for _, useCase := range useCases {
+		if err := creator.ValidateUseCase(useCase); err != nil {
+			return 0, fmt.Errorf("invalid use case: %v", err)
+		}
+	}
+
+	// Generated agents are Python
+	if err := llm.NewCodeValidator().Available("python"); err != nil {
+		return 0, fmt.Errorf("cannot check generated code: %v", err)
+	}
+
+	dir, err := os.MkdirTemp("", "agent-template-validate-")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	options.OutputDir = dir
+	options.StrictCodegen = true
+
+	fmt.Printf("🔍 Checking generated code of %d use cases\n\n", len(useCases))
+	failed := 0
+	for _, useCase := range useCases {
+		model, err := creator.GetRecommendedModel(useCase)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get recommended model: %v", err)
+		}
+
+		if _, err := creator.CreateAgent(useCase, model, options); err != nil {
+			failed++
+			fmt.Printf("❌ %s\n", useCase)
+			// Paths in the temporary directory mean nothing once it is gone
+			message := strings.ReplaceAll(err.Error(), dir+string(os.PathSeparator), "")
+			for _, line := range strings.Split(message, "\n") {
+				fmt.Printf("   %s\n", line)
+			}
+			continue
+		}
+		fmt.Printf("✅ %s\n", useCase)
+	}
+
+	fmt.Printf("\n📊 %d passed, %d failed\n", len(useCases)-failed, failed)
+	return failed, nil
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pxkundu/agent-as-code/internal/llm"
+	"github.com/spf13/cobra"
+)
+
+var llmTokensCmd = &cobra.Command{
+	Use:   "tokens",
+	Short: "Count the tokens in a text file or stdin for a model",
+	Long: `Count the tokens text encodes to with a local model's tokenizer.
+
+The text is sent to Ollama as a raw prompt with generation disabled and the
+prompt token count is read from the response. Large inputs are counted in
+chunks and summed.
+
+Formats:
+  number     print the total token count only
+  breakdown  print the token count of every sentence and the total
+
+Examples:
+  agent llm tokens --model llama2 --input prompt.txt
+  cat prompt.txt | agent llm tokens --model llama2 --input -
+  agent llm tokens --model mistral:7b --input prompt.txt --format breakdown`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		model, _ := cmd.Flags().GetString("model")
+		input, _ := cmd.Flags().GetString("input")
+		format, _ := cmd.Flags().GetString("format")
+		return countTokens(model, input, format)
+	},
+}
+
+func init() {
+	llmCmd.AddCommand(llmTokensCmd)
+
+	llmTokensCmd.Flags().String("model", "", "model whose tokenizer is used (required)")
+	llmTokensCmd.Flags().String("input", "", "text file to count, or - for stdin (required)")
+	llmTokensCmd.Flags().String("format", "number", "output format (number|breakdown)")
+	llmTokensCmd.MarkFlagRequired("model")
+	llmTokensCmd.MarkFlagRequired("input")
+}
+
+func countTokens(model, input, format string) error {
+	if format != "number" && format != "breakdown" {
+		return fmt.Errorf("invalid format '%s' (valid: number, breakdown)", format)
+	}
+
+	var data []byte
+	var err error
+	if input == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(input)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read input: %v", err)
+	}
+
+	tokenizer := llm.NewTokenizer()
+
+	if format == "number" {
+		count, err := tokenizer.CountTokens(model, string(data))
+		if err != nil {
+			return err
+		}
+		fmt.Println(count)
+		return nil
+	}
+
+	sentences, err := tokenizer.CountSentences(model, string(data))
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TOKENS\tSENTENCE")
+	total := 0
+	for _, sentence := range sentences {
+		fmt.Fprintf(w, "%d\t%s\n", sentence.Tokens, truncateSentence(sentence.Text, 70))
+		total += sentence.Tokens
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Printf("\nTotal: %d tokens in %d sentences\n", total, len(sentences))
+	return nil
+}
+
+// truncateSentence shortens s to at most n runes for table display
+func truncateSentence(s string, n int) string {
+	runes := []rune(strings.Join(strings.Fields(s), " "))
+	if len(runes) <= n {
+		return string(runes)
+	}
+	return string(runes[:n-3]) + "..."
+}
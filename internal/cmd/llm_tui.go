@@ -0,0 +1,335 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pxkundu/agent-as-code/internal/llm"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var llmTUICmd = &cobra.Command{
+	Use:   "tui",
+	Short: "Full-screen dashboard of running models and system stats",
+	Long: `Open a full-screen dashboard of the local Ollama server, refreshed every
+2 seconds.
+
+The dashboard shows:
+  - the running models with their memory use and request counts
+  - a sparkline of requests/sec over the last 60 seconds
+  - CPU and GPU utilization
+  - the 20 most recent lines of the Ollama log
+
+Ollama does not report request counts, so they are estimated from the
+request lines in its log and from each model's keep-alive being renewed.
+
+The log is read from --log-file, or the llm.ollama-log config key. Without
+either it is read from the journal ('journalctl -u ollama'), or from
+~/.ollama/logs/server.log on macOS.
+
+Keys:
+  ↑/↓  select a model
+  k    unload the selected model from memory
+  r    refresh now
+  q    quit
+
+Examples:
+  agent llm tui
+  agent llm tui --log-file /var/log/ollama.log`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runLLMTUI()
+	},
+}
+
+var tuiLogFile string
+
+// tuiRefreshInterval is how often the dashboard samples Ollama
+const tuiRefreshInterval = 2 * time.Second
+
+// tuiLogLines is how many lines of the Ollama log are shown
+const tuiLogLines = 20
+
+func init() {
+	llmCmd.AddCommand(llmTUICmd)
+
+	llmTUICmd.Flags().StringVar(&tuiLogFile, "log-file", "", "Ollama log file to tail (default: llm.ollama-log, or the journal)")
+}
+
+type tuiSnapshotMsg *llm.MonitorSnapshot
+
+type tuiTickMsg int
+
+type tuiUnloadMsg struct {
+	model string
+	err   error
+}
+
+var (
+	tuiPaneStyle     = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0, 1)
+	tuiTitleStyle    = lipgloss.NewStyle().Bold(true)
+	tuiSelectedStyle = lipgloss.NewStyle().Reverse(true)
+	tuiFaintStyle    = lipgloss.NewStyle().Faint(true)
+)
+
+// tuiModel is the bubbletea model of the dashboard
+type tuiModel struct {
+	monitor  *llm.ModelMonitor
+	snapshot *llm.MonitorSnapshot
+
+	selected   int
+	width      int
+	height     int
+	tick       int
+	refreshing bool
+	status     string
+}
+
+func runLLMTUI() error {
+	logFile := tuiLogFile
+	if logFile == "" {
+		logFile = viper.GetString("llm.ollama-log")
+	}
+
+	m := &tuiModel{monitor: llm.NewModelMonitor(logFile, tuiLogLines)}
+	_, err := tea.NewProgram(m, tea.WithAltScreen()).Run()
+	return err
+}
+
+func (m *tuiModel) Init() tea.Cmd {
+	return m.refresh()
+}
+
+func (m *tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			return m, tea.Quit
+		case "r":
+			return m, m.refresh()
+		case "up":
+			m.selected = max(m.selected-1, 0)
+		case "down":
+			if m.snapshot != nil {
+				m.selected = min(m.selected+1, max(len(m.snapshot.Models)-1, 0))
+			}
+		case "k":
+			if m.snapshot == nil || len(m.snapshot.Models) == 0 {
+				return m, nil
+			}
+			name := m.snapshot.Models[m.selected].Name
+			m.status = fmt.Sprintf("⏳ Unloading %s...", name)
+			return m, func() tea.Msg {
+				return tuiUnloadMsg{model: name, err: m.monitor.Unload(name)}
+			}
+		}
+		return m, nil
+
+	case tuiTickMsg:
+		// Only the latest tick refreshes; a forced refresh replaces the
+		// pending one
+		if int(msg) != m.tick {
+			return m, nil
+		}
+		return m, m.refresh()
+
+	case tuiSnapshotMsg:
+		m.refreshing = false
+		m.snapshot = msg
+		m.selected = min(m.selected, max(len(m.snapshot.Models)-1, 0))
+		m.tick++
+		tick := m.tick
+		return m, tea.Tick(tuiRefreshInterval, func(time.Time) tea.Msg {
+			return tuiTickMsg(tick)
+		})
+
+	case tuiUnloadMsg:
+		if msg.err != nil {
+			m.status = fmt.Sprintf("❌ Failed to unload %s: %v", msg.model, msg.err)
+			return m, nil
+		}
+		m.status = fmt.Sprintf("✅ Unloaded %s", msg.model)
+		return m, m.refresh()
+	}
+
+	return m, nil
+}
+
+// refresh returns a command that samples the monitor, unless a refresh is
+// already running
+func (m *tuiModel) refresh() tea.Cmd {
+	if m.refreshing {
+		return nil
+	}
+	m.refreshing = true
+	monitor := m.monitor
+	return func() tea.Msg {
+		return tuiSnapshotMsg(monitor.Refresh())
+	}
+}
+
+func (m *tuiModel) View() string {
+	if m.width == 0 || m.snapshot == nil {
+		return "Loading..."
+	}
+
+	// Each bordered pane uses 2 columns for the border and 2 for padding
+	const sideWidth = 36
+	mainWidth := max(m.width-sideWidth-8, 30)
+
+	header := tuiTitleStyle.Render("📊 Ollama: "+m.monitor.URL()) +
+		tuiFaintStyle.Render("  updated "+m.snapshot.Time.Format("15:04:05")) + "  " + m.status
+	models := tuiPaneStyle.Width(mainWidth + 2).Render(m.modelsView(mainWidth))
+	side := tuiPaneStyle.Width(sideWidth - 2).Height(lipgloss.Height(models) - 2).Render(m.statsView(sideWidth - 4))
+	logs := tuiPaneStyle.Width(max(m.width-2, 10)).Render(m.logsView(max(m.width-4, 10)))
+	help := tuiFaintStyle.Render("↑/↓ select • k unload model • r refresh • q quit")
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		header,
+		lipgloss.JoinHorizontal(lipgloss.Top, models, side),
+		logs,
+		help,
+	)
+}
+
+// modelsView renders the table of running models
+func (m *tuiModel) modelsView(width int) string {
+	lines := []string{tuiTitleStyle.Render("Running models")}
+	if err := m.snapshot.ModelsError; err != nil {
+		return strings.Join(append(lines, "", "❌ "+err.Error()), "\n")
+	}
+	if len(m.snapshot.Models) == 0 {
+		return strings.Join(append(lines, "", tuiFaintStyle.Render("(no models loaded)")), "\n")
+	}
+
+	nameWidth := max(width-44, 12)
+	row := func(name, size, placement, requests, expires string) string {
+		return fmt.Sprintf("%-*s %9s %9s %9s %12s", nameWidth, truncateSentence(name, nameWidth), size, placement, requests, expires)
+	}
+
+	lines = append(lines, "", tuiFaintStyle.Render(row("NAME", "SIZE", "PLACEMENT", "REQUESTS", "EXPIRES")))
+	for i, model := range m.snapshot.Models {
+		placement := "CPU"
+		if model.Size > 0 && model.SizeVRAM > 0 {
+			placement = fmt.Sprintf("%.0f%% GPU", float64(model.SizeVRAM)/float64(model.Size)*100)
+		}
+		line := row(model.Name, formatSize(model.Size), placement, fmt.Sprint(model.Requests), tuiExpiry(model.ExpiresAt, m.snapshot.Time))
+		if i == m.selected {
+			line = tuiSelectedStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// statsView renders the request rate, CPU and GPU pane
+func (m *tuiModel) statsView(width int) string {
+	rate := m.snapshot.RequestRate
+	current, peak := 0.0, 0.0
+	if len(rate) > 0 {
+		current = rate[len(rate)-1]
+	}
+	for _, r := range rate {
+		peak = max(peak, r)
+	}
+
+	lines := []string{
+		tuiTitleStyle.Render("Requests/sec (60s)"),
+		sparkline(rate, min(llm.MonitorHistory, width)),
+		fmt.Sprintf("now %.1f • peak %.1f", current, peak),
+		"",
+		tuiTitleStyle.Render("Utilization"),
+	}
+
+	if m.snapshot.CPUPercent >= 0 {
+		lines = append(lines, fmt.Sprintf("CPU  %5.1f%%", m.snapshot.CPUPercent))
+	} else {
+		lines = append(lines, "CPU  n/a")
+	}
+
+	switch {
+	case m.snapshot.GPUError != nil:
+		lines = append(lines, "GPU  n/a")
+	case len(m.snapshot.GPUs) == 0:
+		lines = append(lines, "GPU  none detected")
+	}
+	for i, gpu := range m.snapshot.GPUs {
+		utilization := gpu.Utilization
+		if utilization == "" {
+			utilization = "n/a"
+		}
+		lines = append(lines, truncateSentence(fmt.Sprintf("GPU%d %s (%s)", i, utilization, gpu.Name), width))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// logsView renders the tail of the Ollama log
+func (m *tuiModel) logsView(width int) string {
+	lines := []string{tuiTitleStyle.Render("Ollama log") + tuiFaintStyle.Render(" ("+m.monitor.LogSource()+")")}
+	if err := m.snapshot.LogError; err != nil {
+		lines = append(lines, "❌ "+err.Error())
+	}
+
+	logs := m.snapshot.Logs
+	for i := 0; i < tuiLogLines; i++ {
+		if i < len(logs) {
+			lines = append(lines, truncateSentence(logs[i], width))
+		} else {
+			lines = append(lines, "")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// sparkline draws values as a line of block characters, scaled to the
+// largest value and right-aligned in width columns
+func sparkline(values []float64, width int) string {
+	const blocks = "▁▂▃▄▅▆▇█"
+	levels := []rune(blocks)
+
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+	peak := 0.0
+	for _, v := range values {
+		peak = max(peak, v)
+	}
+
+	var b strings.Builder
+	b.WriteString(strings.Repeat(" ", width-len(values)))
+	for _, v := range values {
+		level := 0
+		if peak > 0 {
+			level = int(v / peak * float64(len(levels)-1))
+		}
+		b.WriteRune(levels[level])
+	}
+	return b.String()
+}
+
+// tuiExpiry formats when a model is unloaded, relative to now
+func tuiExpiry(expiresAt string, now time.Time) string {
+	t, err := time.Parse(time.RFC3339Nano, expiresAt)
+	if err != nil {
+		return "-"
+	}
+	remaining := t.Sub(now)
+	switch {
+	case remaining <= 0:
+		return "now"
+	case remaining > 100*365*24*time.Hour:
+		// keep_alive -1 keeps a model loaded for good
+		return "never"
+	default:
+		return "in " + remaining.Round(time.Second).String()
+	}
+}
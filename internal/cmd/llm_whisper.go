@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pxkundu/agent-as-code/internal/llm"
+	"github.com/spf13/cobra"
+)
+
+var llmWhisperCmd = &cobra.Command{
+	Use:   "whisper",
+	Short: "Transcribe audio with a local Whisper model",
+	Long: `Transcribe an audio file to text with a local Whisper model.
+
+The audio is uploaded to the OpenAI-compatible transcription endpoint
+(/v1/audio/transcriptions) of a local Whisper server, such as
+faster-whisper-server, at --server. Without --server, the Ollama endpoint
+in OLLAMA_HOST is used, for setups that serve Whisper models next to Ollama.
+
+--timestamps controls the timestamps in the output:
+  none     the plain transcript
+  segment  one line per segment, with its start and end time
+  word     one line per word, with its start and end time
+
+Without --output, the transcript is printed to stdout.
+
+Examples:
+  agent llm whisper --model openai/whisper --input audio.mp3
+  agent llm whisper --model Systran/faster-whisper-small --input call.wav --output text.txt --language en
+  agent llm whisper --model openai/whisper --input talk.mp3 --timestamps word --server http://localhost:8000`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return transcribeAudio()
+	},
+}
+
+var (
+	whisperModel      string
+	whisperInput      string
+	whisperOutput     string
+	whisperLanguage   string
+	whisperTimestamps string
+	whisperServer     string
+)
+
+func init() {
+	llmCmd.AddCommand(llmWhisperCmd)
+
+	llmWhisperCmd.Flags().StringVar(&whisperModel, "model", "", "Whisper model to transcribe with (required)")
+	llmWhisperCmd.Flags().StringVar(&whisperInput, "input", "", "audio file to transcribe (required)")
+	llmWhisperCmd.Flags().StringVar(&whisperOutput, "output", "", "file to write the transcript to (default: stdout)")
+	llmWhisperCmd.Flags().StringVar(&whisperLanguage, "language", "", "language of the audio, e.g. en (default: detected)")
+	llmWhisperCmd.Flags().StringVar(&whisperTimestamps, "timestamps", llm.TimestampsNone, "timestamps in the output (none|segment|word)")
+	llmWhisperCmd.Flags().StringVar(&whisperServer, "server", "", "URL of the Whisper server (default: OLLAMA_HOST)")
+	llmWhisperCmd.MarkFlagRequired("model")
+	llmWhisperCmd.MarkFlagRequired("input")
+}
+
+func transcribeAudio() error {
+	client := llm.NewSTTClient(whisperServer)
+	client.Timestamps = whisperTimestamps
+
+	fmt.Fprintf(os.Stderr, "🎙️  Transcribing %s with %s (%s)\n", whisperInput, whisperModel, client.URL())
+
+	result, err := client.Transcribe(whisperModel, whisperInput, whisperLanguage)
+	if err != nil {
+		return fmt.Errorf("transcription failed: %v", err)
+	}
+
+	var out strings.Builder
+	switch whisperTimestamps {
+	case llm.TimestampsSegment:
+		writeTimedSpans(&out, result.Segments)
+	case llm.TimestampsWord:
+		if len(result.Words) == 0 {
+			fmt.Fprintln(os.Stderr, "⚠️  The server returned no word timestamps; showing segments")
+			writeTimedSpans(&out, result.Segments)
+		} else {
+			writeTimedSpans(&out, result.Words)
+		}
+	default:
+		out.WriteString(result.Text + "\n")
+	}
+
+	if whisperOutput == "" {
+		fmt.Print(out.String())
+	} else {
+		if err := os.WriteFile(whisperOutput, []byte(out.String()), 0644); err != nil {
+			return fmt.Errorf("failed to write transcript: %v", err)
+		}
+		fmt.Fprintf(os.Stderr, "💾 Transcript written to %s\n", whisperOutput)
+	}
+
+	if result.Language != "" {
+		fmt.Fprintf(os.Stderr, "🌐 Language: %s\n", result.Language)
+	}
+
+	return nil
+}
+
+func writeTimedSpans(out *strings.Builder, spans []llm.Segment) {
+	for _, span := range spans {
+		fmt.Fprintf(out, "[%s --> %s] %s\n", llm.FormatTimestamp(span.Start), llm.FormatTimestamp(span.End), span.Text)
+	}
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"github.com/pxkundu/agent-as-code/internal/runtime"
+	"github.com/spf13/cobra"
+)
+
+var logsCmd = &cobra.Command{
+	Use:   "logs [OPTIONS] CONTAINER",
+	Short: "Follow the logs of an agent container",
+	Long: `Follow the logs of an agent container, given by name or ID.
+
+--since and --until limit the logs to a time range, each given as a
+timestamp (e.g. 2026-01-02T15:04:05) or a duration relative to now
+(e.g. 10m). --tail shows only that many lines from the end before
+following.
+
+Examples:
+  agent logs my-agent
+  agent logs --tail 100 my-agent
+  agent logs --since 10m my-agent
+  agent logs --since 2026-01-02T15:00:00 --until 2026-01-02T16:00:00 my-agent`,
+	Args: cobra.ExactArgs(1),
+	RunE: runLogs,
+}
+
+var (
+	logsSince string
+	logsUntil string
+	logsTail  string
+)
+
+func init() {
+	rootCmd.AddCommand(logsCmd)
+
+	logsCmd.Flags().StringVar(&logsSince, "since", "", "show logs since a timestamp or relative duration (e.g. 10m)")
+	logsCmd.Flags().StringVar(&logsUntil, "until", "", "show logs until a timestamp or relative duration (e.g. 1m)")
+	logsCmd.Flags().StringVarP(&logsTail, "tail", "n", "all", "number of lines to show from the end of the logs")
+}
+
+func runLogs(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	return runtime.New().StreamLogs(args[0], runtime.LogOptions{
+		Since: logsSince,
+		Until: logsUntil,
+		Tail:  logsTail,
+	})
+}
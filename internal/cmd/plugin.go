@@ -0,0 +1,280 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const pluginPrefix = "agent-"
+
+var pluginCmd = &cobra.Command{
+	Use:   "plugin",
+	Short: "Manage CLI plugins",
+	Long: `Manage plugins that add custom commands to the agent CLI.
+
+A plugin is an executable named agent-<name> in the plugin directory
+(~/.agent/plugins by default, or $AGENT_PLUGIN_DIR). Each plugin is
+available as 'agent <name>'; arguments are passed through to the binary.
+
+Examples:
+  agent plugin install ./agent-deploy-k8s
+  agent plugin install https://example.com/releases/agent-deploy-k8s
+  agent plugin list
+  agent plugin remove deploy-k8s`,
+}
+
+var pluginInstallCmd = &cobra.Command{
+	Use:   "install <url|path>",
+	Short: "Install a plugin from a URL or local path",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, _ := cmd.Flags().GetString("name")
+		return installPlugin(args[0], name)
+	},
+}
+
+var pluginListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List installed plugins",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return listPlugins()
+	},
+}
+
+var pluginRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove an installed plugin",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return removePlugin(args[0])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pluginCmd)
+
+	pluginCmd.AddCommand(pluginInstallCmd)
+	pluginCmd.AddCommand(pluginListCmd)
+	pluginCmd.AddCommand(pluginRemoveCmd)
+
+	pluginInstallCmd.Flags().String("name", "", "plugin name (defaults to the file name without the agent- prefix)")
+}
+
+// getPluginDir returns the directory plugins are installed in
+func getPluginDir() (string, error) {
+	if dir := os.Getenv("AGENT_PLUGIN_DIR"); dir != "" {
+		return dir, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %v", err)
+	}
+
+	return filepath.Join(home, ".agent", "plugins"), nil
+}
+
+// pluginFileName returns the binary name for a plugin
+func pluginFileName(name string) string {
+	fileName := pluginPrefix + name
+	if runtime.GOOS == "windows" {
+		fileName += ".exe"
+	}
+	return fileName
+}
+
+// discoverPlugins returns the installed plugins keyed by name
+func discoverPlugins() (map[string]string, error) {
+	dir, err := getPluginDir()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	plugins := make(map[string]string)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasPrefix(entry.Name(), pluginPrefix) {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if runtime.GOOS != "windows" && info.Mode()&0111 == 0 {
+			continue
+		}
+
+		name := strings.TrimSuffix(strings.TrimPrefix(entry.Name(), pluginPrefix), ".exe")
+		if name != "" {
+			plugins[name] = filepath.Join(dir, entry.Name())
+		}
+	}
+
+	return plugins, nil
+}
+
+// registerPlugins adds a command to rootCmd for each installed plugin.
+// Plugins never shadow built-in commands.
+func registerPlugins() {
+	plugins, err := discoverPlugins()
+	if err != nil {
+		return
+	}
+
+	for name, path := range plugins {
+		if existing, _, err := rootCmd.Find([]string{name}); err == nil && existing != rootCmd {
+			continue
+		}
+		rootCmd.AddCommand(newPluginCommand(name, path))
+	}
+}
+
+// newPluginCommand creates a command that runs the plugin binary
+func newPluginCommand(name, path string) *cobra.Command {
+	return &cobra.Command{
+		Use:                name,
+		Short:              fmt.Sprintf("Plugin: %s", path),
+		DisableFlagParsing: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pluginProc := exec.Command(path, args...)
+			pluginProc.Stdin = os.Stdin
+			pluginProc.Stdout = os.Stdout
+			pluginProc.Stderr = os.Stderr
+
+			if err := pluginProc.Run(); err != nil {
+				if exitErr, ok := err.(*exec.ExitError); ok {
+					os.Exit(exitErr.ExitCode())
+				}
+				return fmt.Errorf("failed to run plugin '%s': %v", name, err)
+			}
+			return nil
+		},
+	}
+}
+
+func installPlugin(source, name string) error {
+	dir, err := getPluginDir()
+	if err != nil {
+		return err
+	}
+
+	if name == "" {
+		base := source
+		if idx := strings.Index(base, "?"); idx >= 0 {
+			base = base[:idx]
+		}
+		name = strings.TrimSuffix(strings.TrimPrefix(filepath.Base(base), pluginPrefix), ".exe")
+	}
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("invalid plugin name '%s'", name)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create plugin directory: %v", err)
+	}
+
+	fmt.Printf("📦 Installing plugin '%s' from %s\n", name, source)
+
+	var reader io.ReadCloser
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		client := &http.Client{Timeout: 5 * time.Minute}
+		resp, err := client.Get(source)
+		if err != nil {
+			return fmt.Errorf("failed to download plugin: %v", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return fmt.Errorf("failed to download plugin: HTTP %d", resp.StatusCode)
+		}
+		reader = resp.Body
+	} else {
+		file, err := os.Open(source)
+		if err != nil {
+			return fmt.Errorf("failed to open plugin: %v", err)
+		}
+		reader = file
+	}
+	defer reader.Close()
+
+	destPath := filepath.Join(dir, pluginFileName(name))
+	dest, err := os.OpenFile(destPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0755)
+	if err != nil {
+		return fmt.Errorf("failed to write plugin: %v", err)
+	}
+
+	if _, err := io.Copy(dest, reader); err != nil {
+		dest.Close()
+		os.Remove(destPath)
+		return fmt.Errorf("failed to write plugin: %v", err)
+	}
+	if err := dest.Close(); err != nil {
+		return fmt.Errorf("failed to write plugin: %v", err)
+	}
+
+	fmt.Printf("✅ Plugin installed: %s\n", destPath)
+	fmt.Printf("💡 Run it with: agent %s\n", name)
+	return nil
+}
+
+func listPlugins() error {
+	plugins, err := discoverPlugins()
+	if err != nil {
+		return err
+	}
+
+	if len(plugins) == 0 {
+		dir, _ := getPluginDir()
+		fmt.Printf("No plugins installed in %s\n", dir)
+		return nil
+	}
+
+	names := make([]string, 0, len(plugins))
+	for name := range plugins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Printf("%-20s %s\n", "NAME", "PATH")
+	for _, name := range names {
+		fmt.Printf("%-20s %s\n", name, plugins[name])
+	}
+
+	return nil
+}
+
+func removePlugin(name string) error {
+	plugins, err := discoverPlugins()
+	if err != nil {
+		return err
+	}
+
+	path, ok := plugins[name]
+	if !ok {
+		return fmt.Errorf("plugin '%s' is not installed", name)
+	}
+
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to remove plugin: %v", err)
+	}
+
+	fmt.Printf("✅ Plugin '%s' removed\n", name)
+	return nil
+}
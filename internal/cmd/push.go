@@ -2,7 +2,9 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/pxkundu/agent-as-code/internal/builder"
 	"github.com/pxkundu/agent-as-code/internal/registry"
 	"github.com/spf13/cobra"
 )
@@ -19,7 +21,26 @@ and use.
 Examples:
   agent push my-agent:latest
   agent push registry.example.com/my-agent:v1.0.0
-  agent push my-agent --registry myagentregistry.com`,
+  agent push my-agent --registry myagentregistry.com
+  agent push my-agent:latest --registry registry.example.com:5000
+  agent push registry.example.com/my-agent:v1.0.0 --sign --sign-key cosign.key
+  agent push registry.example.com/my-agent:v1.0.0 --sign-keyless
+
+With --sign the pushed image is signed by digest with cosign, which must
+be installed. The key may be a cosign key file or a KMS URI. Each signing
+is recorded in ~/.agent/history.jsonl.
+
+With --sign-keyless no key is needed: cosign gets a short-lived
+certificate from Sigstore's Fulcio for your OIDC identity and records the
+signature in the Rekor transparency log. In CI (GITHUB_ACTIONS, GITLAB_CI
+or CI set) the identity is the job's, e.g. a GitHub Actions workflow with
+the id-token: write permission; elsewhere cosign opens a browser to log
+in. COSIGN_EXPERIMENTAL=1 is set in CI for cosign 1.x.
+
+With --registry the image is tagged with the registry before the push, as
+Docker pushes to the registry an image's name starts with:
+my-agent:latest is pushed as registry.example.com:5000/my-agent:latest.
+--untag removes that tag again after the push.`,
 	Args: cobra.ExactArgs(1),
 	RunE: runPush,
 }
@@ -27,6 +48,10 @@ Examples:
 var (
 	pushRegistry string
 	pushAll      bool
+	pushSign     bool
+	pushSignKey  string
+	pushKeyless  bool
+	pushUntag    bool
 )
 
 func init() {
@@ -34,10 +59,26 @@ func init() {
 
 	pushCmd.Flags().StringVar(&pushRegistry, "registry", "", "registry to push to")
 	pushCmd.Flags().BoolVarP(&pushAll, "all-tags", "a", false, "push all tagged images in the repository")
+	pushCmd.Flags().BoolVar(&pushSign, "sign", false, "sign the pushed image with cosign")
+	pushCmd.Flags().StringVar(&pushSignKey, "sign-key", "cosign.key", "cosign private key file or KMS URI used with --sign")
+	pushCmd.Flags().BoolVar(&pushKeyless, "sign-keyless", false, "sign the pushed image with cosign keyless signing (Sigstore Fulcio and Rekor)")
+	pushCmd.Flags().BoolVar(&pushUntag, "untag", false, "remove the registry-qualified tag after pushing with --registry")
 }
 
 func runPush(cmd *cobra.Command, args []string) error {
 	imageName := args[0]
+	sign := pushSign || pushKeyless
+
+	if pushKeyless && cmd.Flags().Changed("sign-key") {
+		return fmt.Errorf("--sign-key cannot be used with --sign-keyless")
+	}
+	if pushUntag && pushRegistry == "" {
+		return fmt.Errorf("--untag requires --registry")
+	}
+	if pushUntag && sign {
+		// Signing needs the digest the registry tag records
+		return fmt.Errorf("--untag cannot be used with --sign or --sign-keyless")
+	}
 
 	// Initialize registry client
 	registryClient := registry.New()
@@ -54,10 +95,15 @@ func runPush(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("image validation failed: %w", err)
 	}
 
-	fmt.Printf("📤 Pushing %s\n", imageName)
-
 	// Push the image
-	result, err := registryClient.Push(options)
+	var result *registry.PushResult
+	var err error
+	if pushRegistry != "" {
+		result, err = pushToRegistry(registryClient, imageName, pushRegistry)
+	} else {
+		fmt.Printf("📤 Pushing %s\n", imageName)
+		result, err = registryClient.Push(options)
+	}
 	if err != nil {
 		return fmt.Errorf("push failed: %w", err)
 	}
@@ -72,8 +118,61 @@ func runPush(cmd *cobra.Command, args []string) error {
 	// Show registry URL if available
 	if result.RegistryURL != "" {
 		fmt.Printf("   Registry: %s\n", result.RegistryURL)
-		fmt.Printf("\n💡 Others can now pull with: agent pull %s\n", imageName)
+		fmt.Printf("\n💡 Others can now pull with: agent pull %s:%s\n", result.Repository, result.Tag)
+	}
+
+	if sign {
+		if !strings.HasPrefix(result.Digest, "sha256:") || result.Digest == "sha256:unknown" {
+			return fmt.Errorf("cannot sign %s: the registry digest of the pushed image is unknown", imageName)
+		}
+
+		imageDigest := result.Repository + "@" + result.Digest
+		if pushKeyless {
+			fmt.Printf("🔏 Signing %s keyless with Sigstore\n", imageDigest)
+			err = registryClient.SignKeyless(imageDigest)
+		} else {
+			fmt.Printf("🔏 Signing %s\n", imageDigest)
+			err = registryClient.Sign(imageDigest, pushSignKey)
+		}
+		if err != nil {
+			return fmt.Errorf("signing failed: %w", err)
+		}
+		fmt.Printf("✅ Image signed\n")
 	}
 
 	return nil
 }
+
+// pushToRegistry tags imageName with registryURL and pushes it there
+func pushToRegistry(registryClient *registry.Registry, imageName, registryURL string) (*registry.PushResult, error) {
+	ref := builder.RegistryTag(registryURL, imageName)
+	fmt.Printf("📤 Pushing %s as %s\n", imageName, ref)
+
+	if err := builder.New().TagAndPush(imageName, registryURL, "", "", pushUntag); err != nil {
+		return nil, err
+	}
+
+	repository, tag := splitImageReference(ref)
+	if tag == "" {
+		tag = "latest"
+	}
+	result := &registry.PushResult{
+		Repository:  repository,
+		Tag:         tag,
+		Digest:      "sha256:unknown",
+		Size:        "unknown",
+		RegistryURL: registryURL,
+	}
+
+	// The push records the registry digest for the qualified repository
+	images, err := registryClient.ListLocal(&registry.ListOptions{Filter: []string{repository}})
+	if err == nil {
+		for _, image := range images {
+			if image.Repository == repository && image.Digest != "" {
+				result.Digest = image.Digest
+				break
+			}
+		}
+	}
+	return result, nil
+}
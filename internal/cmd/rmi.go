@@ -1,10 +1,15 @@
 package cmd
 
 import (
+	"bufio"
 	"fmt"
+	"os"
 	"os/exec"
+	"path"
 	"strings"
+	"time"
 
+	"github.com/pxkundu/agent-as-code/internal/registry"
 	"github.com/spf13/cobra"
 )
 
@@ -17,32 +22,61 @@ This command removes the specified agent image, freeing up disk space.
 If the image is currently being used by running containers, the removal
 will fail unless the --force flag is used.
 
+The image may be a glob pattern using * and ? (see path.Match), such as
+"my-agent:v1.*". A pattern without a tag matches every tag. With --before,
+the images matching a pattern that were created before the given date are
+removed. The date is a day (2006-01-02), an RFC 3339 timestamp or a duration
+ago (e.g. 720h). Removing several images asks for confirmation unless --yes
+is set.
+
 Examples:
   agent rmi my-agent:latest
   agent rmi my-agent:v1.0.0
   agent rmi --force my-agent:latest
-  agent rmi --all-tags my-agent`,
-	Args: cobra.ExactArgs(1),
+  agent rmi --all-tags my-agent
+  agent rmi "my-agent:v1.*"
+  agent rmi --before 2024-01-01 my-agent
+  agent rmi --before 720h --yes "my-agent:*"`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		tag := args[0]
 		force, _ := cmd.Flags().GetBool("force")
 		allTags, _ := cmd.Flags().GetBool("all-tags")
+		before, _ := cmd.Flags().GetString("before")
+		yes, _ := cmd.Flags().GetBool("yes")
+
+		if len(args) == 0 {
+			if before != "" {
+				return fmt.Errorf("--before requires an image pattern, e.g. \"my-agent:*\"")
+			}
+			return fmt.Errorf("requires an image tag")
+		}
+
+		if before != "" {
+			return removeImagesBefore(before, args[0], force, yes)
+		}
+		tag := args[0]
 		
 		if allTags {
 			return removeAllTags(strings.Split(tag, ":")[0], force)
 		}
 		
-		return removeImage(tag, force)
+		return removeImage(tag, force, yes)
 	},
 }
 
 func init() {
 	rmiCmd.Flags().Bool("force", false, "force removal even if image is in use")
 	rmiCmd.Flags().Bool("all-tags", false, "remove all tags for the specified image")
+	rmiCmd.Flags().String("before", "", "remove images matching a pattern created before a date, timestamp or duration ago")
+	rmiCmd.Flags().BoolP("yes", "y", false, "remove several images without asking for confirmation")
 	rootCmd.AddCommand(rmiCmd)
 }
 
-func removeImage(tag string, force bool) error {
+func removeImage(tag string, force, yes bool) error {
+	if isImagePattern(tag) {
+		return removeMatchingImages(tag, force, yes)
+	}
+
 	fmt.Printf("🗑️  Removing agent image: %s\n", tag)
 	
 	// Check if the image exists
@@ -90,7 +124,7 @@ func removeAllTags(imageName string, force bool) error {
 	// Remove each tag
 	removedCount := 0
 	for _, tag := range tags {
-		if err := removeImage(tag, force); err != nil {
+		if err := removeImage(tag, force, false); err != nil {
 			fmt.Printf("⚠️  Warning: failed to remove tag '%s': %v\n", tag, err)
 			continue
 		}
@@ -146,14 +180,12 @@ func imageInUse(tag string) bool {
 
 func getImageTags(imageName string) ([]string, error) {
 	// Get all tags for the specified image
-	cmd := exec.Command("docker", "images", "--format", "{{.Repository}}:{{.Tag}}")
-	output, err := cmd.Output()
+	images, err := listImageTags()
 	if err != nil {
 		return nil, err
 	}
 	
 	var tags []string
-	images := strings.Split(strings.TrimSpace(string(output)), "\n")
 	for _, image := range images {
 		if strings.HasPrefix(image, imageName+":") {
 			tags = append(tags, image)
@@ -162,3 +194,182 @@ func getImageTags(imageName string) ([]string, error) {
 	
 	return tags, nil
 }
+
+// listImageTags returns the repository:tag of every local image
+func listImageTags() ([]string, error) {
+	cmd := exec.Command("docker", "images", "--format", "{{.Repository}}:{{.Tag}}")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.Split(strings.TrimSpace(string(output)), "\n"), nil
+}
+
+// isImagePattern reports whether tag is a glob pattern
+func isImagePattern(tag string) bool {
+	return strings.ContainsAny(tag, "*?")
+}
+
+// splitImageReference splits an image reference into repository and tag.
+// The tag is empty if the reference has none; a colon followed by a path is
+// a registry port, not a tag.
+func splitImageReference(ref string) (repository, tag string) {
+	i := strings.LastIndex(ref, ":")
+	if i == -1 || strings.Contains(ref[i+1:], "/") {
+		return ref, ""
+	}
+	return ref[:i], ref[i+1:]
+}
+
+// matchImagePattern reports whether the image reference matches pattern.
+// A pattern without a tag matches every tag.
+func matchImagePattern(pattern, ref string) bool {
+	if _, tag := splitImageReference(pattern); tag == "" {
+		pattern += ":*"
+	}
+	matched, _ := path.Match(pattern, ref)
+	return matched
+}
+
+// matchImageTags returns the local images matching pattern
+func matchImageTags(pattern string) ([]string, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid pattern '%s': %v", pattern, err)
+	}
+
+	// Only the tags of the repository need to be checked unless the
+	// repository itself is a pattern
+	repository, _ := splitImageReference(pattern)
+	var candidates []string
+	var err error
+	if isImagePattern(repository) {
+		candidates, err = listImageTags()
+	} else {
+		candidates, err = getImageTags(repository)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list images: %v", err)
+	}
+
+	var tags []string
+	for _, candidate := range candidates {
+		if matchImagePattern(pattern, candidate) {
+			tags = append(tags, candidate)
+		}
+	}
+
+	return tags, nil
+}
+
+// removeMatchingImages removes every local image matching pattern
+func removeMatchingImages(pattern string, force, yes bool) error {
+	fmt.Printf("🗑️  Removing agent images matching: %s\n", pattern)
+
+	tags, err := matchImageTags(pattern)
+	if err != nil {
+		return err
+	}
+
+	if len(tags) == 0 {
+		fmt.Printf("ℹ️  No images match: %s\n", pattern)
+		return nil
+	}
+
+	return removeImages(tags, force, yes)
+}
+
+// removeImagesBefore removes the local images matching pattern that were
+// created before the given date
+func removeImagesBefore(before, pattern string, force, yes bool) error {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("invalid pattern '%s': %v", pattern, err)
+	}
+
+	cutoff, err := parseBeforeTime(before)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("🗑️  Removing agent images matching %s created before %s\n", pattern, cutoff.Format(time.RFC3339))
+
+	images, err := registry.New().ListLocal(&registry.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list images: %v", err)
+	}
+
+	var tags []string
+	for _, image := range images {
+		ref := image.Repository + ":" + image.Tag
+		if !image.Created.Before(cutoff) {
+			continue
+		}
+		if !matchImagePattern(pattern, ref) {
+			continue
+		}
+		tags = append(tags, ref)
+	}
+
+	if len(tags) == 0 {
+		fmt.Println("ℹ️  No images found")
+		return nil
+	}
+
+	return removeImages(tags, force, yes)
+}
+
+// parseBeforeTime parses a --before value: a day, an RFC 3339 timestamp or
+// a duration before now
+func parseBeforeTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --before value '%s': use a date (2006-01-02), an RFC 3339 timestamp or a duration (e.g. 720h)", value)
+}
+
+// removeImages lists the images to be removed, asks for confirmation unless
+// yes is set, and removes them
+func removeImages(tags []string, force, yes bool) error {
+	fmt.Printf("Found %d images:\n", len(tags))
+	for _, tag := range tags {
+		fmt.Printf("  - %s\n", tag)
+	}
+
+	if !yes && !confirm(fmt.Sprintf("Remove %d images?", len(tags))) {
+		fmt.Println("Aborted")
+		return nil
+	}
+
+	removedCount := 0
+	for _, tag := range tags {
+		if err := removeImage(tag, force, yes); err != nil {
+			fmt.Printf("⚠️  Warning: failed to remove '%s': %v\n", tag, err)
+			continue
+		}
+		removedCount++
+	}
+
+	if removedCount > 0 {
+		fmt.Printf("✅ Successfully removed %d/%d images\n", removedCount, len(tags))
+	}
+
+	if removedCount < len(tags) {
+		return fmt.Errorf("some images could not be removed. Check warnings above")
+	}
+
+	return nil
+}
+
+// confirm asks a yes/no question on stdin, defaulting to no
+func confirm(prompt string) bool {
+	fmt.Printf("%s [y/N]: ", prompt)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
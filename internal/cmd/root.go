@@ -5,10 +5,13 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
 	"strings"
 
 	"github.com/docker/docker/client"
+	"github.com/pxkundu/agent-as-code/internal/config"
+	"github.com/pxkundu/agent-as-code/internal/log"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -44,6 +47,15 @@ Examples:
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 func Execute() error {
+	registerPlugins()
+
+	// agent --version --output json|short skips the banner
+	if output, rest, ok := versionOutputFromArgs(os.Args[1:]); ok {
+		if output != "text" {
+			return printVersion(output)
+		}
+		rootCmd.SetArgs(rest)
+	}
 	return rootCmd.Execute()
 }
 
@@ -135,10 +147,14 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.agent-as-code.yaml)")
 	rootCmd.PersistentFlags().Bool("verbose", false, "verbose output")
 	rootCmd.PersistentFlags().Bool("quiet", false, "quiet output")
+	rootCmd.PersistentFlags().String("log-format", "text", "log format (text|json)")
+	rootCmd.PersistentFlags().String("log-level", "info", "log level (debug|info|warn|error)")
 
 	// Bind flags to viper
 	viper.BindPFlag("verbose", rootCmd.PersistentFlags().Lookup("verbose"))
 	viper.BindPFlag("quiet", rootCmd.PersistentFlags().Lookup("quiet"))
+	viper.BindPFlag("log-format", rootCmd.PersistentFlags().Lookup("log-format"))
+	viper.BindPFlag("log-level", rootCmd.PersistentFlags().Lookup("log-level"))
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -146,6 +162,11 @@ func initConfig() {
 	if cfgFile != "" {
 		// Use config file from the flag.
 		viper.SetConfigFile(cfgFile)
+
+		// A JSON or TOML file also holds the registry profiles
+		if ext := strings.ToLower(filepath.Ext(cfgFile)); ext == ".json" || ext == ".toml" {
+			config.SetFile(cfgFile)
+		}
 	} else {
 		// Find home directory.
 		home, err := os.UserHomeDir()
@@ -165,4 +186,11 @@ func initConfig() {
 			fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
 		}
 	}
+
+	// --verbose lowers the log level unless one was given explicitly
+	logLevel := viper.GetString("log-level")
+	if viper.GetBool("verbose") && !rootCmd.PersistentFlags().Changed("log-level") {
+		logLevel = "debug"
+	}
+	cobra.CheckErr(log.Init(viper.GetString("log-format"), logLevel))
 }
@@ -1,11 +1,16 @@
 package cmd
 
 import (
+	"bufio"
 	"fmt"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
+	units "github.com/docker/go-units"
+	"github.com/pxkundu/agent-as-code/internal/parser"
 	"github.com/pxkundu/agent-as-code/internal/runtime"
 	"github.com/spf13/cobra"
 )
@@ -17,23 +22,69 @@ var runCmd = &cobra.Command{
 
 This command starts an agent container and manages its lifecycle.
 The agent will be accessible on the specified port (default: 8080).
+In the foreground, the agent is reported as started once its Docker health
+check passes or, without one, once /health on the first published port
+answers.
+
+Memory and CPU limits are taken from spec.resources.limits in the
+agent.yaml under --path, if there is one. Its spec.environment is set in
+the container. ${VAR} references in it are resolved from --env and
+--env-file first, then from the host. This is where values that agent
+build leaves out of the image, such as secrets, get resolved.
+
+--env and --env-file values are passed as given and override
+spec.environment. --memory overrides the memory limit.
+
+An invalid ./agent.yaml is ignored with a warning. If only its
+spec.environment cannot be resolved, its limits still apply. An invalid
+agent.yaml under an explicit --path is an error.
+
+--gpu passes NVIDIA GPUs to the container, for local LLM inference: all
+of them, or the listed device IDs or UUIDs. --runtime nvidia selects the
+NVIDIA runtime instead, for Docker versions older than 19.03. Both require
+the NVIDIA Container Toolkit on the Docker host.
+
+--init runs Docker's init process (tini) as PID 1, which reaps zombie
+processes the agent leaves behind. --oom-kill-disable and --oom-score-adj
+control how the kernel treats the container under memory pressure, e.g.
+during large local LLM inferences. Only disable the OOM killer together
+with a memory limit, or the host itself may run out of memory.
 
 Examples:
   agent run my-agent:latest
   agent run -p 9000:8080 my-agent:latest
+  agent run -p 127.0.0.1:9000:8080/tcp my-agent:latest
   agent run --env OPENAI_API_KEY=sk-... my-agent:latest
-  agent run -d my-agent:latest`,
+  agent run --env-file .env --env-file .env.local my-agent:latest
+  agent run -d my-agent:latest
+  agent run --memory 512m --pids-limit 100 my-agent:latest
+  agent run --gpu all my-agent:latest
+  agent run --gpu 0,1 my-agent:latest
+  agent run --runtime nvidia my-agent:latest
+  agent run --init -d my-agent:latest
+  agent run --memory 8g --oom-kill-disable my-agent:latest
+  agent run --oom-score-adj -500 my-agent:latest`,
 	Args: cobra.ExactArgs(1),
 	RunE: runRun,
 }
 
 var (
-	runPort        []string
-	runEnv         []string
-	runDetach      bool
-	runName        string
-	runVolume      []string
-	runInteractive bool
+	runPort           []string
+	runEnv            []string
+	runEnvFile        []string
+	runDetach         bool
+	runName           string
+	runVolume         []string
+	runInteractive    bool
+	runPath           string
+	runMemory         string
+	runPidsLimit      int64
+	runHealthTimeout  time.Duration
+	runGPU            string
+	runRuntime        string
+	runInitProcess    bool
+	runOomKillDisable bool
+	runOomScoreAdj    int
 )
 
 func init() {
@@ -41,27 +92,55 @@ func init() {
 
 	runCmd.Flags().StringSliceVarP(&runPort, "port", "p", []string{}, "publish a container's port(s) to the host")
 	runCmd.Flags().StringSliceVarP(&runEnv, "env", "e", []string{}, "set environment variables")
+	runCmd.Flags().StringArrayVar(&runEnvFile, "env-file", []string{}, "read environment variables from a file (repeatable)")
 	runCmd.Flags().BoolVarP(&runDetach, "detach", "d", false, "run container in background")
 	runCmd.Flags().StringVar(&runName, "name", "", "assign a name to the container")
 	runCmd.Flags().StringSliceVarP(&runVolume, "volume", "v", []string{}, "bind mount a volume")
 	runCmd.Flags().BoolVarP(&runInteractive, "interactive", "i", false, "run in interactive mode")
+	runCmd.Flags().StringVar(&runPath, "path", ".", "directory containing the agent.yaml with resource limits")
+	runCmd.Flags().StringVarP(&runMemory, "memory", "m", "", "memory limit (e.g. 512m, 1g); overrides spec.resources")
+	runCmd.Flags().Int64Var(&runPidsLimit, "pids-limit", 0, "maximum number of processes in the container (0 for unlimited)")
+	runCmd.Flags().DurationVar(&runHealthTimeout, "health-timeout", 60*time.Second, "how long to wait for the agent to become healthy in the foreground (0 to skip)")
+	runCmd.Flags().StringVar(&runGPU, "gpu", "", "GPUs to pass to the container: 'all' or device IDs such as 0,1 (requires the NVIDIA Container Toolkit)")
+	runCmd.Flags().StringVar(&runRuntime, "runtime", "", "OCI runtime for the container, e.g. nvidia for legacy GPU support")
+	runCmd.Flags().BoolVar(&runInitProcess, "init", false, "run an init process (tini) as PID 1 to reap zombie processes")
+	runCmd.Flags().BoolVar(&runOomKillDisable, "oom-kill-disable", false, "disable the OOM killer for the container (use with --memory)")
+	runCmd.Flags().IntVar(&runOomScoreAdj, "oom-score-adj", 0, "adjust the container's OOM score (-1000 to 1000)")
 }
 
 func runRun(cmd *cobra.Command, args []string) error {
 	imageName := args[0]
 
+	// Merge environment files
+	environment, err := mergeEnvFiles(runEnv, runEnvFile)
+	if err != nil {
+		return err
+	}
+
 	// Initialize runtime
 	agentRuntime := runtime.New()
 
 	// Run options
 	options := &runtime.RunOptions{
-		Image:       imageName,
-		Ports:       runPort,
-		Environment: runEnv,
-		Detach:      runDetach,
-		Name:        runName,
-		Volumes:     runVolume,
-		Interactive: runInteractive,
+		Image:          imageName,
+		Ports:          runPort,
+		Environment:    environment,
+		Detach:         runDetach,
+		Name:           runName,
+		Volumes:        runVolume,
+		Interactive:    runInteractive,
+		PidsLimit:      runPidsLimit,
+		GPUs:           runGPU,
+		Runtime:        runRuntime,
+		Init:           runInitProcess,
+		OomKillDisable: runOomKillDisable,
+		OomScoreAdj:    runOomScoreAdj,
+	}
+	if err := applyAgentSpec(options, cmd.Flags().Changed("path")); err != nil {
+		return err
+	}
+	if options.OomKillDisable && options.Memory == 0 {
+		fmt.Println("⚠️  --oom-kill-disable without a memory limit lets the container exhaust the host's memory")
 	}
 
 	// Validate image exists
@@ -93,6 +172,14 @@ func runRun(cmd *cobra.Command, args []string) error {
 		fmt.Printf("\n💡 Use 'agent logs %s' to view logs\n", container.Name)
 		fmt.Printf("💡 Use 'agent stop %s' to stop the agent\n", container.Name)
 	} else {
+		if runHealthTimeout > 0 {
+			fmt.Printf("⏳ Waiting for agent to become healthy...\n")
+			if err := agentRuntime.WaitHealthy(container.ID, runHealthTimeout); err != nil {
+				agentRuntime.Stop(container.ID)
+				return fmt.Errorf("agent failed to start: %w", err)
+			}
+		}
+
 		fmt.Printf("✅ Agent started successfully\n")
 		fmt.Printf("   Container: %s\n", container.Name)
 
@@ -115,7 +202,7 @@ func runRun(cmd *cobra.Command, args []string) error {
 		// Stream logs in foreground mode
 		if !runDetach {
 			go func() {
-				if err := agentRuntime.StreamLogs(container.ID); err != nil {
+				if err := agentRuntime.StreamLogs(container.ID, runtime.LogOptions{}); err != nil {
 					fmt.Printf("Error streaming logs: %v\n", err)
 				}
 			}()
@@ -135,3 +222,210 @@ func runRun(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// mergeEnvFiles appends the variables from envFiles to envVars. Later files
+// override earlier ones, and file values override --env values with a warning.
+func mergeEnvFiles(envVars []string, envFiles []string) ([]string, error) {
+	if len(envFiles) == 0 {
+		return envVars, nil
+	}
+
+	fromFlags := make(map[string]bool)
+	for _, env := range envVars {
+		key := strings.SplitN(env, "=", 2)[0]
+		fromFlags[key] = true
+	}
+
+	values := make(map[string]string)
+	var order []string
+	for _, path := range envFiles {
+		vars, err := readEnvFile(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, kv := range vars {
+			if _, seen := values[kv[0]]; !seen {
+				order = append(order, kv[0])
+			}
+			values[kv[0]] = kv[1]
+		}
+	}
+
+	var merged []string
+	for _, env := range envVars {
+		key := strings.SplitN(env, "=", 2)[0]
+		if _, overridden := values[key]; overridden {
+			continue
+		}
+		merged = append(merged, env)
+	}
+
+	for _, key := range order {
+		if fromFlags[key] {
+			fmt.Printf("⚠️  %s from --env-file overrides the value set with --env\n", key)
+		}
+		merged = append(merged, key+"="+values[key])
+	}
+
+	return merged, nil
+}
+
+// readEnvFile reads KEY=VALUE pairs from a file, skipping blank lines and
+// # comments and stripping surrounding quotes from values
+func readEnvFile(path string) ([][2]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open env file: %w", err)
+	}
+	defer file.Close()
+
+	var vars [][2]string
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		parts := strings.SplitN(line, "=", 2)
+		key := strings.TrimSpace(parts[0])
+		if len(parts) != 2 || key == "" {
+			return nil, fmt.Errorf("%s:%d: expected KEY=VALUE", path, lineNum)
+		}
+
+		value := strings.TrimSpace(parts[1])
+		if len(value) >= 2 {
+			if (value[0] == '"' && value[len(value)-1] == '"') || (value[0] == '\'' && value[len(value)-1] == '\'') {
+				value = value[1 : len(value)-1]
+			}
+		}
+
+		vars = append(vars, [2]string{key, value})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read env file: %w", err)
+	}
+
+	return vars, nil
+}
+
+// applyAgentSpec sets the resource limits and environment of options from
+// the agent.yaml under --path, if any, and from --memory. Unless --path was
+// given explicitly, the agent.yaml may be unrelated to the image, so
+// problems with it are warnings rather than errors. Its resource limits
+// still apply when only spec.environment cannot be resolved.
+func applyAgentSpec(options *runtime.RunOptions, explicitPath bool) error {
+	if err := applySpec(options); err != nil {
+		if explicitPath {
+			return err
+		}
+		fmt.Printf("⚠️  agent.yaml in %s: %v\n", runPath, err)
+	}
+
+	if runMemory != "" {
+		memory, err := units.RAMInBytes(runMemory)
+		if err != nil || memory <= 0 {
+			return fmt.Errorf("invalid --memory '%s' (e.g. 512m, 1g)", runMemory)
+		}
+		options.Memory = memory
+	}
+
+	return nil
+}
+
+// applySpec sets the resource limits of options from spec.resources.limits
+// in the agent.yaml under --path, if there is one, and puts its resolved
+// spec.environment before the variables given with --env and --env-file.
+// References are resolved from the --env and --env-file values first, then
+// from the host. The limits are set even if the environment fails to resolve.
+func applySpec(options *runtime.RunOptions) error {
+	agentParser := parser.New()
+	agentFile, err := agentParser.FindAgentFile(runPath)
+	if err != nil {
+		return nil
+	}
+
+	spec, err := agentParser.ParseFile(agentFile)
+	if err != nil {
+		return fmt.Errorf("invalid agent.yaml: %w", err)
+	}
+
+	if resources := spec.Spec.Resources; resources != nil {
+		memory := options.Memory
+		if resources.Limits.Memory != "" {
+			memory, err = parser.ParseMemory(resources.Limits.Memory)
+			if err != nil {
+				return fmt.Errorf("invalid memory limit: %w", err)
+			}
+		}
+		if resources.Limits.CPU != "" {
+			quota, err := parser.CPUQuota(resources.Limits.CPU)
+			if err != nil {
+				return fmt.Errorf("invalid cpu limit: %w", err)
+			}
+			options.CPUPeriod = parser.CPUPeriod
+			options.CPUQuota = quota
+		}
+		options.Memory = memory
+	}
+
+	given, values := commandLineEnv(options.Environment)
+
+	// Entries the command line sets are never used, so they need not resolve
+	var specEnv []parser.EnvironmentVar
+	for _, env := range spec.Spec.Environment {
+		if !given[env.Name] {
+			specEnv = append(specEnv, env)
+		}
+	}
+	spec.Spec.Environment = specEnv
+
+	spec, err = agentParser.InterpolateEnvWith(spec, func(name string) (string, bool) {
+		if value, ok := values[name]; ok {
+			return value, true
+		}
+		return os.LookupEnv(name)
+	})
+	if err != nil {
+		return fmt.Errorf("spec.environment not set: %w", err)
+	}
+	options.Environment = mergeSpecEnvironment(spec.Spec.Environment, options.Environment)
+
+	return nil
+}
+
+// commandLineEnv returns the names of the variables in environment and the
+// values of those given as KEY=VALUE. A bare KEY takes its value from the
+// host, so it has a name but no value.
+func commandLineEnv(environment []string) (map[string]bool, map[string]string) {
+	given := make(map[string]bool)
+	values := make(map[string]string)
+	for _, env := range environment {
+		name, value, hasValue := strings.Cut(env, "=")
+		given[name] = true
+		if hasValue {
+			values[name] = value
+		}
+	}
+	return given, values
+}
+
+// mergeSpecEnvironment returns the spec.environment entries that have a
+// value as KEY=VALUE, followed by environment. Entries that environment sets
+// as well are left out, so the command line wins.
+func mergeSpecEnvironment(specEnv []parser.EnvironmentVar, environment []string) []string {
+	given, _ := commandLineEnv(environment)
+
+	var merged []string
+	for _, env := range specEnv {
+		if env.Value == "" || given[env.Name] {
+			continue
+		}
+		merged = append(merged, env.Name+"="+env.Value)
+	}
+	return append(merged, environment...)
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/pxkundu/agent-as-code/internal/runtime"
+)
+
+const runTestAgentYAML = `apiVersion: agent.dev/v1
+kind: Agent
+metadata:
+  name: run-test
+spec:
+  runtime: python
+  model:
+    provider: ollama
+    name: llama2
+  resources:
+    limits:
+      memory: 512Mi
+      cpu: "1"
+  environment:
+    - name: OPENAI_API_KEY
+      value: ${AGENT_RUN_TEST_KEY}
+    - name: GREETING
+      value: ${AGENT_RUN_TEST_UNSET}
+    - name: MODE
+      value: production
+`
+
+func TestApplyAgentSpecResolvesFromCommandLine(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "agent.yaml"), []byte(runTestAgentYAML), 0644); err != nil {
+		t.Fatal(err)
+	}
+	defer func(path, memory string) { runPath, runMemory = path, memory }(runPath, runMemory)
+	runPath, runMemory = dir, ""
+
+	options := &runtime.RunOptions{Environment: []string{
+		"AGENT_RUN_TEST_KEY=sk-test",
+		"GREETING=hello",
+	}}
+	if err := applyAgentSpec(options, true); err != nil {
+		t.Fatalf("applyAgentSpec() error = %v", err)
+	}
+
+	wantEnv := []string{"OPENAI_API_KEY=sk-test", "MODE=production", "AGENT_RUN_TEST_KEY=sk-test", "GREETING=hello"}
+	if !reflect.DeepEqual(options.Environment, wantEnv) {
+		t.Errorf("environment = %v, want %v", options.Environment, wantEnv)
+	}
+	if options.Memory != 512*1024*1024 {
+		t.Errorf("memory = %d, want %d", options.Memory, 512*1024*1024)
+	}
+	if options.CPUQuota != 100000 {
+		t.Errorf("cpu quota = %d, want 100000", options.CPUQuota)
+	}
+}
+
+func TestApplyAgentSpecKeepsLimitsWhenEnvironmentFails(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "agent.yaml"), []byte(runTestAgentYAML), 0644); err != nil {
+		t.Fatal(err)
+	}
+	defer func(path, memory string) { runPath, runMemory = path, memory }(runPath, runMemory)
+	runPath, runMemory = dir, ""
+
+	options := &runtime.RunOptions{}
+	if err := applyAgentSpec(options, true); err == nil {
+		t.Fatal("applyAgentSpec() with an explicit --path succeeded, want an error for ${AGENT_RUN_TEST_KEY}")
+	}
+
+	options = &runtime.RunOptions{}
+	if err := applyAgentSpec(options, false); err != nil {
+		t.Fatalf("applyAgentSpec() error = %v", err)
+	}
+	if options.Memory != 512*1024*1024 || options.CPUQuota != 100000 {
+		t.Errorf("limits = %d bytes, %d quota; want them applied despite the unresolved environment", options.Memory, options.CPUQuota)
+	}
+	if len(options.Environment) != 0 {
+		t.Errorf("environment = %v, want none", options.Environment)
+	}
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/pxkundu/agent-as-code/internal/parser"
+	"github.com/spf13/cobra"
+)
+
+var scaleCmd = &cobra.Command{
+	Use:   "scale [OPTIONS] NAME",
+	Short: "Scale an agent to a number of replicas",
+	Long: `Scale a deployed agent to the given number of replicas.
+
+When an agent.yaml with a spec.scaling section is found in --path, the
+requested replica count is checked against minReplicas and maxReplicas.
+
+Note: the local Docker runtime does not manage replicas yet. This command
+validates the request and reports the desired state; orchestrators can
+read the agent.dev/min-replicas and agent.dev/max-replicas image labels.
+
+Examples:
+  agent scale my-agent --replicas 3
+  agent scale my-agent --replicas 5 --path ./my-agent`,
+	Args: cobra.ExactArgs(1),
+	RunE: runScale,
+}
+
+var (
+	scaleReplicas int
+	scalePath     string
+)
+
+func init() {
+	rootCmd.AddCommand(scaleCmd)
+
+	scaleCmd.Flags().IntVar(&scaleReplicas, "replicas", 1, "desired number of replicas")
+	scaleCmd.Flags().StringVar(&scalePath, "path", ".", "directory containing the agent.yaml")
+}
+
+func runScale(cmd *cobra.Command, args []string) error {
+	agentName := args[0]
+
+	if scaleReplicas <= 0 {
+		return fmt.Errorf("replicas must be greater than 0")
+	}
+
+	// Check the request against the scaling hints in agent.yaml, if any
+	agentParser := parser.New()
+	if agentFile, err := agentParser.FindAgentFile(scalePath); err == nil {
+		spec, err := agentParser.ParseFile(agentFile)
+		if err != nil {
+			return fmt.Errorf("invalid agent.yaml: %w", err)
+		}
+
+		if scaling := spec.Spec.Scaling; scaling != nil {
+			if scaleReplicas < scaling.MinReplicas || scaleReplicas > scaling.MaxReplicas {
+				return fmt.Errorf("replicas must be between %d and %d (spec.scaling)", scaling.MinReplicas, scaling.MaxReplicas)
+			}
+		}
+	}
+
+	fmt.Printf("📐 Scaling %s to %d replicas\n", agentName, scaleReplicas)
+	fmt.Printf("⚠️  The local runtime does not manage replicas yet; no containers were changed\n")
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pxkundu/agent-as-code/internal/security"
+	"github.com/spf13/cobra"
+)
+
+var securityCmd = &cobra.Command{
+	Use:   "security",
+	Short: "Check agent images for security issues",
+}
+
+var securityScanCmd = &cobra.Command{
+	Use:   "scan IMAGE",
+	Short: "Scan an agent image for vulnerabilities with Trivy",
+	Long: `Scan an agent image for known vulnerabilities with Trivy and list them
+grouped by severity. Trivy must be installed and in PATH.
+
+The exit code reflects the highest severity found among the severities
+selected by --severity: 4 for CRITICAL, 3 for HIGH, 2 for MEDIUM, 1 for LOW
+or UNKNOWN, and 0 if there are none.
+
+Examples:
+  agent security scan my-agent:latest
+  agent security scan my-agent:latest --severity HIGH,CRITICAL
+  agent security scan my-agent:latest --ignore-unfixed`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSecurityScan,
+}
+
+var (
+	securitySeverity      string
+	securityIgnoreUnfixed bool
+)
+
+func init() {
+	rootCmd.AddCommand(securityCmd)
+	securityCmd.AddCommand(securityScanCmd)
+
+	securityScanCmd.Flags().StringVar(&securitySeverity, "severity", strings.Join(security.Severities, ","), "comma-separated severities that fail the scan")
+	securityScanCmd.Flags().BoolVar(&securityIgnoreUnfixed, "ignore-unfixed", false, "skip vulnerabilities without a released fix")
+}
+
+func runSecurityScan(cmd *cobra.Command, args []string) error {
+	image := args[0]
+
+	failOn, err := security.ParseSeverities(securitySeverity)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("🔍 Scanning %s with Trivy...\n", image)
+	vulnerabilities, err := security.Scan(image, security.ScanOptions{IgnoreUnfixed: securityIgnoreUnfixed})
+	if err != nil {
+		return err
+	}
+
+	if len(vulnerabilities) == 0 {
+		fmt.Println("✅ No vulnerabilities found")
+		return nil
+	}
+
+	bySeverity := make(map[string][]security.Vulnerability)
+	for _, v := range vulnerabilities {
+		bySeverity[v.Severity] = append(bySeverity[v.Severity], v)
+	}
+
+	var counts []string
+	for _, severity := range security.Severities {
+		if n := len(bySeverity[severity]); n > 0 {
+			counts = append(counts, fmt.Sprintf("%s: %d", severity, n))
+		}
+	}
+	fmt.Printf("\nFound %d vulnerabilities (%s)\n", len(vulnerabilities), strings.Join(counts, ", "))
+
+	for _, severity := range security.Severities {
+		found := bySeverity[severity]
+		if len(found) == 0 {
+			continue
+		}
+
+		fmt.Printf("\n%s (%d)\n", severity, len(found))
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tPACKAGE\tINSTALLED\tFIXED\tTITLE")
+		for _, v := range found {
+			fixed := v.FixedVersion
+			if fixed == "" {
+				fixed = "-"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", v.ID, v.Package, v.InstalledVersion, fixed, truncateSentence(v.Title, 60))
+		}
+		w.Flush()
+	}
+
+	// Exit with the rank of the most severe finding that fails the scan
+	highest := -1
+	for _, v := range vulnerabilities {
+		if failOn[v.Severity] && security.SeverityRank(v.Severity) > highest {
+			highest = security.SeverityRank(v.Severity)
+		}
+	}
+	if highest < 0 {
+		fmt.Printf("\n✅ No vulnerabilities with severity %s\n", strings.ToUpper(securitySeverity))
+		return nil
+	}
+
+	fmt.Printf("\n❌ Vulnerabilities with severity %s found\n", strings.ToUpper(securitySeverity))
+	if highest == 0 {
+		highest = 1 // UNKNOWN
+	}
+	os.Exit(highest)
+	return nil
+}
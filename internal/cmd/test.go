@@ -2,14 +2,16 @@ package cmd
 
 import (
 	"fmt"
+	"net/http"
 	"os/exec"
 	"path/filepath"
+	"time"
 
 	"github.com/spf13/cobra"
 )
 
 var testCmd = &cobra.Command{
-	Use:   "test [TAG]",
+	Use:   "test [TAG | --in-place [PATH]]",
 	Short: "Test agent functionality",
 	Long: `Test agent functionality by running the agent and executing test scenarios.
 
@@ -17,14 +19,37 @@ This command starts the agent container and runs predefined tests to verify
 that the agent is working correctly. Tests may include health checks,
 API endpoint validation, and basic functionality verification.
 
+With --in-place, the agent is tested from its source directory (PATH,
+default the current directory) without building an image. For python
+agents, requirements.txt is installed into a .venv virtual environment in
+that directory, and main.py is started with the PORT environment variable
+set to a random free port. After the same checks as for an image, the
+agent's tests/ directory is run with pytest, if pytest is installed, with
+AGENT_URL pointing at the running agent. main.py is stopped afterwards.
+
 Examples:
   agent test my-agent:latest
   agent test my-agent:v1.0.0
-  agent test --timeout 60s my-agent:latest`,
-	Args: cobra.ExactArgs(1),
+  agent test --timeout 60s my-agent:latest
+  agent test --in-place
+  agent test --in-place ./my-agent`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if inPlace, _ := cmd.Flags().GetBool("in-place"); inPlace {
+			return cobra.MaximumNArgs(1)(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		tag := args[0]
 		timeout, _ := cmd.Flags().GetString("timeout")
+		if inPlace, _ := cmd.Flags().GetBool("in-place"); inPlace {
+			dir := "."
+			if len(args) == 1 {
+				dir = args[0]
+			}
+			return runInPlaceTests(dir, timeout)
+		}
+
+		tag := args[0]
 		
 		fmt.Printf("🧪 Testing agent: %s\n", tag)
 		
@@ -40,6 +65,7 @@ Examples:
 
 func init() {
 	testCmd.Flags().String("timeout", "30s", "test timeout duration")
+	testCmd.Flags().Bool("in-place", false, "test the agent from its source directory without Docker")
 	rootCmd.AddCommand(testCmd)
 }
 
@@ -76,7 +102,7 @@ func runAgentTests(tag, timeout string) error {
 	fmt.Println("  Waiting for agent to be ready...")
 	
 	// Wait for the agent to be ready
-	if err := waitForAgentReady("localhost:8080", timeout); err != nil {
+	if err := waitForAgentReady("localhost:8080", timeout, nil); err != nil {
 		return fmt.Errorf("agent failed to become ready: %v", err)
 	}
 	
@@ -103,11 +129,38 @@ func sanitizeTag(tag string) string {
 	return filepath.Base(tag)
 }
 
-func waitForAgentReady(addr, timeout string) error {
-	// Simple wait implementation
-	// In a real implementation, this would poll the health endpoint
-	fmt.Printf("    Agent ready at %s\n", addr)
-	return nil
+// waitForAgentReady polls the agent's health endpoint until it responds
+// with 200 OK. It gives up after timeout, or when exited is closed because
+// the agent's process stopped; exited may be nil.
+func waitForAgentReady(addr, timeout string, exited <-chan struct{}) error {
+	limit, err := time.ParseDuration(timeout)
+	if err != nil {
+		return fmt.Errorf("invalid timeout '%s': %v", timeout, err)
+	}
+
+	client := &http.Client{Timeout: 2 * time.Second}
+	deadline := time.After(limit)
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-exited:
+			return fmt.Errorf("agent process exited")
+		case <-deadline:
+			return fmt.Errorf("health endpoint did not respond within %s", timeout)
+		case <-ticker.C:
+			resp, err := client.Get(fmt.Sprintf("http://%s/health", addr))
+			if err != nil {
+				continue
+			}
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				fmt.Printf("    Agent ready at %s\n", addr)
+				return nil
+			}
+		}
+	}
 }
 
 func runHealthCheck(addr string) error {
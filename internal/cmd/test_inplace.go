@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/pxkundu/agent-as-code/internal/parser"
+)
+
+// inPlaceVenvDir is the virtual environment agent test --in-place creates
+// in the agent's source directory and reuses across runs
+const inPlaceVenvDir = ".venv"
+
+// runInPlaceTests tests the agent in dir without Docker: it installs the
+// agent's requirements into a virtual environment, starts main.py on a
+// random port, and runs the same checks as for an image, followed by the
+// agent's pytest suite when it has one
+func runInPlaceTests(dir, timeout string) error {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %v", err)
+	}
+
+	agentParser := parser.New()
+	agentFile, err := agentParser.FindAgentFile(absDir)
+	if err != nil {
+		return err
+	}
+	spec, err := agentParser.ParseFile(agentFile)
+	if err != nil {
+		return fmt.Errorf("invalid agent.yaml: %v", err)
+	}
+	if spec.Spec.Runtime != "python" {
+		return fmt.Errorf("--in-place supports python agents only, %s uses the %s runtime", spec.Metadata.Name, spec.Spec.Runtime)
+	}
+
+	fmt.Printf("🧪 Testing agent in place: %s (%s)\n", spec.Metadata.Name, absDir)
+
+	python, err := ensureVenv(absDir)
+	if err != nil {
+		return err
+	}
+
+	port, err := freePort()
+	if err != nil {
+		return fmt.Errorf("failed to find a free port: %v", err)
+	}
+	addr := fmt.Sprintf("127.0.0.1:%d", port)
+
+	fmt.Printf("  Starting main.py on port %d...\n", port)
+
+	// The agent's output is only shown when it fails to start
+	var output bytes.Buffer
+	agent := exec.Command(python, "main.py")
+	agent.Dir = absDir
+	agent.Env = append(os.Environ(), "PORT="+strconv.Itoa(port))
+	agent.Stdout = &output
+	agent.Stderr = &output
+	if err := agent.Start(); err != nil {
+		return fmt.Errorf("failed to start main.py: %v", err)
+	}
+
+	// done is closed when main.py exits, after exitErr is set
+	done := make(chan struct{})
+	var exitErr error
+	go func() {
+		exitErr = agent.Wait()
+		close(done)
+	}()
+	defer func() {
+		agent.Process.Kill()
+		<-done
+	}()
+
+	fmt.Println("  Waiting for agent to be ready...")
+
+	if err := waitForAgentReady(addr, timeout, done); err != nil {
+		select {
+		case <-done:
+			err = fmt.Errorf("%v (%v)", err, exitErr)
+		default:
+			// Still running but not answering, most likely on another port
+			err = fmt.Errorf("%v. main.py must listen on the port in $PORT", err)
+			agent.Process.Kill()
+			<-done
+		}
+		if out := strings.TrimSpace(output.String()); out != "" {
+			fmt.Printf("  Agent output:\n%s\n", indent(lastLines(out, 20), "    "))
+		}
+		return fmt.Errorf("agent failed to become ready: %v", err)
+	}
+
+	fmt.Println("  Running health check...")
+
+	if err := runHealthCheck(addr); err != nil {
+		return fmt.Errorf("health check failed: %v", err)
+	}
+
+	fmt.Println("  Running basic functionality tests...")
+
+	if err := runBasicTests(addr); err != nil {
+		return fmt.Errorf("basic tests failed: %v", err)
+	}
+
+	if err := runPytest(absDir, python, addr); err != nil {
+		return err
+	}
+
+	fmt.Println("✅ All tests passed!")
+	return nil
+}
+
+// ensureVenv creates the agent's virtual environment if needed, installs
+// requirements.txt into it, and returns the path of its python
+func ensureVenv(dir string) (string, error) {
+	venv := filepath.Join(dir, inPlaceVenvDir)
+	python := filepath.Join(venv, "bin", "python")
+	if runtime.GOOS == "windows" {
+		python = filepath.Join(venv, "Scripts", "python.exe")
+	}
+
+	if _, err := os.Stat(python); os.IsNotExist(err) {
+		system, err := findPython()
+		if err != nil {
+			return "", err
+		}
+
+		fmt.Printf("  Creating virtual environment in %s...\n", inPlaceVenvDir)
+		if out, err := exec.Command(system, "-m", "venv", venv).CombinedOutput(); err != nil {
+			return "", fmt.Errorf("failed to create virtual environment: %v\n%s", err, strings.TrimSpace(string(out)))
+		}
+	}
+
+	requirements := filepath.Join(dir, "requirements.txt")
+	if _, err := os.Stat(requirements); err == nil {
+		fmt.Println("  Installing requirements.txt...")
+		install := exec.Command(python, "-m", "pip", "install", "--quiet", "--disable-pip-version-check", "-r", requirements)
+		install.Dir = dir
+		if out, err := install.CombinedOutput(); err != nil {
+			return "", fmt.Errorf("failed to install requirements: %v\n%s", err, strings.TrimSpace(string(out)))
+		}
+	}
+
+	return python, nil
+}
+
+// findPython returns the system Python 3 used to create virtual environments
+func findPython() (string, error) {
+	for _, name := range []string{"python3", "python"} {
+		path, err := exec.LookPath(name)
+		if err != nil {
+			continue
+		}
+		out, err := exec.Command(path, "--version").Output()
+		if err == nil && strings.HasPrefix(strings.TrimSpace(string(out)), "Python 3") {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("Python 3 not found. Install Python 3.8 or later to use --in-place")
+}
+
+// runPytest runs the agent's tests directory with pytest, when the agent
+// has tests and pytest is installed. AGENT_URL points the tests at the
+// running agent.
+func runPytest(dir, python, addr string) error {
+	if _, err := os.Stat(filepath.Join(dir, "tests")); os.IsNotExist(err) {
+		return nil
+	}
+
+	if err := exec.Command(python, "-c", "import pytest").Run(); err != nil {
+		fmt.Println("  ⚠️  Skipping tests/: pytest is not installed, add it to requirements.txt")
+		return nil
+	}
+
+	fmt.Println("  Running pytest...")
+
+	pytest := exec.Command(python, "-m", "pytest", "tests/")
+	pytest.Dir = dir
+	pytest.Env = append(os.Environ(), "AGENT_URL=http://"+addr)
+	pytest.Stdout = os.Stdout
+	pytest.Stderr = os.Stderr
+	if err := pytest.Run(); err != nil {
+		return fmt.Errorf("pytest failed: %v", err)
+	}
+	return nil
+}
+
+// freePort asks the OS for a free local TCP port
+func freePort() (int, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
+
+// lastLines returns the last n lines of s
+func lastLines(s string, n int) string {
+	lines := strings.Split(s, "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// indent prefixes every line of s
+func indent(s, prefix string) string {
+	return prefix + strings.ReplaceAll(s, "\n", "\n"+prefix)
+}
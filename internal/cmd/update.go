@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pxkundu/agent-as-code/internal/api"
+	"github.com/spf13/cobra"
+)
+
+var updateCmd = &cobra.Command{
+	Use:   "update [OPTIONS] VERSION",
+	Short: "Install a release of the agent CLI",
+	Long: `Download and install a release of the agent CLI binary.
+
+The binary for the current platform is downloaded from the binary API (or
+the backend selected by AGENT_BINARY_BACKEND) into --install-dir, by
+default the directory of the running agent binary. It is checked by
+running 'agent version', unless --skip-verify is given, and only then
+replaces the installed binary. If the download or the check fails, the
+installed binary is kept.
+
+The install directory is then added to PATH for future shells: on Unix an
+export is appended to the rc file of the shell in $SHELL, on Windows the
+user PATH is updated with setx. --no-path-update skips this.
+
+Examples:
+  agent update 1.2.0
+  agent update --install-dir ~/.local/bin 1.2.0
+  agent update --no-path-update 1.2.0`,
+	Args: cobra.ExactArgs(1),
+	RunE: runUpdate,
+}
+
+var (
+	updateRegistry     string
+	updateInstallDir   string
+	updateNoPathUpdate bool
+	updateSkipVerify   bool
+)
+
+func init() {
+	rootCmd.AddCommand(updateCmd)
+
+	updateCmd.Flags().StringVar(&updateRegistry, "registry", "https://api.myagentregistry.com", "binary API URL")
+	updateCmd.Flags().StringVar(&updateInstallDir, "install-dir", "", "directory to install the binary into (default: that of the running binary)")
+	updateCmd.Flags().BoolVar(&updateNoPathUpdate, "no-path-update", false, "do not add the install directory to PATH")
+	updateCmd.Flags().BoolVar(&updateSkipVerify, "skip-verify", false, "do not run 'agent version' on the downloaded binary before installing it")
+}
+
+func runUpdate(cmd *cobra.Command, args []string) error {
+	version := args[0]
+
+	installDir := updateInstallDir
+	if installDir == "" {
+		executable, err := os.Executable()
+		if err != nil {
+			return fmt.Errorf("failed to locate the running binary; use --install-dir: %w", err)
+		}
+		installDir = filepath.Dir(executable)
+	}
+
+	fmt.Printf("📥 Installing agent %s into %s\n", version, installDir)
+
+	downloader := api.NewDownloader(updateRegistry)
+	result := downloader.InstallBinaryWithOptions(version, installDir, api.InstallOptions{
+		NoPathUpdate: updateNoPathUpdate,
+		SkipVerify:   updateSkipVerify,
+	})
+	if !result.Success {
+		return fmt.Errorf("update failed: %w", result.Error)
+	}
+
+	return nil
+}
@@ -1,22 +1,104 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"runtime"
+	"strings"
 
 	"github.com/docker/docker/client"
+	"github.com/pxkundu/agent-as-code/internal/config"
 	"github.com/spf13/cobra"
 )
 
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Show version information",
-	Run:   runVersion,
+	Long: `Show version information.
+
+The default text output is a banner with system, Docker and LLM details.
+Use --output json for machine-readable version info or --output short for
+just the version number. agent --version also accepts --output.
+
+Examples:
+  agent version
+  agent version --output json
+  agent --version --output short`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		output, _ := cmd.Flags().GetString("output")
+		if output == "text" {
+			runVersion(cmd, args)
+			return nil
+		}
+		return printVersion(output)
+	},
+}
+
+// versionInfo is the machine-readable version information
+type versionInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	Date      string `json:"date"`
+	GoVersion string `json:"go_version"`
+	OS        string `json:"os"`
+	Arch      string `json:"arch"`
 }
 
 func init() {
 	rootCmd.AddCommand(versionCmd)
+
+	versionCmd.Flags().String("output", "text", "output format (text|json|short)")
+}
+
+// printVersion prints the version in the json or short format
+func printVersion(output string) error {
+	switch output {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(versionInfo{
+			Version:   version,
+			Commit:    commit,
+			Date:      date,
+			GoVersion: runtime.Version(),
+			OS:        runtime.GOOS,
+			Arch:      runtime.GOARCH,
+		})
+	case "short":
+		fmt.Println(version)
+		return nil
+	default:
+		return fmt.Errorf("invalid output format '%s' (valid: text, json, short)", output)
+	}
+}
+
+// versionOutputFromArgs returns the --output value given along with the root
+// --version flag, and the arguments without it. cobra handles --version
+// before any subcommand, and the root command has no --output flag, so the
+// arguments are checked directly.
+func versionOutputFromArgs(args []string) (output string, rest []string, ok bool) {
+	hasVersion := false
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			rest = append(rest, args[i:]...)
+			i = len(args)
+		case arg == "--output" && i+1 < len(args):
+			output = args[i+1]
+			i++
+		case strings.HasPrefix(arg, "--output="):
+			output = strings.TrimPrefix(arg, "--output=")
+		default:
+			if arg == "--version" {
+				hasVersion = true
+			}
+			rest = append(rest, arg)
+		}
+	}
+	return output, rest, hasVersion && output != ""
 }
 
 func runVersion(cmd *cobra.Command, args []string) {
@@ -70,6 +152,8 @@ func runVersion(cmd *cobra.Command, args []string) {
 	}
 	if os.Getenv("AGENT_REGISTRY_TOKEN") != "" {
 		fmt.Println("📦 Registry: ✅ Authenticated")
+	} else if os.Getenv(config.ServiceAccountTokenEnv) != "" {
+		fmt.Println("📦 Registry: ✅ Authenticated (service account)")
 	}
 
 	// LLM info
@@ -0,0 +1,193 @@
+package compose
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// File is a compose.yaml: a Docker Compose compatible subset describing the
+// agents of a multi-agent system
+type File struct {
+	Name     string             `yaml:"name,omitempty"`
+	Services map[string]Service `yaml:"services"`
+}
+
+// Service is an agent container in a compose file
+type Service struct {
+	Image       string      `yaml:"image"`
+	DependsOn   StringList  `yaml:"depends_on,omitempty"`
+	Environment Environment `yaml:"environment,omitempty"`
+	Ports       []string    `yaml:"ports,omitempty"`
+}
+
+// StringList is a list of names written either as a sequence or as the
+// keys of a mapping, as depends_on allows
+type StringList []string
+
+// UnmarshalYAML accepts a sequence or a mapping
+func (l *StringList) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.SequenceNode:
+		var names []string
+		if err := node.Decode(&names); err != nil {
+			return err
+		}
+		*l = names
+	case yaml.MappingNode:
+		var names []string
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			names = append(names, node.Content[i].Value)
+		}
+		*l = names
+	default:
+		return fmt.Errorf("line %d: expected a list or a mapping", node.Line)
+	}
+	return nil
+}
+
+// Environment holds KEY=VALUE pairs written either as a sequence or as a
+// mapping
+type Environment []string
+
+// UnmarshalYAML accepts a sequence of KEY=VALUE or a mapping of KEY: VALUE.
+// As in Compose, a KEY without a value (a bare list entry, or KEY: with a
+// null value) passes the variable through from the shell, and is left out
+// if the shell does not set it.
+func (e *Environment) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.SequenceNode:
+		var entries []string
+		if err := node.Decode(&entries); err != nil {
+			return err
+		}
+		var pairs []string
+		for _, entry := range entries {
+			if strings.Contains(entry, "=") {
+				pairs = append(pairs, entry)
+			} else if value, ok := os.LookupEnv(entry); ok {
+				pairs = append(pairs, entry+"="+value)
+			}
+		}
+		*e = pairs
+	case yaml.MappingNode:
+		var pairs []string
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i].Value, node.Content[i+1]
+			if value.Tag == "!!null" {
+				if resolved, ok := os.LookupEnv(key); ok {
+					pairs = append(pairs, key+"="+resolved)
+				}
+				continue
+			}
+			pairs = append(pairs, key+"="+value.Value)
+		}
+		*e = pairs
+	default:
+		return fmt.Errorf("line %d: expected a list or a mapping", node.Line)
+	}
+	return nil
+}
+
+var projectNameInvalid = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+// Load reads and validates a compose file
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read compose file: %w", err)
+	}
+
+	var file File
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	if len(file.Services) == 0 {
+		return nil, fmt.Errorf("%s defines no services", path)
+	}
+	for name, service := range file.Services {
+		if service.Image == "" {
+			return nil, fmt.Errorf("service '%s' has no image", name)
+		}
+		for _, dependency := range service.DependsOn {
+			if _, ok := file.Services[dependency]; !ok {
+				return nil, fmt.Errorf("service '%s' depends on undefined service '%s'", name, dependency)
+			}
+		}
+	}
+
+	return &file, nil
+}
+
+// ProjectName returns the project name of a compose file: its name field,
+// or else the name of the directory it is in
+func ProjectName(path string, file *File) string {
+	name := ""
+	if file != nil {
+		name = file.Name
+	}
+	if name == "" {
+		if abs, err := filepath.Abs(path); err == nil {
+			name = filepath.Base(filepath.Dir(abs))
+		}
+	}
+
+	name = projectNameInvalid.ReplaceAllString(strings.ToLower(name), "")
+	if name == "" {
+		name = "default"
+	}
+	return name
+}
+
+// StartOrder returns the service names so that every service comes after
+// the services it depends on. Independent services are sorted by name.
+func (f *File) StartOrder() ([]string, error) {
+	names := make([]string, 0, len(f.Services))
+	for name := range f.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]int)
+	var order []string
+
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		switch state[name] {
+		case done:
+			return nil
+		case visiting:
+			return fmt.Errorf("dependency cycle: %s", strings.Join(append(path, name), " -> "))
+		}
+
+		state[name] = visiting
+		dependencies := append([]string(nil), f.Services[name].DependsOn...)
+		sort.Strings(dependencies)
+		for _, dependency := range dependencies {
+			if err := visit(dependency, append(path, name)); err != nil {
+				return err
+			}
+		}
+		state[name] = done
+		order = append(order, name)
+		return nil
+	}
+
+	for _, name := range names {
+		if err := visit(name, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return order, nil
+}
@@ -0,0 +1,46 @@
+package compose
+
+import (
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestEnvironmentUnmarshalYAML(t *testing.T) {
+	t.Setenv("AGENT_TEST_TOKEN", "from-shell")
+
+	tests := []struct {
+		name string
+		yaml string
+		want Environment
+	}{
+		{
+			name: "mapping",
+			yaml: "LEVEL: debug\nEMPTY: \"\"\nTOKEN_VAR: ${X}\n",
+			want: Environment{"LEVEL=debug", "EMPTY=", "TOKEN_VAR=${X}"},
+		},
+		{
+			name: "mapping with null values",
+			yaml: "AGENT_TEST_TOKEN:\nAGENT_TEST_UNSET:\nLEVEL: debug\n",
+			want: Environment{"AGENT_TEST_TOKEN=from-shell", "LEVEL=debug"},
+		},
+		{
+			name: "sequence",
+			yaml: "- LEVEL=debug\n- AGENT_TEST_TOKEN\n- AGENT_TEST_UNSET\n- EMPTY=\n",
+			want: Environment{"LEVEL=debug", "AGENT_TEST_TOKEN=from-shell", "EMPTY="},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var env Environment
+			if err := yaml.Unmarshal([]byte(tt.yaml), &env); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if !reflect.DeepEqual(env, tt.want) {
+				t.Errorf("Environment = %q, want %q", env, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+// Package compose runs multi-agent systems described by a compose.yaml
+package compose
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/pxkundu/agent-as-code/internal/runtime"
+)
+
+// Labels identifying the containers of a compose project
+const (
+	projectLabel = "agent.dev/compose-project"
+	serviceLabel = "agent.dev/compose-service"
+)
+
+// Orchestrator starts and stops the services of a compose project. All
+// services join a network named after the project and reach each other by
+// service name.
+type Orchestrator struct {
+	project string
+	runtime *runtime.Runtime
+	out     io.Writer
+}
+
+// NewOrchestrator creates an orchestrator for a project, writing progress to
+// out
+func NewOrchestrator(project string, out io.Writer) *Orchestrator {
+	return &Orchestrator{
+		project: project,
+		runtime: runtime.New(),
+		out:     out,
+	}
+}
+
+// Up starts the services of a compose file in dependency order. Services
+// that are already running are left alone.
+func (o *Orchestrator) Up(composeFile string) error {
+	file, err := Load(composeFile)
+	if err != nil {
+		return err
+	}
+	order, err := file.StartOrder()
+	if err != nil {
+		return err
+	}
+
+	if err := o.runtime.CreateNetwork(o.project, map[string]string{projectLabel: o.project}); err != nil {
+		return err
+	}
+
+	existing, err := o.containers()
+	if err != nil {
+		return err
+	}
+
+	for _, name := range order {
+		service := file.Services[name]
+
+		if c, ok := existing[name]; ok {
+			if c.State == "running" {
+				fmt.Fprintf(o.out, "✅ %s is already running\n", name)
+				continue
+			}
+			// Replace containers left behind by an earlier run
+			if err := o.runtime.Remove(c.ID); err != nil {
+				return fmt.Errorf("service '%s': %w", name, err)
+			}
+		}
+
+		if err := o.runtime.ValidateImage(service.Image); err != nil {
+			return fmt.Errorf("service '%s': %w", name, err)
+		}
+
+		fmt.Fprintf(o.out, "🚀 Starting %s (%s)\n", name, service.Image)
+		container, err := o.runtime.Run(&runtime.RunOptions{
+			Image:          service.Image,
+			Ports:          service.Ports,
+			Environment:    service.Environment,
+			Detach:         true,
+			Name:           o.containerName(name),
+			Network:        o.project,
+			NetworkAliases: []string{name},
+			Labels: map[string]string{
+				projectLabel: o.project,
+				serviceLabel: name,
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to start service '%s': %w", name, err)
+		}
+
+		for _, port := range container.Ports {
+			if port.Host != "" {
+				fmt.Fprintf(o.out, "   %s -> %s\n", port.Host, port.Container)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Down stops and removes the containers of the project, newest first, and
+// then its network
+func (o *Orchestrator) Down() error {
+	containers, err := o.runtime.FindContainers(map[string]string{projectLabel: o.project})
+	if err != nil {
+		return err
+	}
+
+	for _, c := range containers {
+		service := c.Labels[serviceLabel]
+		if c.State == "running" {
+			fmt.Fprintf(o.out, "🛑 Stopping %s\n", service)
+			if err := o.runtime.Stop(c.ID); err != nil {
+				return fmt.Errorf("service '%s': %w", service, err)
+			}
+		}
+		if err := o.runtime.Remove(c.ID); err != nil {
+			return fmt.Errorf("service '%s': %w", service, err)
+		}
+	}
+
+	return o.runtime.RemoveNetwork(o.project)
+}
+
+// Logs follows the logs of a service
+func (o *Orchestrator) Logs(service string) error {
+	containers, err := o.containers()
+	if err != nil {
+		return err
+	}
+
+	c, ok := containers[service]
+	if !ok {
+		return fmt.Errorf("service '%s' has no container in project '%s'", service, o.project)
+	}
+
+	return o.runtime.StreamLogs(c.ID, runtime.LogOptions{})
+}
+
+// containers returns the project's containers by service name
+func (o *Orchestrator) containers() (map[string]runtime.ContainerInfo, error) {
+	containers, err := o.runtime.FindContainers(map[string]string{projectLabel: o.project})
+	if err != nil {
+		return nil, err
+	}
+
+	byService := make(map[string]runtime.ContainerInfo)
+	for _, c := range containers {
+		byService[c.Labels[serviceLabel]] = c
+	}
+	return byService, nil
+}
+
+// containerName returns the name of a service's container
+func (o *Orchestrator) containerName(service string) string {
+	return o.project + "-" + service
+}
@@ -0,0 +1,231 @@
+// Package config stores registry profiles and their encrypted PATs in
+// ~/.agent/config.json. A ~/.agent/config.toml is read instead when there is
+// no config.json. Profiles from a system-wide /etc/agent/config.json and a
+// project-local .agent.json are merged in when loading.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pxkundu/agent-as-code/internal/log"
+)
+
+// Profile is a registry and the PAT used to authenticate with it
+type Profile struct {
+	Registry    string `json:"registry" toml:"registry"`
+	PAT         string `json:"pat" toml:"pat,omitempty"`
+	Description string `json:"description" toml:"description,omitempty"`
+
+	// ServiceAccount is the profile's CI identity, if one was created
+	ServiceAccount *ServiceAccount `json:"service_account,omitempty" toml:"service_account,omitempty"`
+
+	// encryptedPAT holds the on-disk PAT until decryptProfiles runs
+	encryptedPAT *EncryptedSecret
+	// plaintextPAT marks profiles written by versions without encryption
+	plaintextPAT bool
+}
+
+// MaskedPAT returns the PAT with all but its first 8 and last 4 characters
+// replaced by asterisks, enough to tell PATs apart without exposing them.
+// PATs too short to keep most of them hidden are masked entirely.
+func (p Profile) MaskedPAT() string {
+	const head, tail = 8, 4
+	if len(p.PAT) < 2*(head+tail) {
+		return strings.Repeat("*", len(p.PAT))
+	}
+	return p.PAT[:head] + strings.Repeat("*", len(p.PAT)-head-tail) + p.PAT[len(p.PAT)-tail:]
+}
+
+// Config holds all profiles and the name of the default profile
+type Config struct {
+	Profiles       map[string]Profile `json:"profiles" toml:"profiles"`
+	DefaultProfile string             `json:"default_profile" toml:"default_profile,omitempty"`
+
+	// layers records where a merged config came from, so Save only writes
+	// the user config file
+	layers *layers
+}
+
+// fileOverride is the config file given with --config, if any
+var fileOverride string
+
+// SetFile makes Load read path instead of the default config files. A path
+// ending in .toml is read as TOML, in which case saves still go to the
+// default config.json.
+func SetFile(path string) {
+	fileOverride = path
+}
+
+// File returns the path config is saved to. Config is always written as
+// JSON.
+func File() string {
+	if fileOverride != "" && !IsTOML(fileOverride) {
+		return fileOverride
+	}
+	return defaultPath("config.json")
+}
+
+// TOMLFile returns the path of the TOML config file
+func TOMLFile() string {
+	if fileOverride != "" && IsTOML(fileOverride) {
+		return fileOverride
+	}
+	return defaultPath("config.toml")
+}
+
+// Source returns the user config file Load reads, in order of precedence: the file given
+// to SetFile, config.json, then config.toml. The file may not exist.
+func Source() string {
+	if fileOverride != "" {
+		return fileOverride
+	}
+
+	jsonFile := File()
+	if _, err := os.Stat(jsonFile); os.IsNotExist(err) {
+		if _, err := os.Stat(TOMLFile()); err == nil {
+			return TOMLFile()
+		}
+	}
+	return jsonFile
+}
+
+// IsTOML reports whether path names a TOML config file
+func IsTOML(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".toml")
+}
+
+// defaultPath returns the path of a file in ~/.agent
+func defaultPath(name string) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = "."
+	}
+
+	return filepath.Join(home, ".agent", name)
+}
+
+// Load reads and merges the system, user and project config files (see
+// Loader) and decrypts the stored PATs. Profiles written in plaintext to the
+// user config by older versions are re-encrypted, which is reported by
+// migrated. TOML config files are read as they are and never rewritten.
+func Load() (config *Config, migrated bool, err error) {
+	loader := NewLoader()
+	config, err = loader.Load()
+	if err != nil {
+		return nil, false, err
+	}
+	return config, loader.Migrated, nil
+}
+
+// loadFile reads a single config file and decrypts its PATs, reporting
+// whether any were stored in plaintext. A missing or unparseable file gives
+// an empty config.
+func loadFile(configFile string) (config *Config, plaintext bool, err error) {
+	// Create default config if file doesn't exist
+	if _, err := os.Stat(configFile); os.IsNotExist(err) {
+		return &Config{
+			Profiles:       make(map[string]Profile),
+			DefaultProfile: "",
+		}, false, nil
+	}
+
+	if IsTOML(configFile) {
+		config, err := LoadTOML(configFile)
+		if err != nil {
+			// Return default config if parsing fails, as for JSON
+			log.Warn("failed to load config", "file", configFile, "error", err)
+			return &Config{
+				Profiles:       make(map[string]Profile),
+				DefaultProfile: "",
+			}, false, nil
+		}
+		return config, false, nil
+	}
+
+	// Read config file
+	data, err := os.ReadFile(configFile)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read config file: %v", err)
+	}
+
+	config = &Config{}
+	if err := json.Unmarshal(data, config); err != nil {
+		// Return default config if parsing fails
+		log.Warn("failed to load config", "file", configFile, "error", err)
+		return &Config{
+			Profiles:       make(map[string]Profile),
+			DefaultProfile: "",
+		}, false, nil
+	}
+
+	// Initialize profiles map if nil
+	if config.Profiles == nil {
+		config.Profiles = make(map[string]Profile)
+	}
+
+	plaintext, err = decryptProfiles(config)
+	if err != nil {
+		return nil, false, fmt.Errorf("%s: %v", configFile, err)
+	}
+
+	return config, plaintext, nil
+}
+
+// Save writes the config file, encrypting the PATs. For a config merged by
+// Loader, only the user's own profiles are written; see Config.userLayer.
+func Save(config *Config) error {
+	config, err := config.userLayer()
+	if err != nil {
+		return err
+	}
+	return saveFile(File(), config)
+}
+
+// saveFile writes config to configFile
+func saveFile(configFile string, config *Config) error {
+	// Ensure config directory exists
+	configDir := filepath.Dir(configFile)
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		return err
+	}
+
+	// Marshal config to JSON
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %v", err)
+	}
+
+	// Write to file
+	if err := os.WriteFile(configFile, data, 0600); err != nil {
+		return fmt.Errorf("failed to write config file: %v", err)
+	}
+
+	return nil
+}
+
+// GetProfile returns the named profile, or the default profile when name is
+// empty. ok is false when name is empty and no default profile is set.
+func GetProfile(name string) (profile Profile, ok bool, err error) {
+	config, _, err := Load()
+	if err != nil {
+		return Profile{}, false, err
+	}
+
+	if name == "" {
+		name = config.DefaultProfile
+		if name == "" {
+			return Profile{}, false, nil
+		}
+	}
+
+	profile, exists := config.Profiles[name]
+	if !exists {
+		return Profile{}, false, fmt.Errorf("profile '%s' not found", name)
+	}
+
+	return profile, true, nil
+}
@@ -0,0 +1,245 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+
+	"github.com/pxkundu/agent-as-code/internal/log"
+)
+
+// ProjectFileName is the project-local config file, looked up in the
+// working directory and its parents
+const ProjectFileName = ".agent.json"
+
+// Config layers, in increasing order of precedence
+const (
+	layerSystem = iota
+	layerUser
+	layerProject
+)
+
+// Loader merges the system, user and project config files. Profiles with
+// the same name are taken from the file with the highest precedence, as is
+// the default profile.
+type Loader struct {
+	// SystemFile is the system-wide config, with the lowest precedence
+	SystemFile string
+	// UserFile is the user's config, which Save writes to
+	UserFile string
+	// ProjectFile is the project-local config, with the highest precedence
+	ProjectFile string
+
+	// Migrated is set by Load when plaintext PATs in the user config were
+	// encrypted
+	Migrated bool
+}
+
+// NewLoader creates a loader for /etc/agent/config.json, the user config
+// (see Source) and the nearest .agent.json
+func NewLoader() *Loader {
+	return &Loader{
+		SystemFile:  systemFile(),
+		UserFile:    Source(),
+		ProjectFile: findProjectFile(),
+	}
+}
+
+// layers records where the profiles of a merged config came from
+type layers struct {
+	files [3]string
+	// user is the user config file as loaded
+	user *Config
+	// sources maps each profile to the layer it was taken from
+	sources map[string]int
+	// loaded and defaultProfile are the merged values as loaded, to tell
+	// which profiles were changed before saving
+	loaded         map[string]Profile
+	defaultProfile string
+	defaultSource  int
+}
+
+// Load reads the config files that exist and merges them. A missing file is
+// skipped; the config is empty when none exists.
+func (l *Loader) Load() (*Config, error) {
+	merged := &Config{Profiles: make(map[string]Profile)}
+	state := &layers{
+		files:         [3]string{l.SystemFile, l.UserFile, l.ProjectFile},
+		sources:       make(map[string]int),
+		loaded:        make(map[string]Profile),
+		defaultSource: -1,
+	}
+
+	var loaded []string
+	for layer, file := range state.files {
+		if file == "" {
+			continue
+		}
+		_, statErr := os.Stat(file)
+
+		config, plaintext, err := loadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		if layer == layerUser {
+			state.user = config
+			// Re-encrypt profiles written by older versions in plaintext.
+			// PATs in the system and project files are left as they are.
+			if plaintext {
+				if err := saveFile(file, config); err != nil {
+					return nil, fmt.Errorf("failed to encrypt stored PATs: %v", err)
+				}
+				l.Migrated = true
+			}
+		}
+
+		if statErr != nil {
+			continue
+		}
+		loaded = append(loaded, file)
+		log.Debug("loaded config file", "file", file, "profiles", len(config.Profiles))
+
+		for name, profile := range config.Profiles {
+			merged.Profiles[name] = profile
+			state.sources[name] = layer
+		}
+		if config.DefaultProfile != "" {
+			merged.DefaultProfile = config.DefaultProfile
+			state.defaultSource = layer
+		}
+	}
+
+	if state.user == nil {
+		state.user = &Config{Profiles: make(map[string]Profile)}
+	}
+	for name, profile := range merged.Profiles {
+		state.loaded[name] = snapshotProfile(profile)
+	}
+	state.defaultProfile = merged.DefaultProfile
+	merged.layers = state
+
+	if len(loaded) == 0 {
+		log.Debug("no config files found", "searched", strings.Join(nonEmpty(state.files[:]), ", "))
+	} else {
+		log.Debug("config loaded", "files", strings.Join(loaded, ", "), "profiles", len(merged.Profiles))
+	}
+
+	return merged, nil
+}
+
+// userLayer returns the config to write to the user config file: the user
+// file as loaded with the changes made to the merged config since. Changes
+// to profiles from the project file, which would have no effect, and
+// removals of profiles from the system or project file, which would be
+// undone on the next load, are errors.
+func (c *Config) userLayer() (*Config, error) {
+	state := c.layers
+	if state == nil {
+		return c, nil
+	}
+
+	out := &Config{
+		Profiles:       make(map[string]Profile, len(state.user.Profiles)),
+		DefaultProfile: state.user.DefaultProfile,
+	}
+	for name, profile := range state.user.Profiles {
+		out.Profiles[name] = profile
+	}
+
+	for name, loaded := range state.loaded {
+		source := state.sources[name]
+		current, ok := c.Profiles[name]
+		switch {
+		case !ok && source != layerUser:
+			return nil, fmt.Errorf("profile '%s' is defined in %s; remove it there", name, state.files[source])
+		case !ok:
+			delete(out.Profiles, name)
+		case !profileChanged(current, loaded):
+			// Unchanged profiles keep coming from their own file
+		case source == layerProject:
+			return nil, fmt.Errorf("profile '%s' is defined in %s, which takes precedence over %s; change it there", name, state.files[source], state.files[layerUser])
+		default:
+			out.Profiles[name] = current
+		}
+	}
+	for name, profile := range c.Profiles {
+		if _, ok := state.loaded[name]; !ok {
+			out.Profiles[name] = profile
+		}
+	}
+
+	if c.DefaultProfile != state.defaultProfile {
+		if state.defaultSource == layerProject {
+			return nil, fmt.Errorf("the default profile is set in %s, which takes precedence over %s; change it there", state.files[layerProject], state.files[layerUser])
+		}
+		out.DefaultProfile = c.DefaultProfile
+	}
+
+	return out, nil
+}
+
+// snapshotProfile copies profile, including its service account, so later
+// changes to the merged config can be detected
+func snapshotProfile(profile Profile) Profile {
+	if profile.ServiceAccount != nil {
+		account := *profile.ServiceAccount
+		profile.ServiceAccount = &account
+	}
+	return profile
+}
+
+// profileChanged reports whether current differs from the profile as
+// loaded. A service account from the environment is not a change, since it
+// is never saved.
+func profileChanged(current, loaded Profile) bool {
+	if current.ServiceAccount != nil && current.ServiceAccount.FromEnv {
+		current.ServiceAccount = current.ServiceAccount.stored
+	}
+	return !reflect.DeepEqual(current, loaded)
+}
+
+// systemFile returns the path of the system-wide config file
+func systemFile() string {
+	if runtime.GOOS == "windows" {
+		if programData := os.Getenv("ProgramData"); programData != "" {
+			return filepath.Join(programData, "agent", "config.json")
+		}
+		return ""
+	}
+	return "/etc/agent/config.json"
+}
+
+// findProjectFile returns the nearest .agent.json in the working directory
+// or one of its parents, or "" if there is none
+func findProjectFile() string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+
+	for {
+		path := filepath.Join(dir, ProjectFileName)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+func nonEmpty(items []string) []string {
+	var out []string
+	for _, item := range items {
+		if item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
@@ -0,0 +1,335 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zalando/go-keyring"
+)
+
+const (
+	keyringService = "agent-as-code"
+	keyringUser    = "config-encryption-key"
+
+	keySourceKeyring   = "keyring"
+	keySourceMachineID = "machine-id"
+)
+
+// EncryptedSecret is the on-disk representation of an encrypted PAT
+type EncryptedSecret struct {
+	Encrypted  bool   `json:"encrypted"`
+	Ciphertext string `json:"ciphertext"`
+	KeySource  string `json:"key_source,omitempty"`
+}
+
+// profileJSON mirrors Profile for (un)marshaling with an opaque PAT field
+type profileJSON struct {
+	Registry       string              `json:"registry"`
+	PAT            json.RawMessage     `json:"pat,omitempty"`
+	Description    string              `json:"description"`
+	ServiceAccount *serviceAccountJSON `json:"service_account,omitempty"`
+}
+
+// serviceAccountJSON mirrors ServiceAccount with an encrypted token
+type serviceAccountJSON struct {
+	Name      string           `json:"name"`
+	Token     *EncryptedSecret `json:"token"`
+	Scope     []string         `json:"scope"`
+	ExpiresAt time.Time        `json:"expires_at"`
+}
+
+// MarshalJSON encrypts the PAT before it is written to disk
+func (p Profile) MarshalJSON() ([]byte, error) {
+	out := profileJSON{
+		Registry:    p.Registry,
+		Description: p.Description,
+	}
+
+	if p.PAT != "" {
+		secret, err := encryptSecret(p.PAT)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt PAT: %v", err)
+		}
+		raw, err := json.Marshal(secret)
+		if err != nil {
+			return nil, err
+		}
+		out.PAT = raw
+	}
+
+	account := p.ServiceAccount
+	if account != nil && account.FromEnv {
+		account = account.stored
+	}
+	if account != nil {
+		secret, err := encryptSecret(account.Token)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt service account token: %v", err)
+		}
+		out.ServiceAccount = &serviceAccountJSON{
+			Name:      account.Name,
+			Token:     secret,
+			Scope:     account.Scope,
+			ExpiresAt: account.ExpiresAt,
+		}
+	}
+
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON accepts both encrypted and legacy plaintext PAT values.
+// Decryption is deferred to decryptProfiles so that a key failure is
+// reported as an error rather than being treated as a corrupt config.
+func (p *Profile) UnmarshalJSON(data []byte) error {
+	var in profileJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+
+	p.Registry = in.Registry
+	p.Description = in.Description
+	p.PAT = ""
+	p.encryptedPAT = nil
+	p.plaintextPAT = false
+	p.ServiceAccount = nil
+
+	if in.ServiceAccount != nil {
+		p.ServiceAccount = &ServiceAccount{
+			Name:           in.ServiceAccount.Name,
+			Scope:          in.ServiceAccount.Scope,
+			ExpiresAt:      in.ServiceAccount.ExpiresAt,
+			encryptedToken: in.ServiceAccount.Token,
+		}
+	}
+
+	if len(in.PAT) == 0 || string(in.PAT) == "null" {
+		return nil
+	}
+
+	// Legacy configs store the PAT as a plain string
+	var plaintext string
+	if err := json.Unmarshal(in.PAT, &plaintext); err == nil {
+		p.PAT = plaintext
+		p.plaintextPAT = plaintext != ""
+		return nil
+	}
+
+	var secret EncryptedSecret
+	if err := json.Unmarshal(in.PAT, &secret); err != nil {
+		return fmt.Errorf("invalid pat field: %v", err)
+	}
+	p.encryptedPAT = &secret
+	return nil
+}
+
+// decryptProfiles decrypts the PATs of all loaded profiles and reports
+// whether any profile still stored its PAT in plaintext
+func decryptProfiles(config *Config) (bool, error) {
+	needsMigration := false
+
+	for name, profile := range config.Profiles {
+		if account := profile.ServiceAccount; account != nil && account.encryptedToken != nil {
+			token, err := decryptSecret(account.encryptedToken)
+			if err != nil {
+				return false, fmt.Errorf("failed to decrypt service account token for profile '%s': %v", name, err)
+			}
+			account.Token = token
+			account.encryptedToken = nil
+		}
+
+		if profile.plaintextPAT {
+			needsMigration = true
+			continue
+		}
+		if profile.encryptedPAT == nil {
+			continue
+		}
+
+		pat, err := decryptSecret(profile.encryptedPAT)
+		if err != nil {
+			return false, fmt.Errorf("failed to decrypt PAT for profile '%s': %v", name, err)
+		}
+		profile.PAT = pat
+		config.Profiles[name] = profile
+	}
+
+	return needsMigration, nil
+}
+
+var (
+	encryptionKeyOnce   sync.Once
+	encryptionKey       []byte
+	encryptionKeySource string
+	encryptionKeyErr    error
+)
+
+// getEncryptionKey returns the key used for new ciphertexts, preferring a
+// random key held in the OS keyring and falling back to a key derived from
+// the machine ID when no keyring is available
+func getEncryptionKey() ([]byte, string, error) {
+	encryptionKeyOnce.Do(func() {
+		if key, err := keyringKey(true); err == nil {
+			encryptionKey, encryptionKeySource = key, keySourceKeyring
+			return
+		}
+
+		key, err := machineIDKey()
+		if err != nil {
+			encryptionKeyErr = fmt.Errorf("no OS keyring available and machine ID could not be read: %v", err)
+			return
+		}
+		encryptionKey, encryptionKeySource = key, keySourceMachineID
+	})
+
+	return encryptionKey, encryptionKeySource, encryptionKeyErr
+}
+
+// keyForSource returns the key for decrypting a secret with the given source
+func keyForSource(source string) ([]byte, error) {
+	switch source {
+	case keySourceKeyring:
+		return keyringKey(false)
+	case keySourceMachineID, "":
+		return machineIDKey()
+	default:
+		return nil, fmt.Errorf("unknown key source '%s'", source)
+	}
+}
+
+// keyringKey loads the encryption key from the OS keyring, generating and
+// storing a new one when create is set and none exists yet
+func keyringKey(create bool) ([]byte, error) {
+	encoded, err := keyring.Get(keyringService, keyringUser)
+	if err == nil {
+		return base64.StdEncoding.DecodeString(encoded)
+	}
+	if err != keyring.ErrNotFound || !create {
+		return nil, err
+	}
+
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+	if err := keyring.Set(keyringService, keyringUser, base64.StdEncoding.EncodeToString(key)); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// machineIDKey derives an AES-256 key from the machine ID
+func machineIDKey() ([]byte, error) {
+	id, err := readMachineID()
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256([]byte("agent-as-code:" + id))
+	return sum[:], nil
+}
+
+var ioregUUIDPattern = regexp.MustCompile(`"IOPlatformUUID"\s*=\s*"([^"]+)"`)
+
+// readMachineID reads a stable per-machine identifier
+func readMachineID() (string, error) {
+	if runtime.GOOS == "darwin" {
+		output, err := exec.Command("ioreg", "-rd1", "-c", "IOPlatformExpertDevice").Output()
+		if err != nil {
+			return "", fmt.Errorf("failed to run ioreg: %v", err)
+		}
+		match := ioregUUIDPattern.FindSubmatch(output)
+		if match == nil {
+			return "", fmt.Errorf("IOPlatformUUID not found in ioreg output")
+		}
+		return string(match[1]), nil
+	}
+
+	for _, path := range []string{"/etc/machine-id", "/var/lib/dbus/machine-id"} {
+		data, err := os.ReadFile(path)
+		if err == nil && strings.TrimSpace(string(data)) != "" {
+			return strings.TrimSpace(string(data)), nil
+		}
+	}
+
+	return "", fmt.Errorf("machine ID not found")
+}
+
+// encryptSecret encrypts plaintext with AES-256-GCM
+func encryptSecret(plaintext string) (*EncryptedSecret, error) {
+	key, source, err := getEncryptionKey()
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return &EncryptedSecret{
+		Encrypted:  true,
+		Ciphertext: base64.StdEncoding.EncodeToString(sealed),
+		KeySource:  source,
+	}, nil
+}
+
+// decryptSecret decrypts a value produced by encryptSecret
+func decryptSecret(secret *EncryptedSecret) (string, error) {
+	if !secret.Encrypted {
+		return secret.Ciphertext, nil
+	}
+
+	key, err := keyForSource(secret.KeySource)
+	if err != nil {
+		return "", fmt.Errorf("failed to load %s key: %v", secret.KeySource, err)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(secret.Ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("invalid ciphertext encoding: %v", err)
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	if len(data) < gcm.NonceSize() {
+		return "", fmt.Errorf("ciphertext too short")
+	}
+
+	nonce, ciphertext := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("ciphertext could not be authenticated: %v", err)
+	}
+
+	return string(plaintext), nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ServiceAccountTokenEnv is the environment variable CI jobs pass a service
+// account token in, as an alternative to AGENT_REGISTRY_TOKEN
+const ServiceAccountTokenEnv = "AGENT_SERVICE_ACCOUNT_TOKEN"
+
+// ServiceAccount is a non-interactive identity for CI. Its token is a JWT
+// signed (HS256) with the PAT of the profile it was created for.
+type ServiceAccount struct {
+	Name      string    `json:"name" toml:"name"`
+	Token     string    `json:"token" toml:"token,omitempty"`
+	Scope     []string  `json:"scope" toml:"scope"`
+	ExpiresAt time.Time `json:"expires_at" toml:"expires_at"`
+
+	// FromEnv marks a token read from ServiceAccountTokenEnv, which is never
+	// saved to the config file
+	FromEnv bool `json:"-" toml:"-"`
+
+	// stored is the profile's own service account, which is saved in place
+	// of a FromEnv one
+	stored *ServiceAccount
+
+	// encryptedToken holds the on-disk token until decryptProfiles runs
+	encryptedToken *EncryptedSecret
+}
+
+// ApplyServiceAccountEnv makes a token in ServiceAccountTokenEnv the default
+// profile's service account. Save keeps writing the profile's own account.
+func (c *Config) ApplyServiceAccountEnv() {
+	token := os.Getenv(ServiceAccountTokenEnv)
+	profile, ok := c.Profiles[c.DefaultProfile]
+	if token == "" || !ok {
+		return
+	}
+
+	profile.ServiceAccount = &ServiceAccount{Token: token, FromEnv: true, stored: profile.ServiceAccount}
+	c.Profiles[c.DefaultProfile] = profile
+}
+
+// serviceAccountClaims are the claims of a service account token
+type serviceAccountClaims struct {
+	Scope string `json:"scope"`
+	jwt.RegisteredClaims
+}
+
+// NewServiceAccount creates a service account and signs its token with
+// secret, the PAT of the profile it belongs to
+func NewServiceAccount(name string, scope []string, expiry time.Duration, secret string) (*ServiceAccount, error) {
+	if secret == "" {
+		return nil, fmt.Errorf("a PAT is required to sign service account tokens")
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	claims := serviceAccountClaims{
+		Scope: strings.Join(scope, ","),
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   name,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(expiry)),
+		},
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign token: %v", err)
+	}
+
+	return &ServiceAccount{
+		Name:      name,
+		Token:     token,
+		Scope:     scope,
+		ExpiresAt: now.Add(expiry),
+	}, nil
+}
+
+// ParseServiceAccountToken reads the claims of a service account token and
+// rejects expired tokens. The signature is checked against secret unless it
+// is empty, as on CI machines that only hold the token.
+func ParseServiceAccountToken(token, secret string) (*ServiceAccount, error) {
+	claims := &serviceAccountClaims{}
+
+	var err error
+	if secret == "" {
+		_, _, err = jwt.NewParser().ParseUnverified(token, claims)
+		if err == nil && claims.ExpiresAt != nil && claims.ExpiresAt.Before(time.Now()) {
+			err = jwt.ErrTokenExpired
+		}
+	} else {
+		_, err = jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+			return []byte(secret), nil
+		}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid service account token: %v", err)
+	}
+
+	account := &ServiceAccount{
+		Name:  claims.Subject,
+		Token: token,
+	}
+	if claims.Scope != "" {
+		account.Scope = strings.Split(claims.Scope, ",")
+	}
+	if claims.ExpiresAt != nil {
+		account.ExpiresAt = claims.ExpiresAt.Time
+	}
+	return account, nil
+}
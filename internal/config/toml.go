@@ -0,0 +1,86 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	// go-toml/v2 rather than BurntSushi/toml: viper already depends on it,
+	// so TOML support adds no new module
+	"github.com/pelletier/go-toml/v2"
+)
+
+// LoadTOML reads a TOML config file. PATs in TOML files are plaintext
+// strings; they are encrypted once the config is saved as JSON.
+func LoadTOML(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %v", err)
+	}
+
+	config := &Config{}
+	if err := toml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+	if config.Profiles == nil {
+		config.Profiles = make(map[string]Profile)
+	}
+
+	return config, nil
+}
+
+// ExportTOML writes config as TOML. PATs are left out unless includePATs is
+// set, since exported configs are usually shared.
+func ExportTOML(config *Config, w io.Writer, includePATs bool) error {
+	out := Config{
+		Profiles:       make(map[string]Profile, len(config.Profiles)),
+		DefaultProfile: config.DefaultProfile,
+	}
+	for name, profile := range config.Profiles {
+		if profile.ServiceAccount != nil && profile.ServiceAccount.FromEnv {
+			profile.ServiceAccount = profile.ServiceAccount.stored
+		}
+		if !includePATs {
+			profile.PAT = ""
+			if profile.ServiceAccount != nil {
+				account := *profile.ServiceAccount
+				account.Token = ""
+				profile.ServiceAccount = &account
+			}
+		}
+		out.Profiles[name] = profile
+	}
+
+	encoder := toml.NewEncoder(w)
+	if err := encoder.Encode(out); err != nil {
+		return fmt.Errorf("failed to marshal config: %v", err)
+	}
+	return nil
+}
+
+// MigrateToJSON converts the TOML config file to JSON, encrypting its PATs.
+// The TOML file is renamed to <file>.bak so it is no longer read. It returns
+// the path of the backup.
+func MigrateToJSON() (backup string, err error) {
+	tomlFile := TOMLFile()
+	if _, err := os.Stat(tomlFile); os.IsNotExist(err) {
+		return "", fmt.Errorf("no TOML config found at %s", tomlFile)
+	}
+	if _, err := os.Stat(File()); err == nil {
+		return "", fmt.Errorf("%s already exists; remove it first to migrate %s", File(), tomlFile)
+	}
+
+	config, err := LoadTOML(tomlFile)
+	if err != nil {
+		return "", err
+	}
+	if err := Save(config); err != nil {
+		return "", err
+	}
+
+	backup = tomlFile + ".bak"
+	if err := os.Rename(tomlFile, backup); err != nil {
+		return "", fmt.Errorf("failed to rename %s: %v", tomlFile, err)
+	}
+	return backup, nil
+}
@@ -0,0 +1,332 @@
+// Package imagediff compares the filesystems and configuration of two
+// Docker images
+package imagediff
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/client"
+)
+
+// Whiteout markers of the layer format: a ".wh.<name>" entry deletes <name>
+// from the lower layers, and an opaque marker hides the whole directory
+// below it
+const (
+	whiteoutPrefix = ".wh."
+	whiteoutOpaque = ".wh..wh..opq"
+)
+
+// maxMetadataSize bounds the non-layer entries of a saved image that are
+// kept in memory, the manifest and image configs
+const maxMetadataSize = 16 << 20
+
+// Change is a changed environment variable or label
+type Change struct {
+	Key string `json:"key"`
+	// Old is empty for added keys, New for removed ones
+	Old string `json:"old,omitempty"`
+	New string `json:"new,omitempty"`
+}
+
+// DiffResult holds the differences from ImageA to ImageB. File paths are
+// absolute paths in the image filesystem; directories are not listed.
+type DiffResult struct {
+	ImageA string `json:"image_a"`
+	ImageB string `json:"image_b"`
+
+	Added    []string `json:"added,omitempty"`
+	Removed  []string `json:"removed,omitempty"`
+	Modified []string `json:"modified,omitempty"`
+
+	EnvAdded       []Change `json:"env_added,omitempty"`
+	EnvRemoved     []Change `json:"env_removed,omitempty"`
+	EnvModified    []Change `json:"env_modified,omitempty"`
+	LabelsAdded    []Change `json:"labels_added,omitempty"`
+	LabelsRemoved  []Change `json:"labels_removed,omitempty"`
+	LabelsModified []Change `json:"labels_modified,omitempty"`
+}
+
+// Differ compares images in the local Docker daemon
+type Differ struct {
+	dockerClient *client.Client
+}
+
+// NewDiffer creates a differ for the Docker daemon in the environment
+func NewDiffer() (*Differ, error) {
+	dockerClient, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Docker client: %w", err)
+	}
+	return &Differ{dockerClient: dockerClient}, nil
+}
+
+// Compare compares the filesystems, environment variables and labels of
+// imageA and imageB. Both images are exported with ImageSave and their
+// layers applied in order, so files deleted by later layers don't count.
+func (d *Differ) Compare(imageA, imageB string) (*DiffResult, error) {
+	filesA, err := d.imageFiles(imageA)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", imageA, err)
+	}
+	filesB, err := d.imageFiles(imageB)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", imageB, err)
+	}
+
+	result, err := d.CompareConfig(imageA, imageB)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, sum := range filesB {
+		if old, ok := filesA[name]; !ok {
+			result.Added = append(result.Added, name)
+		} else if old != sum {
+			result.Modified = append(result.Modified, name)
+		}
+	}
+	for name := range filesA {
+		if _, ok := filesB[name]; !ok {
+			result.Removed = append(result.Removed, name)
+		}
+	}
+	sort.Strings(result.Added)
+	sort.Strings(result.Removed)
+	sort.Strings(result.Modified)
+
+	return result, nil
+}
+
+// CompareConfig compares only the environment variables and labels of
+// imageA and imageB, which needs no export of the images
+func (d *Differ) CompareConfig(imageA, imageB string) (*DiffResult, error) {
+	envA, labelsA, err := d.imageConfig(imageA)
+	if err != nil {
+		return nil, err
+	}
+	envB, labelsB, err := d.imageConfig(imageB)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DiffResult{ImageA: imageA, ImageB: imageB}
+	result.EnvAdded, result.EnvRemoved, result.EnvModified = compareMaps(envA, envB)
+	result.LabelsAdded, result.LabelsRemoved, result.LabelsModified = compareMaps(labelsA, labelsB)
+	return result, nil
+}
+
+// imageConfig returns the environment variables and labels of image
+func (d *Differ) imageConfig(image string) (map[string]string, map[string]string, error) {
+	inspect, _, err := d.dockerClient.ImageInspectWithRaw(context.Background(), image)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to inspect %s: %w", image, err)
+	}
+
+	env := make(map[string]string)
+	labels := make(map[string]string)
+	if inspect.Config != nil {
+		for _, entry := range inspect.Config.Env {
+			key, value, _ := strings.Cut(entry, "=")
+			env[key] = value
+		}
+		for key, value := range inspect.Config.Labels {
+			labels[key] = value
+		}
+	}
+	return env, labels, nil
+}
+
+// compareMaps returns the keys added in b, removed from a, and changed
+func compareMaps(a, b map[string]string) (added, removed, modified []Change) {
+	for key, value := range b {
+		if old, ok := a[key]; !ok {
+			added = append(added, Change{Key: key, New: value})
+		} else if old != value {
+			modified = append(modified, Change{Key: key, Old: old, New: value})
+		}
+	}
+	for key, value := range a {
+		if _, ok := b[key]; !ok {
+			removed = append(removed, Change{Key: key, Old: value})
+		}
+	}
+
+	for _, changes := range [][]Change{added, removed, modified} {
+		sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	}
+	return added, removed, modified
+}
+
+// layerChanges is what a single layer does to the filesystem below it
+type layerChanges struct {
+	files     map[string]string // path -> sha256 of the content
+	whiteouts []string          // paths deleted from lower layers
+	opaque    []string          // directories whose lower contents are hidden
+}
+
+// imageFiles exports image and returns the sha256 of every file in its
+// final filesystem, keyed by path
+func (d *Differ) imageFiles(image string) (map[string]string, error) {
+	reader, err := d.dockerClient.ImageSave(context.Background(), []string{image})
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	// Layers can come in any order in the archive, so collect each layer's
+	// changes and apply them in the order of manifest.json
+	layers := make(map[string]*layerChanges)
+	metadata := make(map[string][]byte)
+
+	archive := tar.NewReader(reader)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name := path.Clean(header.Name)
+		buffered := bufio.NewReader(archive)
+		peek, _ := buffered.Peek(1)
+		if len(peek) == 1 && (peek[0] == '{' || peek[0] == '[') {
+			if header.Size > maxMetadataSize {
+				continue
+			}
+			content, err := io.ReadAll(buffered)
+			if err != nil {
+				return nil, err
+			}
+			metadata[name] = content
+			continue
+		}
+
+		changes, err := readLayer(buffered)
+		if err != nil {
+			// Not a layer; the manifest lists every layer, so a real
+			// layer that fails to parse is reported below
+			continue
+		}
+		layers[name] = changes
+	}
+
+	var manifest []struct {
+		Layers []string `json:"Layers"`
+	}
+	data, ok := metadata["manifest.json"]
+	if !ok {
+		return nil, fmt.Errorf("saved image has no manifest.json")
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil || len(manifest) == 0 {
+		return nil, fmt.Errorf("invalid manifest.json in saved image")
+	}
+
+	files := make(map[string]string)
+	for _, layerPath := range manifest[0].Layers {
+		changes, ok := layers[path.Clean(layerPath)]
+		if !ok {
+			return nil, fmt.Errorf("layer %s missing from saved image", layerPath)
+		}
+		for _, dir := range changes.opaque {
+			removeTree(files, dir, false)
+		}
+		for _, deleted := range changes.whiteouts {
+			removeTree(files, deleted, true)
+		}
+		for name, sum := range changes.files {
+			files[name] = sum
+		}
+	}
+
+	return files, nil
+}
+
+// readLayer reads a layer archive, which may be gzip-compressed
+func readLayer(r *bufio.Reader) (*layerChanges, error) {
+	var layer io.Reader = r
+	if magic, _ := r.Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		layer = gz
+	}
+
+	changes := &layerChanges{files: make(map[string]string)}
+	entries := tar.NewReader(layer)
+	for {
+		entry, err := entries.Next()
+		if err == io.EOF {
+			return changes, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		name := "/" + strings.TrimPrefix(path.Clean("/"+entry.Name), "/")
+		dir, base := path.Split(name)
+		switch {
+		case base == whiteoutOpaque:
+			changes.opaque = append(changes.opaque, path.Clean(dir))
+			continue
+		case strings.HasPrefix(base, whiteoutPrefix):
+			changes.whiteouts = append(changes.whiteouts, path.Join(dir, strings.TrimPrefix(base, whiteoutPrefix)))
+			continue
+		}
+
+		var sum string
+		switch entry.Typeflag {
+		case tar.TypeReg:
+			hash := sha256.New()
+			if _, err := io.Copy(hash, entries); err != nil {
+				return nil, err
+			}
+			sum = hex.EncodeToString(hash.Sum(nil))
+		case tar.TypeSymlink:
+			sum = hashString("symlink:" + entry.Linkname)
+		case tar.TypeLink:
+			sum = hashString("link:" + entry.Linkname)
+		case tar.TypeDir:
+			continue
+		default:
+			sum = hashString(fmt.Sprintf("special:%c:%d:%d", entry.Typeflag, entry.Devmajor, entry.Devminor))
+		}
+		changes.files[name] = sum
+	}
+}
+
+// removeTree deletes dir's contents from files, and dir itself if self is
+// set
+func removeTree(files map[string]string, dir string, self bool) {
+	if self {
+		delete(files, dir)
+	}
+	prefix := strings.TrimSuffix(dir, "/") + "/"
+	for name := range files {
+		if strings.HasPrefix(name, prefix) {
+			delete(files, name)
+		}
+	}
+}
+
+func hashString(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,107 @@
+// Package install provides helpers for setting up an installed binary
+package install
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// AddToPath makes dir available on PATH for future shells. On Unix it
+// appends an export to the rc file of the shell in $SHELL; on Windows it
+// updates the user PATH with setx. It does nothing if dir is already on PATH.
+func AddToPath(dir string) error {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve directory: %w", err)
+	}
+
+	if inPath(dir) {
+		return nil
+	}
+
+	if runtime.GOOS == "windows" {
+		return addToWindowsPath(dir)
+	}
+
+	rcFile, line, err := shellProfile(dir)
+	if err != nil {
+		return err
+	}
+
+	existing, err := os.ReadFile(rcFile)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", rcFile, err)
+	}
+	if strings.Contains(string(existing), line) {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(rcFile), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(rcFile), err)
+	}
+
+	file, err := os.OpenFile(rcFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", rcFile, err)
+	}
+	defer file.Close()
+
+	if _, err := fmt.Fprintf(file, "\n# Added by agent installer\n%s\n", line); err != nil {
+		return fmt.Errorf("failed to update %s: %w", rcFile, err)
+	}
+
+	fmt.Printf("✅ Added %s to PATH in %s\n", dir, rcFile)
+	fmt.Printf("💡 Restart your shell or run 'source %s' to use it now\n", rcFile)
+	return nil
+}
+
+// shellProfile returns the rc file and PATH line for the shell in $SHELL
+func shellProfile(dir string) (string, string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	switch filepath.Base(os.Getenv("SHELL")) {
+	case "zsh":
+		return filepath.Join(home, ".zshrc"), fmt.Sprintf("export PATH=\"$PATH:%s\"", dir), nil
+	case "fish":
+		return filepath.Join(home, ".config", "fish", "config.fish"), fmt.Sprintf("set -gx PATH $PATH \"%s\"", dir), nil
+	default:
+		return filepath.Join(home, ".bashrc"), fmt.Sprintf("export PATH=\"$PATH:%s\"", dir), nil
+	}
+}
+
+// addToWindowsPath appends dir to the user PATH with setx
+func addToWindowsPath(dir string) error {
+	newPath := os.Getenv("PATH") + ";" + dir
+	// setx silently truncates values longer than 1024 characters
+	if len(newPath) > 1024 {
+		return fmt.Errorf("PATH is too long for setx; add %s to PATH manually", dir)
+	}
+
+	if output, err := exec.Command("setx", "PATH", newPath).CombinedOutput(); err != nil {
+		return fmt.Errorf("setx failed: %v: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	fmt.Printf("✅ Added %s to PATH\n", dir)
+	fmt.Printf("💡 Open a new terminal to use it\n")
+	return nil
+}
+
+// inPath reports whether dir is already an entry of PATH
+func inPath(dir string) bool {
+	for _, entry := range filepath.SplitList(os.Getenv("PATH")) {
+		if entry == "" {
+			continue
+		}
+		if abs, err := filepath.Abs(entry); err == nil && abs == dir {
+			return true
+		}
+	}
+	return false
+}
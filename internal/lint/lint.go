@@ -0,0 +1,399 @@
+// Package lint checks agent projects against best-practice rules
+package lint
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Severity levels of a violation
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
+// Violation is a single rule violation at a file position
+type Violation struct {
+	File     string
+	Line     int
+	Severity string
+	Rule     string
+	Message  string
+}
+
+// String formats the violation as file:line: severity: message, which most
+// CI tools parse as an annotation
+func (v Violation) String() string {
+	return fmt.Sprintf("%s:%d: %s: %s (%s)", v.File, v.Line, v.Severity, v.Message, v.Rule)
+}
+
+// RuleConfig overrides the defaults of a rule
+type RuleConfig struct {
+	Enabled  *bool  `yaml:"enabled,omitempty"`
+	Severity string `yaml:"severity,omitempty"`
+}
+
+// Config is the lint configuration read from ~/.agent/lint.yaml
+type Config struct {
+	Rules map[string]RuleConfig `yaml:"rules"`
+	// SecretPatterns are the substrings of env var names that mark a secret
+	SecretPatterns []string `yaml:"secretPatterns,omitempty"`
+}
+
+// Rule is a lint rule
+type Rule struct {
+	Name        string
+	Description string
+	Severity    string // Default severity
+	check       func(l *Linter, dir string) ([]Violation, error)
+}
+
+// Rules returns all lint rules
+func Rules() []Rule {
+	return []Rule{
+		{"pinned-base-image", "Dockerfile FROM images are pinned to a digest", SeverityWarning, checkPinnedBaseImage},
+		{"pinned-requirements", "requirements.txt versions are pinned or bounded", SeverityWarning, checkPinnedRequirements},
+		{"agent-description", "agent.yaml has metadata.description", SeverityInfo, checkDescription},
+		{"secret-env-from-secret", "secret-like env vars use from: secret", SeverityError, checkSecretEnv},
+		{"privileged-port", "container ports are 1024 or above", SeverityWarning, checkPrivilegedPort},
+	}
+}
+
+// Linter runs lint rules over an agent project
+type Linter struct {
+	config *Config
+}
+
+var (
+	fromPattern        = regexp.MustCompile(`(?i)^\s*FROM\s+(?:--platform=\S+\s+)?(\S+)`)
+	requirementPattern = regexp.MustCompile(`^([A-Za-z0-9_.\-\[\],]+)\s*(.*)$`)
+)
+
+// New creates a linter with the given configuration; nil uses the defaults
+func New(config *Config) *Linter {
+	if config == nil {
+		config = &Config{}
+	}
+	if len(config.SecretPatterns) == 0 {
+		config.SecretPatterns = []string{"KEY", "SECRET"}
+	}
+	return &Linter{config: config}
+}
+
+// DefaultConfigPath returns the path of the user's lint configuration
+func DefaultConfigPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".agent", "lint.yaml"), nil
+}
+
+// LoadConfig reads a lint configuration. A missing file yields the defaults.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lint config: %w", err)
+	}
+
+	var config Config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse lint config %s: %w", path, err)
+	}
+
+	known := make(map[string]bool)
+	for _, rule := range Rules() {
+		known[rule.Name] = true
+	}
+	for name, rule := range config.Rules {
+		if !known[name] {
+			return nil, fmt.Errorf("unknown lint rule '%s' in %s", name, path)
+		}
+		switch rule.Severity {
+		case "", SeverityError, SeverityWarning, SeverityInfo:
+		default:
+			return nil, fmt.Errorf("invalid severity '%s' for rule '%s' (valid: error, warning, info)", rule.Severity, name)
+		}
+	}
+
+	return &config, nil
+}
+
+// Lint runs every enabled rule over the project in dir and returns the
+// violations sorted by file and line
+func (l *Linter) Lint(dir string) ([]Violation, error) {
+	var violations []Violation
+
+	for _, rule := range Rules() {
+		override := l.config.Rules[rule.Name]
+		if override.Enabled != nil && !*override.Enabled {
+			continue
+		}
+
+		found, err := rule.check(l, dir)
+		if err != nil {
+			return nil, err
+		}
+
+		severity := rule.Severity
+		if override.Severity != "" {
+			severity = override.Severity
+		}
+		for _, v := range found {
+			v.Rule = rule.Name
+			v.Severity = severity
+			violations = append(violations, v)
+		}
+	}
+
+	sort.SliceStable(violations, func(i, j int) bool {
+		if violations[i].File != violations[j].File {
+			return violations[i].File < violations[j].File
+		}
+		return violations[i].Line < violations[j].Line
+	})
+
+	return violations, nil
+}
+
+// checkPinnedBaseImage flags FROM lines whose image has no @sha256 digest.
+// References to earlier build stages and scratch are skipped.
+func checkPinnedBaseImage(l *Linter, dir string) ([]Violation, error) {
+	path := filepath.Join(dir, "Dockerfile")
+	lines, err := readLines(path)
+	if err != nil || lines == nil {
+		return nil, err
+	}
+
+	stages := make(map[string]bool)
+	var violations []Violation
+	for i, line := range lines {
+		match := fromPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		image := match[1]
+
+		if image != "scratch" && !stages[strings.ToLower(image)] && !strings.Contains(image, "@sha256:") {
+			violations = append(violations, Violation{
+				File:    path,
+				Line:    i + 1,
+				Message: fmt.Sprintf("base image '%s' is not pinned to a digest (use %s@sha256:...)", image, image),
+			})
+		}
+
+		// Later stages may build FROM this one by name
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && strings.EqualFold(fields[len(fields)-2], "AS") {
+			stages[strings.ToLower(fields[len(fields)-1])] = true
+		}
+	}
+
+	return violations, nil
+}
+
+// checkPinnedRequirements flags requirements without a version, and lower
+// bounds without an upper bound
+func checkPinnedRequirements(l *Linter, dir string) ([]Violation, error) {
+	path := filepath.Join(dir, "requirements.txt")
+	lines, err := readLines(path)
+	if err != nil || lines == nil {
+		return nil, err
+	}
+
+	var violations []Violation
+	for i, line := range lines {
+		line, _, _ = strings.Cut(line, "#")
+		line = strings.TrimSpace(line)
+		// Options (-r, -e, --index-url) and URLs are not version specifiers
+		if line == "" || strings.HasPrefix(line, "-") || strings.Contains(line, "://") {
+			continue
+		}
+
+		match := requirementPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		name, spec := match[1], strings.TrimSpace(match[2])
+		spec, _, _ = strings.Cut(spec, ";") // Environment markers
+
+		var message string
+		switch {
+		case spec == "":
+			message = fmt.Sprintf("'%s' is not pinned to a version", name)
+		case strings.Contains(spec, "==") || strings.Contains(spec, "~="):
+			continue
+		case (strings.Contains(spec, ">") || strings.Contains(spec, "!=")) && !strings.Contains(spec, "<"):
+			message = fmt.Sprintf("'%s%s' has no upper bound", name, spec)
+		default:
+			continue
+		}
+
+		violations = append(violations, Violation{File: path, Line: i + 1, Message: message})
+	}
+
+	return violations, nil
+}
+
+// checkDescription flags an agent.yaml without metadata.description
+func checkDescription(l *Linter, dir string) ([]Violation, error) {
+	path, root, err := readAgentYAML(dir)
+	if err != nil || root == nil {
+		return nil, err
+	}
+
+	metadata, metadataKey := mappingEntry(root, "metadata")
+	if description, _ := mappingEntry(metadata, "description"); description != nil && strings.TrimSpace(description.Value) != "" {
+		return nil, nil
+	}
+
+	line := 1
+	if metadataKey != nil {
+		line = metadataKey.Line
+	}
+	return []Violation{{File: path, Line: line, Message: "metadata.description is missing"}}, nil
+}
+
+// checkSecretEnv flags env vars whose name looks like a secret but whose
+// value is not taken from a secret
+func checkSecretEnv(l *Linter, dir string) ([]Violation, error) {
+	path, root, err := readAgentYAML(dir)
+	if err != nil || root == nil {
+		return nil, err
+	}
+
+	spec, _ := mappingEntry(root, "spec")
+	environment, _ := mappingEntry(spec, "environment")
+	if environment == nil {
+		return nil, nil
+	}
+
+	var violations []Violation
+	for _, envVar := range environment.Content {
+		name, _ := mappingEntry(envVar, "name")
+		if name == nil || !l.isSecretName(name.Value) {
+			continue
+		}
+		if from, _ := mappingEntry(envVar, "from"); from != nil && from.Value == "secret" {
+			continue
+		}
+		violations = append(violations, Violation{
+			File:    path,
+			Line:    name.Line,
+			Message: fmt.Sprintf("env var '%s' looks like a secret but is not set with 'from: secret'", name.Value),
+		})
+	}
+
+	return violations, nil
+}
+
+// checkPrivilegedPort flags container ports below 1024, which need root to
+// bind
+func checkPrivilegedPort(l *Linter, dir string) ([]Violation, error) {
+	path, root, err := readAgentYAML(dir)
+	if err != nil || root == nil {
+		return nil, err
+	}
+
+	spec, _ := mappingEntry(root, "spec")
+	ports, _ := mappingEntry(spec, "ports")
+	if ports == nil {
+		return nil, nil
+	}
+
+	var violations []Violation
+	for _, port := range ports.Content {
+		container, _ := mappingEntry(port, "container")
+		if container == nil {
+			continue
+		}
+		var number int
+		if err := container.Decode(&number); err != nil || number <= 0 || number >= 1024 {
+			continue
+		}
+		violations = append(violations, Violation{
+			File:    path,
+			Line:    container.Line,
+			Message: fmt.Sprintf("container port %d is below 1024 and requires root", number),
+		})
+	}
+
+	return violations, nil
+}
+
+// isSecretName reports whether an env var name contains a secret pattern
+func (l *Linter) isSecretName(name string) bool {
+	upper := strings.ToUpper(name)
+	for _, pattern := range l.config.SecretPatterns {
+		if strings.Contains(upper, strings.ToUpper(pattern)) {
+			return true
+		}
+	}
+	return false
+}
+
+// readLines reads a file's lines, returning nil if it does not exist
+func readLines(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	lines := []string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines, scanner.Err()
+}
+
+// readAgentYAML parses the project's agent.yaml into a node tree, which
+// keeps line numbers. It returns a nil root if there is no agent.yaml.
+func readAgentYAML(dir string) (string, *yaml.Node, error) {
+	path := filepath.Join(dir, "agent.yaml")
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return path, nil, nil
+	}
+	if err != nil {
+		return path, nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return path, nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if len(doc.Content) == 0 {
+		return path, nil, nil
+	}
+
+	return path, doc.Content[0], nil
+}
+
+// mappingEntry returns the value and key nodes for key in a mapping node
+func mappingEntry(node *yaml.Node, key string) (value, keyNode *yaml.Node) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1], node.Content[i]
+		}
+	}
+	return nil, nil
+}
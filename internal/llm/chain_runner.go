@@ -0,0 +1,137 @@
+package llm
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// chainInputKey is the variable that holds the chain's input
+const chainInputKey = "Input"
+
+// ChainStep is a single model call in a chain. PromptTemplate is a Go
+// template over the chain's variables: Input and the output_key of every
+// earlier step.
+type ChainStep struct {
+	Model          string `yaml:"model"`
+	System         string `yaml:"system,omitempty"`
+	PromptTemplate string `yaml:"prompt_template"`
+	OutputKey      string `yaml:"output_key"`
+}
+
+// ChainRunner runs prompts through a sequence of local models, feeding each
+// step's output to the steps after it
+type ChainRunner struct {
+	modelManager *LocalLLMManager
+	progress     io.Writer
+}
+
+// NewChainRunner creates a chain runner that reports each step to progress,
+// which may be nil
+func NewChainRunner(progress io.Writer) *ChainRunner {
+	return &ChainRunner{
+		modelManager: NewLocalLLMManager(),
+		progress:     progress,
+	}
+}
+
+// LoadChainSteps loads and validates the chain steps in a YAML file
+func LoadChainSteps(path string) ([]ChainStep, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read steps file: %v", err)
+	}
+
+	var steps []ChainStep
+	if err := yaml.Unmarshal(data, &steps); err != nil {
+		return nil, fmt.Errorf("failed to parse steps file: %v", err)
+	}
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("no steps defined in %s", path)
+	}
+
+	if err := validateChainSteps(steps); err != nil {
+		return nil, err
+	}
+	return steps, nil
+}
+
+// validateChainSteps checks that every step is complete, that output keys
+// are unique, and that templates only use variables defined before them
+func validateChainSteps(steps []ChainStep) error {
+	defined := map[string]string{chainInputKey: ""}
+
+	for i, step := range steps {
+		switch {
+		case step.Model == "":
+			return fmt.Errorf("step %d is missing a model", i+1)
+		case step.PromptTemplate == "":
+			return fmt.Errorf("step %d is missing a prompt_template", i+1)
+		case step.OutputKey == "":
+			return fmt.Errorf("step %d is missing an output_key", i+1)
+		}
+		if _, exists := defined[step.OutputKey]; exists {
+			return fmt.Errorf("step %d: output_key '%s' is already defined", i+1, step.OutputKey)
+		}
+
+		if _, err := renderChainPrompt(step, defined); err != nil {
+			return fmt.Errorf("step %d: %v", i+1, err)
+		}
+		defined[step.OutputKey] = ""
+	}
+
+	return nil
+}
+
+// Execute runs the steps in order and returns every variable of the chain:
+// Input and the output of each step under its output_key
+func (r *ChainRunner) Execute(steps []ChainStep, input string) (map[string]string, error) {
+	if err := validateChainSteps(steps); err != nil {
+		return nil, err
+	}
+
+	vars := map[string]string{chainInputKey: input}
+
+	for i, step := range steps {
+		if r.progress != nil {
+			fmt.Fprintf(r.progress, "⛓️  Step %d/%d: %s → %s\n", i+1, len(steps), step.Model, step.OutputKey)
+		}
+
+		prompt, err := renderChainPrompt(step, vars)
+		if err != nil {
+			return nil, fmt.Errorf("step %d: %v", i+1, err)
+		}
+
+		resp, err := r.modelManager.Generate(GenerateRequest{
+			Model:  step.Model,
+			System: step.System,
+			Prompt: prompt,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("step %d (%s) failed: %v", i+1, step.Model, err)
+		}
+
+		vars[step.OutputKey] = strings.TrimSpace(resp.Response)
+	}
+
+	return vars, nil
+}
+
+// renderChainPrompt executes the step's prompt template with vars. Referring
+// to a variable that is not defined is an error.
+func renderChainPrompt(step ChainStep, vars map[string]string) (string, error) {
+	tmpl, err := template.New(step.OutputKey).Option("missingkey=error").Parse(step.PromptTemplate)
+	if err != nil {
+		return "", fmt.Errorf("invalid prompt_template: %v", err)
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, vars); err != nil {
+		return "", fmt.Errorf("invalid prompt_template: %v", err)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,122 @@
+package llm
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxExplainFileSize is the number of bytes of each file sent to the model,
+// so large sources don't overflow small context windows
+const maxExplainFileSize = 12000
+
+// explainFocuses maps each --focus value to the questions the model answers
+var explainFocuses = map[string]string{
+	"": `1. Purpose: what the agent does and who would use it.
+2. API endpoints: each route, its method, inputs and outputs.
+3. Limitations: missing features, risky assumptions and likely failure modes.`,
+	"endpoints": `1. Each HTTP endpoint: route, method, request body, response and status codes.
+2. Input validation and error handling per endpoint.
+3. Endpoints that are missing or inconsistent with agent.yaml.`,
+	"security": `1. How secrets and API keys are loaded and whether they can leak.
+2. Input validation, injection risks and unsafe use of model output.
+3. Authentication, authorization and exposure of the container ports.`,
+	"performance": `1. Blocking calls, missing timeouts and retries around model or network calls.
+2. Resource usage compared with the limits in agent.yaml.
+3. Concrete changes that would improve latency or throughput.`,
+}
+
+// ExplainFocuses returns the valid --focus values
+func ExplainFocuses() []string {
+	return []string{"endpoints", "security", "performance"}
+}
+
+// CodeExplainer summarizes an existing agent project by asking a local model
+// to read its agent.yaml, entry point and README
+type CodeExplainer struct {
+	modelManager *LocalLLMManager
+	focus        string
+}
+
+// NewCodeExplainer creates a code explainer. An empty focus gives a general
+// overview; see ExplainFocuses for the others.
+func NewCodeExplainer(focus string) *CodeExplainer {
+	return &CodeExplainer{
+		modelManager: NewLocalLLMManager(),
+		focus:        focus,
+	}
+}
+
+// Explain streams the model's explanation of the agent in agentDir to out
+func (e *CodeExplainer) Explain(agentDir, modelName string, out io.Writer) error {
+	questions, ok := explainFocuses[e.focus]
+	if !ok {
+		return fmt.Errorf("invalid focus '%s' (valid: %s)", e.focus, strings.Join(ExplainFocuses(), ", "))
+	}
+
+	prompt, err := buildExplainPrompt(agentDir, questions)
+	if err != nil {
+		return err
+	}
+
+	_, err = e.modelManager.StreamGenerate(GenerateRequest{
+		Model:   modelName,
+		System:  codeExplainerSystemPrompt,
+		Prompt:  prompt,
+		Options: map[string]interface{}{"temperature": 0.2},
+	}, func(chunk GenerateResponse) error {
+		_, err := io.WriteString(out, chunk.Response)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(out, "\n")
+	return err
+}
+
+const codeExplainerSystemPrompt = `You are a senior engineer explaining an unfamiliar codebase to a colleague who just inherited it. Be concrete, refer to names from the code, and answer in markdown with a heading per question.`
+
+// buildExplainPrompt builds the prompt from agent.yaml, main.py or main.go,
+// and README.md. Only agent.yaml is required.
+func buildExplainPrompt(agentDir, questions string) (string, error) {
+	agentYAML, err := os.ReadFile(filepath.Join(agentDir, "agent.yaml"))
+	if err != nil {
+		return "", fmt.Errorf("failed to read agent.yaml: %v", err)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Given this agent code, explain:\n%s\n\nagent.yaml:\n%s\n", questions, fence(truncateSource(string(agentYAML)), "yaml"))
+
+	for _, source := range []struct{ name, language string }{
+		{"main.py", "python"},
+		{"main.go", "go"},
+		{"README.md", "markdown"},
+	} {
+		data, err := os.ReadFile(filepath.Join(agentDir, source.name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %v", source.name, err)
+		}
+		fmt.Fprintf(&b, "\n%s:\n%s\n", source.name, fence(truncateSource(string(data)), source.language))
+	}
+
+	return b.String(), nil
+}
+
+// truncateSource cuts a file to maxExplainFileSize bytes at a line break
+func truncateSource(source string) string {
+	if len(source) <= maxExplainFileSize {
+		return source
+	}
+	cut := source[:maxExplainFileSize]
+	if i := strings.LastIndex(cut, "\n"); i > 0 {
+		cut = cut[:i]
+	}
+	return cut + "\n... (truncated)"
+}
@@ -0,0 +1,467 @@
+package llm
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// reviewContextLines is the number of lines read from the working tree
+// around each hunk, beyond the context the diff already carries
+const reviewContextLines = 20
+
+// ReviewRules are the severity levels and rules the reviewer applies
+type ReviewRules struct {
+	Severities []ReviewSeverity `yaml:"severities"`
+	Rules      []ReviewRule     `yaml:"rules"`
+}
+
+// ReviewSeverity is a severity level, listed from most to least severe
+type ReviewSeverity struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+}
+
+// ReviewRule is a single thing the reviewer checks for
+type ReviewRule struct {
+	ID          string `yaml:"id"`
+	Severity    string `yaml:"severity"`
+	Description string `yaml:"description"`
+}
+
+// ReviewComment is a single review finding, as returned by the model
+type ReviewComment struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Severity string `json:"severity"`
+	Comment  string `json:"comment"`
+	// Code is the changed line the comment refers to, if it is in the diff
+	Code string `json:"-"`
+}
+
+// FileDiff is the part of a unified diff that changes a single file
+type FileDiff struct {
+	Path  string
+	Hunks []DiffHunk
+	// Lines maps new-file line numbers to the added and context lines
+	Lines map[int]string
+}
+
+// DiffHunk is a single @@ hunk of a file diff
+type DiffHunk struct {
+	NewStart int
+	NewLines int
+	Text     string
+}
+
+var hunkHeaderPattern = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// DefaultReviewRules returns the rules used when no rules file is given
+func DefaultReviewRules() *ReviewRules {
+	return &ReviewRules{
+		Severities: []ReviewSeverity{
+			{Name: "error", Description: "bugs, security issues and data loss; must be fixed before merging"},
+			{Name: "warning", Description: "likely problems, missing error handling or tests"},
+			{Name: "info", Description: "readability, naming and style suggestions"},
+		},
+		Rules: []ReviewRule{
+			{ID: "correctness", Severity: "error", Description: "logic errors, off-by-one errors, nil or null dereferences and race conditions"},
+			{ID: "security", Severity: "error", Description: "injection, hardcoded secrets, missing input validation and unsafe use of model output"},
+			{ID: "error-handling", Severity: "warning", Description: "ignored errors, missing timeouts and unclear failure modes"},
+			{ID: "readability", Severity: "info", Description: "unclear names, dead code and overly complex logic"},
+		},
+	}
+}
+
+// LoadReviewRules loads and validates review rules from a YAML file
+func LoadReviewRules(path string) (*ReviewRules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules file: %v", err)
+	}
+
+	var rules ReviewRules
+	if err := yaml.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse rules file: %v", err)
+	}
+
+	if len(rules.Severities) == 0 {
+		return nil, fmt.Errorf("no severities defined in %s", path)
+	}
+	if len(rules.Rules) == 0 {
+		return nil, fmt.Errorf("no rules defined in %s", path)
+	}
+	for i, rule := range rules.Rules {
+		if rule.Description == "" {
+			return nil, fmt.Errorf("rule at index %d is missing a description", i)
+		}
+		if rules.severityRank(rule.Severity) < 0 {
+			return nil, fmt.Errorf("rule '%s' has unknown severity '%s'", rule.ID, rule.Severity)
+		}
+	}
+
+	return &rules, nil
+}
+
+// severityRank returns the position of a severity, 0 being the most severe,
+// or -1 if it is not defined
+func (r *ReviewRules) severityRank(name string) int {
+	for i, severity := range r.Severities {
+		if strings.EqualFold(severity.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// ParseUnifiedDiff splits a unified diff (as produced by git diff or
+// diff -u) into per-file diffs. Deleted files are skipped.
+func ParseUnifiedDiff(r io.Reader) ([]FileDiff, error) {
+	var files []FileDiff
+	var current *FileDiff
+	var hunk *DiffHunk
+	var oldLeft, newLeft, newLine int
+
+	flushFile := func() {
+		if current != nil && current.Path != "" && len(current.Hunks) > 0 {
+			files = append(files, *current)
+		}
+		current = nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		// Inside a hunk until its old and new line counts are used up
+		if hunk != nil {
+			hunk.Text += line + "\n"
+			switch {
+			case strings.HasPrefix(line, "-"):
+				oldLeft--
+			case strings.HasPrefix(line, "+"):
+				current.Lines[newLine] = line[1:]
+				newLine++
+				newLeft--
+			case strings.HasPrefix(line, "\\"):
+				// "\ No newline at end of file"
+			default:
+				if line != "" {
+					current.Lines[newLine] = line[1:]
+				}
+				newLine++
+				oldLeft--
+				newLeft--
+			}
+			if oldLeft <= 0 && newLeft <= 0 {
+				current.Hunks = append(current.Hunks, *hunk)
+				hunk = nil
+			}
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "diff "):
+			flushFile()
+		case strings.HasPrefix(line, "--- "):
+			flushFile()
+			current = &FileDiff{Lines: make(map[int]string)}
+		case strings.HasPrefix(line, "+++ ") && current != nil:
+			path := strings.TrimSpace(strings.TrimPrefix(line, "+++ "))
+			if i := strings.IndexByte(path, '\t'); i >= 0 {
+				path = path[:i]
+			}
+			if path != "/dev/null" {
+				current.Path = strings.TrimPrefix(path, "b/")
+			}
+		case strings.HasPrefix(line, "@@") && current != nil:
+			match := hunkHeaderPattern.FindStringSubmatch(line)
+			if match == nil {
+				return nil, fmt.Errorf("invalid hunk header: %s", line)
+			}
+			oldLeft = atoiDefault(match[2], 1)
+			newLine = atoiDefault(match[3], 0)
+			newLeft = atoiDefault(match[4], 1)
+			hunk = &DiffHunk{NewStart: newLine, NewLines: newLeft, Text: line + "\n"}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read diff: %v", err)
+	}
+	if hunk != nil {
+		current.Hunks = append(current.Hunks, *hunk)
+	}
+	flushFile()
+
+	return files, nil
+}
+
+// atoiDefault parses an optional hunk header count
+func atoiDefault(value string, fallback int) int {
+	if n, err := strconv.Atoi(value); err == nil {
+		return n
+	}
+	return fallback
+}
+
+// CodeReviewer reviews unified diffs file by file with a local model
+type CodeReviewer struct {
+	modelManager *LocalLLMManager
+	progress     io.Writer
+}
+
+// NewCodeReviewer creates a code reviewer that reports each reviewed file to
+// progress, which may be nil
+func NewCodeReviewer(progress io.Writer) *CodeReviewer {
+	return &CodeReviewer{
+		modelManager: NewLocalLLMManager(),
+		progress:     progress,
+	}
+}
+
+// Review asks the model to review every file in files and returns the
+// comments ordered by file and line. Context around each hunk is read from
+// the working tree when the file exists there.
+func (r *CodeReviewer) Review(modelName string, files []FileDiff, rules *ReviewRules) ([]ReviewComment, error) {
+	var comments []ReviewComment
+
+	for i, file := range files {
+		if r.progress != nil {
+			fmt.Fprintf(r.progress, "🔍 Reviewing %s (%d/%d)\n", file.Path, i+1, len(files))
+		}
+
+		resp, err := r.modelManager.Generate(GenerateRequest{
+			Model:   modelName,
+			System:  codeReviewerSystemPrompt,
+			Prompt:  buildReviewPrompt(file, rules),
+			Format:  "json",
+			Options: map[string]interface{}{"temperature": 0.1},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to review %s: %v", file.Path, err)
+		}
+
+		fileComments, err := parseReviewComments(resp.Response, file, rules)
+		if err != nil {
+			return nil, fmt.Errorf("failed to review %s: %v", file.Path, err)
+		}
+		comments = append(comments, fileComments...)
+	}
+
+	sort.SliceStable(comments, func(a, b int) bool {
+		if comments[a].File != comments[b].File {
+			return comments[a].File < comments[b].File
+		}
+		return comments[a].Line < comments[b].Line
+	})
+	return comments, nil
+}
+
+const codeReviewerSystemPrompt = `You are a meticulous senior engineer reviewing a pull request. Only comment on real problems in the changed lines, be specific, and suggest a fix. You only answer with a JSON object, without markdown or explanations.`
+
+// buildReviewPrompt builds the structured review prompt for a single file
+func buildReviewPrompt(file FileDiff, rules *ReviewRules) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Review the changes to %s.\n\nSeverity levels, most severe first:\n", file.Path)
+	for _, severity := range rules.Severities {
+		fmt.Fprintf(&b, "- %s: %s\n", severity.Name, severity.Description)
+	}
+	b.WriteString("\nRules:\n")
+	for _, rule := range rules.Rules {
+		if rule.ID != "" {
+			fmt.Fprintf(&b, "- [%s] %s (%s)\n", rule.Severity, rule.Description, rule.ID)
+		} else {
+			fmt.Fprintf(&b, "- [%s] %s\n", rule.Severity, rule.Description)
+		}
+	}
+
+	fmt.Fprintf(&b, `
+Respond with JSON in exactly this shape, using line numbers of the new version of the file and an empty list when there is nothing to report:
+{
+  "comments": [
+    {"file": %q, "line": 42, "severity": %q, "comment": "what is wrong and how to fix it"}
+  ]
+}
+
+Diff:
+%s
+`, file.Path, rules.Severities[0].Name, fence(diffText(file), "diff"))
+
+	if context := surroundingContext(file); context != "" {
+		fmt.Fprintf(&b, "\nThe new version of the file around the changes, with line numbers:\n%s\n", fence(context, ""))
+	}
+
+	return b.String()
+}
+
+// diffText joins the hunks of a file diff
+func diffText(file FileDiff) string {
+	var b strings.Builder
+	for _, hunk := range file.Hunks {
+		b.WriteString(hunk.Text)
+	}
+	return b.String()
+}
+
+// surroundingContext returns the numbered lines of the working tree file
+// around each hunk, or "" if the file cannot be read
+func surroundingContext(file FileDiff) string {
+	data, err := os.ReadFile(file.Path)
+	if err != nil {
+		return ""
+	}
+	lines := strings.Split(string(data), "\n")
+
+	var b strings.Builder
+	last := 0
+	for _, hunk := range file.Hunks {
+		start := hunk.NewStart - reviewContextLines
+		if start <= last {
+			start = last + 1
+		}
+		if start < 1 {
+			start = 1
+		}
+		end := hunk.NewStart + hunk.NewLines + reviewContextLines
+		if end > len(lines) {
+			end = len(lines)
+		}
+		if start > end {
+			continue
+		}
+		if last > 0 && start > last+1 {
+			b.WriteString("...\n")
+		}
+		for n := start; n <= end; n++ {
+			fmt.Fprintf(&b, "%5d  %s\n", n, lines[n-1])
+		}
+		last = end
+	}
+	return b.String()
+}
+
+// parseReviewComments extracts the comments from a model response. Comments
+// are attributed to the reviewed file, and unknown severities are mapped to
+// the least severe level.
+func parseReviewComments(response string, file FileDiff, rules *ReviewRules) ([]ReviewComment, error) {
+	var parsed struct {
+		Comments []ReviewComment `json:"comments"`
+	}
+	if err := json.Unmarshal([]byte(extractJSONObject(response)), &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse model response as JSON: %v", err)
+	}
+
+	var comments []ReviewComment
+	for _, comment := range parsed.Comments {
+		comment.Comment = strings.TrimSpace(comment.Comment)
+		if comment.Comment == "" {
+			continue
+		}
+		comment.File = file.Path
+
+		if rank := rules.severityRank(comment.Severity); rank >= 0 {
+			comment.Severity = rules.Severities[rank].Name
+		} else {
+			comment.Severity = rules.Severities[len(rules.Severities)-1].Name
+		}
+		comment.Code = file.Lines[comment.Line]
+
+		comments = append(comments, comment)
+	}
+	return comments, nil
+}
+
+// RenderReview renders comments as an annotated plain-text review grouped by
+// file
+func RenderReview(comments []ReviewComment, rules *ReviewRules) string {
+	if len(comments) == 0 {
+		return "✅ No issues found\n"
+	}
+
+	var b strings.Builder
+	file := ""
+	for _, comment := range comments {
+		if comment.File != file {
+			file = comment.File
+			fmt.Fprintf(&b, "\n📄 %s\n", file)
+		}
+		fmt.Fprintf(&b, "  %s %s:%d [%s]\n", severityIcon(comment.Severity, rules), comment.File, comment.Line, comment.Severity)
+		if comment.Code != "" {
+			fmt.Fprintf(&b, "     │ %s\n", strings.TrimSpace(comment.Code))
+		}
+		for _, line := range strings.Split(comment.Comment, "\n") {
+			fmt.Fprintf(&b, "     %s\n", line)
+		}
+	}
+
+	fmt.Fprintf(&b, "\n%s\n", reviewTotals(comments, rules))
+	return b.String()
+}
+
+// RenderGitHubComment renders comments as the markdown body of a GitHub pull
+// request comment
+func RenderGitHubComment(comments []ReviewComment, rules *ReviewRules, model string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## 🤖 Code review (%s)\n\n", model)
+
+	if len(comments) == 0 {
+		b.WriteString("✅ No issues found.\n")
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "%s\n", reviewTotals(comments, rules))
+
+	file := ""
+	for _, comment := range comments {
+		if comment.File != file {
+			file = comment.File
+			fmt.Fprintf(&b, "\n### `%s`\n\n", file)
+		}
+		fmt.Fprintf(&b, "- %s **%s** line %d: %s\n", severityIcon(comment.Severity, rules), comment.Severity, comment.Line,
+			strings.ReplaceAll(comment.Comment, "\n", " "))
+		if comment.Code != "" {
+			fmt.Fprintf(&b, "  ```\n  %s\n  ```\n", strings.TrimSpace(comment.Code))
+		}
+	}
+
+	return b.String()
+}
+
+// reviewTotals counts comments per severity, most severe first
+func reviewTotals(comments []ReviewComment, rules *ReviewRules) string {
+	counts := make(map[string]int)
+	for _, comment := range comments {
+		counts[comment.Severity]++
+	}
+
+	var parts []string
+	for _, severity := range rules.Severities {
+		if n := counts[severity.Name]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, severity.Name))
+		}
+	}
+	return fmt.Sprintf("📊 %d comments: %s", len(comments), strings.Join(parts, ", "))
+}
+
+// severityIcon returns the icon of a severity by its rank
+func severityIcon(severity string, rules *ReviewRules) string {
+	icons := []string{"🔴", "🟡", "🔵"}
+	rank := rules.severityRank(severity)
+	if rank < 0 {
+		return "⚪"
+	}
+	if rank >= len(icons) {
+		rank = len(icons) - 1
+	}
+	return icons[rank]
+}
@@ -0,0 +1,172 @@
+package llm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pxkundu/agent-as-code/internal/log"
+)
+
+// ErrNoValidator is returned when the tools that check code of a runtime
+// are not installed, so the code was not checked
+var ErrNoValidator = errors.New("no code validator available")
+
+// flake8Checks are the flake8 checks run on generated Python: syntax
+// errors (E9), misplaced statements such as return outside a function
+// (F7), and undefined or redefined names (F8)
+const flake8Checks = "E9,F7,F8"
+
+// CodeValidator checks generated agent code with the language's own tools:
+// py_compile and flake8 for Python, tsc for TypeScript
+type CodeValidator struct{}
+
+// NewCodeValidator creates a new code validator
+func NewCodeValidator() *CodeValidator {
+	return &CodeValidator{}
+}
+
+// Available returns an ErrNoValidator error when the tools that check code
+// of runtime are not installed
+func (v *CodeValidator) Available(runtime string) error {
+	tool := ""
+	switch runtime {
+	case "python":
+		tool = "python3"
+	case "typescript", "node", "nodejs":
+		tool = "tsc"
+	default:
+		return fmt.Errorf("%w for runtime '%s'", ErrNoValidator, runtime)
+	}
+	if _, err := exec.LookPath(tool); err != nil {
+		return fmt.Errorf("%w: %s not found", ErrNoValidator, tool)
+	}
+	return nil
+}
+
+// ValidateProject checks the source files of a generated project. runtime
+// is the agent's runtime, python or typescript. Problems in several files
+// are joined into one error.
+func (v *CodeValidator) ValidateProject(projectDir, runtime string) error {
+	if err := v.Available(runtime); err != nil {
+		return err
+	}
+
+	switch runtime {
+	case "python":
+		files, err := projectFiles(projectDir, ".py")
+		if err != nil {
+			return err
+		}
+		var errs []error
+		for _, file := range files {
+			if err := v.ValidatePython(file); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	default:
+		return v.ValidateTypeScript(projectDir)
+	}
+}
+
+// ValidatePython compiles a Python file with py_compile and, when flake8 is
+// installed, checks it for undefined names. The error holds the tools'
+// output.
+func (v *CodeValidator) ValidatePython(filePath string) error {
+	if _, err := exec.LookPath("python3"); err != nil {
+		return fmt.Errorf("%w: python3 not found", ErrNoValidator)
+	}
+
+	// Bytecode is written elsewhere so no __pycache__ is left in the project
+	cache, err := os.MkdirTemp("", "agent-pycache-")
+	if err != nil {
+		return fmt.Errorf("failed to create bytecode cache: %w", err)
+	}
+	defer os.RemoveAll(cache)
+
+	cmd := exec.Command("python3", "-m", "py_compile", filePath)
+	cmd.Env = append(os.Environ(), "PYTHONPYCACHEPREFIX="+cache)
+	if output, err := runValidator(cmd); err != nil {
+		return fmt.Errorf("%s does not compile: %s", filePath, output)
+	}
+
+	if _, err := exec.LookPath("flake8"); err != nil {
+		log.Debug("flake8 not found, skipping undefined name checks", "file", filePath)
+		return nil
+	}
+	if output, err := runValidator(exec.Command("flake8", "--select="+flake8Checks, filePath)); err != nil {
+		return fmt.Errorf("flake8 found problems in %s:\n%s", filePath, output)
+	}
+	return nil
+}
+
+// ValidateTypeScript type-checks a TypeScript project with tsc --noEmit,
+// using its tsconfig.json or, without one, its .ts files
+func (v *CodeValidator) ValidateTypeScript(projectDir string) error {
+	if _, err := exec.LookPath("tsc"); err != nil {
+		return fmt.Errorf("%w: tsc not found", ErrNoValidator)
+	}
+
+	args := []string{"--noEmit"}
+	if _, err := os.Stat(filepath.Join(projectDir, "tsconfig.json")); err == nil {
+		args = append(args, "-p", projectDir)
+	} else {
+		files, err := projectFiles(projectDir, ".ts")
+		if err != nil {
+			return err
+		}
+		if len(files) == 0 {
+			return nil
+		}
+		args = append(args, files...)
+	}
+
+	if output, err := runValidator(exec.Command("tsc", args...)); err != nil {
+		return fmt.Errorf("tsc found problems in %s:\n%s", projectDir, output)
+	}
+	return nil
+}
+
+// runValidator runs a check and returns its combined output, trimmed
+func runValidator(cmd *exec.Cmd) (string, error) {
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	err := cmd.Run()
+	text := strings.TrimSpace(output.String())
+	if err != nil && text == "" {
+		text = err.Error()
+	}
+	return text, err
+}
+
+// projectFiles returns the files under dir with the extension ext, skipping
+// dependency and hidden directories
+func projectFiles(dir, ext string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if path != dir && (strings.HasPrefix(name, ".") || name == "node_modules" || name == "venv" || name == "__pycache__") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) == ext {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list source files: %w", err)
+	}
+	return files, nil
+}
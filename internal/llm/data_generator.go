@@ -0,0 +1,537 @@
+package llm
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pxkundu/agent-as-code/internal/log"
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats of a training data schema
+const (
+	DataFormatText = "text"
+	DataFormatJSON = "json"
+)
+
+const (
+	// minHashPermutations is the size of a MinHash signature
+	minHashPermutations = 128
+	// shingleSize is the number of words in each shingle hashed by MinHash
+	shingleSize = 3
+)
+
+// DataSchema describes the training data to generate
+type DataSchema struct {
+	Domain               string   `yaml:"domain"`
+	OutputFormat         string   `yaml:"output_format"`
+	InstructionTemplates []string `yaml:"instruction_templates"`
+
+	// Variables fill {name} placeholders in the templates with a randomly
+	// chosen value
+	Variables map[string][]string `yaml:"variables,omitempty"`
+
+	// OutputSchema is the JSON Schema each output must match when
+	// OutputFormat is json
+	OutputSchema map[string]interface{} `yaml:"output_schema,omitempty"`
+}
+
+// DataGenOptions represents options for training data generation
+type DataGenOptions struct {
+	Count         int
+	OutputPath    string
+	MinSimilarity float64
+	BatchSize     int
+
+	// Each batch is generated at a temperature drawn from this range
+	MinTemperature float64
+	MaxTemperature float64
+}
+
+// DataGenerator generates synthetic instruction/response pairs from a schema
+type DataGenerator struct {
+	modelManager *LocalLLMManager
+	rand         *rand.Rand
+}
+
+// NewDataGenerator creates a new training data generator
+func NewDataGenerator() *DataGenerator {
+	return &DataGenerator{
+		modelManager: NewLocalLLMManager(),
+		rand:         rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+var placeholderPattern = regexp.MustCompile(`\{([A-Za-z0-9_-]+)\}`)
+
+// LoadDataSchema loads and validates a training data schema from a YAML file
+func LoadDataSchema(path string) (*DataSchema, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema file: %w", err)
+	}
+
+	var schema DataSchema
+	if err := yaml.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("failed to parse schema file: %w", err)
+	}
+
+	if len(schema.InstructionTemplates) == 0 {
+		return nil, fmt.Errorf("no instruction_templates defined in %s", path)
+	}
+
+	switch schema.OutputFormat {
+	case "":
+		schema.OutputFormat = DataFormatText
+	case DataFormatText, DataFormatJSON:
+	default:
+		return nil, fmt.Errorf("unsupported output_format '%s', use json or text", schema.OutputFormat)
+	}
+
+	if schema.OutputSchema != nil && schema.OutputFormat != DataFormatJSON {
+		return nil, fmt.Errorf("output_schema requires output_format json")
+	}
+
+	for i, template := range schema.InstructionTemplates {
+		for _, match := range placeholderPattern.FindAllStringSubmatch(template, -1) {
+			if len(schema.Variables[match[1]]) == 0 {
+				return nil, fmt.Errorf("instruction template %d uses {%s}, which has no values in variables", i, match[1])
+			}
+		}
+	}
+
+	return &schema, nil
+}
+
+// Generate creates opts.Count instruction/response pairs with model and
+// writes them to opts.OutputPath as Alpaca-format JSONL. Pairs whose
+// estimated Jaccard similarity to an accepted pair reaches
+// opts.MinSimilarity are dropped as duplicates.
+func (g *DataGenerator) Generate(model string, schema *DataSchema, opts DataGenOptions) error {
+	if opts.Count <= 0 {
+		return fmt.Errorf("count must be greater than 0")
+	}
+	if opts.MinSimilarity <= 0 || opts.MinSimilarity > 1 {
+		return fmt.Errorf("min-similarity must be between 0 and 1")
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 10
+	}
+	if opts.MinTemperature == 0 && opts.MaxTemperature == 0 {
+		opts.MinTemperature, opts.MaxTemperature = 0.5, 1.1
+	}
+	if opts.MaxTemperature < opts.MinTemperature {
+		return fmt.Errorf("maximum temperature must not be below the minimum")
+	}
+
+	if err := g.modelManager.CheckOllamaAvailability(); err != nil {
+		return err
+	}
+
+	file, err := os.Create(opts.OutputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	defer writer.Flush()
+
+	var signatures [][]uint64
+	generated, duplicates, invalid, failures := 0, 0, 0, 0
+	maxAttempts := opts.Count * 3
+	temperature := 0.0
+
+	for attempt := 0; generated < opts.Count && attempt < maxAttempts; attempt++ {
+		if attempt%opts.BatchSize == 0 {
+			temperature = opts.MinTemperature + g.rand.Float64()*(opts.MaxTemperature-opts.MinTemperature)
+			log.Debug("starting batch", "attempt", attempt, "temperature", temperature)
+		}
+
+		record, err := g.generateRecord(model, schema, temperature)
+		if err != nil {
+			failures++
+			log.Warn("generation failed", "error", err)
+			continue
+		}
+
+		if schema.OutputFormat == DataFormatJSON {
+			if err := validateJSONOutput(record.Output, schema.OutputSchema); err != nil {
+				invalid++
+				log.Warn("output failed validation", "error", err)
+				continue
+			}
+		}
+
+		signature := minHashSignature(record.Instruction + "\n" + record.Input + "\n" + record.Output)
+		if isMinHashDuplicate(signature, signatures, opts.MinSimilarity) {
+			duplicates++
+			continue
+		}
+		signatures = append(signatures, signature)
+
+		line, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("failed to marshal record: %w", err)
+		}
+		if _, err := writer.Write(append(line, '\n')); err != nil {
+			return fmt.Errorf("failed to write record: %w", err)
+		}
+
+		generated++
+		if generated%10 == 0 || generated == opts.Count {
+			fmt.Printf("📝 Generated %d/%d examples\n", generated, opts.Count)
+		}
+	}
+
+	fmt.Printf("✅ Wrote %d examples to %s (%d duplicates skipped, %d invalid, %d failures)\n",
+		generated, opts.OutputPath, duplicates, invalid, failures)
+
+	if generated < opts.Count {
+		return fmt.Errorf("only generated %d of %d requested examples", generated, opts.Count)
+	}
+
+	return nil
+}
+
+// generateRecord fills a randomly chosen template and asks the model for an
+// instruction and the ideal response
+func (g *DataGenerator) generateRecord(model string, schema *DataSchema, temperature float64) (*DistillRecord, error) {
+	template := schema.InstructionTemplates[g.rand.Intn(len(schema.InstructionTemplates))]
+	seed := placeholderPattern.ReplaceAllStringFunc(template, func(placeholder string) string {
+		values := schema.Variables[placeholder[1:len(placeholder)-1]]
+		return values[g.rand.Intn(len(values))]
+	})
+
+	var prompt strings.Builder
+	prompt.WriteString("You are generating training data for fine-tuning a model")
+	if schema.Domain != "" {
+		prompt.WriteString(fmt.Sprintf(" in the %s domain", schema.Domain))
+	}
+	prompt.WriteString(".\n")
+	prompt.WriteString(fmt.Sprintf("Instruction template: %s\n\n", seed))
+	prompt.WriteString("Write one realistic, specific instruction based on the template, and the ideal response to it. ")
+	prompt.WriteString("Vary the wording, details and tone from other examples.\n")
+
+	if schema.OutputFormat == DataFormatJSON {
+		prompt.WriteString("The response must be a JSON value")
+		if schema.OutputSchema != nil {
+			outputSchema, err := json.Marshal(schema.OutputSchema)
+			if err != nil {
+				return nil, fmt.Errorf("invalid output_schema: %v", err)
+			}
+			prompt.WriteString(fmt.Sprintf(" matching this JSON Schema: %s", outputSchema))
+		}
+		prompt.WriteString(".\n")
+		prompt.WriteString(`Respond with only a JSON object of the form {"instruction": "...", "input": "...", "output": <the JSON response>}. `)
+	} else {
+		prompt.WriteString(`Respond with only a JSON object of the form {"instruction": "...", "input": "...", "output": "..."}. `)
+	}
+	prompt.WriteString(`Use an empty string for "input" if the instruction needs no extra context.`)
+
+	resp, err := g.modelManager.Generate(GenerateRequest{
+		Model:  model,
+		Prompt: prompt.String(),
+		Options: map[string]interface{}{
+			"temperature": temperature,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var raw struct {
+		Instruction string          `json:"instruction"`
+		Input       string          `json:"input"`
+		Output      json.RawMessage `json:"output"`
+	}
+	if err := json.Unmarshal([]byte(extractJSONObject(resp.Response)), &raw); err != nil {
+		return nil, fmt.Errorf("model returned invalid JSON: %v", err)
+	}
+
+	// JSON outputs may come back as a nested value or as an encoded string
+	record := &DistillRecord{Instruction: raw.Instruction, Input: raw.Input}
+	var text string
+	if err := json.Unmarshal(raw.Output, &text); err == nil {
+		record.Output = text
+	} else {
+		record.Output = string(raw.Output)
+	}
+
+	if strings.TrimSpace(record.Instruction) == "" || strings.TrimSpace(record.Output) == "" {
+		return nil, fmt.Errorf("model returned an incomplete example")
+	}
+
+	return record, nil
+}
+
+// validateJSONOutput checks that output is JSON and, when schema is set,
+// that it matches it
+func validateJSONOutput(output string, schema map[string]interface{}) error {
+	var value interface{}
+	if err := json.Unmarshal([]byte(output), &value); err != nil {
+		return fmt.Errorf("output is not valid JSON: %v", err)
+	}
+	if schema == nil {
+		return nil
+	}
+	return validateJSONSchema(value, schema, "$")
+}
+
+// validateJSONSchema validates value against the commonly used subset of
+// JSON Schema: type, enum, const, required, properties,
+// additionalProperties, items, min/maxItems, min/maxLength, pattern and
+// minimum/maximum
+func validateJSONSchema(value interface{}, schema map[string]interface{}, path string) error {
+	if expected, ok := schema["type"]; ok {
+		if !matchesSchemaType(value, expected) {
+			return fmt.Errorf("%s: expected type %v, got %s", path, expected, jsonTypeName(value))
+		}
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, allowed := range enum {
+			if jsonEqual(value, allowed) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s: value is not one of %v", path, enum)
+		}
+	}
+
+	if constant, ok := schema["const"]; ok && !jsonEqual(value, constant) {
+		return fmt.Errorf("%s: value must be %v", path, constant)
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if required, ok := schema["required"].([]interface{}); ok {
+			for _, name := range required {
+				if key, ok := name.(string); ok {
+					if _, present := v[key]; !present {
+						return fmt.Errorf("%s: missing required property '%s'", path, key)
+					}
+				}
+			}
+		}
+
+		properties, _ := schema["properties"].(map[string]interface{})
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			propertyPath := path + "." + key
+			if propertySchema, ok := properties[key].(map[string]interface{}); ok {
+				if err := validateJSONSchema(v[key], propertySchema, propertyPath); err != nil {
+					return err
+				}
+				continue
+			}
+
+			switch additional := schema["additionalProperties"].(type) {
+			case bool:
+				if !additional {
+					return fmt.Errorf("%s: property is not allowed", propertyPath)
+				}
+			case map[string]interface{}:
+				if err := validateJSONSchema(v[key], additional, propertyPath); err != nil {
+					return err
+				}
+			}
+		}
+
+	case []interface{}:
+		if min, ok := schemaNumber(schema, "minItems"); ok && float64(len(v)) < min {
+			return fmt.Errorf("%s: expected at least %v items, got %d", path, min, len(v))
+		}
+		if max, ok := schemaNumber(schema, "maxItems"); ok && float64(len(v)) > max {
+			return fmt.Errorf("%s: expected at most %v items, got %d", path, max, len(v))
+		}
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				if err := validateJSONSchema(item, items, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+
+	case string:
+		length := float64(len([]rune(v)))
+		if min, ok := schemaNumber(schema, "minLength"); ok && length < min {
+			return fmt.Errorf("%s: expected at least %v characters", path, min)
+		}
+		if max, ok := schemaNumber(schema, "maxLength"); ok && length > max {
+			return fmt.Errorf("%s: expected at most %v characters", path, max)
+		}
+		if pattern, ok := schema["pattern"].(string); ok {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return fmt.Errorf("%s: invalid pattern in schema: %v", path, err)
+			}
+			if !re.MatchString(v) {
+				return fmt.Errorf("%s: value does not match pattern %s", path, pattern)
+			}
+		}
+
+	case float64:
+		if min, ok := schemaNumber(schema, "minimum"); ok && v < min {
+			return fmt.Errorf("%s: %v is below the minimum %v", path, v, min)
+		}
+		if max, ok := schemaNumber(schema, "maximum"); ok && v > max {
+			return fmt.Errorf("%s: %v is above the maximum %v", path, v, max)
+		}
+	}
+
+	return nil
+}
+
+// matchesSchemaType reports whether value has the JSON Schema type
+// expected, which is a type name or a list of them
+func matchesSchemaType(value interface{}, expected interface{}) bool {
+	var types []string
+	switch t := expected.(type) {
+	case string:
+		types = []string{t}
+	case []interface{}:
+		for _, name := range t {
+			if s, ok := name.(string); ok {
+				types = append(types, s)
+			}
+		}
+	default:
+		return true
+	}
+
+	actual := jsonTypeName(value)
+	for _, name := range types {
+		if name == actual {
+			return true
+		}
+		if name == "number" && actual == "integer" {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonTypeName returns the JSON Schema type of a decoded JSON value
+func jsonTypeName(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// schemaNumber returns a numeric schema keyword. YAML decodes integers as
+// int, JSON as float64.
+func schemaNumber(schema map[string]interface{}, key string) (float64, bool) {
+	switch n := schema[key].(type) {
+	case int:
+		return float64(n), true
+	case float64:
+		return n, true
+	default:
+		return 0, false
+	}
+}
+
+// jsonEqual compares two decoded values by their JSON encoding
+func jsonEqual(a, b interface{}) bool {
+	encodedA, errA := json.Marshal(a)
+	encodedB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(encodedA) == string(encodedB)
+}
+
+// minHashSignature computes the MinHash signature of the word shingles of
+// text, so that the fraction of matching positions between two signatures
+// estimates the Jaccard similarity of the texts
+func minHashSignature(text string) []uint64 {
+	words := strings.Fields(strings.ToLower(text))
+	for i, word := range words {
+		words[i] = strings.Trim(word, ".,;:!?\"'()[]{}")
+	}
+
+	var shingles []string
+	if len(words) < shingleSize {
+		shingles = []string{strings.Join(words, " ")}
+	} else {
+		for i := 0; i+shingleSize <= len(words); i++ {
+			shingles = append(shingles, strings.Join(words[i:i+shingleSize], " "))
+		}
+	}
+
+	signature := make([]uint64, minHashPermutations)
+	for i := range signature {
+		signature[i] = math.MaxUint64
+	}
+
+	for _, shingle := range shingles {
+		hasher := fnv.New64a()
+		hasher.Write([]byte(shingle))
+		hash := hasher.Sum64()
+
+		// Each permutation mixes the hash with its own seed
+		for i := range signature {
+			if permuted := splitMix64(hash ^ uint64(i)*0x9E3779B97F4A7C15); permuted < signature[i] {
+				signature[i] = permuted
+			}
+		}
+	}
+
+	return signature
+}
+
+// splitMix64 is the SplitMix64 finalizer, a bijective 64-bit mixing function
+func splitMix64(x uint64) uint64 {
+	x += 0x9E3779B97F4A7C15
+	x = (x ^ (x >> 30)) * 0xBF58476D1CE4E5B9
+	x = (x ^ (x >> 27)) * 0x94D049BB133111EB
+	return x ^ (x >> 31)
+}
+
+// isMinHashDuplicate reports whether signature's estimated similarity to
+// any accepted signature is at least threshold
+func isMinHashDuplicate(signature []uint64, accepted [][]uint64, threshold float64) bool {
+	for _, other := range accepted {
+		matches := 0
+		for i := range signature {
+			if signature[i] == other[i] {
+				matches++
+			}
+		}
+		if float64(matches)/float64(len(signature)) >= threshold {
+			return true
+		}
+	}
+	return false
+}
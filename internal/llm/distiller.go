@@ -0,0 +1,251 @@
+package llm
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pxkundu/agent-as-code/internal/log"
+	"gopkg.in/yaml.v3"
+)
+
+// Distiller generates synthetic training datasets from a teacher model
+type Distiller struct {
+	modelManager *LocalLLMManager
+}
+
+// DistillTask describes a task the teacher model should generate examples for
+type DistillTask struct {
+	Name        string   `yaml:"name"`
+	Description string   `yaml:"description"`
+	Examples    []string `yaml:"examples,omitempty"`
+}
+
+// DistillOptions represents options for dataset generation
+type DistillOptions struct {
+	Count               int
+	OutputPath          string
+	Temperature         float64
+	SimilarityThreshold float64
+	EmbeddingModel      string
+	RequestsPerMinute   int
+}
+
+// DistillRecord represents a single Alpaca-format training example
+type DistillRecord struct {
+	Instruction string `json:"instruction"`
+	Input       string `json:"input"`
+	Output      string `json:"output"`
+}
+
+// distillTasksFile represents the tasks YAML file
+type distillTasksFile struct {
+	Tasks []DistillTask `yaml:"tasks"`
+}
+
+// NewDistiller creates a new distiller
+func NewDistiller() *Distiller {
+	return &Distiller{
+		modelManager: NewLocalLLMManager(),
+	}
+}
+
+// LoadDistillTasks loads task descriptions from a YAML file
+func LoadDistillTasks(path string) ([]DistillTask, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tasks file: %w", err)
+	}
+
+	var file distillTasksFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse tasks file: %w", err)
+	}
+
+	if len(file.Tasks) == 0 {
+		return nil, fmt.Errorf("no tasks defined in %s", path)
+	}
+
+	for i, task := range file.Tasks {
+		if task.Description == "" {
+			return nil, fmt.Errorf("task at index %d is missing a description", i)
+		}
+	}
+
+	return file.Tasks, nil
+}
+
+// Generate creates opts.Count instruction/response pairs with the teacher
+// model and writes them to opts.OutputPath as Alpaca-format JSONL
+func (d *Distiller) Generate(teacherModel string, tasks []DistillTask, opts DistillOptions) error {
+	if len(tasks) == 0 {
+		return fmt.Errorf("at least one task is required")
+	}
+	if opts.Count <= 0 {
+		return fmt.Errorf("count must be greater than 0")
+	}
+	if opts.Temperature == 0 {
+		opts.Temperature = 0.7
+	}
+	if opts.SimilarityThreshold == 0 {
+		opts.SimilarityThreshold = 0.95
+	}
+	if opts.EmbeddingModel == "" {
+		opts.EmbeddingModel = teacherModel
+	}
+	if opts.RequestsPerMinute <= 0 {
+		opts.RequestsPerMinute = 60
+	}
+
+	if err := d.modelManager.CheckOllamaAvailability(); err != nil {
+		return err
+	}
+
+	file, err := os.Create(opts.OutputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	defer writer.Flush()
+
+	// Each generated example costs two requests (generation + embedding)
+	limiter := time.NewTicker(time.Minute / time.Duration(opts.RequestsPerMinute))
+	defer limiter.Stop()
+
+	var embeddings [][]float64
+	generated, duplicates, failures := 0, 0, 0
+	maxAttempts := opts.Count * 3
+
+	for attempt := 0; generated < opts.Count && attempt < maxAttempts; attempt++ {
+		task := tasks[attempt%len(tasks)]
+
+		<-limiter.C
+		record, err := d.generateRecord(teacherModel, task, opts.Temperature)
+		if err != nil {
+			failures++
+			log.Warn("generation failed", "task", task.Name, "error", err)
+			continue
+		}
+
+		<-limiter.C
+		embedding, err := d.modelManager.Embed(opts.EmbeddingModel, record.Instruction+"\n"+record.Input)
+		if err != nil {
+			failures++
+			log.Warn("embedding failed", "model", opts.EmbeddingModel, "error", err)
+			continue
+		}
+
+		if isNearDuplicate(embedding, embeddings, opts.SimilarityThreshold) {
+			duplicates++
+			continue
+		}
+		embeddings = append(embeddings, embedding)
+
+		line, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("failed to marshal record: %w", err)
+		}
+		if _, err := writer.Write(append(line, '\n')); err != nil {
+			return fmt.Errorf("failed to write record: %w", err)
+		}
+
+		generated++
+		if generated%10 == 0 || generated == opts.Count {
+			fmt.Printf("📝 Generated %d/%d examples\n", generated, opts.Count)
+		}
+	}
+
+	fmt.Printf("✅ Wrote %d examples to %s (%d duplicates skipped, %d failures)\n",
+		generated, opts.OutputPath, duplicates, failures)
+
+	if generated < opts.Count {
+		return fmt.Errorf("only generated %d of %d requested examples", generated, opts.Count)
+	}
+
+	return nil
+}
+
+// generateRecord asks the teacher model for a single training example
+func (d *Distiller) generateRecord(teacherModel string, task DistillTask, temperature float64) (*DistillRecord, error) {
+	var prompt strings.Builder
+	prompt.WriteString("You are generating training data for a smaller model.\n")
+	prompt.WriteString(fmt.Sprintf("Task: %s\n", task.Description))
+	if len(task.Examples) > 0 {
+		prompt.WriteString("Example instructions for this task:\n")
+		for _, example := range task.Examples {
+			prompt.WriteString(fmt.Sprintf("- %s\n", example))
+		}
+	}
+	prompt.WriteString("\nWrite one new, realistic and varied example for this task. ")
+	prompt.WriteString(`Respond with only a JSON object of the form {"instruction": "...", "input": "...", "output": "..."}. `)
+	prompt.WriteString(`Use an empty string for "input" if the instruction needs no extra context.`)
+
+	resp, err := d.modelManager.Generate(GenerateRequest{
+		Model:  teacherModel,
+		Prompt: prompt.String(),
+		Options: map[string]interface{}{
+			"temperature": temperature,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var record DistillRecord
+	if err := json.Unmarshal([]byte(extractJSONObject(resp.Response)), &record); err != nil {
+		return nil, fmt.Errorf("teacher returned invalid JSON: %v", err)
+	}
+
+	if strings.TrimSpace(record.Instruction) == "" || strings.TrimSpace(record.Output) == "" {
+		return nil, fmt.Errorf("teacher returned an incomplete example")
+	}
+
+	return &record, nil
+}
+
+// extractJSONObject returns the outermost JSON object embedded in text
+func extractJSONObject(text string) string {
+	start := strings.Index(text, "{")
+	end := strings.LastIndex(text, "}")
+	if start == -1 || end <= start {
+		return text
+	}
+	return text[start : end+1]
+}
+
+// isNearDuplicate reports whether embedding is more similar than threshold
+// to any previously accepted embedding
+func isNearDuplicate(embedding []float64, accepted [][]float64, threshold float64) bool {
+	for _, other := range accepted {
+		if cosineSimilarity(embedding, other) > threshold {
+			return true
+		}
+	}
+	return false
+}
+
+// cosineSimilarity computes the cosine similarity between two vectors
+func cosineSimilarity(a, b []float64) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for i := range a {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
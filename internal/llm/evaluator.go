@@ -0,0 +1,141 @@
+package llm
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// EvalExample is one line of an evaluation dataset
+type EvalExample struct {
+	Prompt    string `json:"prompt"`
+	Reference string `json:"reference"`
+}
+
+// EvalResult is the score of the model's output for one example
+type EvalResult struct {
+	Index   int
+	Example EvalExample
+	Output  string
+	Score   float64
+	Latency time.Duration
+}
+
+// EvalSummary summarizes the scores of an evaluation run
+type EvalSummary struct {
+	Metric string
+	Count  int
+	MetricStats
+	Median float64
+}
+
+// LoadEvalDataset reads a JSON Lines dataset of {"prompt", "reference"}
+// objects. Blank lines are skipped.
+func LoadEvalDataset(path string) ([]EvalExample, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open dataset: %w", err)
+	}
+	defer file.Close()
+
+	var examples []EvalExample
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var example EvalExample
+		if err := json.Unmarshal([]byte(line), &example); err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid JSON: %v", path, lineNum, err)
+		}
+		if example.Prompt == "" {
+			return nil, fmt.Errorf("%s:%d: missing prompt", path, lineNum)
+		}
+		examples = append(examples, example)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read dataset: %w", err)
+	}
+	if len(examples) == 0 {
+		return nil, fmt.Errorf("dataset %s has no examples", path)
+	}
+
+	return examples, nil
+}
+
+// Evaluator scores a local model's outputs against reference answers
+type Evaluator struct {
+	modelManager *LocalLLMManager
+}
+
+// NewEvaluator creates a new evaluator
+func NewEvaluator() *Evaluator {
+	return &Evaluator{
+		modelManager: NewLocalLLMManager(),
+	}
+}
+
+// Evaluate runs every example's prompt through model and scores the output
+// against the reference with metric, calling progress after each example if
+// it is not nil. Outputs are generated at temperature 0, so runs are
+// repeatable.
+func (e *Evaluator) Evaluate(model string, examples []EvalExample, metric string, progress func(EvalResult)) ([]EvalResult, error) {
+	score, ok := evalMetrics[metric]
+	if !ok {
+		return nil, fmt.Errorf("unknown metric '%s' (valid: %s)", metric, strings.Join(EvalMetrics(), ", "))
+	}
+	if err := e.modelManager.CheckOllamaAvailability(); err != nil {
+		return nil, err
+	}
+
+	results := make([]EvalResult, 0, len(examples))
+	for i, example := range examples {
+		start := time.Now()
+		resp, err := e.modelManager.Generate(GenerateRequest{
+			Model:   model,
+			Prompt:  example.Prompt,
+			Options: map[string]interface{}{"temperature": 0},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("example %d failed: %v", i+1, err)
+		}
+
+		output := strings.TrimSpace(resp.Response)
+		result := EvalResult{
+			Index:   i + 1,
+			Example: example,
+			Output:  output,
+			Score:   score(output, example.Reference),
+			Latency: time.Since(start),
+		}
+		results = append(results, result)
+		if progress != nil {
+			progress(result)
+		}
+	}
+
+	return results, nil
+}
+
+// SummarizeEval returns the statistics of the scores of results
+func SummarizeEval(metric string, results []EvalResult) EvalSummary {
+	scores := make([]float64, len(results))
+	for i, result := range results {
+		scores[i] = result.Score
+	}
+	sort.Float64s(scores)
+
+	return EvalSummary{
+		Metric:      metric,
+		Count:       len(results),
+		MetricStats: summarize(scores),
+		Median:      percentile(scores, 50),
+	}
+}
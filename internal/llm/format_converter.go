@@ -0,0 +1,327 @@
+package llm
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Model formats supported by the converter
+const (
+	FormatSafeTensors = "safetensors"
+	FormatGGUF        = "gguf"
+)
+
+// ggufOutTypes are the quantization types llama.cpp's converter writes
+// directly, without a separate quantize step
+var ggufOutTypes = map[string]string{
+	"F32":  "f32",
+	"F16":  "f16",
+	"BF16": "bf16",
+	"Q8_0": "q8_0",
+}
+
+// FormatConverter converts models between the SafeTensors format used by
+// HuggingFace and the GGUF format used by Ollama. SafeTensors to GGUF runs
+// llama.cpp's converter, optionally followed by its quantize binary; GGUF to
+// SafeTensors loads the model with the transformers library.
+type FormatConverter struct {
+	llamaCppPath string
+	fromFormat   string
+	toFormat     string
+}
+
+// ConversionPlan describes a validated conversion and its expected result
+type ConversionPlan struct {
+	FromFormat     string
+	ToFormat       string
+	Input          string
+	Output         string
+	QuantType      string // Empty for an unquantized F16 model
+	InputSize      int64
+	ParameterCount int64
+	EstimatedSize  int64
+	// Steps are the shell commands the conversion script runs
+	Steps []string
+}
+
+// NewFormatConverter creates a converter from one model format to another,
+// using the llama.cpp installation at llamaCppPath
+func NewFormatConverter(llamaCppPath, fromFormat, toFormat string) *FormatConverter {
+	return &FormatConverter{
+		llamaCppPath: llamaCppPath,
+		fromFormat:   strings.ToLower(fromFormat),
+		toFormat:     strings.ToLower(toFormat),
+	}
+}
+
+// Plan validates the input and the conversion options, locates the tools
+// needed and estimates the output size without converting anything
+func (c *FormatConverter) Plan(input, output, quantType string) (ConversionPlan, error) {
+	plan := ConversionPlan{
+		FromFormat: c.fromFormat,
+		ToFormat:   c.toFormat,
+		Input:      input,
+		Output:     output,
+		QuantType:  strings.ToUpper(quantType),
+	}
+
+	if output == "" {
+		return plan, fmt.Errorf("output path is required")
+	}
+	if dir := filepath.Dir(output); dir != "." {
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			return plan, fmt.Errorf("output directory %s does not exist", dir)
+		}
+	}
+
+	switch {
+	case c.fromFormat == FormatSafeTensors && c.toFormat == FormatGGUF:
+		return c.planToGGUF(plan)
+	case c.fromFormat == FormatGGUF && c.toFormat == FormatSafeTensors:
+		return c.planToSafeTensors(plan)
+	case c.fromFormat == c.toFormat:
+		return plan, fmt.Errorf("input and output formats are both %s; use 'agent llm quantize' to requantize GGUF models", c.fromFormat)
+	default:
+		return plan, fmt.Errorf("unsupported conversion %s -> %s (supported: safetensors -> gguf, gguf -> safetensors)", c.fromFormat, c.toFormat)
+	}
+}
+
+// planToGGUF plans a SafeTensors directory to GGUF conversion
+func (c *FormatConverter) planToGGUF(plan ConversionPlan) (ConversionPlan, error) {
+	if plan.QuantType == "" {
+		plan.QuantType = "F16"
+	}
+	bpw, ok := quantBitsPerWeight[plan.QuantType]
+	if !ok {
+		return plan, fmt.Errorf("unsupported quantization type '%s' (supported: %s)",
+			plan.QuantType, strings.Join(SupportedQuantTypes(), ", "))
+	}
+
+	size, params, err := readSafeTensorsDir(plan.Input)
+	if err != nil {
+		return plan, err
+	}
+	plan.InputSize = size
+	plan.ParameterCount = params
+	plan.EstimatedSize = int64(float64(params) * bpw / 8)
+
+	convertScript, err := c.findConvertScript()
+	if err != nil {
+		return plan, err
+	}
+
+	// The converter writes F32, F16, BF16 and Q8_0 itself; other types are
+	// converted to F16 first and then quantized
+	if outType, ok := ggufOutTypes[plan.QuantType]; ok {
+		plan.Steps = []string{
+			fmt.Sprintf("python3 %s %s --outtype %s --outfile %s", shellQuote(convertScript), shellQuote(plan.Input), outType, shellQuote(plan.Output)),
+		}
+		return plan, nil
+	}
+
+	quantizeBin, err := NewQuantizer(c.llamaCppPath).findQuantizeBinary()
+	if err != nil {
+		return plan, err
+	}
+	intermediate := strings.TrimSuffix(plan.Output, filepath.Ext(plan.Output)) + ".f16.gguf"
+	plan.Steps = []string{
+		fmt.Sprintf("python3 %s %s --outtype f16 --outfile %s", shellQuote(convertScript), shellQuote(plan.Input), shellQuote(intermediate)),
+		fmt.Sprintf("%s %s %s %s", shellQuote(quantizeBin), shellQuote(intermediate), shellQuote(plan.Output), plan.QuantType),
+		fmt.Sprintf("rm -f %s", shellQuote(intermediate)),
+	}
+	return plan, nil
+}
+
+// planToSafeTensors plans a GGUF to SafeTensors directory conversion. The
+// model is dequantized to F16.
+func (c *FormatConverter) planToSafeTensors(plan ConversionPlan) (ConversionPlan, error) {
+	if plan.QuantType != "" && plan.QuantType != "F16" {
+		return plan, fmt.Errorf("--quantize is only supported when converting to gguf")
+	}
+	plan.QuantType = ""
+
+	info, err := os.Stat(plan.Input)
+	if err != nil {
+		return plan, fmt.Errorf("input model not found: %s", plan.Input)
+	}
+	header, err := readGGUFHeader(plan.Input)
+	if err != nil {
+		return plan, err
+	}
+	plan.InputSize = info.Size()
+	plan.ParameterCount = header.parameterCount
+	plan.EstimatedSize = header.parameterCount * 2
+
+	plan.Steps = []string{
+		fmt.Sprintf("python3 - %s %s <<'PYTHON'\n%sPYTHON", shellQuote(plan.Input), shellQuote(plan.Output), ggufToSafeTensorsPython),
+	}
+	return plan, nil
+}
+
+// ggufToSafeTensorsPython loads a GGUF model with transformers and saves it
+// as SafeTensors
+const ggufToSafeTensorsPython = `import os, sys
+import torch
+from transformers import AutoModelForCausalLM, AutoTokenizer
+
+directory, filename = os.path.split(os.path.abspath(sys.argv[1]))
+tokenizer = AutoTokenizer.from_pretrained(directory, gguf_file=filename)
+model = AutoModelForCausalLM.from_pretrained(directory, gguf_file=filename, torch_dtype=torch.float16)
+model.save_pretrained(sys.argv[2], safe_serialization=True)
+tokenizer.save_pretrained(sys.argv[2])
+`
+
+// Convert writes the conversion script for plan and runs it
+func (c *FormatConverter) Convert(plan ConversionPlan) error {
+	scriptPath, err := c.WriteScript(plan)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command("sh", scriptPath)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("conversion failed: %v", err)
+	}
+
+	return nil
+}
+
+// WriteScript writes the conversion shell script next to the output and
+// returns its path
+func (c *FormatConverter) WriteScript(plan ConversionPlan) (string, error) {
+	var script strings.Builder
+	script.WriteString("#!/bin/sh\n# Generated by agent llm convert\nset -e\n\n")
+	fmt.Fprintf(&script, "echo %s\n", shellQuote(fmt.Sprintf("Converting %s (%s) to %s (%s)...", plan.Input, plan.FromFormat, plan.Output, plan.ToFormat)))
+	for _, step := range plan.Steps {
+		script.WriteString(step + "\n")
+	}
+	fmt.Fprintf(&script, "echo %s\n", shellQuote("Wrote "+plan.Output))
+
+	scriptPath := strings.TrimSuffix(strings.TrimSuffix(plan.Output, "/"), filepath.Ext(plan.Output)) + ".convert.sh"
+	if err := os.WriteFile(scriptPath, []byte(script.String()), 0755); err != nil {
+		return "", fmt.Errorf("failed to write conversion script: %v", err)
+	}
+
+	return scriptPath, nil
+}
+
+// findConvertScript locates llama.cpp's HuggingFace to GGUF converter,
+// convert_hf_to_gguf.py in current checkouts and convert.py in older ones
+func (c *FormatConverter) findConvertScript() (string, error) {
+	if c.llamaCppPath == "" {
+		return "", fmt.Errorf("llama.cpp not found. Set LLAMACPP_PATH or the llm.llamacpp-path config key")
+	}
+
+	if info, err := os.Stat(c.llamaCppPath); err == nil && !info.IsDir() {
+		if strings.HasSuffix(c.llamaCppPath, ".py") {
+			return c.llamaCppPath, nil
+		}
+		// LLAMACPP_PATH may point at the quantize binary inside the checkout
+		return (&FormatConverter{llamaCppPath: filepath.Dir(c.llamaCppPath)}).findConvertScript()
+	}
+
+	for _, name := range []string{"convert_hf_to_gguf.py", "convert-hf-to-gguf.py", "convert.py"} {
+		candidate := filepath.Join(c.llamaCppPath, name)
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		}
+	}
+
+	return "", fmt.Errorf("convert.py not found in %s", c.llamaCppPath)
+}
+
+// readSafeTensorsDir returns the total size and parameter count of the
+// .safetensors files in a HuggingFace model directory
+func readSafeTensorsDir(dir string) (size, params int64, err error) {
+	info, err := os.Stat(dir)
+	if err != nil || !info.IsDir() {
+		return 0, 0, fmt.Errorf("input must be a HuggingFace model directory: %s", dir)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "config.json")); err != nil {
+		return 0, 0, fmt.Errorf("config.json not found in %s", dir)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.safetensors"))
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(files) == 0 {
+		return 0, 0, fmt.Errorf("no .safetensors files found in %s", dir)
+	}
+
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return 0, 0, err
+		}
+		count, err := readSafeTensorsParams(file)
+		if err != nil {
+			return 0, 0, err
+		}
+		size += info.Size()
+		params += count
+	}
+
+	return size, params, nil
+}
+
+// readSafeTensorsParams counts the parameters in a .safetensors file from
+// its JSON header
+func readSafeTensorsParams(path string) (int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open %s: %v", path, err)
+	}
+	defer file.Close()
+
+	var headerSize uint64
+	if err := binary.Read(file, binary.LittleEndian, &headerSize); err != nil {
+		return 0, fmt.Errorf("%s is not a SafeTensors file", path)
+	}
+	if headerSize > 100<<20 {
+		return 0, fmt.Errorf("%s is not a SafeTensors file", path)
+	}
+
+	header := make([]byte, headerSize)
+	if _, err := io.ReadFull(file, header); err != nil {
+		return 0, fmt.Errorf("%s is not a SafeTensors file", path)
+	}
+
+	var tensors map[string]json.RawMessage
+	if err := json.Unmarshal(header, &tensors); err != nil {
+		return 0, fmt.Errorf("invalid SafeTensors header in %s: %v", path, err)
+	}
+
+	var params int64
+	for name, raw := range tensors {
+		if name == "__metadata__" {
+			continue
+		}
+		var tensor struct {
+			Shape []int64 `json:"shape"`
+		}
+		if err := json.Unmarshal(raw, &tensor); err != nil {
+			return 0, fmt.Errorf("invalid tensor %s in %s: %v", name, path, err)
+		}
+		elements := int64(1)
+		for _, dim := range tensor.Shape {
+			elements *= dim
+		}
+		params += elements
+	}
+
+	return params, nil
+}
+
+// shellQuote quotes s for a POSIX shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,207 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// GPUDetector detects GPUs available for model inference
+type GPUDetector struct {
+	modelManager *LocalLLMManager
+}
+
+// GPUInfo represents a detected GPU
+type GPUInfo struct {
+	Name           string
+	VRAM           string
+	Driver         string
+	CUDAVersion    string
+	Utilization    string // Current GPU utilization, when reported by the driver
+	IsUsedByOllama bool
+}
+
+var (
+	cudaVersionPattern   = regexp.MustCompile(`CUDA Version:\s*([0-9.]+)`)
+	nvidiaDriverPattern  = regexp.MustCompile(`Kernel Module\s+([0-9.]+)`)
+	systemProfilerFields = regexp.MustCompile(`^\s*(Chipset Model|VRAM \(Total\)|VRAM \(Dynamic, Max\)|Metal Support|Metal Family):\s*(.+)$`)
+)
+
+// NewGPUDetector creates a new GPU detector
+func NewGPUDetector() *GPUDetector {
+	return &GPUDetector{
+		modelManager: NewLocalLLMManager(),
+	}
+}
+
+// DetectGPUs returns the GPUs on this machine. Ollama does not report which
+// GPU a model runs on, so every GPU is marked as used when any running model
+// is at least partly loaded into VRAM.
+func (d *GPUDetector) DetectGPUs() ([]GPUInfo, error) {
+	var gpus []GPUInfo
+	var err error
+
+	switch runtime.GOOS {
+	case "linux":
+		gpus, err = d.detectNvidiaGPUs()
+	case "darwin":
+		gpus, err = d.detectMacGPUs()
+	default:
+		return nil, fmt.Errorf("GPU detection is not supported on %s", runtime.GOOS)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if d.IsOllamaUsingGPU() {
+		for i := range gpus {
+			gpus[i].IsUsedByOllama = true
+		}
+	}
+
+	return gpus, nil
+}
+
+// IsOllamaUsingGPU reports whether any running model is loaded into VRAM
+func (d *GPUDetector) IsOllamaUsingGPU() bool {
+	models, err := d.modelManager.ListRunningModels()
+	if err != nil {
+		return false
+	}
+
+	for _, model := range models {
+		if model.SizeVRAM > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// RunningModels returns the models currently loaded by Ollama
+func (d *GPUDetector) RunningModels() ([]RunningModel, error) {
+	return d.modelManager.ListRunningModels()
+}
+
+// detectNvidiaGPUs detects NVIDIA GPUs using nvidia-smi, falling back to
+// /proc/driver/nvidia when nvidia-smi is unavailable
+func (d *GPUDetector) detectNvidiaGPUs() ([]GPUInfo, error) {
+	output, err := exec.Command("nvidia-smi", "--query-gpu=name,memory.total,driver_version,utilization.gpu", "--format=csv,noheader").Output()
+	if err != nil {
+		return d.detectProcNvidiaGPUs()
+	}
+
+	cudaVersion := ""
+	if summary, err := exec.Command("nvidia-smi").Output(); err == nil {
+		if match := cudaVersionPattern.FindSubmatch(summary); match != nil {
+			cudaVersion = string(match[1])
+		}
+	}
+
+	var gpus []GPUInfo
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), ",")
+		if len(fields) < 2 {
+			continue
+		}
+
+		gpu := GPUInfo{
+			Name:        strings.TrimSpace(fields[0]),
+			VRAM:        strings.TrimSpace(fields[1]),
+			CUDAVersion: cudaVersion,
+		}
+		if len(fields) > 2 {
+			gpu.Driver = "NVIDIA " + strings.TrimSpace(fields[2])
+		}
+		if len(fields) > 3 {
+			gpu.Utilization = strings.TrimSpace(fields[3])
+		}
+		gpus = append(gpus, gpu)
+	}
+
+	return gpus, nil
+}
+
+// detectProcNvidiaGPUs reads GPU models from /proc/driver/nvidia/gpus
+func (d *GPUDetector) detectProcNvidiaGPUs() ([]GPUInfo, error) {
+	infoFiles, err := filepath.Glob("/proc/driver/nvidia/gpus/*/information")
+	if err != nil || len(infoFiles) == 0 {
+		return nil, nil
+	}
+
+	driver := ""
+	if version, err := os.ReadFile("/proc/driver/nvidia/version"); err == nil {
+		if match := nvidiaDriverPattern.FindSubmatch(version); match != nil {
+			driver = "NVIDIA " + string(match[1])
+		}
+	}
+
+	var gpus []GPUInfo
+	for _, infoFile := range infoFiles {
+		data, err := os.ReadFile(infoFile)
+		if err != nil {
+			continue
+		}
+
+		gpu := GPUInfo{Driver: driver, VRAM: "unknown"}
+		for _, line := range strings.Split(string(data), "\n") {
+			if strings.HasPrefix(line, "Model:") {
+				gpu.Name = strings.TrimSpace(strings.TrimPrefix(line, "Model:"))
+			}
+		}
+		if gpu.Name != "" {
+			gpus = append(gpus, gpu)
+		}
+	}
+
+	return gpus, nil
+}
+
+// detectMacGPUs detects GPUs using system_profiler
+func (d *GPUDetector) detectMacGPUs() ([]GPUInfo, error) {
+	output, err := exec.Command("system_profiler", "SPDisplaysDataType").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run system_profiler: %v", err)
+	}
+
+	var gpus []GPUInfo
+	var current *GPUInfo
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		match := systemProfilerFields.FindStringSubmatch(scanner.Text())
+		if match == nil {
+			continue
+		}
+
+		key, value := match[1], strings.TrimSpace(match[2])
+		switch key {
+		case "Chipset Model":
+			gpus = append(gpus, GPUInfo{Name: value})
+			current = &gpus[len(gpus)-1]
+		case "VRAM (Total)", "VRAM (Dynamic, Max)":
+			if current != nil {
+				current.VRAM = value
+			}
+		case "Metal Support", "Metal Family":
+			if current != nil {
+				current.Driver = value
+			}
+		}
+	}
+
+	// Apple Silicon GPUs share system memory
+	for i := range gpus {
+		if gpus[i].VRAM == "" {
+			gpus[i].VRAM = "shared"
+		}
+	}
+
+	return gpus, nil
+}
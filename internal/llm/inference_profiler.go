@@ -0,0 +1,325 @@
+package llm
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// InferenceSample holds the timings of one generation request. The
+// durations other than TTFT and Wall are reported by Ollama.
+type InferenceSample struct {
+	Iteration          int
+	TTFT               time.Duration // Until the first token arrived
+	Wall               time.Duration // Until the response was done
+	LoadDuration       time.Duration
+	PromptEvalDuration time.Duration
+	EvalDuration       time.Duration
+	TotalDuration      time.Duration
+	PromptEvalCount    int
+	EvalCount          int
+}
+
+// TokensPerSecond returns the generation throughput of the sample
+func (s InferenceSample) TokensPerSecond() float64 {
+	if s.EvalDuration <= 0 {
+		return 0
+	}
+	return float64(s.EvalCount) / s.EvalDuration.Seconds()
+}
+
+// InferenceProfile is the result of running a prompt repeatedly
+type InferenceProfile struct {
+	Model   string
+	Prompt  string
+	Samples []InferenceSample
+}
+
+// ProfileMetric summarizes one metric over all samples
+type ProfileMetric struct {
+	Name string
+	Unit string
+	MetricStats
+	P50 float64
+	P95 float64
+	P99 float64
+}
+
+// InferenceProfiler measures time to first token and throughput of a local
+// model
+type InferenceProfiler struct {
+	modelManager *LocalLLMManager
+}
+
+// NewInferenceProfiler creates a new inference profiler
+func NewInferenceProfiler() *InferenceProfiler {
+	return &InferenceProfiler{
+		modelManager: NewLocalLLMManager(),
+	}
+}
+
+// Profile runs prompt iterations times, calling progress after each run if
+// it is not nil. The first run includes loading the model unless it is
+// already loaded.
+func (p *InferenceProfiler) Profile(model, prompt string, iterations int, progress func(InferenceSample)) (*InferenceProfile, error) {
+	if iterations <= 0 {
+		return nil, fmt.Errorf("iterations must be greater than 0")
+	}
+	if err := p.modelManager.CheckOllamaAvailability(); err != nil {
+		return nil, err
+	}
+
+	profile := &InferenceProfile{Model: model, Prompt: prompt}
+	for i := 1; i <= iterations; i++ {
+		var ttft time.Duration
+		start := time.Now()
+		resp, err := p.modelManager.StreamGenerate(GenerateRequest{
+			Model:  model,
+			Prompt: prompt,
+		}, func(chunk GenerateResponse) error {
+			if ttft == 0 && chunk.Response != "" {
+				ttft = time.Since(start)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("iteration %d failed: %v", i, err)
+		}
+		wall := time.Since(start)
+		if ttft == 0 {
+			ttft = wall
+		}
+
+		sample := InferenceSample{
+			Iteration:          i,
+			TTFT:               ttft,
+			Wall:               wall,
+			LoadDuration:       time.Duration(resp.LoadDuration),
+			PromptEvalDuration: time.Duration(resp.PromptEvalDuration),
+			EvalDuration:       time.Duration(resp.EvalDuration),
+			TotalDuration:      time.Duration(resp.TotalDuration),
+			PromptEvalCount:    resp.PromptEvalCount,
+			EvalCount:          resp.EvalCount,
+		}
+		profile.Samples = append(profile.Samples, sample)
+		if progress != nil {
+			progress(sample)
+		}
+	}
+
+	return profile, nil
+}
+
+// Stats returns the statistics of every metric
+func (p *InferenceProfile) Stats() []ProfileMetric {
+	metrics := []struct {
+		name  string
+		unit  string
+		value func(InferenceSample) float64
+	}{
+		{"ttft", "ms", func(s InferenceSample) float64 { return milliseconds(s.TTFT) }},
+		{"load_duration", "ms", func(s InferenceSample) float64 { return milliseconds(s.LoadDuration) }},
+		{"prompt_eval_duration", "ms", func(s InferenceSample) float64 { return milliseconds(s.PromptEvalDuration) }},
+		{"eval_duration", "ms", func(s InferenceSample) float64 { return milliseconds(s.EvalDuration) }},
+		{"total_duration", "ms", func(s InferenceSample) float64 { return milliseconds(s.TotalDuration) }},
+		{"eval_count", "tokens", func(s InferenceSample) float64 { return float64(s.EvalCount) }},
+		{"throughput", "tokens/s", InferenceSample.TokensPerSecond},
+	}
+
+	stats := make([]ProfileMetric, 0, len(metrics))
+	for _, metric := range metrics {
+		values := make([]float64, len(p.Samples))
+		for i, sample := range p.Samples {
+			values[i] = metric.value(sample)
+		}
+		sort.Float64s(values)
+
+		stats = append(stats, ProfileMetric{
+			Name:        metric.name,
+			Unit:        metric.unit,
+			MetricStats: summarize(values),
+			P50:         percentile(values, 50),
+			P95:         percentile(values, 95),
+			P99:         percentile(values, 99),
+		})
+	}
+	return stats
+}
+
+// percentile returns the nearest-rank percentile of sorted values
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// WriteCSV writes the raw timings of every sample to path
+func (p *InferenceProfile) WriteCSV(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	writer.Write([]string{
+		"iteration", "ttft_ms", "wall_ms", "load_duration_ms", "prompt_eval_duration_ms",
+		"eval_duration_ms", "total_duration_ms", "prompt_eval_count", "eval_count", "tokens_per_second",
+	})
+	for _, s := range p.Samples {
+		writer.Write([]string{
+			strconv.Itoa(s.Iteration),
+			formatFloat(milliseconds(s.TTFT)),
+			formatFloat(milliseconds(s.Wall)),
+			formatFloat(milliseconds(s.LoadDuration)),
+			formatFloat(milliseconds(s.PromptEvalDuration)),
+			formatFloat(milliseconds(s.EvalDuration)),
+			formatFloat(milliseconds(s.TotalDuration)),
+			strconv.Itoa(s.PromptEvalCount),
+			strconv.Itoa(s.EvalCount),
+			formatFloat(s.TokensPerSecond()),
+		})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', 3, 64)
+}
+
+// flameChartPhases are the phases of a request, in the order Ollama runs
+// them, with the character that draws each in per-iteration rows
+var flameChartPhases = []struct {
+	name   string
+	symbol byte
+	value  func(InferenceSample) time.Duration
+}{
+	{"load", 'L', func(s InferenceSample) time.Duration { return s.LoadDuration }},
+	{"prompt eval", 'P', func(s InferenceSample) time.Duration { return s.PromptEvalDuration }},
+	{"eval", 'E', func(s InferenceSample) time.Duration { return s.EvalDuration }},
+}
+
+// WriteFlameChart draws an ASCII flame chart of the profile, width
+// characters wide: the mean request split into its phases, then one row per
+// iteration on a common time scale
+func (p *InferenceProfile) WriteFlameChart(w io.Writer, width int) {
+	if len(p.Samples) == 0 {
+		return
+	}
+	if width < 40 {
+		width = 40
+	}
+
+	// Mean request: a frame for the whole request above its phases
+	var meanTotal time.Duration
+	means := make([]time.Duration, len(flameChartPhases))
+	for _, s := range p.Samples {
+		meanTotal += s.TotalDuration
+		for i, phase := range flameChartPhases {
+			means[i] += phase.value(s)
+		}
+	}
+	n := time.Duration(len(p.Samples))
+	meanTotal /= n
+	for i := range means {
+		means[i] /= n
+	}
+
+	fmt.Fprintf(w, "Mean request (%d iterations)\n", len(p.Samples))
+	fmt.Fprintln(w, flameFrame(fmt.Sprintf("request %s", formatMillis(meanTotal)), width))
+
+	var row strings.Builder
+	used := 0
+	var elapsed time.Duration
+	for i, phase := range flameChartPhases {
+		elapsed += means[i]
+		end := scaleTo(elapsed, meanTotal, width)
+		if end > width {
+			end = width
+		}
+		if end > used {
+			row.WriteString(flameFrame(fmt.Sprintf("%s %s", phase.name, formatMillis(means[i])), end-used))
+			used = end
+		}
+	}
+	if used < width {
+		// Time Ollama reports outside the three phases
+		row.WriteString(flameFrame("other", width-used))
+	}
+	fmt.Fprintln(w, row.String())
+
+	// One row per iteration, scaled to the slowest request
+	var longest time.Duration
+	for _, s := range p.Samples {
+		if s.TotalDuration > longest {
+			longest = s.TotalDuration
+		}
+	}
+
+	fmt.Fprintf(w, "\nIterations (L load, P prompt eval, E eval, . other; full width %s)\n", formatMillis(longest))
+	for _, s := range p.Samples {
+		bar := make([]byte, 0, width)
+		var elapsed time.Duration
+		for _, phase := range flameChartPhases {
+			elapsed += phase.value(s)
+			for end := scaleTo(elapsed, longest, width); len(bar) < end && len(bar) < width; {
+				bar = append(bar, phase.symbol)
+			}
+		}
+		for end := scaleTo(s.TotalDuration, longest, width); len(bar) < end && len(bar) < width; {
+			bar = append(bar, '.')
+		}
+		fmt.Fprintf(w, "%4d |%-*s| %s\n", s.Iteration, width, bar, formatMillis(s.TotalDuration))
+	}
+}
+
+// flameFrame draws a frame width characters wide, labelled when the label
+// fits
+func flameFrame(label string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if width < 3 {
+		return strings.Repeat("#", width)
+	}
+
+	inner := width - 2
+	if len(label)+2 > inner {
+		return "[" + strings.Repeat("=", inner) + "]"
+	}
+	left := (inner - len(label) - 2) / 2
+	right := inner - len(label) - 2 - left
+	return "[" + strings.Repeat("=", left) + " " + label + " " + strings.Repeat("=", right) + "]"
+}
+
+// scaleTo returns the number of characters d takes up when total is width
+// characters
+func scaleTo(d, total time.Duration, width int) int {
+	if total <= 0 {
+		return 0
+	}
+	return int(math.Round(float64(d) / float64(total) * float64(width)))
+}
+
+func formatMillis(d time.Duration) string {
+	return fmt.Sprintf("%.1fms", milliseconds(d))
+}
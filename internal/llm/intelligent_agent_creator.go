@@ -1,13 +1,22 @@
 package llm
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"text/template"
+
+	"github.com/pxkundu/agent-as-code/internal/log"
 )
 
+// AgentUseCases are the use cases agents can be created for
+var AgentUseCases = []string{
+	"chatbot", "sentiment-analyzer", "code-assistant", "data-analyzer",
+	"content-generator", "translator", "qa-system", "workflow-automation",
+}
+
 // IntelligentAgentCreator creates intelligent, fully functional agents
 type IntelligentAgentCreator struct {
 	templateManager *TemplateManager
@@ -25,8 +34,41 @@ type AgentConfig struct {
 	Capabilities []string
 	Ports        []Port
 	Environment  []Environment
+	Prometheus   bool
+	AuthMode     string
+	A2A          bool
+}
+
+// CreateAgentOptions represents optional features of a generated agent
+type CreateAgentOptions struct {
+	// Prometheus replaces the JSON /metrics endpoint with Prometheus metrics
+	Prometheus bool
+
+	// AuthMode protects the generated agent's endpoints: AuthAPIKey,
+	// AuthJWT or AuthOAuth2. Empty leaves them open.
+	AuthMode string
+
+	// A2A adds an agent2agent message endpoint and registers the agent
+	// with the registry in A2A_REGISTRY_URL at startup
+	A2A bool
+
+	// StrictCodegen fails agent creation when the generated code does not
+	// pass static analysis (see CodeValidator). Otherwise the problems are
+	// logged as warnings.
+	StrictCodegen bool
+
+	// OutputDir is the directory the project is created in; empty means
+	// the current directory
+	OutputDir string
 }
 
+// Authentication modes of a generated agent
+const (
+	AuthAPIKey = "apikey"
+	AuthJWT    = "jwt"
+	AuthOAuth2 = "oauth2"
+)
+
 // Port represents a port mapping
 type Port struct {
 	Container int
@@ -74,19 +116,14 @@ func NewTemplateManager() *TemplateManager {
 
 // ValidateUseCase validates if a use case is supported
 func (c *IntelligentAgentCreator) ValidateUseCase(useCase string) error {
-	validUseCases := []string{
-		"chatbot", "sentiment-analyzer", "code-assistant", "data-analyzer",
-		"content-generator", "translator", "qa-system", "workflow-automation",
-	}
-
-	for _, valid := range validUseCases {
+	for _, valid := range AgentUseCases {
 		if useCase == valid {
 			return nil
 		}
 	}
 
 	return fmt.Errorf("unsupported use case '%s'. Valid use cases: %s",
-		useCase, strings.Join(validUseCases, ", "))
+		useCase, strings.Join(AgentUseCases, ", "))
 }
 
 // GetRecommendedModel gets the recommended model for a use case
@@ -141,9 +178,16 @@ func (c *IntelligentAgentCreator) GetCapabilities(useCase string) []string {
 }
 
 // CreateAgent creates a complete intelligent agent
-func (c *IntelligentAgentCreator) CreateAgent(useCase, model string) (*AgentConfig, error) {
+func (c *IntelligentAgentCreator) CreateAgent(useCase, model string, options CreateAgentOptions) (*AgentConfig, error) {
+	switch options.AuthMode {
+	case "", AuthAPIKey, AuthJWT, AuthOAuth2:
+	default:
+		return nil, fmt.Errorf("unsupported auth mode '%s' (valid: apikey, jwt, oauth2)", options.AuthMode)
+	}
+
 	// Create project directory
-	projectDir := useCase + "-agent"
+	name := useCase + "-agent"
+	projectDir := filepath.Join(options.OutputDir, name)
 	if err := os.MkdirAll(projectDir, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create project directory: %w", err)
 	}
@@ -156,7 +200,7 @@ func (c *IntelligentAgentCreator) CreateAgent(useCase, model string) (*AgentConf
 
 	// Create agent configuration
 	config := &AgentConfig{
-		Name:         projectDir,
+		Name:         name,
 		Template:     useCase,
 		Runtime:      "python",
 		Model:        model,
@@ -170,6 +214,9 @@ func (c *IntelligentAgentCreator) CreateAgent(useCase, model string) (*AgentConf
 			{Name: "LOG_LEVEL", Value: "INFO"},
 			{Name: "MODEL_NAME", Value: model},
 		},
+		Prometheus: options.Prometheus,
+		AuthMode:   options.AuthMode,
+		A2A:        options.A2A,
 	}
 
 	// Generate project files
@@ -179,6 +226,18 @@ func (c *IntelligentAgentCreator) CreateAgent(useCase, model string) (*AgentConf
 		return nil, fmt.Errorf("failed to generate project files: %w", err)
 	}
 
+	// Generated code problems are warnings unless asked to be fatal
+	if err := NewCodeValidator().ValidateProject(projectDir, config.Runtime); err != nil {
+		switch {
+		case errors.Is(err, ErrNoValidator):
+			log.Warn("generated code was not validated", "error", err)
+		case options.StrictCodegen:
+			return nil, fmt.Errorf("generated code in %s failed validation: %w", projectDir, err)
+		default:
+			log.Warn("generated code failed validation", "project", projectDir, "error", err)
+		}
+	}
+
 	return config, nil
 }
 
@@ -404,6 +463,16 @@ if __name__ == "__main__":
 		config.Name, config.Model,
 		config.Name)
 
+	if config.Prometheus {
+		code = addPrometheusMetrics(code)
+	}
+	if config.AuthMode != "" {
+		code = addAuthentication(code, config.AuthMode)
+	}
+	if config.A2A {
+		code = addA2A(code, config.Name)
+	}
+
 	file, err := os.Create(filepath.Join(projectDir, "main.py"))
 	if err != nil {
 		return fmt.Errorf("failed to create main.py: %w", err)
@@ -414,6 +483,314 @@ if __name__ == "__main__":
 	return err
 }
 
+// addPrometheusMetrics instruments the generated FastAPI app with
+// Prometheus metrics and replaces the JSON /metrics endpoint
+func addPrometheusMetrics(code string) string {
+	code = strings.Replace(code, "import uvicorn\n", `import time
+import uvicorn
+from prometheus_client import Counter, Histogram
+from prometheus_fastapi_instrumentator import Instrumentator
+`, 1)
+
+	code = strings.Replace(code, "# Pydantic models\n", `# Prometheus metrics
+REQUEST_COUNT = Counter("request_count", "Total number of processing requests")
+REQUEST_DURATION = Histogram("request_duration_seconds", "Processing request duration in seconds")
+MODEL_ERROR_COUNT = Counter("model_error_count", "Total number of model errors")
+
+# Expose HTTP and custom metrics at /metrics
+Instrumentator().instrument(app).expose(app, endpoint="/metrics")
+
+# Pydantic models
+`, 1)
+
+	code = strings.Replace(code, `    """Process request"""
+    try:
+`, `    """Process request"""
+    REQUEST_COUNT.inc()
+    start = time.perf_counter()
+    try:
+`, 1)
+
+	code = strings.Replace(code, `        logger.error(f"Error processing request: {e}")
+        raise HTTPException(status_code=500, detail=str(e))
+`, `        MODEL_ERROR_COUNT.inc()
+        logger.error(f"Error processing request: {e}")
+        raise HTTPException(status_code=500, detail=str(e))
+    finally:
+        REQUEST_DURATION.observe(time.perf_counter() - start)
+`, 1)
+
+	// The instrumentator serves /metrics, so drop the JSON endpoint
+	if start := strings.Index(code, "# Metrics endpoint\n"); start >= 0 {
+		if end := strings.Index(code[start:], "# Startup event\n"); end >= 0 {
+			code = code[:start] + code[start+end:]
+		}
+	}
+
+	return code
+}
+
+// addA2A adds agent2agent messaging to the generated FastAPI app: the
+// message endpoint with a handler per message type, send_a2a for messages
+// to other agents, and registration with the A2A_REGISTRY_URL sidecar at
+// startup. The wire format matches internal/a2a.
+func addA2A(code, name string) string {
+	code = strings.Replace(code, "import logging\n", `import logging
+import uuid
+from datetime import datetime, timezone
+from typing import Any, Awaitable, Callable, Dict
+`, 1)
+	code = strings.Replace(code, "import uvicorn\n", "import httpx\nimport uvicorn\n", 1)
+
+	code = strings.Replace(code, "# Health check endpoint\n", fmt.Sprintf(`# Agent-to-agent (A2A) messaging
+A2A_AGENT_NAME = os.getenv("A2A_AGENT_NAME", "%s")
+A2A_REGISTRY_URL = os.getenv("A2A_REGISTRY_URL", "").rstrip("/")
+A2A_AGENT_URL = os.getenv("A2A_AGENT_URL", f"http://{A2A_AGENT_NAME}:{os.getenv('PORT', 8080)}")
+
+class A2AMessage(BaseModel):
+    from_agent: str
+    to_agent: str = ""
+    message_id: str = Field(default_factory=lambda: uuid.uuid4().hex)
+    type: str
+    payload: Any = None
+    timestamp: datetime = Field(default_factory=lambda: datetime.now(timezone.utc))
+
+a2a_handlers: Dict[str, Callable[[A2AMessage], Awaitable[None]]] = {}
+
+def a2a_handler(message_type: str):
+    """Register a coroutine as the handler of an A2A message type"""
+    def register(func):
+        a2a_handlers[message_type] = func
+        return func
+    return register
+
+@a2a_handler("ping")
+async def handle_ping(message: A2AMessage):
+    """Log pings, to check that agents can reach each other"""
+    logger.info(f"A2A ping from {message.from_agent}")
+
+async def resolve_agent(agent: str) -> str:
+    """Return the base URL of an agent: its registered URL if there is a
+    registry, otherwise its service name on the compose network"""
+    if "://" in agent:
+        return agent.rstrip("/")
+    if not A2A_REGISTRY_URL:
+        return f"http://{agent}:8080"
+    async with httpx.AsyncClient(timeout=10) as http:
+        response = await http.get(f"{A2A_REGISTRY_URL}/agents/{agent}")
+        response.raise_for_status()
+        return response.json()["url"].rstrip("/")
+
+async def send_a2a(to_agent: str, message_type: str, payload: Any = None):
+    """Send a message to another agent"""
+    message = A2AMessage(
+        from_agent=A2A_AGENT_NAME,
+        to_agent="" if "://" in to_agent else to_agent,
+        type=message_type,
+        payload=payload,
+    )
+    url = await resolve_agent(to_agent)
+    async with httpx.AsyncClient(timeout=30) as http:
+        response = await http.post(
+            f"{url}/a2a/messages",
+            content=message.model_dump_json(),
+            headers={"Content-Type": "application/json"},
+        )
+        response.raise_for_status()
+
+# Health check endpoint
+`, name), 1)
+
+	code = strings.Replace(code, "# Startup event\n", `# A2A message endpoint
+@app.post("/a2a/messages", status_code=202)
+async def receive_a2a(message: A2AMessage):
+    """Receive a message from another agent"""
+    if not message.to_agent:
+        message.to_agent = A2A_AGENT_NAME
+    if message.to_agent != A2A_AGENT_NAME:
+        raise HTTPException(status_code=400, detail=f"message is addressed to '{message.to_agent}', not '{A2A_AGENT_NAME}'")
+    handler = a2a_handlers.get(message.type)
+    if handler is None:
+        raise HTTPException(status_code=404, detail=f"no handler for message type '{message.type}'")
+    await handler(message)
+    return {"message_id": message.message_id}
+
+# Startup event
+`, 1)
+
+	// Register with the discovery sidecar at the end of the startup event
+	code = strings.Replace(code, "\n# Shutdown event\n", `    if A2A_REGISTRY_URL:
+        try:
+            async with httpx.AsyncClient(timeout=10) as http:
+                response = await http.post(
+                    f"{A2A_REGISTRY_URL}/register",
+                    json={"name": A2A_AGENT_NAME, "url": A2A_AGENT_URL},
+                )
+                response.raise_for_status()
+            logger.info(f"Registered with A2A registry {A2A_REGISTRY_URL} as {A2A_AGENT_NAME}")
+        except Exception as e:
+            logger.warning(f"A2A registration failed: {e}")
+
+# Shutdown event
+`, 1)
+
+	return code
+}
+
+// authDependencies holds the Python authentication code of each auth mode.
+// Each defines an authenticate dependency that rejects a request without
+// credentials with 401 before checking the agent's configuration.
+var authDependencies = map[string]string{
+	AuthAPIKey: `# API key authentication
+api_key_header = APIKeyHeader(name="X-API-Key", auto_error=False)
+
+async def authenticate(api_key: str = Security(api_key_header)):
+    """Require the X-API-Key header to match AGENT_API_KEY"""
+    if not api_key:
+        raise HTTPException(status_code=401, detail="Missing API key")
+    expected = os.getenv("AGENT_API_KEY")
+    if not expected:
+        logger.error("AGENT_API_KEY is not set")
+        raise HTTPException(status_code=500, detail="Authentication is not configured")
+    if not secrets.compare_digest(api_key, expected):
+        raise HTTPException(status_code=401, detail="Invalid API key")
+    return api_key
+
+`,
+	AuthJWT: `# JWT authentication
+bearer_scheme = HTTPBearer(auto_error=False)
+JWT_ALGORITHMS = os.getenv("JWT_ALGORITHMS", "HS256").split(",")
+
+async def authenticate(credentials: HTTPAuthorizationCredentials = Security(bearer_scheme)):
+    """Require a Bearer token signed with JWT_SECRET and return its claims"""
+    if credentials is None:
+        raise HTTPException(status_code=401, detail="Missing bearer token", headers={"WWW-Authenticate": "Bearer"})
+    secret = os.getenv("JWT_SECRET")
+    if not secret:
+        logger.error("JWT_SECRET is not set")
+        raise HTTPException(status_code=500, detail="Authentication is not configured")
+    audience = os.getenv("JWT_AUDIENCE")
+    try:
+        return jwt.decode(
+            credentials.credentials,
+            secret,
+            algorithms=JWT_ALGORITHMS,
+            audience=audience,
+            issuer=os.getenv("JWT_ISSUER"),
+            options={"verify_aud": bool(audience)},
+        )
+    except JWTError as e:
+        raise HTTPException(status_code=401, detail=f"Invalid token: {e}", headers={"WWW-Authenticate": "Bearer"})
+
+`,
+	AuthOAuth2: `# OAuth2 authentication: access tokens are JWTs verified with the
+# authorization server's signing keys
+bearer_scheme = HTTPBearer(auto_error=False)
+_jwks = {}
+
+async def get_jwks():
+    """Fetch and cache the authorization server's JSON Web Key Set"""
+    if not _jwks:
+        url = os.getenv("OAUTH2_JWKS_URL")
+        if not url:
+            logger.error("OAUTH2_JWKS_URL is not set")
+            raise HTTPException(status_code=500, detail="Authentication is not configured")
+        async with httpx.AsyncClient(timeout=10) as http:
+            response = await http.get(url)
+            response.raise_for_status()
+            _jwks.update(response.json())
+    return _jwks
+
+async def authenticate(credentials: HTTPAuthorizationCredentials = Security(bearer_scheme)):
+    """Require an OAuth2 access token from OAUTH2_ISSUER and return its claims"""
+    if credentials is None:
+        raise HTTPException(status_code=401, detail="Missing bearer token", headers={"WWW-Authenticate": "Bearer"})
+    jwks = await get_jwks()
+    audience = os.getenv("OAUTH2_AUDIENCE")
+    try:
+        return jwt.decode(
+            credentials.credentials,
+            jwks,
+            algorithms=os.getenv("OAUTH2_ALGORITHMS", "RS256").split(","),
+            audience=audience,
+            issuer=os.getenv("OAUTH2_ISSUER"),
+            options={"verify_aud": bool(audience)},
+        )
+    except JWTError as e:
+        raise HTTPException(status_code=401, detail=f"Invalid token: {e}", headers={"WWW-Authenticate": "Bearer"})
+
+`,
+}
+
+// authImports holds the Python imports of each auth mode
+var authImports = map[string]string{
+	AuthAPIKey: "import secrets\nfrom fastapi.security import APIKeyHeader\n",
+	AuthJWT:    "from fastapi.security import HTTPAuthorizationCredentials, HTTPBearer\nfrom jose import JWTError, jwt\n",
+	AuthOAuth2: "import httpx\nfrom fastapi.security import HTTPAuthorizationCredentials, HTTPBearer\nfrom jose import JWTError, jwt\n",
+}
+
+// addAuthentication protects the generated FastAPI app's /process and JSON
+// /metrics endpoints with the authenticate dependency of mode. /health
+// stays open for container health checks, as does the Prometheus endpoint
+// for scrapers.
+func addAuthentication(code, mode string) string {
+	code = strings.Replace(code, "from fastapi import FastAPI, HTTPException\n",
+		"from fastapi import Depends, FastAPI, HTTPException, Security\n"+authImports[mode], 1)
+
+	code = strings.Replace(code, "# Pydantic models\n", authDependencies[mode]+"# Pydantic models\n", 1)
+
+	code = strings.Replace(code, `@app.post("/process", response_model=ProcessResponse)`,
+		`@app.post("/process", response_model=ProcessResponse, dependencies=[Depends(authenticate)])`, 1)
+	code = strings.Replace(code, `@app.get("/metrics")`,
+		`@app.get("/metrics", dependencies=[Depends(authenticate)])`, 1)
+
+	return code
+}
+
+// addAuthTests makes the generated tests authenticate, and adds a test that
+// /process rejects unauthenticated requests
+func addAuthTests(code, mode string) string {
+	var setup string
+	switch mode {
+	case AuthAPIKey:
+		setup = `os.environ["AGENT_API_KEY"] = "test-key"
+AUTH_HEADERS = {"X-API-Key": "test-key"}
+`
+	case AuthJWT:
+		setup = `from jose import jwt
+
+os.environ["JWT_SECRET"] = "test-secret"
+AUTH_HEADERS = {"Authorization": "Bearer " + jwt.encode({"sub": "test"}, "test-secret", algorithm="HS256")}
+`
+	case AuthOAuth2:
+		// Tokens from a real authorization server aren't available in tests
+		setup = `from main import authenticate
+
+app.dependency_overrides[authenticate] = lambda: {"sub": "test"}
+AUTH_HEADERS = {}
+`
+	}
+
+	code = strings.Replace(code, "import pytest\n", "import os\nimport pytest\n", 1)
+	code = strings.Replace(code, "client = TestClient(app)\n", "client = TestClient(app)\n"+setup, 1)
+	code = strings.ReplaceAll(code, `client.post("/process", json=request_data)`, `client.post("/process", json=request_data, headers=AUTH_HEADERS)`)
+	code = strings.ReplaceAll(code, `client.post("/process", json={"input": "metrics test"})`, `client.post("/process", json={"input": "metrics test"}, headers=AUTH_HEADERS)`)
+	code = strings.ReplaceAll(code, `client.get("/metrics")`, `client.get("/metrics", headers=AUTH_HEADERS)`)
+
+	unauthenticated := `def test_process_requires_authentication():
+    """Test that /process rejects requests without credentials"""
+    overrides = dict(app.dependency_overrides)
+    app.dependency_overrides.clear()
+    try:
+        response = client.post("/process", json={"input": "no credentials"})
+        assert response.status_code == 401
+    finally:
+        app.dependency_overrides.update(overrides)
+
+`
+	return strings.Replace(code, "if __name__ == \"__main__\":\n", unauthenticated+"if __name__ == \"__main__\":\n", 1)
+}
+
 // generateTests generates the test suite
 func (c *IntelligentAgentCreator) generateTests(projectDir string, config *AgentConfig, template *AgentTemplate) error {
 	// Create tests directory
@@ -422,6 +799,27 @@ func (c *IntelligentAgentCreator) generateTests(projectDir string, config *Agent
 		return fmt.Errorf("failed to create tests directory: %w", err)
 	}
 
+	metricsTest := fmt.Sprintf(`def test_metrics():
+    """Test metrics endpoint"""
+    response = client.get("/metrics")
+    assert response.status_code == 200
+    
+    data = response.json()
+    assert data["status"] == "healthy"
+    assert data["model"] == "%s"
+`, config.Model)
+	if config.Prometheus {
+		metricsTest = `def test_metrics():
+    """Test Prometheus metrics endpoint"""
+    client.post("/process", json={"input": "metrics test"})
+    response = client.get("/metrics")
+    assert response.status_code == 200
+    assert "request_count_total" in response.text
+    assert "request_duration_seconds" in response.text
+    assert "model_error_count_total" in response.text
+`
+	}
+
 	// Generate test code with proper formatting
 	testCode := fmt.Sprintf(`#!/usr/bin/env python3
 """
@@ -460,23 +858,33 @@ def test_process_%s():
     assert "metadata" in data
     assert data["metadata"]["model"] == "%s"
 
-def test_metrics():
-    """Test metrics endpoint"""
-    response = client.get("/metrics")
-    assert response.status_code == 200
-    
-    data = response.json()
-    assert data["status"] == "healthy"
-    assert data["model"] == "%s"
-
+%s
 if __name__ == "__main__":
     pytest.main([__file__])
 `,
 		config.Name, config.Template,
 		config.Model, config.Template,
-		config.Template, config.Template, config.Template,
+		pythonIdentifier(config.Template), config.Template, config.Template,
 		config.Model,
-		config.Model)
+		metricsTest)
+
+	if config.AuthMode != "" {
+		testCode = addAuthTests(testCode, config.AuthMode)
+	}
+	if config.A2A {
+		a2aTest := fmt.Sprintf(`def test_a2a_message():
+    """Test that A2A messages are dispatched by type"""
+    message = {"from_agent": "test-agent", "to_agent": "%s", "type": "ping", "payload": {}}
+    response = client.post("/a2a/messages", json=message)
+    assert response.status_code == 202
+
+    message["type"] = "unknown"
+    response = client.post("/a2a/messages", json=message)
+    assert response.status_code == 404
+
+`, config.Name)
+		testCode = strings.Replace(testCode, "if __name__ == \"__main__\":\n", a2aTest+"if __name__ == \"__main__\":\n", 1)
+	}
 
 	// Create test file with proper name
 	testFileName := fmt.Sprintf("test_%s.py", config.Template)
@@ -510,7 +918,6 @@ structlog==23.1.0
 
 # Utilities
 python-multipart==0.0.6
-python-jose[cryptography]==3.3.0
 passlib[bcrypt]==1.7.4
 
 # Development
@@ -519,6 +926,28 @@ flake8==6.1.0
 mypy==1.5.1
 `
 
+	if config.Prometheus {
+		requirements += `
+# Prometheus metrics
+prometheus_client==0.19.0
+prometheus-fastapi-instrumentator==6.1.0
+`
+	}
+
+	switch config.AuthMode {
+	case AuthJWT:
+		requirements += `
+# JWT authentication
+python-jose[cryptography]==3.3.0
+`
+	case AuthOAuth2:
+		// httpx, which fetches the signing keys, is already listed for testing
+		requirements += `
+# OAuth2 authentication
+python-jose[cryptography]==3.3.0
+`
+	}
+
 	file, err := os.Create(filepath.Join(projectDir, "requirements.txt"))
 	if err != nil {
 		return fmt.Errorf("failed to create requirements.txt: %w", err)
@@ -578,6 +1007,12 @@ CMD ["python", "main.py"]
 }
 
 // formatCapabilities formats capabilities for Python code
+// pythonIdentifier turns a use case such as qa-system into a name that
+// can be part of a Python identifier
+func pythonIdentifier(name string) string {
+	return strings.ReplaceAll(name, "-", "_")
+}
+
 func formatCapabilities(capabilities []string) string {
 	if len(capabilities) == 0 {
 		return "[]"
@@ -676,6 +1111,13 @@ func (c *IntelligentAgentCreator) generateREADME(projectDir string, config *Agen
 	content.WriteString("- PORT: Server port (default: 8080)\n")
 	content.WriteString(fmt.Sprintf("- MODEL_NAME: LLM model name (default: %s)\n\n", config.Model))
 
+	if config.AuthMode != "" {
+		content.WriteString(authREADMESection(config))
+	}
+	if config.A2A {
+		content.WriteString(a2aREADMESection(config))
+	}
+
 	content.WriteString("## Monitoring\n\n")
 	content.WriteString("- Health Checks: Automatic health monitoring at /health\n")
 	content.WriteString("- Metrics: Performance metrics at /metrics\n")
@@ -719,6 +1161,84 @@ func (c *IntelligentAgentCreator) generateREADME(projectDir string, config *Agen
 	return err
 }
 
+// authREADMESection documents the authentication setup of the agent
+func authREADMESection(config *AgentConfig) string {
+	var content strings.Builder
+	content.WriteString("## Authentication\n\n")
+	if config.Prometheus {
+		content.WriteString("/process requires authentication. /health stays open for container health checks, and /metrics for Prometheus scrapers.\n\n")
+	} else {
+		content.WriteString("/process and /metrics require authentication. /health stays open for container health checks.\n\n")
+	}
+
+	switch config.AuthMode {
+	case AuthAPIKey:
+		content.WriteString("Requests must send the key in the X-API-Key header. Set the key with:\n\n")
+		content.WriteString("- AGENT_API_KEY: The API key clients must send (required)\n\n")
+		content.WriteString("```bash\n")
+		content.WriteString("export AGENT_API_KEY=$(openssl rand -hex 32)\n")
+		content.WriteString("curl -X POST http://localhost:8080/process \\\n")
+		content.WriteString("  -H \"X-API-Key: $AGENT_API_KEY\" \\\n")
+		content.WriteString("  -H \"Content-Type: application/json\" \\\n")
+		content.WriteString("  -d '{\"input\": \"Your input here\"}'\n")
+		content.WriteString("```\n\n")
+	case AuthJWT:
+		content.WriteString("Requests must send a JWT as a Bearer token, verified with python-jose:\n\n")
+		content.WriteString("- JWT_SECRET: Key the tokens are signed with (required)\n")
+		content.WriteString("- JWT_ALGORITHMS: Comma-separated accepted algorithms (default: HS256)\n")
+		content.WriteString("- JWT_AUDIENCE: Required aud claim (optional)\n")
+		content.WriteString("- JWT_ISSUER: Required iss claim (optional)\n\n")
+		content.WriteString("```bash\n")
+		content.WriteString("export JWT_SECRET=$(openssl rand -hex 32)\n")
+		content.WriteString("TOKEN=$(python -c \"from jose import jwt; import os; print(jwt.encode({'sub': 'me'}, os.environ['JWT_SECRET'], algorithm='HS256'))\")\n")
+		content.WriteString("curl -X POST http://localhost:8080/process \\\n")
+		content.WriteString("  -H \"Authorization: Bearer $TOKEN\" \\\n")
+		content.WriteString("  -H \"Content-Type: application/json\" \\\n")
+		content.WriteString("  -d '{\"input\": \"Your input here\"}'\n")
+		content.WriteString("```\n\n")
+	case AuthOAuth2:
+		content.WriteString("Requests must send an access token from your OAuth2 authorization server as a Bearer token. ")
+		content.WriteString("Tokens are verified as JWTs against the server's signing keys:\n\n")
+		content.WriteString("- OAUTH2_JWKS_URL: URL of the server's JSON Web Key Set (required)\n")
+		content.WriteString("- OAUTH2_ISSUER: Required iss claim (recommended)\n")
+		content.WriteString("- OAUTH2_AUDIENCE: Required aud claim (recommended)\n")
+		content.WriteString("- OAUTH2_ALGORITHMS: Comma-separated accepted algorithms (default: RS256)\n\n")
+		content.WriteString("```bash\n")
+		content.WriteString("export OAUTH2_ISSUER=https://auth.example.com/\n")
+		content.WriteString("export OAUTH2_JWKS_URL=https://auth.example.com/.well-known/jwks.json\n")
+		content.WriteString("export OAUTH2_AUDIENCE=https://agents.example.com\n")
+		content.WriteString("curl -X POST http://localhost:8080/process \\\n")
+		content.WriteString("  -H \"Authorization: Bearer $ACCESS_TOKEN\" \\\n")
+		content.WriteString("  -H \"Content-Type: application/json\" \\\n")
+		content.WriteString("  -d '{\"input\": \"Your input here\"}'\n")
+		content.WriteString("```\n\n")
+	}
+
+	return content.String()
+}
+
+// a2aREADMESection documents the agent2agent messaging of the agent
+func a2aREADMESection(config *AgentConfig) string {
+	var content strings.Builder
+	content.WriteString("## Agent-to-Agent Messaging\n\n")
+	content.WriteString("The agent receives messages from other agents at POST /a2a/messages. ")
+	content.WriteString("Register a handler per message type with the a2a_handler decorator, and send messages with send_a2a:\n\n")
+	content.WriteString("```python\n")
+	content.WriteString("@a2a_handler(\"task\")\n")
+	content.WriteString("async def handle_task(message: A2AMessage):\n")
+	content.WriteString("    await send_a2a(message.from_agent, \"result\", {\"status\": \"done\"})\n")
+	content.WriteString("```\n\n")
+	content.WriteString(fmt.Sprintf("- A2A_AGENT_NAME: Name of this agent (default: %s)\n", config.Name))
+	content.WriteString("- A2A_REGISTRY_URL: Registry to register with at startup and look other agents up in (optional)\n")
+	content.WriteString("- A2A_AGENT_URL: URL registered for this agent (default: http://$A2A_AGENT_NAME:$PORT)\n\n")
+	content.WriteString("Without a registry, other agents are reached by their service name on port 8080. ")
+	content.WriteString("Run a registry with agent llm agent2agent registry.\n\n")
+	if config.AuthMode != "" {
+		content.WriteString("/a2a/messages does not require authentication, so only expose it on the stack's network.\n\n")
+	}
+	return content.String()
+}
+
 // generateCICD generates CI/CD configuration
 func (c *IntelligentAgentCreator) generateCICD(projectDir string, config *AgentConfig) error {
 	// Create .github/workflows directory
@@ -0,0 +1,592 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/pxkundu/agent-as-code/internal/log"
+)
+
+const (
+	// defaultGraphChunkSize is the size in bytes of the document batches
+	// sent to the model for extraction
+	defaultGraphChunkSize = 4000
+	// defaultGraphDepth is how many relations a query follows from its
+	// starting entities
+	defaultGraphDepth = 2
+	// graphVocabulary is the JSON-LD vocabulary of saved graphs
+	graphVocabulary = "urn:agent-as-code:graph#"
+)
+
+// graphFileExtensions are the files read from a graph's input directory
+var graphFileExtensions = map[string]bool{
+	".txt":      true,
+	".md":       true,
+	".markdown": true,
+	".rst":      true,
+	".text":     true,
+}
+
+// GraphEntity is a node of a knowledge graph
+type GraphEntity struct {
+	ID   string
+	Name string
+	Type string
+}
+
+// GraphRelation is a directed edge of a knowledge graph
+type GraphRelation struct {
+	Subject   string
+	Predicate string
+	Object    string
+	Sources   []string
+}
+
+// KnowledgeGraph is a directed graph of entities and the relations between
+// them. Entities are identified by their normalized name.
+type KnowledgeGraph struct {
+	entities  map[string]*GraphEntity
+	relations []*GraphRelation
+	edges     map[string]*GraphRelation
+}
+
+// NewKnowledgeGraph creates an empty knowledge graph
+func NewKnowledgeGraph() *KnowledgeGraph {
+	return &KnowledgeGraph{
+		entities: make(map[string]*GraphEntity),
+		edges:    make(map[string]*GraphRelation),
+	}
+}
+
+// AddEntity adds an entity, returning the existing one if an entity with
+// the same name is already in the graph. A known type replaces an unknown
+// one.
+func (g *KnowledgeGraph) AddEntity(name, entityType string) *GraphEntity {
+	id := entityID(name)
+	entityType = normalizeLabel(entityType)
+	if entity, ok := g.entities[id]; ok {
+		if entity.Type == "" {
+			entity.Type = entityType
+		}
+		return entity
+	}
+
+	entity := &GraphEntity{ID: id, Name: strings.TrimSpace(name), Type: entityType}
+	g.entities[id] = entity
+	return entity
+}
+
+// AddRelation adds the relation subject -predicate-> object, adding the
+// entities if needed. source records the document the relation came from.
+func (g *KnowledgeGraph) AddRelation(subject, predicate, object, source string) {
+	predicate = normalizeLabel(predicate)
+	s, o := g.AddEntity(subject, ""), g.AddEntity(object, "")
+	if predicate == "" || s.ID == o.ID {
+		return
+	}
+
+	key := s.ID + "\x00" + predicate + "\x00" + o.ID
+	relation, ok := g.edges[key]
+	if !ok {
+		relation = &GraphRelation{Subject: s.ID, Predicate: predicate, Object: o.ID}
+		g.edges[key] = relation
+		g.relations = append(g.relations, relation)
+	}
+	if source != "" && !containsString(relation.Sources, source) {
+		relation.Sources = append(relation.Sources, source)
+	}
+}
+
+// Entities returns the graph's entities sorted by name
+func (g *KnowledgeGraph) Entities() []*GraphEntity {
+	entities := make([]*GraphEntity, 0, len(g.entities))
+	for _, entity := range g.entities {
+		entities = append(entities, entity)
+	}
+	sort.Slice(entities, func(i, j int) bool {
+		return entities[i].ID < entities[j].ID
+	})
+	return entities
+}
+
+// Relations returns the graph's relations in the order they were added
+func (g *KnowledgeGraph) Relations() []*GraphRelation {
+	return g.relations
+}
+
+// Entity returns the entity with the given name, if any
+func (g *KnowledgeGraph) Entity(name string) (*GraphEntity, bool) {
+	entity, ok := g.entities[entityID(name)]
+	return entity, ok
+}
+
+// entityID returns the identifier of the entity with the given name
+func entityID(name string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), "-")
+}
+
+// normalizeLabel turns a type or predicate into a lowercase identifier
+// such as "works_for"
+func normalizeLabel(label string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(label), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), "_")
+}
+
+func containsString(items []string, item string) bool {
+	for _, existing := range items {
+		if existing == item {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonLDGraph is the JSON-LD document a knowledge graph is saved as.
+// Relations are reified as nodes so predicates can be any label.
+type jsonLDGraph struct {
+	Context map[string]interface{} `json:"@context"`
+	Graph   []jsonLDNode           `json:"@graph"`
+}
+
+type jsonLDNode struct {
+	ID        string   `json:"@id,omitempty"`
+	Type      string   `json:"@type"`
+	Name      string   `json:"name,omitempty"`
+	Category  string   `json:"category,omitempty"`
+	Subject   string   `json:"subject,omitempty"`
+	Predicate string   `json:"predicate,omitempty"`
+	Object    string   `json:"object,omitempty"`
+	Sources   []string `json:"source,omitempty"`
+}
+
+// Save writes the graph to path as JSON-LD
+func (g *KnowledgeGraph) Save(path string) error {
+	doc := jsonLDGraph{
+		Context: map[string]interface{}{
+			"@vocab":  graphVocabulary,
+			"name":    "http://schema.org/name",
+			"subject": map[string]string{"@type": "@id"},
+			"object":  map[string]string{"@type": "@id"},
+		},
+	}
+	for _, entity := range g.Entities() {
+		doc.Graph = append(doc.Graph, jsonLDNode{
+			ID:       "_:" + entity.ID,
+			Type:     "Entity",
+			Name:     entity.Name,
+			Category: entity.Type,
+		})
+	}
+	for _, relation := range g.relations {
+		doc.Graph = append(doc.Graph, jsonLDNode{
+			Type:      "Relation",
+			Subject:   "_:" + relation.Subject,
+			Predicate: relation.Predicate,
+			Object:    "_:" + relation.Object,
+			Sources:   relation.Sources,
+		})
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal graph: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write graph: %w", err)
+	}
+	return nil
+}
+
+// LoadKnowledgeGraph reads a graph saved by Save
+func LoadKnowledgeGraph(path string) (*KnowledgeGraph, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read graph: %w", err)
+	}
+
+	var doc jsonLDGraph
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid graph %s: %w", path, err)
+	}
+
+	graph := NewKnowledgeGraph()
+	names := make(map[string]string)
+	for _, node := range doc.Graph {
+		if node.Type == "Entity" {
+			graph.AddEntity(node.Name, node.Category)
+			names[node.ID] = node.Name
+		}
+	}
+	for _, node := range doc.Graph {
+		if node.Type != "Relation" {
+			continue
+		}
+		subject, object := names[node.Subject], names[node.Object]
+		if subject == "" || object == "" {
+			return nil, fmt.Errorf("invalid graph %s: relation %s refers to an unknown entity", path, node.Predicate)
+		}
+		if len(node.Sources) == 0 {
+			graph.AddRelation(subject, node.Predicate, object, "")
+		}
+		for _, source := range node.Sources {
+			graph.AddRelation(subject, node.Predicate, object, source)
+		}
+	}
+	return graph, nil
+}
+
+// GraphBuildOptions represents options for building a knowledge graph
+type GraphBuildOptions struct {
+	InputDir   string
+	OutputPath string
+	ChunkSize  int
+}
+
+// KnowledgeGraphBuilder extracts entities and relations from documents with
+// a local model
+type KnowledgeGraphBuilder struct {
+	modelManager *LocalLLMManager
+}
+
+// NewKnowledgeGraphBuilder creates a new knowledge graph builder
+func NewKnowledgeGraphBuilder() *KnowledgeGraphBuilder {
+	return &KnowledgeGraphBuilder{
+		modelManager: NewLocalLLMManager(),
+	}
+}
+
+// graphExtraction is the model's reply for one batch of text
+type graphExtraction struct {
+	Entities []struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+	} `json:"entities"`
+	Relations []struct {
+		Subject   string `json:"subject"`
+		Predicate string `json:"predicate"`
+		Object    string `json:"object"`
+	} `json:"relations"`
+}
+
+// Build reads the text files under opts.InputDir, extracts entities and
+// relations from each batch of text, and writes the combined graph to
+// opts.OutputPath
+func (b *KnowledgeGraphBuilder) Build(model string, opts GraphBuildOptions) (*KnowledgeGraph, error) {
+	if opts.ChunkSize <= 0 {
+		opts.ChunkSize = defaultGraphChunkSize
+	}
+
+	files, err := findGraphDocuments(opts.InputDir)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no text files found in %s", opts.InputDir)
+	}
+
+	if err := b.modelManager.CheckOllamaAvailability(); err != nil {
+		return nil, err
+	}
+
+	graph := NewKnowledgeGraph()
+	failures := 0
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		source, _ := filepath.Rel(opts.InputDir, file)
+
+		chunks := chunkText(string(data), opts.ChunkSize)
+		for i, chunk := range chunks {
+			if strings.TrimSpace(chunk) == "" {
+				continue
+			}
+			fmt.Printf("📄 %s (%d/%d)\n", source, i+1, len(chunks))
+
+			extraction, err := b.extract(model, chunk)
+			if err != nil {
+				failures++
+				log.Warn("extraction failed", "file", source, "batch", i+1, "error", err)
+				continue
+			}
+
+			for _, entity := range extraction.Entities {
+				if strings.TrimSpace(entity.Name) != "" {
+					graph.AddEntity(entity.Name, entity.Type)
+				}
+			}
+			for _, relation := range extraction.Relations {
+				if strings.TrimSpace(relation.Subject) == "" || strings.TrimSpace(relation.Object) == "" {
+					continue
+				}
+				graph.AddRelation(relation.Subject, relation.Predicate, relation.Object, source)
+			}
+		}
+	}
+
+	if len(graph.entities) == 0 {
+		return nil, fmt.Errorf("no entities extracted (%d batches failed)", failures)
+	}
+
+	if err := graph.Save(opts.OutputPath); err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("✅ Wrote %d entities and %d relations to %s (%d batches failed)\n",
+		len(graph.entities), len(graph.relations), opts.OutputPath, failures)
+	return graph, nil
+}
+
+// extract asks the model for the entities and relations in text
+func (b *KnowledgeGraphBuilder) extract(model, text string) (*graphExtraction, error) {
+	prompt := `Extract the entities and the relations between them from the text below.
+Entities are people, organizations, places, products, concepts and other named things; give each a short lowercase type such as person, organization or location.
+Relations connect two entities with a short predicate such as works_for, located_in or part_of. Only use entities that appear in the text.
+Respond with only a JSON object of the form {"entities": [{"name": "...", "type": "..."}], "relations": [{"subject": "...", "predicate": "...", "object": "..."}]}.
+
+Text:
+` + text
+
+	resp, err := b.modelManager.Generate(GenerateRequest{
+		Model:  model,
+		Prompt: prompt,
+		Options: map[string]interface{}{
+			"temperature": 0.1,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var extraction graphExtraction
+	if err := json.Unmarshal([]byte(extractJSONObject(resp.Response)), &extraction); err != nil {
+		return nil, fmt.Errorf("model returned invalid JSON: %v", err)
+	}
+	return &extraction, nil
+}
+
+// findGraphDocuments returns the text files under dir, sorted by path
+func findGraphDocuments(dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if graphFileExtensions[strings.ToLower(filepath.Ext(path))] {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read input directory: %w", err)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// GraphQuery is a traversal of a knowledge graph: starting from Entities,
+// follow up to Depth relations, optionally only those with one of
+// Predicates, and report the entities reached that have one of Types
+type GraphQuery struct {
+	Entities   []string `json:"entities"`
+	Predicates []string `json:"predicates"`
+	Types      []string `json:"types"`
+	Depth      int      `json:"depth"`
+}
+
+// GraphMatch is an entity found by a query and the relations leading to it
+// from a starting entity
+type GraphMatch struct {
+	Start  *GraphEntity
+	Entity *GraphEntity
+	Path   []*GraphRelation
+}
+
+// TranslateQuery turns a natural language question into a graph
+// traversal. With a model, the model picks the starting entities,
+// predicates and types from those in the graph; without one, entities and
+// types named in the question are used.
+func (b *KnowledgeGraphBuilder) TranslateQuery(model, question string, graph *KnowledgeGraph) (*GraphQuery, error) {
+	if model == "" {
+		return keywordQuery(question, graph), nil
+	}
+
+	var names, types, predicates []string
+	seenTypes, seenPredicates := make(map[string]bool), make(map[string]bool)
+	for _, entity := range graph.Entities() {
+		names = append(names, entity.Name)
+		if entity.Type != "" && !seenTypes[entity.Type] {
+			seenTypes[entity.Type] = true
+			types = append(types, entity.Type)
+		}
+	}
+	for _, relation := range graph.relations {
+		if !seenPredicates[relation.Predicate] {
+			seenPredicates[relation.Predicate] = true
+			predicates = append(predicates, relation.Predicate)
+		}
+	}
+
+	prompt := fmt.Sprintf(`Translate the question into a traversal of a knowledge graph.
+Entities: %s
+Entity types: %s
+Predicates: %s
+
+Question: %s
+
+Respond with only a JSON object of the form {"entities": [...], "predicates": [...], "types": [...], "depth": n}, where entities are the entities the question starts from, predicates are the relations to follow (empty for any), types are the types of the entities asked for (empty for any), and depth is how many relations to follow (1-3). Use only names from the lists above.`,
+		strings.Join(names, "; "), strings.Join(types, ", "), strings.Join(predicates, ", "), question)
+
+	resp, err := b.modelManager.Generate(GenerateRequest{
+		Model:  model,
+		Prompt: prompt,
+		Options: map[string]interface{}{
+			"temperature": 0,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var query GraphQuery
+	if err := json.Unmarshal([]byte(extractJSONObject(resp.Response)), &query); err != nil {
+		return nil, fmt.Errorf("model returned invalid JSON: %v", err)
+	}
+	return &query, nil
+}
+
+// keywordQuery starts from the entities whose names appear in question and
+// looks for the entity types it mentions
+func keywordQuery(question string, graph *KnowledgeGraph) *GraphQuery {
+	text := " " + strings.Join(strings.FieldsFunc(strings.ToLower(question), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ") + " "
+
+	query := &GraphQuery{}
+	seenTypes := make(map[string]bool)
+	for _, entity := range graph.Entities() {
+		if strings.Contains(text, " "+strings.ReplaceAll(entity.ID, "-", " ")+" ") {
+			query.Entities = append(query.Entities, entity.Name)
+		}
+		if entity.Type == "" || seenTypes[entity.Type] {
+			continue
+		}
+		word := strings.ReplaceAll(entity.Type, "_", " ")
+		plural := word + "s"
+		if entity.Type == "person" {
+			plural = "people"
+		}
+		if strings.Contains(text, " "+word+" ") || strings.Contains(text, " "+plural+" ") {
+			seenTypes[entity.Type] = true
+			query.Types = append(query.Types, entity.Type)
+		}
+	}
+	return query
+}
+
+// Query runs a traversal, following relations in both directions. Each
+// entity is reported once per starting entity, with the shortest path to
+// it.
+func (g *KnowledgeGraph) Query(query *GraphQuery) ([]GraphMatch, error) {
+	depth := query.Depth
+	if depth <= 0 {
+		depth = defaultGraphDepth
+	}
+
+	var starts []*GraphEntity
+	for _, name := range query.Entities {
+		entity, ok := g.Entity(name)
+		if !ok {
+			log.Debug("query entity not in graph", "entity", name)
+			continue
+		}
+		starts = append(starts, entity)
+	}
+	if len(starts) == 0 {
+		return nil, fmt.Errorf("none of the entities in the query are in the graph")
+	}
+
+	predicates := make(map[string]bool)
+	for _, predicate := range query.Predicates {
+		predicates[normalizeLabel(predicate)] = true
+	}
+	types := make(map[string]bool)
+	for _, entityType := range query.Types {
+		types[normalizeLabel(entityType)] = true
+	}
+
+	var matches []GraphMatch
+	for _, start := range starts {
+		// Breadth-first search, remembering the relation each entity was
+		// reached through
+		via := map[string]*GraphRelation{start.ID: nil}
+		frontier := []string{start.ID}
+		for level := 0; level < depth && len(frontier) > 0; level++ {
+			var next []string
+			for _, id := range frontier {
+				for _, relation := range g.relations {
+					if len(predicates) > 0 && !predicates[relation.Predicate] {
+						continue
+					}
+					var neighbor string
+					switch id {
+					case relation.Subject:
+						neighbor = relation.Object
+					case relation.Object:
+						neighbor = relation.Subject
+					default:
+						continue
+					}
+					if _, seen := via[neighbor]; seen {
+						continue
+					}
+					via[neighbor] = relation
+					next = append(next, neighbor)
+
+					entity := g.entities[neighbor]
+					if len(types) == 0 || types[entity.Type] {
+						matches = append(matches, GraphMatch{Start: start, Entity: entity, Path: pathTo(neighbor, via)})
+					}
+				}
+			}
+			frontier = next
+		}
+	}
+	return matches, nil
+}
+
+// pathTo follows the relations in via back from id to the start of the
+// search and returns them in order from the start
+func pathTo(id string, via map[string]*GraphRelation) []*GraphRelation {
+	var path []*GraphRelation
+	for relation := via[id]; relation != nil; relation = via[id] {
+		path = append([]*GraphRelation{relation}, path...)
+		if relation.Object == id {
+			id = relation.Subject
+		} else {
+			id = relation.Object
+		}
+	}
+	return path
+}
+
+// FormatRelation returns relation as "Subject --predicate--> Object"
+func (g *KnowledgeGraph) FormatRelation(relation *GraphRelation) string {
+	return fmt.Sprintf("%s --%s--> %s", g.entities[relation.Subject].Name, relation.Predicate, g.entities[relation.Object].Name)
+}
@@ -0,0 +1,168 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pxkundu/agent-as-code/internal/log"
+)
+
+// benchmarkFileSuffix is the suffix of saved benchmark result files
+const benchmarkFileSuffix = ".benchmark.json"
+
+// Leaderboard aggregates saved benchmark results across runs
+type Leaderboard struct{}
+
+// MetricStats holds the min, max and average of a metric across runs
+type MetricStats struct {
+	Min float64
+	Max float64
+	Avg float64
+}
+
+// ModelStats summarizes all benchmark runs of a model
+type ModelStats struct {
+	ModelName      string
+	Runs           int
+	ResponseTime   MetricStats // seconds
+	QualityScore   MetricStats // percent
+	Efficiency     float64     // average quality percent per second
+	CompositeScore float64
+}
+
+// NewLeaderboard creates a new leaderboard
+func NewLeaderboard() *Leaderboard {
+	return &Leaderboard{}
+}
+
+// DefaultBenchmarkDir returns the directory benchmark results are saved in
+func DefaultBenchmarkDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %v", err)
+	}
+	return filepath.Join(home, ".agent", "benchmarks"), nil
+}
+
+// Load reads every benchmark file in dir and groups the runs by model
+func (l *Leaderboard) Load(dir string) ([]ModelStats, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*"+benchmarkFileSuffix))
+	if err != nil {
+		return nil, err
+	}
+
+	responseTimes := make(map[string][]float64)
+	qualityScores := make(map[string][]float64)
+	runs := make(map[string]int)
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", file, err)
+		}
+
+		var result BenchmarkResult
+		if err := json.Unmarshal(data, &result); err != nil {
+			log.Warn("skipping invalid benchmark file", "file", filepath.Base(file), "error", err)
+			continue
+		}
+
+		runs[result.ModelName]++
+		if seconds, err := parseBenchmarkValue(result.AverageResponseTime, "s"); err == nil {
+			responseTimes[result.ModelName] = append(responseTimes[result.ModelName], seconds)
+		}
+		if quality, err := parseBenchmarkValue(result.QualityScore, "%"); err == nil {
+			qualityScores[result.ModelName] = append(qualityScores[result.ModelName], quality)
+		}
+	}
+
+	var stats []ModelStats
+	for model, count := range runs {
+		s := ModelStats{
+			ModelName:    model,
+			Runs:         count,
+			ResponseTime: summarize(responseTimes[model]),
+			QualityScore: summarize(qualityScores[model]),
+		}
+		if s.ResponseTime.Avg > 0 {
+			s.Efficiency = s.QualityScore.Avg / s.ResponseTime.Avg
+		}
+		// Weight quality over speed; speed maps response time into (0, 1]
+		speed := 1 / (1 + s.ResponseTime.Avg)
+		s.CompositeScore = 0.6*(s.QualityScore.Avg/100) + 0.4*speed
+		stats = append(stats, s)
+	}
+
+	if err := l.Sort(stats, "score"); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// Sort orders stats best first by score, speed, quality or efficiency
+func (l *Leaderboard) Sort(stats []ModelStats, sortBy string) error {
+	var less func(a, b ModelStats) bool
+	switch sortBy {
+	case "score", "":
+		less = func(a, b ModelStats) bool { return a.CompositeScore > b.CompositeScore }
+	case "speed":
+		less = func(a, b ModelStats) bool { return a.ResponseTime.Avg < b.ResponseTime.Avg }
+	case "quality":
+		less = func(a, b ModelStats) bool { return a.QualityScore.Avg > b.QualityScore.Avg }
+	case "efficiency":
+		less = func(a, b ModelStats) bool { return a.Efficiency > b.Efficiency }
+	default:
+		return fmt.Errorf("invalid sort key '%s' (valid: score, speed, quality, efficiency)", sortBy)
+	}
+
+	// Sort by name first so ties are ordered deterministically
+	sort.Slice(stats, func(i, j int) bool { return stats[i].ModelName < stats[j].ModelName })
+	sort.SliceStable(stats, func(i, j int) bool { return less(stats[i], stats[j]) })
+	return nil
+}
+
+// Reset deletes all benchmark files in dir and returns how many were removed
+func (l *Leaderboard) Reset(dir string) (int, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*"+benchmarkFileSuffix))
+	if err != nil {
+		return 0, err
+	}
+
+	for _, file := range files {
+		if err := os.Remove(file); err != nil {
+			return 0, fmt.Errorf("failed to remove %s: %v", file, err)
+		}
+	}
+
+	return len(files), nil
+}
+
+// parseBenchmarkValue parses values such as "1.23s" or "87.5%"
+func parseBenchmarkValue(value, suffix string) (float64, error) {
+	return strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(value), suffix), 64)
+}
+
+// summarize computes min, max and average of values
+func summarize(values []float64) MetricStats {
+	if len(values) == 0 {
+		return MetricStats{}
+	}
+
+	stats := MetricStats{Min: math.Inf(1), Max: math.Inf(-1)}
+	total := 0.0
+	for _, v := range values {
+		stats.Min = math.Min(stats.Min, v)
+		stats.Max = math.Max(stats.Max, v)
+		total += v
+	}
+	stats.Avg = total / float64(len(values))
+
+	return stats
+}
@@ -1,13 +1,22 @@
 package llm
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
+	"path"
+	"sort"
 	"strings"
 	"time"
+
+	"github.com/pxkundu/agent-as-code/internal/log"
 )
 
 // LocalLLMManager handles local LLM operations
@@ -18,13 +27,51 @@ type LocalLLMManager struct {
 
 // LocalModel represents a local LLM model
 type LocalModel struct {
-	Name        string            `json:"name"`
-	Size        string            `json:"size"`
-	ModifiedAt  string            `json:"modified_at"`
-	Digest      string            `json:"digest"`
-	Details     map[string]string `json:"details,omitempty"`
-	Backend     string            `json:"backend"`
-	Status      string            `json:"status"`
+	Name        string                 `json:"name"`
+	Size        string                 `json:"-"`
+	SizeBytes   int64                  `json:"size"`
+	ModifiedAt  string                 `json:"modified_at"`
+	Digest      string                 `json:"digest"`
+	Details     map[string]interface{} `json:"details,omitempty"`
+	Backend     string                 `json:"backend"`
+	Status      string                 `json:"status"`
+
+	// Filled from /api/show by ShowModel callers
+	ModelfileContent string `json:"modelfile,omitempty"`
+	License          string `json:"license,omitempty"`
+	Template         string `json:"template,omitempty"`
+	System           string `json:"system,omitempty"`
+	Parameters       string `json:"parameters,omitempty"`
+}
+
+// OllamaShowResponse is the Ollama /api/show response
+type OllamaShowResponse struct {
+	Modelfile  string `json:"modelfile"`
+	Parameters string `json:"parameters"`
+	Template   string `json:"template"`
+	System     string `json:"system"`
+	License    string `json:"license"`
+	Details    struct {
+		ParentModel       string   `json:"parent_model"`
+		Format            string   `json:"format"`
+		Family            string   `json:"family"`
+		Families          []string `json:"families"`
+		ParameterSize     string   `json:"parameter_size"`
+		QuantizationLevel string   `json:"quantization_level"`
+	} `json:"details"`
+	ModelInfo map[string]interface{} `json:"model_info,omitempty"`
+}
+
+// ModelPath returns the model file the Modelfile's FROM line points to, or
+// "" if it does not name a local file
+func (r *OllamaShowResponse) ModelPath() string {
+	for _, line := range strings.Split(r.Modelfile, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && strings.EqualFold(fields[0], "FROM") && strings.HasPrefix(fields[1], "/") {
+			return fields[1]
+		}
+	}
+	return ""
 }
 
 // LocalModelResponse represents Ollama API response
@@ -32,14 +79,118 @@ type LocalModelResponse struct {
 	Models []LocalModel `json:"models"`
 }
 
-// NewLocalLLMManager creates a new local LLM manager
+// GenerateRequest represents an Ollama generate request
+type GenerateRequest struct {
+	Model   string                 `json:"model"`
+	Prompt  string                 `json:"prompt"`
+	System  string                 `json:"system,omitempty"`
+	Raw     bool                   `json:"raw,omitempty"`
+	Format  string                 `json:"format,omitempty"` // "json" constrains the response to JSON
+	Stream  bool                   `json:"stream"`
+	Options map[string]interface{} `json:"options,omitempty"`
+}
+
+// GenerateResponse represents an Ollama generate response
+type GenerateResponse struct {
+	Model              string `json:"model"`
+	Response           string `json:"response"`
+	Done               bool   `json:"done"`
+	TotalDuration      int64  `json:"total_duration"`
+	LoadDuration       int64  `json:"load_duration"`
+	PromptEvalCount    int    `json:"prompt_eval_count"`
+	PromptEvalDuration int64  `json:"prompt_eval_duration"`
+	EvalCount          int    `json:"eval_count"`
+	EvalDuration       int64  `json:"eval_duration"`
+}
+
+// Message is a single turn in a chat conversation
+type Message struct {
+	Role    string `json:"role"` // system, user, assistant or tool
+	Content string `json:"content"`
+
+	// ToolCalls are the tools an assistant message asks to run
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+	// ToolName is the tool whose result a tool message carries
+	ToolName string `json:"tool_name,omitempty"`
+}
+
+// ChatRequest represents an Ollama chat request
+type ChatRequest struct {
+	Model    string                 `json:"model"`
+	Messages []Message              `json:"messages"`
+	Tools    []Tool                 `json:"tools,omitempty"`
+	Stream   bool                   `json:"stream"`
+	Options  map[string]interface{} `json:"options,omitempty"`
+}
+
+// ChatResponse represents an Ollama chat response
+type ChatResponse struct {
+	Model           string  `json:"model"`
+	Message         Message `json:"message"`
+	Done            bool    `json:"done"`
+	TotalDuration   int64   `json:"total_duration"`
+	PromptEvalCount int     `json:"prompt_eval_count"`
+	EvalCount       int     `json:"eval_count"`
+	EvalDuration    int64   `json:"eval_duration"`
+}
+
+// RunningModel represents a model currently loaded by Ollama
+type RunningModel struct {
+	Name      string `json:"name"`
+	Model     string `json:"model"`
+	Size      int64  `json:"size"`
+	SizeVRAM  int64  `json:"size_vram"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+// generateTimeout bounds a single inference request, which can take far
+// longer than the metadata calls covered by LocalLLMManager.timeout
+const generateTimeout = 5 * time.Minute
+
+// NewLocalLLMManager creates a new local LLM manager for the Ollama endpoint
+// in OLLAMA_HOST, or http://localhost:11434
 func NewLocalLLMManager() *LocalLLMManager {
 	return &LocalLLMManager{
-		ollamaURL: "http://localhost:11434",
+		ollamaURL: ollamaURLFromEnv(),
 		timeout:   30 * time.Second,
 	}
 }
 
+// ollamaURLFromEnv returns the Ollama endpoint set in OLLAMA_HOST, which,
+// as for the ollama CLI, may be a host, host:port or a URL. This is also how
+// tests point the manager at llm mock-server.
+func ollamaURLFromEnv() string {
+	host := strings.TrimSpace(os.Getenv("OLLAMA_HOST"))
+	if host == "" {
+		return defaultOllamaURL
+	}
+	if !strings.Contains(host, "://") {
+		host = "http://" + host
+	}
+
+	u, err := url.Parse(host)
+	if err != nil || u.Hostname() == "" {
+		log.Warn("ignoring invalid OLLAMA_HOST", "value", host)
+		return defaultOllamaURL
+	}
+
+	hostname := u.Hostname()
+	if hostname == "0.0.0.0" {
+		hostname = "localhost"
+	}
+	port := u.Port()
+	if port == "" {
+		port = "11434"
+	}
+	u.Host = net.JoinHostPort(hostname, port)
+	return strings.TrimRight(u.String(), "/")
+}
+
+// URL returns the Ollama endpoint the manager talks to
+func (m *LocalLLMManager) URL() string {
+	return m.ollamaURL
+}
+
 // CheckOllamaAvailability checks if Ollama is running
 func (m *LocalLLMManager) CheckOllamaAvailability() error {
 	client := &http.Client{Timeout: m.timeout}
@@ -79,18 +230,79 @@ func (m *LocalLLMManager) ListLocalModels() ([]LocalModel, error) {
 	for i := range modelResp.Models {
 		modelResp.Models[i].Backend = "ollama"
 		modelResp.Models[i].Status = "available"
+		modelResp.Models[i].Size = formatSize(modelResp.Models[i].SizeBytes)
 	}
 	
 	return modelResp.Models, nil
 }
 
+// ModelFamily returns the model name without its tag (e.g. llama2 for llama2:7b)
+func ModelFamily(name string) string {
+	family, _, _ := strings.Cut(name, ":")
+	return family
+}
+
+// GroupModels groups models by "family". Any other value puts all models in
+// a single group with an empty key.
+func GroupModels(models []LocalModel, by string) map[string][]LocalModel {
+	groups := make(map[string][]LocalModel)
+	for _, model := range models {
+		key := ""
+		switch by {
+		case "family":
+			key = ModelFamily(model.Name)
+		}
+		groups[key] = append(groups[key], model)
+	}
+	return groups
+}
+
+// SortModels sorts models by name, size (largest first) or date (newest first)
+func SortModels(models []LocalModel, by string) error {
+	var less func(a, b LocalModel) bool
+	switch by {
+	case "name", "":
+		less = func(a, b LocalModel) bool { return a.Name < b.Name }
+	case "size":
+		less = func(a, b LocalModel) bool { return a.SizeBytes > b.SizeBytes }
+	case "date":
+		less = func(a, b LocalModel) bool { return modifiedTime(a).After(modifiedTime(b)) }
+	default:
+		return fmt.Errorf("invalid sort key '%s' (valid: name, size, date)", by)
+	}
+
+	sort.SliceStable(models, func(i, j int) bool { return less(models[i], models[j]) })
+	return nil
+}
+
+// modifiedTime parses a model's modification time, returning the zero time
+// if it is missing or malformed
+func modifiedTime(model LocalModel) time.Time {
+	t, _ := time.Parse(time.RFC3339Nano, model.ModifiedAt)
+	return t
+}
+
+// formatSize formats a byte count for display
+func formatSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
 // PullModel pulls a model from Ollama
 func (m *LocalLLMManager) PullModel(modelName string) error {
 	if err := m.CheckOllamaAvailability(); err != nil {
 		return err
 	}
 	
-	fmt.Printf("📥 Pulling model: %s\n", modelName)
+	log.Info("pulling model", "model", modelName)
 	
 	// Use ollama CLI to pull the model
 	cmd := exec.Command("ollama", "pull", modelName)
@@ -101,7 +313,7 @@ func (m *LocalLLMManager) PullModel(modelName string) error {
 		return fmt.Errorf("failed to pull model '%s': %v", modelName, err)
 	}
 	
-	fmt.Printf("✅ Model '%s' pulled successfully\n", modelName)
+	log.Info("model pulled", "model", modelName)
 	return nil
 }
 
@@ -111,24 +323,82 @@ func (m *LocalLLMManager) RemoveModel(modelName string) error {
 		return err
 	}
 	
-	fmt.Printf("🗑️  Removing model: %s\n", modelName)
+	log.Info("removing model", "model", modelName)
 	
 	cmd := exec.Command("ollama", "rm", modelName)
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("failed to remove model '%s': %v", modelName, err)
 	}
 	
-	fmt.Printf("✅ Model '%s' removed successfully\n", modelName)
+	log.Info("model removed", "model", modelName)
 	return nil
 }
 
+// RemoveReport is the result of removing several models
+type RemoveReport struct {
+	Removed []string
+	Failed  []string
+}
+
+// RemoveAllModels removes every local model
+func (m *LocalLLMManager) RemoveAllModels() (*RemoveReport, error) {
+	return m.RemoveMatchingModels("*")
+}
+
+// RemoveMatchingModels removes the local models whose names match a glob
+// pattern such as llama2:* and returns which were removed. It continues
+// past failures and returns them joined in the error.
+func (m *LocalLLMManager) RemoveMatchingModels(pattern string) (*RemoveReport, error) {
+	names, err := m.MatchModels(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return m.RemoveModels(names)
+}
+
+// MatchModels returns the names of the local models matching a glob pattern
+func (m *LocalLLMManager) MatchModels(pattern string) ([]string, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid pattern '%s': %v", pattern, err)
+	}
+
+	models, err := m.ListLocalModels()
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, model := range models {
+		if matched, _ := path.Match(pattern, model.Name); matched {
+			names = append(names, model.Name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// RemoveModels removes models in sequence, collecting the failures
+func (m *LocalLLMManager) RemoveModels(names []string) (*RemoveReport, error) {
+	report := &RemoveReport{}
+	var errs []error
+	for _, name := range names {
+		if err := m.RemoveModel(name); err != nil {
+			report.Failed = append(report.Failed, name)
+			errs = append(errs, err)
+			continue
+		}
+		report.Removed = append(report.Removed, name)
+	}
+	return report, errors.Join(errs...)
+}
+
 // TestModel tests if a local model is working
 func (m *LocalLLMManager) TestModel(modelName string) error {
 	if err := m.CheckOllamaAvailability(); err != nil {
 		return err
 	}
 	
-	fmt.Printf("🧪 Testing model: %s\n", modelName)
+	log.Info("testing model", "model", modelName)
 	
 	// Simple test prompt
 	testPrompt := "Hello, this is a test. Please respond with 'Test successful' if you can see this message."
@@ -162,6 +432,34 @@ func (m *LocalLLMManager) GetModelInfo(modelName string) (*LocalModel, error) {
 	return nil, fmt.Errorf("model '%s' not found", modelName)
 }
 
+// ShowModel returns the Modelfile, parameters, template and details of a
+// local model
+func (m *LocalLLMManager) ShowModel(modelName string) (*OllamaShowResponse, error) {
+	body, err := json.Marshal(map[string]string{"name": modelName})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %v", err)
+	}
+
+	client := &http.Client{Timeout: m.timeout}
+	resp, err := client.Post(fmt.Sprintf("%s/api/show", m.ollamaURL), "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to show model: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("Ollama returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	var showResp OllamaShowResponse
+	if err := json.NewDecoder(resp.Body).Decode(&showResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	return &showResp, nil
+}
+
 // IsModelAvailable checks if a specific model is available
 func (m *LocalLLMManager) IsModelAvailable(modelName string) bool {
 	_, err := m.GetModelInfo(modelName)
@@ -208,6 +506,252 @@ func (m *LocalLLMManager) GetModelSize(modelName string) string {
 	return info.Size
 }
 
+// Generate runs a single non-streaming generation request against Ollama
+func (m *LocalLLMManager) Generate(req GenerateRequest) (*GenerateResponse, error) {
+	req.Stream = false
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %v", err)
+	}
+
+	client := &http.Client{Timeout: generateTimeout}
+	resp, err := client.Post(fmt.Sprintf("%s/api/generate", m.ollamaURL), "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to call Ollama: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("Ollama returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	var genResp GenerateResponse
+	if err := json.NewDecoder(resp.Body).Decode(&genResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	return &genResp, nil
+}
+
+// StreamGenerate runs a streaming generation request against Ollama,
+// calling onChunk for every partial response. It returns the final chunk,
+// which carries the token counts and durations.
+func (m *LocalLLMManager) StreamGenerate(req GenerateRequest, onChunk func(GenerateResponse) error) (*GenerateResponse, error) {
+	req.Stream = true
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %v", err)
+	}
+
+	client := &http.Client{Timeout: generateTimeout}
+	resp, err := client.Post(fmt.Sprintf("%s/api/generate", m.ollamaURL), "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to call Ollama: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("Ollama returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		var chunk struct {
+			GenerateResponse
+			Error string `json:"error"`
+		}
+		if err := decoder.Decode(&chunk); err != nil {
+			if err == io.EOF {
+				return nil, fmt.Errorf("stream ended before the response was done")
+			}
+			return nil, fmt.Errorf("failed to decode response: %v", err)
+		}
+
+		if chunk.Error != "" {
+			return nil, fmt.Errorf("generation failed: %s", chunk.Error)
+		}
+
+		if onChunk != nil {
+			if err := onChunk(chunk.GenerateResponse); err != nil {
+				return nil, err
+			}
+		}
+
+		if chunk.Done {
+			return &chunk.GenerateResponse, nil
+		}
+	}
+}
+
+// StreamChat runs a streaming chat request against Ollama, calling onChunk
+// for every partial message. It returns the final chunk with the token
+// counts; its message content is the full reply, with the tool calls of
+// every chunk.
+func (m *LocalLLMManager) StreamChat(req ChatRequest, onChunk func(ChatResponse) error) (*ChatResponse, error) {
+	req.Stream = true
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %v", err)
+	}
+
+	client := &http.Client{Timeout: generateTimeout}
+	resp, err := client.Post(fmt.Sprintf("%s/api/chat", m.ollamaURL), "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to call Ollama: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("Ollama returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	var reply strings.Builder
+	var toolCalls []ToolCall
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		var chunk struct {
+			ChatResponse
+			Error string `json:"error"`
+		}
+		if err := decoder.Decode(&chunk); err != nil {
+			if err == io.EOF {
+				return nil, fmt.Errorf("stream ended before the response was done")
+			}
+			return nil, fmt.Errorf("failed to decode response: %v", err)
+		}
+
+		if chunk.Error != "" {
+			return nil, fmt.Errorf("chat failed: %s", chunk.Error)
+		}
+
+		reply.WriteString(chunk.Message.Content)
+		toolCalls = append(toolCalls, chunk.Message.ToolCalls...)
+		if onChunk != nil {
+			if err := onChunk(chunk.ChatResponse); err != nil {
+				return nil, err
+			}
+		}
+
+		if chunk.Done {
+			final := chunk.ChatResponse
+			final.Message = Message{Role: "assistant", Content: reply.String(), ToolCalls: toolCalls}
+			return &final, nil
+		}
+	}
+}
+
+// GetOllamaVersion returns the version of the running Ollama server. Older
+// servers without /api/version return an error.
+func (m *LocalLLMManager) GetOllamaVersion() (string, error) {
+	client := &http.Client{Timeout: m.timeout}
+	resp, err := client.Get(fmt.Sprintf("%s/api/version", m.ollamaURL))
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch version: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Ollama returned status %d", resp.StatusCode)
+	}
+
+	var versionResp struct {
+		Version string `json:"version"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&versionResp); err != nil {
+		return "", fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	return versionResp.Version, nil
+}
+
+// ListRunningModels lists the models currently loaded into memory
+func (m *LocalLLMManager) ListRunningModels() ([]RunningModel, error) {
+	client := &http.Client{Timeout: m.timeout}
+	resp, err := client.Get(fmt.Sprintf("%s/api/ps", m.ollamaURL))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch running models: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Ollama returned status %d", resp.StatusCode)
+	}
+
+	var psResp struct {
+		Models []RunningModel `json:"models"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&psResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	return psResp.Models, nil
+}
+
+// UnloadModel frees the memory of a running model by asking Ollama to
+// expire it now. The model stays installed and is loaded again by the next
+// request for it.
+func (m *LocalLLMManager) UnloadModel(modelName string) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"model":      modelName,
+		"keep_alive": 0,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %v", err)
+	}
+
+	client := &http.Client{Timeout: m.timeout}
+	resp, err := client.Post(fmt.Sprintf("%s/api/generate", m.ollamaURL), "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to call Ollama: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("Ollama returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	return nil
+}
+
+// Embed returns the embedding vector for text using the given model
+func (m *LocalLLMManager) Embed(modelName, text string) ([]float64, error) {
+	body, err := json.Marshal(map[string]string{
+		"model":  modelName,
+		"prompt": text,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %v", err)
+	}
+
+	client := &http.Client{Timeout: generateTimeout}
+	resp, err := client.Post(fmt.Sprintf("%s/api/embeddings", m.ollamaURL), "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to call Ollama: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("Ollama returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	var embedResp struct {
+		Embedding []float64 `json:"embedding"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&embedResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	return embedResp.Embedding, nil
+}
+
 // ValidateModelName validates if a model name is valid for Ollama
 func (m *LocalLLMManager) ValidateModelName(modelName string) error {
 	if modelName == "" {
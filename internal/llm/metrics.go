@@ -0,0 +1,205 @@
+package llm
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Evaluation metrics, each scoring an output against a reference from 0
+// to 1
+const (
+	MetricExact = "exact"
+	MetricF1    = "f1"
+	MetricRouge = "rouge"
+	MetricBLEU  = "bleu"
+)
+
+// evalMetrics maps each metric to its scoring function
+var evalMetrics = map[string]func(output, reference string) float64{
+	MetricExact: ExactMatch,
+	MetricF1:    TokenF1,
+	MetricRouge: RougeL,
+	MetricBLEU:  BLEU,
+}
+
+// EvalMetrics returns the valid evaluation metrics
+func EvalMetrics() []string {
+	metrics := make([]string, 0, len(evalMetrics))
+	for metric := range evalMetrics {
+		metrics = append(metrics, metric)
+	}
+	sort.Strings(metrics)
+	return metrics
+}
+
+// ExactMatch returns 1 if output and reference are equal ignoring case and
+// differences in whitespace, and 0 otherwise
+func ExactMatch(output, reference string) float64 {
+	normalize := func(s string) string {
+		return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+	}
+	if normalize(output) == normalize(reference) {
+		return 1
+	}
+	return 0
+}
+
+// TokenF1 returns the harmonic mean of the precision and recall of the
+// output's words against the reference's, counting repeated words
+func TokenF1(output, reference string) float64 {
+	outTokens := metricTokens(output)
+	refTokens := metricTokens(reference)
+	if len(outTokens) == 0 || len(refTokens) == 0 {
+		if len(outTokens) == len(refTokens) {
+			return 1
+		}
+		return 0
+	}
+
+	refCounts := make(map[string]int)
+	for _, token := range refTokens {
+		refCounts[token]++
+	}
+	common := 0
+	for _, token := range outTokens {
+		if refCounts[token] > 0 {
+			refCounts[token]--
+			common++
+		}
+	}
+
+	return fMeasure(common, len(outTokens), len(refTokens))
+}
+
+// RougeL returns the ROUGE-L F-measure: precision and recall of the longest
+// common subsequence of the output's and the reference's words
+func RougeL(output, reference string) float64 {
+	outTokens := metricTokens(output)
+	refTokens := metricTokens(reference)
+	if len(outTokens) == 0 || len(refTokens) == 0 {
+		if len(outTokens) == len(refTokens) {
+			return 1
+		}
+		return 0
+	}
+
+	return fMeasure(lcsLength(outTokens, refTokens), len(outTokens), len(refTokens))
+}
+
+// BLEU returns the smoothed BLEU-4 score of the output against the
+// reference
+func BLEU(output, reference string) float64 {
+	outTokens := metricTokens(output)
+	refTokens := metricTokens(reference)
+	if len(refTokens) == 0 {
+		if len(outTokens) == 0 {
+			return 1
+		}
+		return 0
+	}
+	return bleu(outTokens, [][]string{refTokens}, 4)
+}
+
+// metricTokens lowercases s and splits it into words, dropping punctuation
+func metricTokens(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// fMeasure returns the F1 score of common matches out of outputLen
+// predicted and referenceLen expected items
+func fMeasure(common, outputLen, referenceLen int) float64 {
+	if common == 0 {
+		return 0
+	}
+	precision := float64(common) / float64(outputLen)
+	recall := float64(common) / float64(referenceLen)
+	return 2 * precision * recall / (precision + recall)
+}
+
+// lcsLength returns the length of the longest common subsequence of a and b
+func lcsLength(a, b []string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			if a[i-1] == b[j-1] {
+				curr[j] = prev[j-1] + 1
+			} else if prev[j] >= curr[j-1] {
+				curr[j] = prev[j]
+			} else {
+				curr[j] = curr[j-1]
+			}
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// bleu computes a smoothed BLEU score up to maxN-grams with brevity penalty
+func bleu(hypothesis []string, references [][]string, maxN int) float64 {
+	if len(hypothesis) == 0 {
+		return 0
+	}
+
+	logSum := 0.0
+	for n := 1; n <= maxN; n++ {
+		hypCounts := ngramCounts(hypothesis, n)
+		maxRefCounts := make(map[string]int)
+		for _, reference := range references {
+			for gram, count := range ngramCounts(reference, n) {
+				if count > maxRefCounts[gram] {
+					maxRefCounts[gram] = count
+				}
+			}
+		}
+
+		matches, total := 0, 0
+		for gram, count := range hypCounts {
+			total += count
+			if ref := maxRefCounts[gram]; ref > 0 {
+				if ref < count {
+					matches += ref
+				} else {
+					matches += count
+				}
+			}
+		}
+
+		// Add-one smoothing keeps short responses from scoring zero
+		logSum += math.Log(float64(matches+1) / float64(total+1))
+	}
+
+	// Brevity penalty against the closest reference length
+	closest := 0
+	for _, reference := range references {
+		if closest == 0 || abs(len(reference)-len(hypothesis)) < abs(closest-len(hypothesis)) {
+			closest = len(reference)
+		}
+	}
+	penalty := 1.0
+	if len(hypothesis) < closest {
+		penalty = math.Exp(1 - float64(closest)/float64(len(hypothesis)))
+	}
+
+	return penalty * math.Exp(logSum/float64(maxN))
+}
+
+// ngramCounts counts the n-grams in tokens
+func ngramCounts(tokens []string, n int) map[string]int {
+	counts := make(map[string]int)
+	for i := 0; i+n <= len(tokens); i++ {
+		counts[strings.Join(tokens[i:i+n], " ")]++
+	}
+	return counts
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,409 @@
+package llm
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultOllamaURL is the Ollama endpoint migrated agents are configured with
+const defaultOllamaURL = "http://localhost:11434"
+
+// httpxRequirement replaces the openai package in migrated agents
+const httpxRequirement = "httpx==0.25.2"
+
+// Migrator converts agents built for a hosted provider to a local model
+type Migrator struct{}
+
+// MigrateOptions represents migration options
+type MigrateOptions struct {
+	From      string // Current model, e.g. openai/gpt-4
+	To        string // Local model, e.g. local/llama2
+	AgentDir  string
+	OllamaURL string // Defaults to http://localhost:11434
+}
+
+// FileChange is the old and new content of a file changed by a migration
+type FileChange struct {
+	Path string
+	Old  []byte
+	New  []byte
+}
+
+var (
+	openaiImportPattern = regexp.MustCompile(`(?m)^(import openai|from openai import [^\n]+)$`)
+	openaiClientPattern = regexp.MustCompile(`\b(?:openai\.)?(?:Async)?OpenAI\(`)
+	openaiLegacyPattern = regexp.MustCompile(`\bopenai\.ChatCompletion\.create\b`)
+	requirementPattern  = regexp.MustCompile(`^\s*([A-Za-z0-9_.\-]+)`)
+)
+
+// ollamaClientShim replaces the openai import in main.py. It provides the
+// subset of the OpenAI client used by agents (chat.completions.create) on top
+// of Ollama's /api/chat endpoint, so the rest of the code keeps working.
+const ollamaClientShim = `import os
+import httpx
+
+
+class _OllamaObject(dict):
+    """Dict that also allows attribute access, like OpenAI response objects"""
+    __getattr__ = dict.__getitem__
+
+
+class _OllamaCompletions:
+    def __init__(self, http):
+        self._http = http
+
+    def create(self, model, messages, max_tokens=None, temperature=None, **kwargs):
+        options = {}
+        if max_tokens is not None:
+            options["num_predict"] = max_tokens
+        if temperature is not None:
+            options["temperature"] = temperature
+        resp = self._http.post("/api/chat", json={
+            "model": model,
+            "messages": messages,
+            "stream": False,
+            "options": options,
+        })
+        resp.raise_for_status()
+        message = resp.json()["message"]
+        return _OllamaObject(choices=[_OllamaObject(
+            message=_OllamaObject(role=message["role"], content=message["content"]),
+        )])
+
+
+class OllamaClient:
+    """Minimal OpenAI-style client for Ollama's /api/chat endpoint"""
+
+    def __init__(self, base_url=None, timeout=120.0):
+        base_url = base_url or os.getenv("OLLAMA_BASE_URL", "http://localhost:11434")
+        self._http = httpx.Client(base_url=base_url, timeout=timeout)
+        self.chat = _OllamaObject(completions=_OllamaCompletions(self._http))`
+
+// NewMigrator creates a new migrator
+func NewMigrator() *Migrator {
+	return &Migrator{}
+}
+
+// Plan computes the changes needed to migrate the agent in options.AgentDir.
+// Only files whose content changes are returned; nothing is written.
+func (m *Migrator) Plan(options *MigrateOptions) ([]FileChange, error) {
+	fromProvider, fromModel := splitModelRef(options.From)
+	toProvider, toModel := splitModelRef(options.To)
+	if toProvider != "ollama" {
+		return nil, fmt.Errorf("can only migrate to a local model (local/<model>), got '%s'", options.To)
+	}
+	if fromModel == "" || toModel == "" {
+		return nil, fmt.Errorf("model names must not be empty")
+	}
+
+	ollamaURL := options.OllamaURL
+	if ollamaURL == "" {
+		ollamaURL = defaultOllamaURL
+	}
+
+	var changes []FileChange
+	addChange := func(name string, migrate func([]byte) ([]byte, error)) error {
+		path := filepath.Join(options.AgentDir, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		migrated, err := migrate(data)
+		if err != nil {
+			return fmt.Errorf("failed to migrate %s: %v", name, err)
+		}
+		if !bytes.Equal(data, migrated) {
+			changes = append(changes, FileChange{Path: path, Old: data, New: migrated})
+		}
+		return nil
+	}
+
+	err := addChange("agent.yaml", func(data []byte) ([]byte, error) {
+		return migrateAgentYAML(data, fromProvider, fromModel, toProvider, toModel, ollamaURL)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// requirements.txt and main.py are optional
+	err = addChange("requirements.txt", migrateRequirements)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	err = addChange("main.py", func(data []byte) ([]byte, error) {
+		return migrateMainPy(data, fromModel, toModel), nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// Apply writes the new content of every change, keeping file permissions
+func (m *Migrator) Apply(changes []FileChange) error {
+	for _, change := range changes {
+		mode := os.FileMode(0644)
+		if info, err := os.Stat(change.Path); err == nil {
+			mode = info.Mode().Perm()
+		}
+		if err := os.WriteFile(change.Path, change.New, mode); err != nil {
+			return fmt.Errorf("failed to write %s: %v", change.Path, err)
+		}
+	}
+	return nil
+}
+
+// Diff returns a unified diff of the change
+func (c FileChange) Diff() string {
+	diff, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(c.Old)),
+		B:        difflib.SplitLines(string(c.New)),
+		FromFile: "a/" + filepath.Base(c.Path),
+		ToFile:   "b/" + filepath.Base(c.Path),
+		Context:  3,
+	})
+	return diff
+}
+
+// splitModelRef splits a model reference like openai/gpt-4 into provider and
+// model name. "local" is an alias for the ollama provider, as in agent init.
+func splitModelRef(ref string) (provider, name string) {
+	provider, name, ok := strings.Cut(ref, "/")
+	if !ok {
+		return "openai", ref
+	}
+	if provider == "local" {
+		provider = "ollama"
+	}
+	return provider, name
+}
+
+// migrateAgentYAML switches spec.model to the local model, points the agent
+// at Ollama and drops the OpenAI API key and package. The YAML is re-encoded
+// from its node tree so comments are kept.
+func migrateAgentYAML(data []byte, fromProvider, fromModel, toProvider, toModel, ollamaURL string) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("agent.yaml is not a mapping")
+	}
+
+	spec := mappingValue(doc.Content[0], "spec")
+	model := mappingValue(spec, "model")
+	provider := mappingValue(model, "provider")
+	name := mappingValue(model, "name")
+	if provider == nil || name == nil {
+		return nil, fmt.Errorf("spec.model.provider and spec.model.name are required")
+	}
+	if provider.Value != fromProvider || name.Value != fromModel {
+		return nil, fmt.Errorf("agent uses %s/%s, not %s/%s", provider.Value, name.Value, fromProvider, fromModel)
+	}
+	provider.Value = toProvider
+	name.Value = toModel
+
+	if config := mappingValue(model, "config"); config != nil && config.Kind == yaml.MappingNode {
+		setMappingValue(config, "base_url", ollamaURL)
+	}
+
+	// Replace the OpenAI API key with the Ollama endpoint
+	environment := mappingValue(spec, "environment")
+	if environment == nil {
+		environment = &yaml.Node{Kind: yaml.SequenceNode}
+		spec.Content = append(spec.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: "environment"}, environment)
+	}
+	var envVars []*yaml.Node
+	hasOllamaURL := false
+	for _, envVar := range environment.Content {
+		switch envName := mappingValue(envVar, "name"); {
+		case envName != nil && envName.Value == "OPENAI_API_KEY":
+			continue
+		case envName != nil && envName.Value == "OLLAMA_BASE_URL":
+			hasOllamaURL = true
+		}
+		envVars = append(envVars, envVar)
+	}
+	if !hasOllamaURL {
+		envVar := &yaml.Node{Kind: yaml.MappingNode}
+		setMappingValue(envVar, "name", "OLLAMA_BASE_URL")
+		setMappingValue(envVar, "value", ollamaURL)
+		envVars = append(envVars, envVar)
+	}
+	environment.Content = envVars
+
+	if dependencies := mappingValue(spec, "dependencies"); dependencies != nil {
+		var deps []*yaml.Node
+		for _, dep := range dependencies.Content {
+			if requirementName(dep.Value) == "openai" {
+				dep = &yaml.Node{Kind: yaml.ScalarNode, Value: httpxRequirement}
+			}
+			if requirementName(dep.Value) == "httpx" && containsRequirement(deps, "httpx") {
+				continue
+			}
+			deps = append(deps, dep)
+		}
+		dependencies.Content = deps
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// migrateRequirements swaps the openai package for httpx
+func migrateRequirements(data []byte) ([]byte, error) {
+	lines := strings.SplitAfter(string(data), "\n")
+	hasHTTPX := false
+	for _, line := range lines {
+		if requirementName(line) == "httpx" {
+			hasHTTPX = true
+		}
+	}
+
+	var out strings.Builder
+	for _, line := range lines {
+		if requirementName(line) == "openai" {
+			if hasHTTPX {
+				continue
+			}
+			hasHTTPX = true
+			newline := ""
+			if strings.HasSuffix(line, "\n") {
+				newline = "\n"
+			}
+			line = httpxRequirement + newline
+		}
+		out.WriteString(line)
+	}
+
+	return []byte(out.String()), nil
+}
+
+// migrateMainPy replaces the openai import with a small Ollama client that
+// mimics the OpenAI chat completions API, constructs it instead of the
+// OpenAI client and renames the model
+func migrateMainPy(data []byte, fromModel, toModel string) []byte {
+	source := string(data)
+	if !openaiImportPattern.MatchString(source) {
+		return data
+	}
+
+	// Replace the first import with the shim and drop any others
+	first := true
+	source = openaiImportPattern.ReplaceAllStringFunc(source, func(string) string {
+		if first {
+			first = false
+			return ollamaClientShim
+		}
+		return ""
+	})
+
+	source = openaiLegacyPattern.ReplaceAllString(source, "OllamaClient().chat.completions.create")
+
+	// Replace OpenAI(...) constructor calls, whose arguments (API key,
+	// organization) are meaningless for Ollama
+	var out strings.Builder
+	for {
+		loc := openaiClientPattern.FindStringIndex(source)
+		if loc == nil {
+			break
+		}
+		end := matchingParen(source, loc[1]-1)
+		if end == -1 {
+			break
+		}
+		out.WriteString(source[:loc[0]])
+		out.WriteString("OllamaClient()")
+		source = source[end+1:]
+	}
+	out.WriteString(source)
+	source = out.String()
+
+	for _, quote := range []string{`"`, `'`} {
+		source = strings.ReplaceAll(source, quote+fromModel+quote, quote+toModel+quote)
+	}
+
+	return []byte(source)
+}
+
+// matchingParen returns the index of the parenthesis closing the one at open,
+// or -1 if it is unbalanced. Parentheses in string literals are not skipped,
+// which is fine for constructor arguments.
+func matchingParen(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// requirementName returns the lowercased package name of a requirement line
+func requirementName(line string) string {
+	match := requirementPattern.FindStringSubmatch(line)
+	if match == nil {
+		return ""
+	}
+	return strings.ToLower(match[1])
+}
+
+// containsRequirement reports whether deps has a requirement for name
+func containsRequirement(deps []*yaml.Node, name string) bool {
+	for _, dep := range deps {
+		if requirementName(dep.Value) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// mappingValue returns the value node for key in a mapping node, or nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// setMappingValue sets key to a scalar value in a mapping node
+func setMappingValue(node *yaml.Node, key, value string) {
+	if existing := mappingValue(node, key); existing != nil {
+		existing.Kind = yaml.ScalarNode
+		existing.Tag = ""
+		existing.Value = value
+		return
+	}
+	node.Content = append(node.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Value: key},
+		&yaml.Node{Kind: yaml.ScalarNode, Value: value},
+	)
+}
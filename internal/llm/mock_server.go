@@ -0,0 +1,274 @@
+package llm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Recording is a recorded model response, looked up by model and the
+// SHA-256 of the prompt
+type Recording struct {
+	Model      string `json:"model"`
+	PromptHash string `json:"prompt_hash"`
+	// Prompt is kept for readability; lookups only use PromptHash
+	Prompt   string `json:"prompt,omitempty"`
+	Response string `json:"response"`
+}
+
+// RecordingsFile is the recordings.json written by llm mock-record
+type RecordingsFile struct {
+	Recordings []Recording `json:"recordings"`
+}
+
+// PromptHash returns the key a prompt is recorded under
+func PromptHash(prompt string) string {
+	sum := sha256.Sum256([]byte(prompt))
+	return hex.EncodeToString(sum[:])
+}
+
+// LoadRecordings reads a recordings file. A missing file yields no
+// recordings.
+func LoadRecordings(path string) (*RecordingsFile, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &RecordingsFile{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recordings: %v", err)
+	}
+
+	var file RecordingsFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid recordings file %s: %v", path, err)
+	}
+	return &file, nil
+}
+
+// Add adds a recording, replacing an earlier one for the same model and
+// prompt
+func (f *RecordingsFile) Add(recording Recording) {
+	for i, existing := range f.Recordings {
+		if existing.Model == recording.Model && existing.PromptHash == recording.PromptHash {
+			f.Recordings[i] = recording
+			return
+		}
+	}
+	f.Recordings = append(f.Recordings, recording)
+}
+
+// Save writes the recordings file
+func (f *RecordingsFile) Save(path string) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal recordings: %v", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write recordings: %v", err)
+	}
+	return nil
+}
+
+// MockServer serves recorded responses through the parts of the Ollama
+// HTTP API that LocalLLMManager uses, so CI runs need no real model
+type MockServer struct {
+	responses map[string]string // model + "\x00" + prompt hash -> response
+	models    []string
+}
+
+// NewMockServer creates a mock server for the given recordings
+func NewMockServer(recordings []Recording) *MockServer {
+	s := &MockServer{responses: make(map[string]string)}
+	seen := make(map[string]bool)
+	for _, recording := range recordings {
+		s.responses[recording.Model+"\x00"+recording.PromptHash] = recording.Response
+		if !seen[recording.Model] {
+			seen[recording.Model] = true
+			s.models = append(s.models, recording.Model)
+		}
+	}
+	sort.Strings(s.models)
+	return s
+}
+
+// Models returns the models with recordings
+func (s *MockServer) Models() []string {
+	return s.models
+}
+
+// Handler returns the HTTP handler serving the Ollama API
+func (s *MockServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			writeMockError(w, http.StatusNotFound, "not found")
+			return
+		}
+		fmt.Fprint(w, "Ollama is running")
+	})
+	mux.HandleFunc("/api/version", func(w http.ResponseWriter, r *http.Request) {
+		writeMockJSON(w, map[string]string{"version": "0.0.0-mock"})
+	})
+	mux.HandleFunc("/api/tags", s.handleTags)
+	mux.HandleFunc("/api/ps", func(w http.ResponseWriter, r *http.Request) {
+		writeMockJSON(w, map[string]interface{}{"models": []RunningModel{}})
+	})
+	mux.HandleFunc("/api/show", s.handleShow)
+	mux.HandleFunc("/api/generate", s.handleGenerate)
+	mux.HandleFunc("/api/chat", s.handleChat)
+	return mux
+}
+
+// ListenAndServe serves the mock API on addr until the server fails
+func (s *MockServer) ListenAndServe(addr string) error {
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return server.ListenAndServe()
+}
+
+func (s *MockServer) handleTags(w http.ResponseWriter, r *http.Request) {
+	models := make([]LocalModel, 0, len(s.models))
+	for _, name := range s.models {
+		models = append(models, LocalModel{Name: name, Digest: PromptHash(name)})
+	}
+	writeMockJSON(w, LocalModelResponse{Models: models})
+}
+
+func (s *MockServer) handleShow(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name  string `json:"name"`
+		Model string `json:"model"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeMockError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	name := req.Name
+	if name == "" {
+		name = req.Model
+	}
+	if !s.hasModel(name) {
+		writeMockError(w, http.StatusNotFound, fmt.Sprintf("model '%s' not found", name))
+		return
+	}
+	writeMockJSON(w, OllamaShowResponse{Modelfile: "# Mock model served from recordings\n"})
+}
+
+func (s *MockServer) handleGenerate(w http.ResponseWriter, r *http.Request) {
+	var req GenerateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeMockError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response, ok := s.lookup(w, req.Model, req.Prompt)
+	if !ok {
+		return
+	}
+
+	final := GenerateResponse{Model: req.Model, Response: response, Done: true, EvalCount: len(strings.Fields(response))}
+	if !req.Stream {
+		writeMockJSON(w, final)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	encoder := json.NewEncoder(w)
+	for _, token := range splitTokens(response) {
+		encoder.Encode(GenerateResponse{Model: req.Model, Response: token})
+	}
+	final.Response = ""
+	encoder.Encode(final)
+}
+
+// handleChat answers with the recording of the last user message
+func (s *MockServer) handleChat(w http.ResponseWriter, r *http.Request) {
+	var req ChatRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeMockError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	prompt := ""
+	for i := len(req.Messages) - 1; i >= 0; i-- {
+		if req.Messages[i].Role == "user" {
+			prompt = req.Messages[i].Content
+			break
+		}
+	}
+
+	response, ok := s.lookup(w, req.Model, prompt)
+	if !ok {
+		return
+	}
+
+	final := ChatResponse{Model: req.Model, Message: Message{Role: "assistant", Content: response}, Done: true, EvalCount: len(strings.Fields(response))}
+	if !req.Stream {
+		writeMockJSON(w, final)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	encoder := json.NewEncoder(w)
+	for _, token := range splitTokens(response) {
+		encoder.Encode(ChatResponse{Model: req.Model, Message: Message{Role: "assistant", Content: token}})
+	}
+	final.Message.Content = ""
+	encoder.Encode(final)
+}
+
+// lookup returns the recorded response, writing a 404 if there is none
+func (s *MockServer) lookup(w http.ResponseWriter, model, prompt string) (string, bool) {
+	hash := PromptHash(prompt)
+	response, ok := s.responses[model+"\x00"+hash]
+	if !ok {
+		writeMockError(w, http.StatusNotFound, fmt.Sprintf("no recording for model '%s' and prompt hash %s", model, hash))
+	}
+	return response, ok
+}
+
+func (s *MockServer) hasModel(name string) bool {
+	for _, model := range s.models {
+		if model == name {
+			return true
+		}
+	}
+	return false
+}
+
+// splitTokens splits a response into word-sized stream chunks that join
+// back to the original text
+func splitTokens(text string) []string {
+	var tokens []string
+	start := 0
+	for i := 1; i < len(text); i++ {
+		if text[i] == ' ' || text[i] == '\n' {
+			tokens = append(tokens, text[start:i])
+			start = i
+		}
+	}
+	if start < len(text) {
+		tokens = append(tokens, text[start:])
+	}
+	return tokens
+}
+
+func writeMockJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeMockError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
@@ -3,13 +3,28 @@ package llm
 import (
 	"fmt"
 	"strings"
+
+	"github.com/pxkundu/agent-as-code/internal/log"
 )
 
 // ModelAnalyzer analyzes model capabilities and limitations
 type ModelAnalyzer struct {
 	modelManager *LocalLLMManager
+	benchmarker  *ModelBenchmarker
 }
 
+// BenchmarkMode selects how much of the benchmark AnalyzeModel runs
+type BenchmarkMode string
+
+const (
+	// BenchmarkNone skips the benchmark and estimates performance from the model name
+	BenchmarkNone BenchmarkMode = "none"
+	// BenchmarkQuick runs the simple question and code generation tasks
+	BenchmarkQuick BenchmarkMode = "quick"
+	// BenchmarkFull runs every benchmark task
+	BenchmarkFull BenchmarkMode = "full"
+)
+
 // ModelAnalysis represents a comprehensive model analysis
 type ModelAnalysis struct {
 	ModelName        string
@@ -40,6 +55,7 @@ type ModelPerformance struct {
 func NewModelAnalyzer() *ModelAnalyzer {
 	return &ModelAnalyzer{
 		modelManager: NewLocalLLMManager(),
+		benchmarker:  NewModelBenchmarker(),
 	}
 }
 
@@ -48,8 +64,9 @@ func (a *ModelAnalyzer) IsModelAvailable(modelName string) bool {
 	return a.modelManager.IsModelAvailable(modelName)
 }
 
-// AnalyzeModel performs comprehensive analysis of a model
-func (a *ModelAnalyzer) AnalyzeModel(modelName string) (*ModelAnalysis, error) {
+// AnalyzeModel performs comprehensive analysis of a model. Performance is
+// measured with a benchmark run unless mode is BenchmarkNone.
+func (a *ModelAnalyzer) AnalyzeModel(modelName string, mode BenchmarkMode) (*ModelAnalysis, error) {
 	// Get model info
 	modelInfo, err := a.modelManager.GetModelInfo(modelName)
 	if err != nil {
@@ -60,7 +77,7 @@ func (a *ModelAnalyzer) AnalyzeModel(modelName string) (*ModelAnalysis, error) {
 	analysis := &ModelAnalysis{
 		ModelName:        modelName,
 		Architecture:     a.analyzeArchitecture(modelName, modelInfo),
-		Performance:      a.analyzePerformance(modelName),
+		Performance:      a.analyzePerformance(modelName, mode),
 		Capabilities:     a.analyzeCapabilities(modelName),
 		Limitations:      a.analyzeLimitations(modelName),
 		BestUseCases:     a.analyzeBestUseCases(modelName),
@@ -121,8 +138,42 @@ func (a *ModelAnalyzer) analyzeArchitecture(modelName string, modelInfo *LocalMo
 	return arch
 }
 
-// analyzePerformance analyzes model performance characteristics
-func (a *ModelAnalyzer) analyzePerformance(modelName string) ModelPerformance {
+// analyzePerformance measures model performance with a benchmark run, falling
+// back to estimates from the model size when the benchmark is skipped or fails
+func (a *ModelAnalyzer) analyzePerformance(modelName string, mode BenchmarkMode) ModelPerformance {
+	perf := a.estimatePerformance(modelName)
+
+	var tasks []BenchmarkTask
+	switch mode {
+	case BenchmarkQuick:
+		tasks = a.benchmarker.quickBenchmarkTasks()
+	case BenchmarkFull:
+		tasks = a.benchmarker.getBenchmarkTasks()
+	default:
+		return perf
+	}
+
+	result, err := a.benchmarker.benchmarkModelTasks(modelName, tasks)
+	if err != nil {
+		log.Warn("benchmark failed, using estimates", "model", modelName, "error", err)
+		return perf
+	}
+
+	if result.AverageResponseTime != "N/A" {
+		perf.ResponseTime = result.AverageResponseTime
+	}
+	if result.MemoryUsage != "N/A" {
+		perf.MemoryUsage = result.MemoryUsage
+	}
+	if result.Throughput != "N/A" {
+		perf.Throughput = result.Throughput
+	}
+
+	return perf
+}
+
+// estimatePerformance estimates model performance from the model size
+func (a *ModelAnalyzer) estimatePerformance(modelName string) ModelPerformance {
 	perf := ModelPerformance{
 		ResponseTime: "Unknown",
 		MemoryUsage:  "Unknown",
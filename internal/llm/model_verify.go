@@ -0,0 +1,138 @@
+package llm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrDigestMismatch is returned by VerifyModel when a model's weights do not
+// match the digest in its manifest. Other errors mean the model could not
+// be checked, not that it is corrupt.
+var ErrDigestMismatch = errors.New("digest mismatch")
+
+// modelLayerMediaType is the manifest layer holding the model weights
+const modelLayerMediaType = "application/vnd.ollama.image.model"
+
+// ollamaManifest is the part of an Ollama model manifest needed to find its
+// blobs
+type ollamaManifest struct {
+	Layers []struct {
+		MediaType string `json:"mediaType"`
+		Digest    string `json:"digest"`
+		Size      int64  `json:"size"`
+	} `json:"layers"`
+}
+
+// OllamaModelsDir returns the directory Ollama stores models in, honouring
+// OLLAMA_MODELS
+func OllamaModelsDir() (string, error) {
+	if dir := os.Getenv("OLLAMA_MODELS"); dir != "" {
+		return dir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %v", err)
+	}
+	return filepath.Join(home, ".ollama", "models"), nil
+}
+
+// manifestPath returns the manifest file of a model name such as llama2,
+// mistral:7b, user/model:tag or host/namespace/model:tag
+func manifestPath(modelsDir, modelName string) (string, error) {
+	name, tag := modelName, "latest"
+	if i := strings.LastIndex(modelName, ":"); i > strings.LastIndex(modelName, "/") {
+		name, tag = modelName[:i], modelName[i+1:]
+	}
+
+	host, namespace := "registry.ollama.ai", "library"
+	parts := strings.Split(name, "/")
+	switch len(parts) {
+	case 1:
+	case 2:
+		namespace = parts[0]
+	case 3:
+		host, namespace = parts[0], parts[1]
+	default:
+		return "", fmt.Errorf("invalid model name '%s'", modelName)
+	}
+
+	return filepath.Join(modelsDir, "manifests", host, namespace, parts[len(parts)-1], tag), nil
+}
+
+// VerifyModel checks that the SHA-256 of a local model's weights matches the
+// digest recorded in its Ollama manifest. It reads Ollama's model directory
+// directly, so Ollama must run on this machine.
+func (m *LocalLLMManager) VerifyModel(modelName string) error {
+	show, err := m.ShowModel(modelName)
+	if err != nil {
+		return fmt.Errorf("model '%s' is not available: %v", modelName, err)
+	}
+
+	modelsDir, err := OllamaModelsDir()
+	if err != nil {
+		return err
+	}
+	manifestFile, err := manifestPath(modelsDir, modelName)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(manifestFile)
+	if err != nil {
+		return fmt.Errorf("failed to read manifest of '%s': %v", modelName, err)
+	}
+	var manifest ollamaManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("invalid manifest %s: %v", manifestFile, err)
+	}
+
+	expected := ""
+	for _, layer := range manifest.Layers {
+		if layer.MediaType == modelLayerMediaType {
+			expected = layer.Digest
+			break
+		}
+	}
+	algorithm, want, ok := strings.Cut(expected, ":")
+	if !ok || algorithm != "sha256" {
+		return fmt.Errorf("manifest of '%s' has no sha256 model layer", modelName)
+	}
+
+	blob := filepath.Join(modelsDir, "blobs", "sha256-"+want)
+	// The Modelfile's FROM line names the blob Ollama actually loads
+	if path := show.ModelPath(); path != "" && filepath.Base(path) != filepath.Base(blob) {
+		return fmt.Errorf("model '%s' loads %s but its manifest lists %s", modelName, path, blob)
+	}
+
+	got, err := fileSHA256(blob)
+	if err != nil {
+		return fmt.Errorf("failed to hash model file: %v", err)
+	}
+	if got != want {
+		return fmt.Errorf("%w for '%s': manifest has sha256:%s, %s has sha256:%s", ErrDigestMismatch, modelName, want, blob, got)
+	}
+
+	return nil
+}
+
+// fileSHA256 returns the hex SHA-256 of a file
+func fileSHA256(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
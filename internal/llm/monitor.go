@@ -0,0 +1,230 @@
+package llm
+
+import (
+	"bufio"
+	"os"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MonitorHistory is how many refreshes of request rates a ModelMonitor
+// keeps
+const MonitorHistory = 30
+
+// MonitoredModel is a running model and the requests it served while it was
+// monitored
+type MonitoredModel struct {
+	RunningModel
+	Requests int
+}
+
+// MonitorSnapshot is the state of Ollama and the machine at one refresh.
+// The errors are set when that part could not be read.
+type MonitorSnapshot struct {
+	Time        time.Time
+	Models      []MonitoredModel
+	ModelsError error
+	// RequestRate is the requests per second of each refresh, oldest
+	// first, up to MonitorHistory of them
+	RequestRate []float64
+	CPUPercent  float64 // -1 until two samples were taken, or if unsupported
+	GPUs        []GPUInfo
+	GPUError    error
+	Logs        []string
+	LogError    error
+}
+
+// ModelMonitor samples the running models, the request rate, CPU and GPU
+// use and the Ollama log for a dashboard.
+//
+// Ollama does not report request counts, so they are estimated: the log
+// has a line for every request, but not the model it was for, and a model's
+// keep-alive expiry moves forward whenever it serves a request. The
+// requests logged since the last refresh are shared among the models whose
+// expiry moved.
+type ModelMonitor struct {
+	manager  *LocalLLMManager
+	gpus     *GPUDetector
+	logs     *OllamaLogTail
+	logLines int
+
+	mu       sync.Mutex
+	last     time.Time
+	expiry   map[string]time.Time
+	requests map[string]int
+	rate     []float64
+	logTail  []string
+	cpu      cpuSample
+}
+
+// NewModelMonitor creates a monitor that keeps the last logLines lines of
+// the Ollama log at logPath (see NewOllamaLogTail)
+func NewModelMonitor(logPath string, logLines int) *ModelMonitor {
+	return &ModelMonitor{
+		manager:  NewLocalLLMManager(),
+		gpus:     NewGPUDetector(),
+		logs:     NewOllamaLogTail(logPath),
+		logLines: logLines,
+		expiry:   make(map[string]time.Time),
+		requests: make(map[string]int),
+	}
+}
+
+// URL returns the Ollama endpoint being monitored
+func (m *ModelMonitor) URL() string {
+	return m.manager.URL()
+}
+
+// LogSource describes where the Ollama log is read from
+func (m *ModelMonitor) LogSource() string {
+	return m.logs.Source()
+}
+
+// Unload frees the memory of a running model
+func (m *ModelMonitor) Unload(modelName string) error {
+	if err := m.manager.UnloadModel(modelName); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// A later reload is a new request, not a refresh of this expiry
+	delete(m.expiry, modelName)
+	return nil
+}
+
+// Refresh samples everything and returns the new snapshot
+func (m *ModelMonitor) Refresh() *MonitorSnapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	first := m.last.IsZero()
+	snapshot := &MonitorSnapshot{Time: now, CPUPercent: -1}
+
+	logged := 0
+	lines, err := m.logs.Poll(m.logLines)
+	if err != nil {
+		snapshot.LogError = err
+	} else if !first {
+		// The lines of the first poll were written before monitoring began
+		for _, line := range lines {
+			if IsInferenceRequest(line) {
+				logged++
+			}
+		}
+	}
+	m.logTail = append(m.logTail, lines...)
+	if len(m.logTail) > m.logLines {
+		m.logTail = m.logTail[len(m.logTail)-m.logLines:]
+	}
+	snapshot.Logs = append([]string(nil), m.logTail...)
+
+	models, err := m.manager.ListRunningModels()
+	if err != nil {
+		snapshot.ModelsError = err
+	}
+	sort.Slice(models, func(i, j int) bool { return models[i].Name < models[j].Name })
+
+	// Models that were loaded or whose expiry moved served a request
+	var served []string
+	loaded := make(map[string]bool)
+	for _, model := range models {
+		loaded[model.Name] = true
+		expiresAt, _ := time.Parse(time.RFC3339Nano, model.ExpiresAt)
+		previous, seen := m.expiry[model.Name]
+		if !first && (!seen || expiresAt.After(previous)) {
+			served = append(served, model.Name)
+		}
+		m.expiry[model.Name] = expiresAt
+	}
+	for name := range m.expiry {
+		if !loaded[name] && err == nil {
+			delete(m.expiry, name)
+		}
+	}
+
+	requests := max(logged, len(served))
+	for i, name := range served {
+		share := requests / len(served)
+		if i < requests%len(served) {
+			share++
+		}
+		m.requests[name] += share
+	}
+	for _, model := range models {
+		snapshot.Models = append(snapshot.Models, MonitoredModel{RunningModel: model, Requests: m.requests[model.Name]})
+	}
+
+	if !first {
+		if elapsed := now.Sub(m.last).Seconds(); elapsed > 0 {
+			m.rate = append(m.rate, float64(requests)/elapsed)
+			if len(m.rate) > MonitorHistory {
+				m.rate = m.rate[len(m.rate)-MonitorHistory:]
+			}
+		}
+	}
+	snapshot.RequestRate = append([]float64(nil), m.rate...)
+	m.last = now
+
+	if sample, ok := readCPUSample(); ok {
+		if m.cpu.total > 0 && sample.total > m.cpu.total {
+			busy := float64((sample.total - sample.idle) - (m.cpu.total - m.cpu.idle))
+			snapshot.CPUPercent = busy / float64(sample.total-m.cpu.total) * 100
+		}
+		m.cpu = sample
+	}
+
+	snapshot.GPUs, snapshot.GPUError = m.gpus.DetectGPUs()
+	return snapshot
+}
+
+// cpuSample is the CPU time counters of /proc/stat, in clock ticks
+type cpuSample struct {
+	total uint64
+	idle  uint64
+}
+
+// readCPUSample reads the CPU time counters on Linux; CPU use is not
+// reported on other systems
+func readCPUSample() (cpuSample, bool) {
+	if runtime.GOOS != "linux" {
+		return cpuSample{}, false
+	}
+
+	f, err := os.Open("/proc/stat")
+	if err != nil {
+		return cpuSample{}, false
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	if !scanner.Scan() {
+		return cpuSample{}, false
+	}
+	// cpu  user nice system idle iowait irq softirq steal ...
+	fields := strings.Fields(scanner.Text())
+	if len(fields) < 5 || fields[0] != "cpu" {
+		return cpuSample{}, false
+	}
+
+	var sample cpuSample
+	for i, field := range fields[1:] {
+		value, err := strconv.ParseUint(field, 10, 64)
+		if err != nil {
+			return cpuSample{}, false
+		}
+		// guest and guest_nice are already counted in user and nice
+		if i < 8 {
+			sample.total += value
+		}
+		if i == 3 || i == 4 {
+			sample.idle += value
+		}
+	}
+	return sample, true
+}
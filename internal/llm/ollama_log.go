@@ -0,0 +1,158 @@
+package llm
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// inferenceRequestPattern matches the access log lines Ollama writes for
+// generation and embedding requests, e.g.
+// [GIN] 2024/05/01 - 12:00:00 | 200 |  1.2s |  127.0.0.1 | POST     "/api/chat"
+var inferenceRequestPattern = regexp.MustCompile(`\[GIN\].*\|\s*POST\s+"/(api/(generate|chat|embed|embeddings)|v1/(chat/completions|completions|embeddings))"`)
+
+// journalCursorPrefix starts the line journalctl --show-cursor ends with
+const journalCursorPrefix = "-- cursor: "
+
+// IsInferenceRequest reports whether an Ollama log line records a
+// generation or embedding request
+func IsInferenceRequest(line string) bool {
+	return inferenceRequestPattern.MatchString(line)
+}
+
+// OllamaLogTail reads the Ollama server log incrementally, from a file or,
+// without one, from the systemd journal of the ollama service
+type OllamaLogTail struct {
+	path string
+
+	offset  int64     // file: bytes read so far
+	cursor  string    // journal: position after the last entry read
+	lastRun time.Time // journal: time of the last poll
+	started bool
+}
+
+// NewOllamaLogTail creates a tail of the log file at path. An empty path
+// reads ~/.ollama/logs/server.log on macOS, where the Ollama app writes
+// its log, and the journal elsewhere.
+func NewOllamaLogTail(path string) *OllamaLogTail {
+	if path == "" && runtime.GOOS == "darwin" {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, ".ollama", "logs", "server.log")
+		}
+	}
+	return &OllamaLogTail{path: path}
+}
+
+// Source describes where the log is read from
+func (t *OllamaLogTail) Source() string {
+	if t.path != "" {
+		return t.path
+	}
+	return "journalctl -u ollama"
+}
+
+// Poll returns the log lines written since the last poll. The first poll
+// returns up to the last n lines.
+func (t *OllamaLogTail) Poll(n int) ([]string, error) {
+	if t.path != "" {
+		return t.pollFile(n)
+	}
+	return t.pollJournal(n)
+}
+
+func (t *OllamaLogTail) pollFile(n int) ([]string, error) {
+	f, err := os.Open(t.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := info.Size()
+
+	start := t.offset
+	if !t.started {
+		// Only the end of the log is shown at first; 64KB holds far more
+		// than n lines
+		start = max(size-64*1024, 0)
+	} else if size < t.offset {
+		// The log was truncated or rotated
+		start = 0
+	}
+
+	data := make([]byte, size-start)
+	if _, err := f.ReadAt(data, start); err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	// Leave a line still being written for the next poll
+	end := bytes.LastIndexByte(data, '\n') + 1
+	t.offset = start + int64(end)
+	lines := splitLogLines(string(data[:end]))
+
+	if !t.started {
+		t.started = true
+		if start > 0 && len(lines) > 0 {
+			// The first line is likely cut off
+			lines = lines[1:]
+		}
+		if len(lines) > n {
+			lines = lines[len(lines)-n:]
+		}
+	}
+	return lines, nil
+}
+
+func (t *OllamaLogTail) pollJournal(n int) ([]string, error) {
+	args := []string{"-u", "ollama", "--no-pager", "-o", "cat", "--show-cursor"}
+	switch {
+	case t.cursor != "":
+		args = append(args, "--after-cursor="+t.cursor)
+	case t.started:
+		// Nothing was logged yet, so there is no cursor to continue from
+		args = append(args, "--since="+t.lastRun.Format("2006-01-02 15:04:05"))
+	default:
+		args = append(args, "-n", fmt.Sprint(n))
+	}
+
+	now := time.Now()
+	output, err := exec.Command("journalctl", args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the journal (set llm.ollama-log to read a log file instead): %v", err)
+	}
+	t.started = true
+	t.lastRun = now
+
+	var lines []string
+	for _, line := range splitLogLines(string(output)) {
+		if strings.HasPrefix(line, journalCursorPrefix) {
+			t.cursor = strings.TrimPrefix(line, journalCursorPrefix)
+			continue
+		}
+		if strings.HasPrefix(line, "-- ") {
+			// Notes such as "-- No entries --"
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, nil
+}
+
+// splitLogLines splits text into lines without their line endings
+func splitLogLines(text string) []string {
+	text = strings.TrimRight(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
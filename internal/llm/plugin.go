@@ -0,0 +1,172 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// maxToolRounds bounds how often a model may call tools before answering
+const maxToolRounds = 5
+
+// LLMPlugin is a tool a model can call during a chat
+type LLMPlugin interface {
+	// Name is the tool name the model calls the plugin by
+	Name() string
+	// Description tells the model what the tool does
+	Description() string
+	// Schema is the JSON Schema of the tool's arguments
+	Schema() json.RawMessage
+	// Execute runs the tool with args, a JSON object matching Schema
+	Execute(args json.RawMessage) (string, error)
+}
+
+// Tool is a tool definition in an Ollama chat request
+type Tool struct {
+	Type     string       `json:"type"`
+	Function ToolFunction `json:"function"`
+}
+
+// ToolFunction describes the function a tool calls
+type ToolFunction struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Parameters  json.RawMessage `json:"parameters"`
+}
+
+// ToolCall is a model's request to run a tool
+type ToolCall struct {
+	Function struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	} `json:"function"`
+}
+
+// PluginRegistry holds the plugins offered to a model as tools
+type PluginRegistry struct {
+	plugins map[string]LLMPlugin
+}
+
+// NewPluginRegistry creates an empty plugin registry
+func NewPluginRegistry() *PluginRegistry {
+	return &PluginRegistry{plugins: make(map[string]LLMPlugin)}
+}
+
+// DefaultPluginRegistry returns a registry with the built-in plugins
+func DefaultPluginRegistry() *PluginRegistry {
+	registry := NewPluginRegistry()
+	registry.Register(NewCalculator())
+	registry.Register(NewWebSearch())
+	return registry
+}
+
+// Register adds p to the registry, replacing a plugin of the same name
+func (r *PluginRegistry) Register(p LLMPlugin) {
+	r.plugins[p.Name()] = p
+}
+
+// Get returns the plugin called name
+func (r *PluginRegistry) Get(name string) (LLMPlugin, bool) {
+	p, ok := r.plugins[name]
+	return p, ok
+}
+
+// Plugins returns the registered plugins sorted by name
+func (r *PluginRegistry) Plugins() []LLMPlugin {
+	plugins := make([]LLMPlugin, 0, len(r.plugins))
+	for _, p := range r.plugins {
+		plugins = append(plugins, p)
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name() < plugins[j].Name() })
+	return plugins
+}
+
+// Select returns a registry with only the named plugins
+func (r *PluginRegistry) Select(names []string) (*PluginRegistry, error) {
+	selected := NewPluginRegistry()
+	for _, name := range names {
+		p, ok := r.plugins[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown plugin '%s' (available: %s)", name, strings.Join(r.names(), ", "))
+		}
+		selected.Register(p)
+	}
+	return selected, nil
+}
+
+// Tools returns the tool definitions of the registered plugins for a chat
+// request
+func (r *PluginRegistry) Tools() []Tool {
+	var tools []Tool
+	for _, p := range r.Plugins() {
+		tools = append(tools, Tool{
+			Type: "function",
+			Function: ToolFunction{
+				Name:        p.Name(),
+				Description: p.Description(),
+				Parameters:  p.Schema(),
+			},
+		})
+	}
+	return tools
+}
+
+// HandleToolCall runs the plugin called name with args
+func (r *PluginRegistry) HandleToolCall(name string, args json.RawMessage) (string, error) {
+	p, ok := r.plugins[name]
+	if !ok {
+		return "", fmt.Errorf("unknown tool '%s'", name)
+	}
+	if len(args) == 0 || string(args) == "null" {
+		args = json.RawMessage("{}")
+	}
+	return p.Execute(args)
+}
+
+func (r *PluginRegistry) names() []string {
+	names := make([]string, 0, len(r.plugins))
+	for name := range r.plugins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// StreamChatWithTools runs a streaming chat in which the model may call the
+// plugins of registry. Tool calls are run and their results sent back until
+// the model answers without calling a tool. onToolCall, if not nil, is
+// called with every call and its result. The returned response is the
+// model's final answer.
+func (m *LocalLLMManager) StreamChatWithTools(req ChatRequest, registry *PluginRegistry, onChunk func(ChatResponse) error, onToolCall func(call ToolCall, result string, err error)) (*ChatResponse, error) {
+	req.Tools = registry.Tools()
+	req.Messages = append([]Message(nil), req.Messages...)
+
+	for round := 0; ; round++ {
+		if round == maxToolRounds {
+			// Ask for an answer with what the tools returned so far
+			req.Tools = nil
+		}
+
+		final, err := m.StreamChat(req, onChunk)
+		if err != nil {
+			return nil, err
+		}
+		if len(final.Message.ToolCalls) == 0 || req.Tools == nil {
+			return final, nil
+		}
+
+		req.Messages = append(req.Messages, final.Message)
+		for _, call := range final.Message.ToolCalls {
+			result, err := registry.HandleToolCall(call.Function.Name, call.Function.Arguments)
+			if onToolCall != nil {
+				onToolCall(call, result, err)
+			}
+			if err != nil {
+				// Let the model see the error and recover from it
+				result = "error: " + err.Error()
+			}
+			req.Messages = append(req.Messages, Message{Role: "tool", Content: result, ToolName: call.Function.Name})
+		}
+	}
+}
@@ -0,0 +1,420 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// maxSearchResults bounds the related topics a web search returns
+const maxSearchResults = 5
+
+// WebSearch looks a query up with a search API over HTTP GET. Queries are
+// chosen by the model, so nothing is sent until an endpoint is configured.
+type WebSearch struct {
+	// Endpoint is the search API, called as Endpoint?q=<query>&format=json,
+	// e.g. DuckDuckGo's Instant Answer API at https://api.duckduckgo.com/.
+	// Searching is disabled while it is empty.
+	Endpoint string
+
+	httpClient *http.Client
+}
+
+// NewWebSearch creates a web search plugin for the endpoint in
+// AGENT_SEARCH_URL. Without it, the plugin refuses to search.
+func NewWebSearch() *WebSearch {
+	return &WebSearch{
+		Endpoint:   strings.TrimSpace(os.Getenv("AGENT_SEARCH_URL")),
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// Name implements LLMPlugin
+func (w *WebSearch) Name() string { return "web_search" }
+
+// Description implements LLMPlugin
+func (w *WebSearch) Description() string {
+	return "Search the web and return a short summary with related results. Use it for facts that may be recent or that you are unsure of."
+}
+
+// Schema implements LLMPlugin
+func (w *WebSearch) Schema() json.RawMessage {
+	return json.RawMessage(`{"type":"object","properties":{"query":{"type":"string","description":"the search query"}},"required":["query"]}`)
+}
+
+// Execute implements LLMPlugin
+func (w *WebSearch) Execute(args json.RawMessage) (string, error) {
+	var params struct {
+		Query string `json:"query"`
+	}
+	if err := json.Unmarshal(args, &params); err != nil {
+		return "", fmt.Errorf("invalid arguments: %v", err)
+	}
+	if strings.TrimSpace(params.Query) == "" {
+		return "", fmt.Errorf("query is required")
+	}
+	if w.Endpoint == "" {
+		return "", fmt.Errorf("web search is disabled: set AGENT_SEARCH_URL to a search API, e.g. https://api.duckduckgo.com/")
+	}
+
+	query := url.Values{"q": {params.Query}, "format": {"json"}, "no_html": {"1"}, "skip_disambig": {"1"}}
+	resp, err := w.httpClient.Get(w.Endpoint + "?" + query.Encode())
+	if err != nil {
+		return "", fmt.Errorf("search failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("search returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var answer struct {
+		Heading       string `json:"Heading"`
+		AbstractText  string `json:"AbstractText"`
+		AbstractURL   string `json:"AbstractURL"`
+		Answer        string `json:"Answer"`
+		RelatedTopics []struct {
+			Text     string `json:"Text"`
+			FirstURL string `json:"FirstURL"`
+		} `json:"RelatedTopics"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&answer); err != nil {
+		return "", fmt.Errorf("failed to decode search results: %v", err)
+	}
+
+	var result strings.Builder
+	if answer.Answer != "" {
+		fmt.Fprintf(&result, "Answer: %s\n", answer.Answer)
+	}
+	if answer.AbstractText != "" {
+		fmt.Fprintf(&result, "%s: %s (%s)\n", answer.Heading, answer.AbstractText, answer.AbstractURL)
+	}
+	count := 0
+	for _, topic := range answer.RelatedTopics {
+		if topic.Text == "" || count == maxSearchResults {
+			continue
+		}
+		fmt.Fprintf(&result, "- %s (%s)\n", topic.Text, topic.FirstURL)
+		count++
+	}
+	if result.Len() == 0 {
+		return fmt.Sprintf("No results for %q", params.Query), nil
+	}
+	return strings.TrimSpace(result.String()), nil
+}
+
+// Calculator evaluates arithmetic expressions
+type Calculator struct{}
+
+// NewCalculator creates a calculator plugin
+func NewCalculator() *Calculator {
+	return &Calculator{}
+}
+
+// Name implements LLMPlugin
+func (c *Calculator) Name() string { return "calculator" }
+
+// Description implements LLMPlugin
+func (c *Calculator) Description() string {
+	return "Evaluate a math expression. Supports + - * / % ^, parentheses, the constants pi and e, and the functions sqrt, abs, exp, ln, log, log2, sin, cos, tan, floor, ceil, round, min and max."
+}
+
+// Schema implements LLMPlugin
+func (c *Calculator) Schema() json.RawMessage {
+	return json.RawMessage(`{"type":"object","properties":{"expression":{"type":"string","description":"the expression to evaluate, e.g. (3 + 4) * 2 ^ 10"}},"required":["expression"]}`)
+}
+
+// Execute implements LLMPlugin
+func (c *Calculator) Execute(args json.RawMessage) (string, error) {
+	var params struct {
+		Expression string `json:"expression"`
+	}
+	if err := json.Unmarshal(args, &params); err != nil {
+		return "", fmt.Errorf("invalid arguments: %v", err)
+	}
+
+	value, err := EvalExpression(params.Expression)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64), nil
+}
+
+// EvalExpression evaluates an arithmetic expression. ^ (or **) is
+// exponentiation and binds tighter than unary minus, so -2^2 is -4.
+func EvalExpression(expr string) (float64, error) {
+	p := &exprParser{input: strings.ReplaceAll(expr, "**", "^")}
+	value, err := p.parseSum()
+	if err != nil {
+		return 0, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.input) {
+		return 0, fmt.Errorf("unexpected '%c' at position %d", p.input[p.pos], p.pos+1)
+	}
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, fmt.Errorf("expression has no finite value")
+	}
+	return value, nil
+}
+
+// exprParser is a recursive descent parser for EvalExpression:
+//
+//	sum     = product { ("+" | "-") product }
+//	product = unary { ("*" | "/" | "%") unary }
+//	unary   = ("-" | "+") unary | power
+//	power   = primary [ "^" unary ]
+//	primary = number | name | name "(" sum { "," sum } ")" | "(" sum ")"
+type exprParser struct {
+	input string
+	pos   int
+}
+
+var exprConstants = map[string]float64{
+	"pi": math.Pi,
+	"e":  math.E,
+}
+
+var exprFunctions = map[string]func(args []float64) (float64, error){
+	"sqrt":  unaryFunc(math.Sqrt),
+	"abs":   unaryFunc(math.Abs),
+	"exp":   unaryFunc(math.Exp),
+	"ln":    unaryFunc(math.Log),
+	"log":   unaryFunc(math.Log10),
+	"log2":  unaryFunc(math.Log2),
+	"sin":   unaryFunc(math.Sin),
+	"cos":   unaryFunc(math.Cos),
+	"tan":   unaryFunc(math.Tan),
+	"floor": unaryFunc(math.Floor),
+	"ceil":  unaryFunc(math.Ceil),
+	"round": unaryFunc(math.Round),
+	"min":   foldFunc(math.Min),
+	"max":   foldFunc(math.Max),
+}
+
+func unaryFunc(f func(float64) float64) func([]float64) (float64, error) {
+	return func(args []float64) (float64, error) {
+		if len(args) != 1 {
+			return 0, fmt.Errorf("takes 1 argument, got %d", len(args))
+		}
+		return f(args[0]), nil
+	}
+}
+
+func foldFunc(f func(float64, float64) float64) func([]float64) (float64, error) {
+	return func(args []float64) (float64, error) {
+		if len(args) == 0 {
+			return 0, fmt.Errorf("takes at least 1 argument")
+		}
+		result := args[0]
+		for _, arg := range args[1:] {
+			result = f(result, arg)
+		}
+		return result, nil
+	}
+}
+
+func (p *exprParser) skipSpace() {
+	for p.pos < len(p.input) && unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+}
+
+// accept consumes c if it is the next non-space character
+func (p *exprParser) accept(c byte) bool {
+	p.skipSpace()
+	if p.pos < len(p.input) && p.input[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *exprParser) parseSum() (float64, error) {
+	value, err := p.parseProduct()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		switch {
+		case p.accept('+'):
+			right, err := p.parseProduct()
+			if err != nil {
+				return 0, err
+			}
+			value += right
+		case p.accept('-'):
+			right, err := p.parseProduct()
+			if err != nil {
+				return 0, err
+			}
+			value -= right
+		default:
+			return value, nil
+		}
+	}
+}
+
+func (p *exprParser) parseProduct() (float64, error) {
+	value, err := p.parseUnary()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		var op byte
+		switch {
+		case p.accept('*'):
+			op = '*'
+		case p.accept('/'):
+			op = '/'
+		case p.accept('%'):
+			op = '%'
+		default:
+			return value, nil
+		}
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return 0, err
+		}
+		switch op {
+		case '*':
+			value *= right
+		case '/', '%':
+			if right == 0 {
+				return 0, fmt.Errorf("division by zero")
+			}
+			if op == '/' {
+				value /= right
+			} else {
+				value = math.Mod(value, right)
+			}
+		}
+	}
+}
+
+func (p *exprParser) parseUnary() (float64, error) {
+	if p.accept('-') {
+		value, err := p.parseUnary()
+		return -value, err
+	}
+	if p.accept('+') {
+		return p.parseUnary()
+	}
+	return p.parsePower()
+}
+
+func (p *exprParser) parsePower() (float64, error) {
+	base, err := p.parsePrimary()
+	if err != nil {
+		return 0, err
+	}
+	if !p.accept('^') {
+		return base, nil
+	}
+	// Right-associative: 2^3^2 is 2^9
+	exponent, err := p.parseUnary()
+	if err != nil {
+		return 0, err
+	}
+	return math.Pow(base, exponent), nil
+}
+
+func (p *exprParser) parsePrimary() (float64, error) {
+	p.skipSpace()
+	if p.pos >= len(p.input) {
+		return 0, fmt.Errorf("unexpected end of expression")
+	}
+
+	if p.accept('(') {
+		value, err := p.parseSum()
+		if err != nil {
+			return 0, err
+		}
+		if !p.accept(')') {
+			return 0, fmt.Errorf("missing ')' at position %d", p.pos+1)
+		}
+		return value, nil
+	}
+
+	start := p.pos
+	c := p.input[p.pos]
+	switch {
+	case c >= '0' && c <= '9' || c == '.':
+		for p.pos < len(p.input) && (isDigit(p.input[p.pos]) || p.input[p.pos] == '.') {
+			p.pos++
+		}
+		// Exponent, as in 1.5e3
+		if p.pos < len(p.input) && (p.input[p.pos] == 'e' || p.input[p.pos] == 'E') {
+			next := p.pos + 1
+			if next < len(p.input) && (p.input[next] == '+' || p.input[next] == '-') {
+				next++
+			}
+			if next < len(p.input) && isDigit(p.input[next]) {
+				p.pos = next
+				for p.pos < len(p.input) && isDigit(p.input[p.pos]) {
+					p.pos++
+				}
+			}
+		}
+		value, err := strconv.ParseFloat(p.input[start:p.pos], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid number '%s'", p.input[start:p.pos])
+		}
+		return value, nil
+
+	case unicode.IsLetter(rune(c)):
+		for p.pos < len(p.input) && (unicode.IsLetter(rune(p.input[p.pos])) || isDigit(p.input[p.pos])) {
+			p.pos++
+		}
+		name := strings.ToLower(p.input[start:p.pos])
+
+		if !p.accept('(') {
+			if value, ok := exprConstants[name]; ok {
+				return value, nil
+			}
+			return 0, fmt.Errorf("unknown name '%s'", name)
+		}
+
+		fn, ok := exprFunctions[name]
+		if !ok {
+			return 0, fmt.Errorf("unknown function '%s'", name)
+		}
+		var args []float64
+		if !p.accept(')') {
+			for {
+				arg, err := p.parseSum()
+				if err != nil {
+					return 0, err
+				}
+				args = append(args, arg)
+				if p.accept(')') {
+					break
+				}
+				if !p.accept(',') {
+					return 0, fmt.Errorf("expected ',' or ')' at position %d", p.pos+1)
+				}
+			}
+		}
+		value, err := fn(args)
+		if err != nil {
+			return 0, fmt.Errorf("%s %v", name, err)
+		}
+		return value, nil
+	}
+
+	return 0, fmt.Errorf("unexpected '%c' at position %d", c, p.pos+1)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,198 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Styles of generated system prompts
+const (
+	PromptStyleConcise        = "concise"
+	PromptStyleDetailed       = "detailed"
+	PromptStyleChainOfThought = "chain-of-thought"
+)
+
+// DefaultRefineRounds is how often a generated prompt is scored and
+// rewritten by default
+const DefaultRefineRounds = 3
+
+// promptStyleGuides tell the model how each style of prompt is written
+var promptStyleGuides = map[string]string{
+	PromptStyleConcise:        "Keep it short: a few sentences covering the role, the task, the key rules and the output format. Every sentence must earn its place.",
+	PromptStyleDetailed:       "Be thorough: cover the role, the task, the audience, constraints, edge cases, tone and the exact output format, using short sections.",
+	PromptStyleChainOfThought: "Instruct the assistant to reason step by step before answering: break the problem down, check its work, and then give a clearly marked final answer.",
+}
+
+// PromptStyles returns the supported prompt styles
+func PromptStyles() []string {
+	styles := make([]string, 0, len(promptStyleGuides))
+	for style := range promptStyleGuides {
+		styles = append(styles, style)
+	}
+	sort.Strings(styles)
+	return styles
+}
+
+// PromptRound is one round of self-evaluation: the score and weaknesses
+// the model found in the prompt it was given, and its rewrite
+type PromptRound struct {
+	Round      int
+	Score      int
+	Weaknesses []string
+	Prompt     string
+}
+
+// GeneratedPrompt is a system prompt and the rounds that refined it
+type GeneratedPrompt struct {
+	UseCase string
+	Style   string
+	Initial string
+	Rounds  []PromptRound
+	Prompt  string
+}
+
+// PromptGenerator crafts system prompts with a local model
+type PromptGenerator struct {
+	modelManager *LocalLLMManager
+}
+
+// NewPromptGenerator creates a new prompt generator
+func NewPromptGenerator() *PromptGenerator {
+	return &PromptGenerator{
+		modelManager: NewLocalLLMManager(),
+	}
+}
+
+// Generate asks model for a system prompt for useCase in style, then has
+// it score and rewrite the prompt rounds times. progress, if not nil, is
+// called after each round.
+func (g *PromptGenerator) Generate(model, useCase, style string, rounds int, progress func(PromptRound)) (*GeneratedPrompt, error) {
+	guide, ok := promptStyleGuides[style]
+	if !ok {
+		return nil, fmt.Errorf("unknown style '%s' (valid: %s)", style, strings.Join(PromptStyles(), ", "))
+	}
+	if err := g.modelManager.CheckOllamaAvailability(); err != nil {
+		return nil, err
+	}
+
+	resp, err := g.modelManager.Generate(GenerateRequest{
+		Model:   model,
+		System:  "You are an expert prompt engineer who writes system prompts for LLM-powered agents.",
+		Prompt:  promptGenerationRequest(useCase, guide),
+		Options: map[string]interface{}{"temperature": 0.7},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate prompt: %v", err)
+	}
+
+	prompt := cleanGeneratedPrompt(resp.Response)
+	if prompt == "" {
+		return nil, fmt.Errorf("model returned an empty prompt")
+	}
+	result := &GeneratedPrompt{UseCase: useCase, Style: style, Initial: prompt}
+
+	for i := 1; i <= rounds; i++ {
+		round, err := g.refine(model, useCase, guide, prompt)
+		if err != nil {
+			return nil, fmt.Errorf("round %d failed: %v", i, err)
+		}
+		round.Round = i
+		result.Rounds = append(result.Rounds, *round)
+		if progress != nil {
+			progress(*round)
+		}
+		prompt = round.Prompt
+	}
+
+	result.Prompt = prompt
+	return result, nil
+}
+
+// refine has model score prompt and rewrite it to fix what it found
+func (g *PromptGenerator) refine(model, useCase, guide, prompt string) (*PromptRound, error) {
+	resp, err := g.modelManager.Generate(GenerateRequest{
+		Model:   model,
+		System:  "You are an expert prompt engineer reviewing a system prompt. Reply only with JSON.",
+		Prompt:  promptRefinementRequest(useCase, guide, prompt),
+		Format:  "json",
+		Options: map[string]interface{}{"temperature": 0.2},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var review struct {
+		Score          json.Number `json:"score"`
+		Weaknesses     []string    `json:"weaknesses"`
+		ImprovedPrompt string      `json:"improved_prompt"`
+	}
+	if err := json.Unmarshal([]byte(resp.Response), &review); err != nil {
+		return nil, fmt.Errorf("model returned an invalid review: %v", err)
+	}
+
+	round := &PromptRound{
+		Weaknesses: review.Weaknesses,
+		Prompt:     cleanGeneratedPrompt(review.ImprovedPrompt),
+	}
+	if score, err := review.Score.Float64(); err == nil {
+		round.Score = int(score + 0.5)
+	}
+	if round.Prompt == "" {
+		// Nothing to improve; keep the prompt as it is
+		round.Prompt = prompt
+	}
+	return round, nil
+}
+
+// Test runs each test case with prompt as its system prompt
+func (g *PromptGenerator) Test(model, prompt string, tests []PromptTestCase) ([]PromptTestResult, error) {
+	cases := make([]PromptTestCase, len(tests))
+	for i, test := range tests {
+		test.System = prompt
+		cases[i] = test
+	}
+	return NewPromptTester().Run([]string{model}, cases, 1)
+}
+
+func promptGenerationRequest(useCase, guide string) string {
+	return fmt.Sprintf(`Write the system prompt for an AI agent with this use case: %s
+
+%s
+
+Address the assistant directly ("You are ..."). Reply with the system prompt only, without a title, quotes or commentary.`, useCase, guide)
+}
+
+func promptRefinementRequest(useCase, guide, prompt string) string {
+	return fmt.Sprintf(`This system prompt was written for an AI agent with the use case: %s
+Style requirement: %s
+
+--- SYSTEM PROMPT ---
+%s
+--- END ---
+
+Score the prompt from 1 to 10 for clarity, completeness, fit to the use case and the style requirement. List its weaknesses, then rewrite it to fix them.
+
+Reply with JSON in this form:
+{"score": 7, "weaknesses": ["..."], "improved_prompt": "..."}`, useCase, guide, prompt)
+}
+
+// cleanGeneratedPrompt strips the code fences and quotes models tend to
+// wrap a prompt in
+func cleanGeneratedPrompt(text string) string {
+	text = strings.TrimSpace(text)
+	if strings.HasPrefix(text, "```") {
+		text = strings.TrimPrefix(text, "```")
+		if i := strings.Index(text, "\n"); i != -1 {
+			// Drop the language tag of the fence
+			text = text[i+1:]
+		}
+		text = strings.TrimSuffix(strings.TrimSpace(text), "```")
+	}
+	text = strings.TrimSpace(text)
+	if len(text) >= 2 && text[0] == '"' && text[len(text)-1] == '"' {
+		text = text[1 : len(text)-1]
+	}
+	return strings.TrimSpace(text)
+}
@@ -0,0 +1,241 @@
+package llm
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PromptTester runs a batch of prompts against several models
+type PromptTester struct {
+	modelManager *LocalLLMManager
+}
+
+// PromptTestCase represents a single prompt from the prompts file
+type PromptTestCase struct {
+	ID               string   `yaml:"id"`
+	System           string   `yaml:"system,omitempty"`
+	User             string   `yaml:"user"`
+	ExpectedContains []string `yaml:"expected_contains,omitempty"`
+}
+
+// PromptTestResult represents the outcome of one prompt on one model
+type PromptTestResult struct {
+	Model     string
+	PromptID  string
+	Response  string
+	LatencyMs int64
+	Tokens    int
+	Passed    bool
+	Error     string
+}
+
+// ModelPassRate summarizes the results of a model across all prompts
+type ModelPassRate struct {
+	Model        string
+	Passed       int
+	Total        int
+	PassRate     float64
+	AvgLatencyMs int64
+}
+
+// promptTestFile represents the prompts YAML file
+type promptTestFile struct {
+	Prompts []PromptTestCase `yaml:"prompts"`
+}
+
+// NewPromptTester creates a new prompt tester
+func NewPromptTester() *PromptTester {
+	return &PromptTester{
+		modelManager: NewLocalLLMManager(),
+	}
+}
+
+// LoadPromptTests loads prompt test cases from a YAML file
+func LoadPromptTests(path string) ([]PromptTestCase, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read prompts file: %w", err)
+	}
+
+	var file promptTestFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse prompts file: %w", err)
+	}
+
+	if len(file.Prompts) == 0 {
+		return nil, fmt.Errorf("no prompts defined in %s", path)
+	}
+
+	seen := make(map[string]bool)
+	for i, prompt := range file.Prompts {
+		if prompt.ID == "" {
+			return nil, fmt.Errorf("prompt at index %d is missing an id", i)
+		}
+		if prompt.User == "" {
+			return nil, fmt.Errorf("prompt '%s' is missing a user message", prompt.ID)
+		}
+		if seen[prompt.ID] {
+			return nil, fmt.Errorf("duplicate prompt id '%s'", prompt.ID)
+		}
+		seen[prompt.ID] = true
+	}
+
+	return file.Prompts, nil
+}
+
+// Run sends every prompt to every model with at most concurrency requests
+// in flight and returns the results ordered by model, then prompt
+func (t *PromptTester) Run(models []string, prompts []PromptTestCase, concurrency int) ([]PromptTestResult, error) {
+	if len(models) == 0 {
+		return nil, fmt.Errorf("at least one model is required")
+	}
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	if err := t.modelManager.CheckOllamaAvailability(); err != nil {
+		return nil, err
+	}
+
+	results := make([]PromptTestResult, len(models)*len(prompts))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for mi, model := range models {
+		for pi, prompt := range prompts {
+			wg.Add(1)
+			go func(idx int, model string, prompt PromptTestCase) {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+
+				results[idx] = t.runPrompt(model, prompt)
+			}(mi*len(prompts)+pi, model, prompt)
+		}
+	}
+
+	wg.Wait()
+	return results, nil
+}
+
+// runPrompt runs a single prompt against a model
+func (t *PromptTester) runPrompt(model string, prompt PromptTestCase) PromptTestResult {
+	result := PromptTestResult{
+		Model:    model,
+		PromptID: prompt.ID,
+	}
+
+	start := time.Now()
+	resp, err := t.modelManager.Generate(GenerateRequest{
+		Model:  model,
+		Prompt: prompt.User,
+		System: prompt.System,
+	})
+	result.LatencyMs = time.Since(start).Milliseconds()
+
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	result.Response = resp.Response
+	result.Tokens = resp.EvalCount
+	result.Passed = containsAll(resp.Response, prompt.ExpectedContains)
+
+	return result
+}
+
+// containsAll reports whether text contains every expected substring,
+// ignoring case
+func containsAll(text string, expected []string) bool {
+	lower := strings.ToLower(text)
+	for _, want := range expected {
+		if !strings.Contains(lower, strings.ToLower(want)) {
+			return false
+		}
+	}
+	return true
+}
+
+// WritePromptTestCSV writes prompt test results to a CSV file
+func WritePromptTestCSV(results []PromptTestResult, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create results file: %w", err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	if err := writer.Write([]string{"model", "prompt_id", "response", "latency_ms", "tokens"}); err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		response := result.Response
+		if result.Error != "" {
+			response = "ERROR: " + result.Error
+		}
+		record := []string{
+			result.Model,
+			result.PromptID,
+			response,
+			strconv.FormatInt(result.LatencyMs, 10),
+			strconv.Itoa(result.Tokens),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// PromptTestLeaderboard computes per-model pass rates sorted from best to
+// worst, breaking ties by average latency
+func PromptTestLeaderboard(results []PromptTestResult) []ModelPassRate {
+	byModel := make(map[string]*ModelPassRate)
+	var order []string
+	latency := make(map[string]int64)
+
+	for _, result := range results {
+		stats, ok := byModel[result.Model]
+		if !ok {
+			stats = &ModelPassRate{Model: result.Model}
+			byModel[result.Model] = stats
+			order = append(order, result.Model)
+		}
+		stats.Total++
+		if result.Passed {
+			stats.Passed++
+		}
+		latency[result.Model] += result.LatencyMs
+	}
+
+	leaderboard := make([]ModelPassRate, 0, len(order))
+	for _, model := range order {
+		stats := byModel[model]
+		if stats.Total > 0 {
+			stats.PassRate = float64(stats.Passed) / float64(stats.Total) * 100
+			stats.AvgLatencyMs = latency[model] / int64(stats.Total)
+		}
+		leaderboard = append(leaderboard, *stats)
+	}
+
+	sort.SliceStable(leaderboard, func(i, j int) bool {
+		if leaderboard[i].PassRate != leaderboard[j].PassRate {
+			return leaderboard[i].PassRate > leaderboard[j].PassRate
+		}
+		return leaderboard[i].AvgLatencyMs < leaderboard[j].AvgLatencyMs
+	})
+
+	return leaderboard
+}
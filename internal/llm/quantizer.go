@@ -0,0 +1,322 @@
+package llm
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Quantizer converts GGUF models to lower precision using llama.cpp
+type Quantizer struct {
+	llamaCppPath string
+}
+
+// quantBitsPerWeight maps llama.cpp quantization types to their approximate
+// average bits per weight
+var quantBitsPerWeight = map[string]float64{
+	"F32":    32.0,
+	"F16":    16.0,
+	"BF16":   16.0,
+	"Q8_0":   8.5,
+	"Q6_K":   6.56,
+	"Q5_1":   6.0,
+	"Q5_K_M": 5.69,
+	"Q5_0":   5.5,
+	"Q5_K_S": 5.54,
+	"Q4_1":   5.0,
+	"Q4_K_M": 4.85,
+	"Q4_K_S": 4.58,
+	"Q4_0":   4.5,
+	"Q3_K_L": 4.27,
+	"Q3_K_M": 3.91,
+	"Q3_K_S": 3.5,
+	"Q2_K":   3.35,
+}
+
+// NewQuantizer creates a new quantizer using the llama.cpp installation at
+// llamaCppPath
+func NewQuantizer(llamaCppPath string) *Quantizer {
+	return &Quantizer{
+		llamaCppPath: llamaCppPath,
+	}
+}
+
+// SupportedQuantTypes returns the quantization types known to the quantizer
+func SupportedQuantTypes() []string {
+	types := make([]string, 0, len(quantBitsPerWeight))
+	for t := range quantBitsPerWeight {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// Quantize writes a shell script that runs llama.cpp's quantize binary and
+// executes it to produce outputPath
+func (q *Quantizer) Quantize(inputPath, outputPath, quantType string) error {
+	scriptPath, err := q.WriteScript(inputPath, outputPath, quantType)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command("sh", scriptPath)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("quantization failed: %v", err)
+	}
+
+	return nil
+}
+
+// WriteScript generates the quantization shell script next to outputPath and
+// returns its path
+func (q *Quantizer) WriteScript(inputPath, outputPath, quantType string) (string, error) {
+	quantType = strings.ToUpper(quantType)
+	if _, ok := quantBitsPerWeight[quantType]; !ok {
+		return "", fmt.Errorf("unsupported quantization type '%s' (supported: %s)",
+			quantType, strings.Join(SupportedQuantTypes(), ", "))
+	}
+
+	if _, err := os.Stat(inputPath); err != nil {
+		return "", fmt.Errorf("input model not found: %s", inputPath)
+	}
+
+	quantizeBin, err := q.findQuantizeBinary()
+	if err != nil {
+		return "", err
+	}
+
+	script := fmt.Sprintf(`#!/bin/sh
+# Generated by agent llm quantize
+set -e
+
+QUANTIZE=%s
+INPUT=%s
+OUTPUT=%s
+TYPE=%s
+
+echo "Quantizing $INPUT to $TYPE..."
+"$QUANTIZE" "$INPUT" "$OUTPUT" "$TYPE"
+echo "Wrote $OUTPUT"
+`, shellQuote(quantizeBin), shellQuote(inputPath), shellQuote(outputPath), shellQuote(quantType))
+
+	scriptPath := strings.TrimSuffix(outputPath, filepath.Ext(outputPath)) + ".quantize.sh"
+	if err := os.WriteFile(scriptPath, []byte(script), 0755); err != nil {
+		return "", fmt.Errorf("failed to write quantization script: %v", err)
+	}
+
+	return scriptPath, nil
+}
+
+// findQuantizeBinary locates the llama.cpp quantize binary
+func (q *Quantizer) findQuantizeBinary() (string, error) {
+	if q.llamaCppPath == "" {
+		if path, err := exec.LookPath("llama-quantize"); err == nil {
+			return path, nil
+		}
+		return "", fmt.Errorf("llama.cpp not found. Set LLAMACPP_PATH or the llm.llamacpp-path config key")
+	}
+
+	// Accept either the binary itself or the llama.cpp checkout directory
+	if info, err := os.Stat(q.llamaCppPath); err == nil && !info.IsDir() {
+		return q.llamaCppPath, nil
+	}
+
+	candidates := []string{
+		filepath.Join(q.llamaCppPath, "llama-quantize"),
+		filepath.Join(q.llamaCppPath, "quantize"),
+		filepath.Join(q.llamaCppPath, "build", "bin", "llama-quantize"),
+		filepath.Join(q.llamaCppPath, "build", "bin", "quantize"),
+	}
+	for _, candidate := range candidates {
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		}
+	}
+
+	return "", fmt.Errorf("quantize binary not found in %s", q.llamaCppPath)
+}
+
+// EstimateSize computes the expected size in bytes of ggufPath after
+// quantization to targetType
+func (q *Quantizer) EstimateSize(ggufPath string, targetType string) (int64, error) {
+	bpw, ok := quantBitsPerWeight[strings.ToUpper(targetType)]
+	if !ok {
+		return 0, fmt.Errorf("unsupported quantization type '%s'", targetType)
+	}
+
+	header, err := readGGUFHeader(ggufPath)
+	if err != nil {
+		return 0, err
+	}
+
+	return header.metadataSize + int64(float64(header.parameterCount)*bpw/8), nil
+}
+
+// ggufHeader holds the parts of a GGUF file header needed for size estimates
+type ggufHeader struct {
+	parameterCount int64
+	metadataSize   int64
+}
+
+// GGUF metadata value types
+const (
+	ggufTypeUint8 uint32 = iota
+	ggufTypeInt8
+	ggufTypeUint16
+	ggufTypeInt16
+	ggufTypeUint32
+	ggufTypeInt32
+	ggufTypeFloat32
+	ggufTypeBool
+	ggufTypeString
+	ggufTypeArray
+	ggufTypeUint64
+	ggufTypeInt64
+	ggufTypeFloat64
+)
+
+// countingReader tracks the number of bytes read
+type countingReader struct {
+	r *bufio.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// readGGUFHeader reads the metadata and tensor descriptors of a GGUF file
+func readGGUFHeader(path string) (*ggufHeader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open model: %v", err)
+	}
+	defer file.Close()
+
+	r := &countingReader{r: bufio.NewReader(file)}
+
+	magic := make([]byte, 4)
+	if _, err := io.ReadFull(r, magic); err != nil || string(magic) != "GGUF" {
+		return nil, fmt.Errorf("%s is not a GGUF file", path)
+	}
+
+	var version uint32
+	if err := binary.Read(r, binary.LittleEndian, &version); err != nil {
+		return nil, err
+	}
+	if version < 2 {
+		return nil, fmt.Errorf("unsupported GGUF version %d", version)
+	}
+
+	var tensorCount, kvCount uint64
+	if err := binary.Read(r, binary.LittleEndian, &tensorCount); err != nil {
+		return nil, err
+	}
+	if err := binary.Read(r, binary.LittleEndian, &kvCount); err != nil {
+		return nil, err
+	}
+
+	for i := uint64(0); i < kvCount; i++ {
+		if _, err := readGGUFString(r); err != nil {
+			return nil, fmt.Errorf("failed to read metadata key: %v", err)
+		}
+		var valueType uint32
+		if err := binary.Read(r, binary.LittleEndian, &valueType); err != nil {
+			return nil, err
+		}
+		if err := skipGGUFValue(r, valueType); err != nil {
+			return nil, fmt.Errorf("failed to read metadata value: %v", err)
+		}
+	}
+
+	header := &ggufHeader{}
+	for i := uint64(0); i < tensorCount; i++ {
+		if _, err := readGGUFString(r); err != nil {
+			return nil, fmt.Errorf("failed to read tensor name: %v", err)
+		}
+		var nDims uint32
+		if err := binary.Read(r, binary.LittleEndian, &nDims); err != nil {
+			return nil, err
+		}
+		elements := int64(1)
+		for d := uint32(0); d < nDims; d++ {
+			var dim uint64
+			if err := binary.Read(r, binary.LittleEndian, &dim); err != nil {
+				return nil, err
+			}
+			elements *= int64(dim)
+		}
+		// Tensor type and data offset
+		if _, err := io.CopyN(io.Discard, r, 4+8); err != nil {
+			return nil, err
+		}
+		header.parameterCount += elements
+	}
+
+	header.metadataSize = r.n
+	return header, nil
+}
+
+// readGGUFString reads a length-prefixed GGUF string
+func readGGUFString(r io.Reader) (string, error) {
+	var length uint64
+	if err := binary.Read(r, binary.LittleEndian, &length); err != nil {
+		return "", err
+	}
+	if length > 1<<24 {
+		return "", fmt.Errorf("string length %d exceeds limit", length)
+	}
+	buf := make([]byte, length)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
+
+// skipGGUFValue discards a metadata value of the given type
+func skipGGUFValue(r io.Reader, valueType uint32) error {
+	switch valueType {
+	case ggufTypeUint8, ggufTypeInt8, ggufTypeBool:
+		_, err := io.CopyN(io.Discard, r, 1)
+		return err
+	case ggufTypeUint16, ggufTypeInt16:
+		_, err := io.CopyN(io.Discard, r, 2)
+		return err
+	case ggufTypeUint32, ggufTypeInt32, ggufTypeFloat32:
+		_, err := io.CopyN(io.Discard, r, 4)
+		return err
+	case ggufTypeUint64, ggufTypeInt64, ggufTypeFloat64:
+		_, err := io.CopyN(io.Discard, r, 8)
+		return err
+	case ggufTypeString:
+		_, err := readGGUFString(r)
+		return err
+	case ggufTypeArray:
+		var elemType uint32
+		var count uint64
+		if err := binary.Read(r, binary.LittleEndian, &elemType); err != nil {
+			return err
+		}
+		if err := binary.Read(r, binary.LittleEndian, &count); err != nil {
+			return err
+		}
+		for i := uint64(0); i < count; i++ {
+			if err := skipGGUFValue(r, elemType); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown GGUF value type %d", valueType)
+	}
+}
@@ -0,0 +1,134 @@
+package llm
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Persona is a character a model plays, read from a persona.yaml
+type Persona struct {
+	Name         string `yaml:"name"`
+	Role         string `yaml:"role"`
+	Instructions string `yaml:"instructions"`
+	// Temperature is the sampling temperature; nil keeps the model default
+	Temperature    *float64        `yaml:"temperature,omitempty"`
+	ExampleDialogs []ExampleDialog `yaml:"example_dialogs,omitempty"`
+}
+
+// ExampleDialog is one exchange shown to the model as a few-shot example
+type ExampleDialog struct {
+	User      string `yaml:"user"`
+	Assistant string `yaml:"assistant"`
+}
+
+// LoadPersona reads and validates a persona file
+func LoadPersona(path string) (*Persona, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read persona file: %w", err)
+	}
+
+	var persona Persona
+	if err := yaml.Unmarshal(data, &persona); err != nil {
+		return nil, fmt.Errorf("failed to parse persona file: %w", err)
+	}
+
+	if strings.TrimSpace(persona.Name) == "" {
+		return nil, fmt.Errorf("persona in %s is missing a name", path)
+	}
+	if strings.TrimSpace(persona.Instructions) == "" {
+		return nil, fmt.Errorf("persona '%s' is missing instructions", persona.Name)
+	}
+	if t := persona.Temperature; t != nil && (*t < 0 || *t > 2) {
+		return nil, fmt.Errorf("persona '%s' has temperature %g; it must be between 0 and 2", persona.Name, *t)
+	}
+	for i, dialog := range persona.ExampleDialogs {
+		if dialog.User == "" || dialog.Assistant == "" {
+			return nil, fmt.Errorf("example dialog %d of persona '%s' needs both user and assistant", i+1, persona.Name)
+		}
+	}
+
+	return &persona, nil
+}
+
+// SystemPrompt builds the system prompt from the persona's identity,
+// instructions and example dialogs
+func (p *Persona) SystemPrompt() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "You are %s", p.Name)
+	if p.Role != "" {
+		fmt.Fprintf(&b, ", %s", p.Role)
+	}
+	b.WriteString(". Stay in character for the whole conversation: never mention that you are an AI model playing a role, and never break character even if asked to.\n\n")
+	fmt.Fprintf(&b, "Instructions:\n%s\n", strings.TrimSpace(p.Instructions))
+
+	if len(p.ExampleDialogs) > 0 {
+		fmt.Fprintf(&b, "\nExamples of how %s answers:\n", p.Name)
+		for _, dialog := range p.ExampleDialogs {
+			fmt.Fprintf(&b, "\nUser: %s\n%s: %s\n", strings.TrimSpace(dialog.User), p.Name, strings.TrimSpace(dialog.Assistant))
+		}
+	}
+
+	return b.String()
+}
+
+// Roleplay is an in-memory conversation with a model playing a persona
+type Roleplay struct {
+	modelManager *LocalLLMManager
+	model        string
+	persona      *Persona
+	// driftInterval re-sends the system prompt every driftInterval turns;
+	// 0 disables it
+	driftInterval int
+	history       []Message
+	turns         int
+}
+
+// NewRoleplay starts a conversation with model playing persona. With
+// driftInterval > 0 the system prompt is repeated every driftInterval turns
+// so long conversations don't drift out of character.
+func NewRoleplay(model string, persona *Persona, driftInterval int) *Roleplay {
+	return &Roleplay{
+		modelManager:  NewLocalLLMManager(),
+		model:         model,
+		persona:       persona,
+		driftInterval: driftInterval,
+		history:       []Message{{Role: "system", Content: persona.SystemPrompt()}},
+	}
+}
+
+// Send sends a user message, streams the reply to onToken and adds both to
+// the history. A failed turn leaves the history unchanged.
+func (r *Roleplay) Send(input string, onToken func(string)) (string, error) {
+	messages := r.history
+	if r.driftInterval > 0 && r.turns > 0 && r.turns%r.driftInterval == 0 {
+		messages = append(messages, Message{Role: "system", Content: r.persona.SystemPrompt()})
+	}
+	messages = append(messages, Message{Role: "user", Content: input})
+
+	options := map[string]interface{}{}
+	if r.persona.Temperature != nil {
+		options["temperature"] = *r.persona.Temperature
+	}
+
+	final, err := r.modelManager.StreamChat(ChatRequest{
+		Model:    r.model,
+		Messages: messages,
+		Options:  options,
+	}, func(chunk ChatResponse) error {
+		if onToken != nil {
+			onToken(chunk.Message.Content)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	r.history = append(messages, final.Message)
+	r.turns++
+	return final.Message.Content, nil
+}
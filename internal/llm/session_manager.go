@@ -0,0 +1,303 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// sessionLockTimeout is how long to wait for another process to release
+	// a session
+	sessionLockTimeout = 10 * time.Second
+
+	// sessionLockStale is the age after which a lock file is assumed to be
+	// left behind by a crashed process
+	sessionLockStale = 5 * time.Minute
+)
+
+var sessionNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Session is a persistent multi-turn conversation with a model
+type Session struct {
+	Name         string    `json:"name"`
+	Model        string    `json:"model"`
+	SystemPrompt string    `json:"systemPrompt,omitempty"`
+	History      []Message `json:"history"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+// SessionManager stores sessions as JSON files, one per session. Updates are
+// serialized within the process by a mutex and across processes by a lock
+// file next to the session file.
+type SessionManager struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewSessionManager creates a session manager storing sessions in dir
+func NewSessionManager(dir string) *SessionManager {
+	return &SessionManager{dir: dir}
+}
+
+// DefaultSessionDir returns the directory sessions are saved in
+func DefaultSessionDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %v", err)
+	}
+	return filepath.Join(home, ".agent", "sessions"), nil
+}
+
+// Create creates a new, empty session
+func (s *SessionManager) Create(name, model, systemPrompt string) (*Session, error) {
+	if err := validateSessionName(name); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create session directory: %v", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	unlock, err := s.lock(name)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	if _, err := os.Stat(s.path(name)); err == nil {
+		return nil, fmt.Errorf("session '%s' already exists", name)
+	}
+
+	now := time.Now().UTC()
+	session := &Session{
+		Name:         name,
+		Model:        model,
+		SystemPrompt: systemPrompt,
+		History:      []Message{},
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if err := s.save(session); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// Load reads a session
+func (s *SessionManager) Load(name string) (*Session, error) {
+	if err := validateSessionName(name); err != nil {
+		return nil, err
+	}
+	return s.read(s.path(name))
+}
+
+// Append adds messages to a session's history and returns the updated session
+func (s *SessionManager) Append(name string, messages ...Message) (*Session, error) {
+	return s.Update(name, func(session *Session) error {
+		session.History = append(session.History, messages...)
+		return nil
+	})
+}
+
+// Update applies fn to a session while holding its lock and saves the result.
+// Changes made by other processes since the session was loaded are kept
+// because the session is re-read under the lock.
+func (s *SessionManager) Update(name string, fn func(*Session) error) (*Session, error) {
+	if err := validateSessionName(name); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	unlock, err := s.lock(name)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	session, err := s.read(s.path(name))
+	if err != nil {
+		return nil, err
+	}
+	if err := fn(session); err != nil {
+		return nil, err
+	}
+	session.UpdatedAt = time.Now().UTC()
+	if err := s.save(session); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// List returns all sessions, most recently used first
+func (s *SessionManager) List() ([]Session, error) {
+	files, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var sessions []Session
+	for _, file := range files {
+		session, err := s.read(file)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, *session)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].UpdatedAt.After(sessions[j].UpdatedAt)
+	})
+
+	return sessions, nil
+}
+
+// Messages returns the messages to send to the model: the system prompt, if
+// any, followed by the history
+func (session *Session) Messages() []Message {
+	var messages []Message
+	if session.SystemPrompt != "" {
+		messages = append(messages, Message{Role: "system", Content: session.SystemPrompt})
+	}
+	return append(messages, session.History...)
+}
+
+// ExportSession writes a session as markdown or json
+func ExportSession(session *Session, format string, w io.Writer) error {
+	switch format {
+	case "markdown", "md":
+		return exportSessionMarkdown(session, w)
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(session)
+	default:
+		return fmt.Errorf("invalid format '%s' (valid: markdown, json)", format)
+	}
+}
+
+// exportSessionMarkdown writes the conversation as a markdown document with
+// a heading per turn
+func exportSessionMarkdown(session *Session, w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Session: %s\n\n", session.Name)
+	fmt.Fprintf(&b, "- **Model:** %s\n", session.Model)
+	fmt.Fprintf(&b, "- **Created:** %s\n", session.CreatedAt.Format(time.RFC3339))
+	fmt.Fprintf(&b, "- **Last used:** %s\n", session.UpdatedAt.Format(time.RFC3339))
+	fmt.Fprintf(&b, "- **Messages:** %d\n", len(session.History))
+
+	if session.SystemPrompt != "" {
+		fmt.Fprintf(&b, "\n## System\n\n%s\n", session.SystemPrompt)
+	}
+
+	for _, message := range session.History {
+		role := message.Role
+		if role != "" {
+			role = strings.ToUpper(role[:1]) + role[1:]
+		}
+		fmt.Fprintf(&b, "\n## %s\n\n%s\n", role, strings.TrimSpace(message.Content))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// path returns the file a session is stored in
+func (s *SessionManager) path(name string) string {
+	return filepath.Join(s.dir, name+".json")
+}
+
+// read reads a session file
+func (s *SessionManager) read(path string) (*Session, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("session '%s' not found", strings.TrimSuffix(filepath.Base(path), ".json"))
+		}
+		return nil, fmt.Errorf("failed to read session: %v", err)
+	}
+
+	var session Session
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, fmt.Errorf("invalid session file %s: %v", filepath.Base(path), err)
+	}
+
+	return &session, nil
+}
+
+// save writes a session atomically so readers never see a partial file
+func (s *SessionManager) save(session *Session) error {
+	data, err := json.MarshalIndent(session, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal session: %v", err)
+	}
+
+	tmp, err := os.CreateTemp(s.dir, session.Name+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to save session: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save session: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save session: %v", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path(session.Name)); err != nil {
+		return fmt.Errorf("failed to save session: %v", err)
+	}
+	return nil
+}
+
+// lock acquires the cross-process lock of a session by creating its lock
+// file exclusively, and returns a function releasing it
+func (s *SessionManager) lock(name string) (func(), error) {
+	lockPath := s.path(name) + ".lock"
+	deadline := time.Now().Add(sessionLockTimeout)
+
+	for {
+		file, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			fmt.Fprintf(file, "%d\n", os.Getpid())
+			file.Close()
+			return func() { os.Remove(lockPath) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to lock session: %v", err)
+		}
+
+		if info, err := os.Stat(lockPath); err == nil && time.Since(info.ModTime()) > sessionLockStale {
+			os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("session '%s' is locked by another process (remove %s if it is stale)", name, lockPath)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// validateSessionName rejects names that are not safe file names
+func validateSessionName(name string) error {
+	if !sessionNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid session name '%s': use letters, digits, '.', '_' and '-'", name)
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package llm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Timestamp granularities of a transcript
+const (
+	TimestampsNone    = "none"
+	TimestampsSegment = "segment"
+	TimestampsWord    = "word"
+)
+
+// Segment is a span of a transcript, in seconds from the start of the audio
+type Segment struct {
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+	Text  string  `json:"text"`
+}
+
+// TranscriptResult is the transcript of an audio file. Words is only set
+// for word-level timestamps.
+type TranscriptResult struct {
+	Text     string    `json:"text"`
+	Segments []Segment `json:"segments"`
+	Words    []Segment `json:"words,omitempty"`
+	Language string    `json:"language"`
+}
+
+// STTClient transcribes audio with a local Whisper model. It talks to the
+// OpenAI-compatible transcription endpoint (POST /v1/audio/transcriptions)
+// served by Faster-Whisper servers and other Whisper runners, including
+// those put in front of Ollama.
+type STTClient struct {
+	baseURL string
+	// Timestamps is the granularity of the timestamps requested, one of
+	// TimestampsNone, TimestampsSegment and TimestampsWord
+	Timestamps string
+}
+
+// NewSTTClient creates a speech-to-text client for the server at baseURL,
+// or for the Ollama endpoint in OLLAMA_HOST when baseURL is empty
+func NewSTTClient(baseURL string) *STTClient {
+	if baseURL == "" {
+		baseURL = ollamaURLFromEnv()
+	}
+	return &STTClient{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		Timestamps: TimestampsSegment,
+	}
+}
+
+// URL returns the server the client talks to
+func (c *STTClient) URL() string {
+	return c.baseURL
+}
+
+// Transcribe transcribes the audio file at audioPath with modelName. An
+// empty language lets the model detect it.
+func (c *STTClient) Transcribe(modelName, audioPath, language string) (TranscriptResult, error) {
+	switch c.Timestamps {
+	case TimestampsNone, TimestampsSegment, TimestampsWord:
+	default:
+		return TranscriptResult{}, fmt.Errorf("invalid timestamps '%s' (valid: none, segment, word)", c.Timestamps)
+	}
+
+	audio, err := os.Open(audioPath)
+	if err != nil {
+		return TranscriptResult{}, fmt.Errorf("failed to open audio file: %w", err)
+	}
+	defer audio.Close()
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", filepath.Base(audioPath))
+	if err != nil {
+		return TranscriptResult{}, err
+	}
+	if _, err := io.Copy(part, audio); err != nil {
+		return TranscriptResult{}, fmt.Errorf("failed to read audio file: %w", err)
+	}
+	form.WriteField("model", modelName)
+	form.WriteField("response_format", "verbose_json")
+	if language != "" {
+		form.WriteField("language", language)
+	}
+	if c.Timestamps != TimestampsNone {
+		form.WriteField("timestamp_granularities[]", TimestampsSegment)
+	}
+	if c.Timestamps == TimestampsWord {
+		form.WriteField("timestamp_granularities[]", TimestampsWord)
+	}
+	if err := form.Close(); err != nil {
+		return TranscriptResult{}, err
+	}
+
+	endpoint := c.baseURL + "/v1/audio/transcriptions"
+	client := &http.Client{Timeout: generateTimeout}
+	resp, err := client.Post(endpoint, form.FormDataContentType(), &body)
+	if err != nil {
+		return TranscriptResult{}, fmt.Errorf("failed to call %s: %v", endpoint, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return TranscriptResult{}, fmt.Errorf("%s has no transcription endpoint; point --server at a Whisper server such as faster-whisper-server", c.baseURL)
+	}
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(resp.Body)
+		return TranscriptResult{}, fmt.Errorf("transcription returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	var result struct {
+		Text     string `json:"text"`
+		Language string `json:"language"`
+		Segments []struct {
+			Start float64 `json:"start"`
+			End   float64 `json:"end"`
+			Text  string  `json:"text"`
+		} `json:"segments"`
+		Words []struct {
+			Start float64 `json:"start"`
+			End   float64 `json:"end"`
+			Word  string  `json:"word"`
+		} `json:"words"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return TranscriptResult{}, fmt.Errorf("failed to decode transcription: %v", err)
+	}
+
+	transcript := TranscriptResult{
+		Text:     strings.TrimSpace(result.Text),
+		Language: result.Language,
+	}
+	if transcript.Language == "" {
+		transcript.Language = language
+	}
+	for _, s := range result.Segments {
+		transcript.Segments = append(transcript.Segments, Segment{Start: s.Start, End: s.End, Text: strings.TrimSpace(s.Text)})
+	}
+	if c.Timestamps == TimestampsWord {
+		for _, w := range result.Words {
+			transcript.Words = append(transcript.Words, Segment{Start: w.Start, End: w.End, Text: strings.TrimSpace(w.Word)})
+		}
+	}
+
+	return transcript, nil
+}
+
+// FormatTimestamp formats seconds as HH:MM:SS.mmm
+func FormatTimestamp(seconds float64) string {
+	ms := int64(seconds*1000 + 0.5)
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
@@ -0,0 +1,165 @@
+package llm
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// defaultContextWindow is Ollama's num_ctx when the model sets none
+	defaultContextWindow = 2048
+	// summaryPromptOverhead is the number of tokens reserved for the
+	// instructions wrapped around each chunk
+	summaryPromptOverhead = 200
+	// maxReduceRounds bounds how often summaries are summarized again
+	maxReduceRounds = 5
+)
+
+// summaryStyles maps each style to the instructions given to the model
+var summaryStyles = map[string]string{
+	"brief":    "Write a brief summary of one short paragraph covering only the main points.",
+	"detailed": "Write a detailed summary that keeps every important fact, name, number and conclusion.",
+	"bullets":  "Write the summary as a markdown bullet list with one key point per bullet.",
+}
+
+// SummaryStyles returns the valid summary styles
+func SummaryStyles() []string {
+	styles := make([]string, 0, len(summaryStyles))
+	for style := range summaryStyles {
+		styles = append(styles, style)
+	}
+	sort.Strings(styles)
+	return styles
+}
+
+// Summarizer summarizes documents with a local model. Documents that don't
+// fit in the model's context window are split into chunks that are
+// summarized separately, and the chunk summaries are then summarized
+// together (map-reduce).
+type Summarizer struct {
+	modelManager *LocalLLMManager
+	tokenizer    *Tokenizer
+	progress     io.Writer
+}
+
+// NewSummarizer creates a summarizer that reports progress through
+// multi-chunk documents to progress, which may be nil
+func NewSummarizer(progress io.Writer) *Summarizer {
+	return &Summarizer{
+		modelManager: NewLocalLLMManager(),
+		tokenizer:    NewTokenizer(),
+		progress:     progress,
+	}
+}
+
+// Summarize summarizes text in the given style in at most maxTokens tokens
+func (s *Summarizer) Summarize(modelName, text, style string, maxTokens int) (string, error) {
+	if _, ok := summaryStyles[style]; !ok {
+		return "", fmt.Errorf("invalid style '%s' (valid: %s)", style, strings.Join(SummaryStyles(), ", "))
+	}
+	if maxTokens <= 0 {
+		return "", fmt.Errorf("max length must be positive")
+	}
+	if strings.TrimSpace(text) == "" {
+		return "", fmt.Errorf("nothing to summarize")
+	}
+
+	budget := s.contextWindow(modelName) - summaryPromptOverhead - maxTokens
+	if budget < summaryPromptOverhead {
+		return "", fmt.Errorf("max length %d leaves no room for input in %s's context window", maxTokens, modelName)
+	}
+
+	for round := 0; ; round++ {
+		chunks, err := s.split(modelName, text, budget)
+		if err != nil {
+			return "", err
+		}
+		if len(chunks) == 1 {
+			return s.summarizeChunk(modelName, chunks[0], style, maxTokens)
+		}
+		if round == maxReduceRounds {
+			return "", fmt.Errorf("document is still too long after %d rounds of summarization", maxReduceRounds)
+		}
+
+		// Map: summarize each chunk in detail so the final pass has the facts
+		summaries := make([]string, 0, len(chunks))
+		for i, chunk := range chunks {
+			s.report("📄 Summarizing chunk %d/%d...", i+1, len(chunks))
+			summary, err := s.summarizeChunk(modelName, chunk, "detailed", maxTokens)
+			if err != nil {
+				return "", fmt.Errorf("failed to summarize chunk %d: %v", i+1, err)
+			}
+			summaries = append(summaries, summary)
+		}
+		s.report("📄 Combining %d chunk summaries...\n", len(chunks))
+
+		// Reduce: the summaries become the next round's document
+		text = strings.Join(summaries, "\n\n")
+	}
+}
+
+// split splits text into chunks of at most budget tokens. The chunk size in
+// bytes is estimated from the token density of the whole text.
+func (s *Summarizer) split(modelName, text string, budget int) ([]string, error) {
+	tokens, err := s.tokenizer.CountTokens(modelName, text)
+	if err != nil {
+		return nil, err
+	}
+	if tokens <= budget {
+		return []string{text}, nil
+	}
+
+	// Leave a 10% margin for chunks denser than the average
+	size := len(text) * budget / tokens * 9 / 10
+	return chunkText(text, size), nil
+}
+
+// summarizeChunk asks the model to summarize a single chunk
+func (s *Summarizer) summarizeChunk(modelName, text, style string, maxTokens int) (string, error) {
+	prompt := fmt.Sprintf("%s Use at most %d words. Reply with the summary only.\n\nText:\n%s", summaryStyles[style], maxTokens*3/4, text)
+
+	resp, err := s.modelManager.Generate(GenerateRequest{
+		Model:  modelName,
+		System: summarizerSystemPrompt,
+		Prompt: prompt,
+		Options: map[string]interface{}{
+			"num_predict": maxTokens,
+			"temperature": 0.3,
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(resp.Response), nil
+}
+
+const summarizerSystemPrompt = `You summarize documents accurately. Never add information that is not in the text.`
+
+// contextWindow returns the model's num_ctx parameter, or Ollama's default
+func (s *Summarizer) contextWindow(modelName string) int {
+	show, err := s.modelManager.ShowModel(modelName)
+	if err != nil {
+		return defaultContextWindow
+	}
+
+	for _, line := range strings.Split(show.Parameters, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == "num_ctx" {
+			if n, err := strconv.Atoi(fields[1]); err == nil && n > 0 {
+				return n
+			}
+		}
+	}
+	return defaultContextWindow
+}
+
+// report writes a progress line, overwriting the previous one
+func (s *Summarizer) report(format string, args ...interface{}) {
+	if s.progress != nil {
+		fmt.Fprintf(s.progress, "\r"+format, args...)
+	}
+}
@@ -0,0 +1,72 @@
+package llm
+
+import (
+	"bufio"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// SystemInfo describes the machine a benchmark ran on. Zero or empty values
+// could not be detected.
+type SystemInfo struct {
+	OS            string
+	Arch          string
+	CPUs          int
+	MemoryBytes   uint64
+	OllamaVersion string
+}
+
+// SystemInfo returns the machine the benchmarks run on and the version of
+// the Ollama server serving the models
+func (b *ModelBenchmarker) SystemInfo() SystemInfo {
+	info := SystemInfo{
+		OS:          runtime.GOOS,
+		Arch:        runtime.GOARCH,
+		CPUs:        runtime.NumCPU(),
+		MemoryBytes: totalMemory(),
+	}
+	if version, err := b.modelManager.GetOllamaVersion(); err == nil {
+		info.OllamaVersion = version
+	}
+	return info
+}
+
+// totalMemory returns the installed RAM in bytes on Linux and macOS, and 0
+// elsewhere or when it cannot be read
+func totalMemory() uint64 {
+	switch runtime.GOOS {
+	case "linux":
+		f, err := os.Open("/proc/meminfo")
+		if err != nil {
+			return 0
+		}
+		defer f.Close()
+
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			// MemTotal:       16307988 kB
+			fields := strings.Fields(scanner.Text())
+			if len(fields) >= 2 && fields[0] == "MemTotal:" {
+				kb, err := strconv.ParseUint(fields[1], 10, 64)
+				if err != nil {
+					return 0
+				}
+				return kb * 1024
+			}
+		}
+	case "darwin":
+		output, err := exec.Command("sysctl", "-n", "hw.memsize").Output()
+		if err != nil {
+			return 0
+		}
+		bytes, err := strconv.ParseUint(strings.TrimSpace(string(output)), 10, 64)
+		if err != nil {
+			return 0
+		}
+		return bytes
+	}
+	return 0
+}
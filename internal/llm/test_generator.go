@@ -0,0 +1,185 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// TestGenerator writes pytest tests for an existing agent by asking a local
+// model to read its agent.yaml and main.py
+type TestGenerator struct {
+	modelManager *LocalLLMManager
+}
+
+// TestGenerationOptions configures a test generation run
+type TestGenerationOptions struct {
+	AgentDir       string
+	Model          string
+	CoverageTarget int // Percentage of main.py lines the tests should cover
+	// OnToken is called with every streamed piece of the model's response
+	OnToken func(string)
+}
+
+// GeneratedTests is the JSON document the model is asked to return
+type GeneratedTests struct {
+	// Setup holds the imports, fixtures and helpers shared by the tests
+	Setup string          `json:"setup"`
+	Tests []GeneratedTest `json:"tests"`
+}
+
+// GeneratedTest is a single pytest test function
+type GeneratedTest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Code        string `json:"code"`
+}
+
+var testNamePattern = regexp.MustCompile(`^test_[A-Za-z0-9_]+$`)
+
+// NewTestGenerator creates a new test generator
+func NewTestGenerator() *TestGenerator {
+	return &TestGenerator{
+		modelManager: NewLocalLLMManager(),
+	}
+}
+
+// Generate sends the agent's agent.yaml and main.py to the model and
+// returns the parsed tests. Tests with invalid names or code that does not
+// define them are dropped.
+func (g *TestGenerator) Generate(options TestGenerationOptions) (*GeneratedTests, error) {
+	if options.CoverageTarget < 1 || options.CoverageTarget > 100 {
+		return nil, fmt.Errorf("coverage target must be between 1 and 100")
+	}
+
+	agentYAML, err := os.ReadFile(filepath.Join(options.AgentDir, "agent.yaml"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read agent.yaml: %v", err)
+	}
+	mainPy, err := os.ReadFile(filepath.Join(options.AgentDir, "main.py"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read main.py: %v", err)
+	}
+
+	var response strings.Builder
+	_, err = g.modelManager.StreamGenerate(GenerateRequest{
+		Model:   options.Model,
+		System:  testGeneratorSystemPrompt,
+		Prompt:  buildTestPrompt(string(agentYAML), string(mainPy), options.CoverageTarget),
+		Format:  "json",
+		Options: map[string]interface{}{"temperature": 0.2},
+	}, func(chunk GenerateResponse) error {
+		response.WriteString(chunk.Response)
+		if options.OnToken != nil {
+			options.OnToken(chunk.Response)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return parseGeneratedTests(response.String())
+}
+
+const testGeneratorSystemPrompt = `You are a senior Python engineer writing pytest tests. You only answer with a JSON object, without markdown or explanations.`
+
+// buildTestPrompt builds the structured prompt asking for tests as JSON
+func buildTestPrompt(agentYAML, mainPy string, coverageTarget int) string {
+	return fmt.Sprintf(`Write pytest tests for the AI agent below.
+
+Requirements:
+- The tests run with pytest from the project root; import the application with "from main import ...".
+- Test the real behavior of main.py: every HTTP endpoint, input validation, error handling and edge cases.
+- Mock calls to LLM providers and other network services with unittest.mock so the tests run offline.
+- Aim for at least %d%% line coverage of main.py.
+- Each test function name starts with "test_" and is unique.
+
+Respond with JSON in exactly this shape:
+{
+  "setup": "imports, fixtures and helpers shared by all tests",
+  "tests": [
+    {"name": "test_example", "description": "what the test checks", "code": "def test_example():\n    ..."}
+  ]
+}
+
+agent.yaml:
+%s
+
+main.py:
+%s
+`, coverageTarget, fence(agentYAML, "yaml"), fence(mainPy, "python"))
+}
+
+// fence wraps code in a markdown code block
+func fence(code, language string) string {
+	return "```" + language + "\n" + strings.TrimRight(code, "\n") + "\n```"
+}
+
+// parseGeneratedTests extracts the JSON object from a model response, which
+// may be wrapped in a markdown code block, and validates the tests
+func parseGeneratedTests(response string) (*GeneratedTests, error) {
+	start := strings.Index(response, "{")
+	end := strings.LastIndex(response, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("model response contains no JSON object")
+	}
+
+	var generated GeneratedTests
+	if err := json.Unmarshal([]byte(response[start:end+1]), &generated); err != nil {
+		return nil, fmt.Errorf("failed to parse model response as JSON: %v", err)
+	}
+
+	seen := make(map[string]bool)
+	var tests []GeneratedTest
+	for _, test := range generated.Tests {
+		if !testNamePattern.MatchString(test.Name) || seen[test.Name] {
+			continue
+		}
+		if !strings.Contains(test.Code, "def "+test.Name+"(") {
+			continue
+		}
+		seen[test.Name] = true
+		tests = append(tests, test)
+	}
+	if len(tests) == 0 {
+		return nil, fmt.Errorf("model response contains no valid tests")
+	}
+
+	generated.Tests = tests
+	return &generated, nil
+}
+
+// RenderPytest renders generated tests as a pytest module. The project root
+// is put on sys.path so main.py imports from the tests directory.
+func RenderPytest(agentName, model string, generated *GeneratedTests) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, `"""
+Tests for %s, generated by agent llm generate-tests with %s.
+Review them before relying on them.
+"""
+
+import os
+import sys
+
+sys.path.insert(0, os.path.dirname(os.path.dirname(os.path.abspath(__file__))))
+
+`, agentName, model)
+
+	if setup := strings.TrimSpace(generated.Setup); setup != "" {
+		b.WriteString(setup + "\n")
+	}
+
+	for _, test := range generated.Tests {
+		b.WriteString("\n\n")
+		if test.Description != "" {
+			fmt.Fprintf(&b, "# %s\n", strings.ReplaceAll(strings.TrimSpace(test.Description), "\n", " "))
+		}
+		b.WriteString(strings.TrimSpace(test.Code) + "\n")
+	}
+
+	return b.String()
+}
@@ -0,0 +1,133 @@
+package llm
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// tokenizerChunkSize is the maximum number of bytes sent to Ollama in one
+// request. Ollama truncates prompts longer than the model's context window,
+// so large inputs are counted in chunks well below the smallest common
+// context (2048 tokens at roughly 4 bytes per token).
+const tokenizerChunkSize = 6000
+
+// Tokenizer counts tokens using a model's own tokenizer via Ollama
+type Tokenizer struct {
+	modelManager *LocalLLMManager
+}
+
+// SentenceTokens is the token count of a single sentence
+type SentenceTokens struct {
+	Text   string
+	Tokens int
+}
+
+// NewTokenizer creates a new tokenizer
+func NewTokenizer() *Tokenizer {
+	return &Tokenizer{
+		modelManager: NewLocalLLMManager(),
+	}
+}
+
+// CountTokens returns the number of tokens text encodes to for modelName.
+// The text is sent as a raw prompt with generation disabled, and the count
+// is read from prompt_eval_count. Splitting input into chunks can change the
+// count by a token or so at each chunk boundary.
+func (t *Tokenizer) CountTokens(modelName, text string) (int, error) {
+	total := 0
+	for _, chunk := range chunkText(text, tokenizerChunkSize) {
+		count, err := t.countChunk(modelName, chunk)
+		if err != nil {
+			return 0, err
+		}
+		total += count
+	}
+	return total, nil
+}
+
+// CountSentences returns the token count of every sentence in text
+func (t *Tokenizer) CountSentences(modelName, text string) ([]SentenceTokens, error) {
+	var counts []SentenceTokens
+	for _, sentence := range SplitSentences(text) {
+		tokens, err := t.CountTokens(modelName, sentence)
+		if err != nil {
+			return nil, err
+		}
+		counts = append(counts, SentenceTokens{Text: sentence, Tokens: tokens})
+	}
+	return counts, nil
+}
+
+// countChunk counts the tokens of a single chunk that fits in the context
+func (t *Tokenizer) countChunk(modelName, text string) (int, error) {
+	if strings.TrimSpace(text) == "" {
+		return 0, nil
+	}
+
+	resp, err := t.modelManager.Generate(GenerateRequest{
+		Model:  modelName,
+		Prompt: text,
+		Raw:    true,
+		Options: map[string]interface{}{
+			"num_predict": 0,
+		},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count tokens: %v", err)
+	}
+
+	return resp.PromptEvalCount, nil
+}
+
+// chunkText splits text into chunks of at most size bytes, breaking at
+// whitespace where possible and never inside a UTF-8 sequence
+func chunkText(text string, size int) []string {
+	var chunks []string
+	for len(text) > size {
+		cut := strings.LastIndexFunc(text[:size], unicode.IsSpace)
+		if cut <= 0 {
+			cut = size
+			for cut > 0 && !utf8.RuneStart(text[cut]) {
+				cut--
+			}
+		}
+		chunks = append(chunks, text[:cut])
+		text = text[cut:]
+	}
+	if text != "" {
+		chunks = append(chunks, text)
+	}
+	return chunks
+}
+
+// SplitSentences splits text into sentences at '.', '!' and '?' followed by
+// whitespace, and at line breaks. Empty sentences are dropped.
+func SplitSentences(text string) []string {
+	var sentences []string
+	add := func(s string) {
+		if s = strings.TrimSpace(s); s != "" {
+			sentences = append(sentences, s)
+		}
+	}
+
+	start := 0
+	for i, r := range text {
+		end := false
+		switch r {
+		case '\n':
+			end = true
+		case '.', '!', '?':
+			next, _ := utf8.DecodeRuneInString(text[i+1:])
+			end = i+1 == len(text) || unicode.IsSpace(next)
+		}
+		if end {
+			add(text[start : i+1])
+			start = i + 1
+		}
+	}
+	add(text[start:])
+
+	return sentences
+}
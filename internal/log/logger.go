@@ -0,0 +1,85 @@
+// Package log provides the structured logger shared by the internal
+// packages. User-facing output such as progress and tables stays on
+// os.Stdout via fmt; diagnostics go through this logger to os.Stderr.
+package log
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+var logger = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+// Init configures the logger. format is "text" or "json" and level is one
+// of debug, info, warn or error.
+func Init(format, level string) error {
+	return InitWithWriter(os.Stderr, format, level)
+}
+
+// InitWithWriter configures the logger to write to w
+func InitWithWriter(w io.Writer, format, level string) error {
+	lvl, err := ParseLevel(level)
+	if err != nil {
+		return err
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl}
+
+	var handler slog.Handler
+	switch strings.ToLower(format) {
+	case "json":
+		handler = slog.NewJSONHandler(w, opts)
+	case "text", "":
+		handler = slog.NewTextHandler(w, opts)
+	default:
+		return fmt.Errorf("invalid log format '%s' (valid: text, json)", format)
+	}
+
+	logger = slog.New(handler)
+	slog.SetDefault(logger)
+	return nil
+}
+
+// ParseLevel converts a level name to a slog.Level
+func ParseLevel(level string) (slog.Level, error) {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info", "":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return slog.LevelInfo, fmt.Errorf("invalid log level '%s' (valid: debug, info, warn, error)", level)
+	}
+}
+
+// Logger returns the underlying slog.Logger
+func Logger() *slog.Logger {
+	return logger
+}
+
+// Debug logs at debug level
+func Debug(msg string, args ...any) {
+	logger.Debug(msg, args...)
+}
+
+// Info logs at info level
+func Info(msg string, args ...any) {
+	logger.Info(msg, args...)
+}
+
+// Warn logs at warn level
+func Warn(msg string, args ...any) {
+	logger.Warn(msg, args...)
+}
+
+// Error logs at error level
+func Error(msg string, args ...any) {
+	logger.Error(msg, args...)
+}
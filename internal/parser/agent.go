@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"io/ioutil"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -36,7 +38,10 @@ type AgentSpecDetails struct {
 	Ports        []PortConfig           `yaml:"ports,omitempty"`
 	Volumes      []VolumeConfig         `yaml:"volumes,omitempty"`
 	HealthCheck  *HealthCheckConfig     `yaml:"healthCheck,omitempty"`
+	Probes       *ProbeConfig           `yaml:"probes,omitempty"`
 	Resources    *ResourceConfig        `yaml:"resources,omitempty"`
+	Scaling      *ScalingConfig         `yaml:"scaling,omitempty"`
+	Secrets      []SecretConfig         `yaml:"secrets,omitempty"`
 	Config       map[string]interface{} `yaml:"config,omitempty"`
 }
 
@@ -77,6 +82,33 @@ type HealthCheckConfig struct {
 	StartPeriod string   `yaml:"startPeriod,omitempty"`
 }
 
+// ProbeConfig separates liveness from readiness. Docker supports a single
+// HEALTHCHECK, which is built from the liveness probe; readiness is only
+// used by orchestrators such as Kubernetes.
+type ProbeConfig struct {
+	Liveness  ProbeDefinition `yaml:"liveness,omitempty"`
+	Readiness ProbeDefinition `yaml:"readiness,omitempty"`
+}
+
+// ProbeDefinition has the same fields as HealthCheckConfig. A probe without
+// a command is not set.
+type ProbeDefinition HealthCheckConfig
+
+// IsSet reports whether the probe defines a command
+func (p ProbeDefinition) IsSet() bool {
+	return len(p.Command) > 0
+}
+
+// LivenessCheck returns the check Docker's HEALTHCHECK is built from:
+// spec.probes.liveness, or spec.healthCheck when there are no probes
+func (s *AgentSpecDetails) LivenessCheck() *HealthCheckConfig {
+	if s.Probes != nil && s.Probes.Liveness.IsSet() {
+		check := HealthCheckConfig(s.Probes.Liveness)
+		return &check
+	}
+	return s.HealthCheck
+}
+
 // ResourceConfig represents resource constraints
 type ResourceConfig struct {
 	Limits   ResourceLimits `yaml:"limits,omitempty"`
@@ -89,6 +121,22 @@ type ResourceLimits struct {
 	Memory string `yaml:"memory,omitempty"`
 }
 
+// ScalingConfig represents horizontal scaling hints
+type ScalingConfig struct {
+	MinReplicas          int `yaml:"minReplicas"`
+	MaxReplicas          int `yaml:"maxReplicas"`
+	TargetCPUUtilization int `yaml:"targetCPUUtilization,omitempty"`
+	TargetConcurrency    int `yaml:"targetConcurrency,omitempty"`
+}
+
+// SecretConfig represents a build-time secret mounted with BuildKit
+type SecretConfig struct {
+	Name   string `yaml:"name"`
+	Source string `yaml:"source,omitempty"` // File on the build host
+	Env    string `yaml:"env,omitempty"`    // Environment variable on the build host
+	Target string `yaml:"target,omitempty"` // Mount path, defaults to /run/secrets/<name>
+}
+
 // Parser handles agent.yaml parsing
 type Parser struct{}
 
@@ -114,7 +162,7 @@ func (p *Parser) Parse(data []byte) (*AgentSpec, error) {
 	
 	// Parse YAML
 	if err := yaml.Unmarshal(data, &spec); err != nil {
-		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+		return nil, fmt.Errorf("failed to parse YAML: %w", newParseError(data, err))
 	}
 	
 	// Validate the spec
@@ -163,6 +211,10 @@ func (p *Parser) Validate(spec *AgentSpec) error {
 		return fmt.Errorf("spec.model.name is required")
 	}
 	
+	if err := validateModelName(spec.Spec.Model.Provider, spec.Spec.Model.Name); err != nil {
+		return err
+	}
+	
 	// Validate ports
 	for i, port := range spec.Spec.Ports {
 		if port.Container <= 0 || port.Container > 65535 {
@@ -174,6 +226,71 @@ func (p *Parser) Validate(spec *AgentSpec) error {
 		}
 	}
 	
+	// Validate resources
+	if resources := spec.Spec.Resources; resources != nil {
+		if err := validateResources(resources); err != nil {
+			return err
+		}
+	}
+	
+	// Validate scaling
+	if scaling := spec.Spec.Scaling; scaling != nil {
+		if scaling.MinReplicas <= 0 {
+			return fmt.Errorf("spec.scaling.minReplicas must be greater than 0")
+		}
+		
+		if scaling.MaxReplicas <= 0 {
+			return fmt.Errorf("spec.scaling.maxReplicas must be greater than 0")
+		}
+		
+		if scaling.MinReplicas > scaling.MaxReplicas {
+			return fmt.Errorf("spec.scaling.minReplicas (%d) must not exceed maxReplicas (%d)", scaling.MinReplicas, scaling.MaxReplicas)
+		}
+		
+		if scaling.TargetCPUUtilization < 0 || scaling.TargetCPUUtilization > 100 {
+			return fmt.Errorf("spec.scaling.targetCPUUtilization must be between 0 and 100")
+		}
+	}
+	
+	// Validate probes
+	if probes := spec.Spec.Probes; probes != nil {
+		if probes.Liveness.IsSet() && spec.Spec.HealthCheck != nil {
+			return fmt.Errorf("spec.healthCheck and spec.probes.liveness both define the container health check; use only one")
+		}
+		
+		if err := validateProbe("liveness", probes.Liveness); err != nil {
+			return err
+		}
+		if err := validateProbe("readiness", probes.Readiness); err != nil {
+			return err
+		}
+	}
+	
+	// Validate secrets
+	secretNames := make(map[string]bool)
+	for i, secret := range spec.Spec.Secrets {
+		if secret.Name == "" {
+			return fmt.Errorf("secret name is required at index %d", i)
+		}
+		
+		if secretNames[secret.Name] {
+			return fmt.Errorf("duplicate secret name '%s'", secret.Name)
+		}
+		secretNames[secret.Name] = true
+		
+		if (secret.Source == "") == (secret.Env == "") {
+			return fmt.Errorf("secret '%s' must set exactly one of source or env", secret.Name)
+		}
+	}
+	
+	return nil
+}
+
+// validateProbe rejects a probe that sets timings but no command
+func validateProbe(name string, probe ProbeDefinition) error {
+	if !probe.IsSet() && (probe.Interval != "" || probe.Timeout != "" || probe.Retries != 0 || probe.StartPeriod != "") {
+		return fmt.Errorf("spec.probes.%s.command is required", name)
+	}
 	return nil
 }
 
@@ -191,6 +308,124 @@ func (p *Parser) FindAgentFile(dir string) (string, error) {
 	return "", fmt.Errorf("no agent.yaml file found in %s", dir)
 }
 
+// validateResources checks that resource values parse and that requests
+// do not exceed limits
+func validateResources(resources *ResourceConfig) error {
+	limits := make(map[string]int64)
+	for name, value := range map[string]string{"memory": resources.Limits.Memory, "cpu": resources.Limits.CPU} {
+		if value == "" {
+			continue
+		}
+		parsed, err := parseResource(name, value)
+		if err == nil && name == "cpu" {
+			_, err = CPUQuota(value)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid spec.resources.limits.%s: %w", name, err)
+		}
+		limits[name] = parsed
+	}
+	
+	for name, value := range map[string]string{"memory": resources.Requests.Memory, "cpu": resources.Requests.CPU} {
+		if value == "" {
+			continue
+		}
+		parsed, err := parseResource(name, value)
+		if err != nil {
+			return fmt.Errorf("invalid spec.resources.requests.%s: %w", name, err)
+		}
+		if limit, ok := limits[name]; ok && parsed > limit {
+			return fmt.Errorf("spec.resources.requests.%s (%s) must not exceed limits.%s", name, value, name)
+		}
+	}
+	
+	return nil
+}
+
+func parseResource(name, value string) (int64, error) {
+	if name == "memory" {
+		return ParseMemory(value)
+	}
+	return ParseCPU(value)
+}
+
+// ParseMemory parses a memory quantity such as 512Mi or 1Gi into bytes.
+// Plain numbers are bytes; Ki, Mi, Gi (and K, M, G) suffixes are supported.
+func ParseMemory(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("empty memory value")
+	}
+	
+	multipliers := []struct {
+		suffix string
+		factor int64
+	}{
+		{"Ki", 1 << 10},
+		{"Mi", 1 << 20},
+		{"Gi", 1 << 30},
+		{"K", 1000},
+		{"M", 1000 * 1000},
+		{"G", 1000 * 1000 * 1000},
+	}
+	
+	for _, m := range multipliers {
+		if strings.HasSuffix(s, m.suffix) {
+			value, err := strconv.ParseFloat(strings.TrimSuffix(s, m.suffix), 64)
+			if err != nil || value < 0 {
+				return 0, fmt.Errorf("invalid memory value '%s'", s)
+			}
+			return int64(value * float64(m.factor)), nil
+		}
+	}
+	
+	value, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid memory value '%s'", s)
+	}
+	return value, nil
+}
+
+// ParseCPU parses a CPU quantity such as 500m or 1.5 into millicores
+func ParseCPU(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasSuffix(s, "m") {
+		value, err := strconv.ParseInt(strings.TrimSuffix(s, "m"), 10, 64)
+		if err != nil || value <= 0 {
+			return 0, fmt.Errorf("invalid cpu value '%s'", s)
+		}
+		return value, nil
+	}
+	
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil || value <= 0 {
+		return 0, fmt.Errorf("invalid cpu value '%s'", s)
+	}
+	return int64(value * 1000), nil
+}
+
+const (
+	// CPUPeriod is the CFS scheduler period in microseconds that CPU limits
+	// are expressed against
+	CPUPeriod = 100000
+	// minCPUMillicores is the smallest CPU limit: Docker rejects CFS quotas
+	// below 1000µs, which is 10m of a 100000µs period
+	minCPUMillicores = 10
+)
+
+// CPUQuota converts a CPU quantity such as 500m or 1.5 into a CFS quota in
+// microseconds per CPUPeriod
+func CPUQuota(s string) (int64, error) {
+	millicores, err := ParseCPU(s)
+	if err != nil {
+		return 0, err
+	}
+	if millicores < minCPUMillicores {
+		return 0, fmt.Errorf("cpu value '%s' is below the minimum of %dm", strings.TrimSpace(s), minCPUMillicores)
+	}
+	return millicores * CPUPeriod / 1000, nil
+}
+
 // Helper functions
 func contains(slice []string, item string) bool {
 	for _, s := range slice {
@@ -0,0 +1,39 @@
+package parser
+
+import "testing"
+
+func TestCPUQuota(t *testing.T) {
+	tests := []struct {
+		cpu     string
+		want    int64
+		wantErr bool
+	}{
+		{cpu: "1", want: 100000},
+		{cpu: "1.5", want: 150000},
+		{cpu: "500m", want: 50000},
+		{cpu: "10m", want: 1000},
+		{cpu: "0.01", want: 1000},
+		{cpu: "5m", wantErr: true},
+		{cpu: "0.0001", wantErr: true},
+		{cpu: "0", wantErr: true},
+		{cpu: "abc", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cpu, func(t *testing.T) {
+			quota, err := CPUQuota(tt.cpu)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("CPUQuota(%q) = %d, want an error", tt.cpu, quota)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CPUQuota(%q) error = %v", tt.cpu, err)
+			}
+			if quota != tt.want {
+				t.Errorf("CPUQuota(%q) = %d, want %d", tt.cpu, quota, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// AgentListFile is the name of the manifest listing the agents of a
+// monorepo
+const AgentListFile = "agents.yaml"
+
+// AgentList is the agents.yaml manifest at the root of a monorepo
+type AgentList struct {
+	APIVersion string          `yaml:"apiVersion"`
+	Kind       string          `yaml:"kind"`
+	Items      []AgentListItem `yaml:"items"`
+}
+
+// AgentListItem is an agent in a monorepo. Path is relative to the
+// directory of agents.yaml.
+type AgentListItem struct {
+	Name     string `yaml:"name"`
+	Path     string `yaml:"path"`
+	Template string `yaml:"template,omitempty"`
+	Model    string `yaml:"model,omitempty"`
+}
+
+// NewAgentList returns an empty agent list
+func NewAgentList() *AgentList {
+	return &AgentList{APIVersion: "agent.dev/v1", Kind: "AgentList"}
+}
+
+// LoadAgentList reads an agents.yaml manifest
+func LoadAgentList(path string) (*AgentList, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var list AgentList
+	if err := yaml.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if list.Kind != "AgentList" {
+		return nil, fmt.Errorf("%s: kind must be 'AgentList', got '%s'", path, list.Kind)
+	}
+
+	seen := make(map[string]bool)
+	for i, item := range list.Items {
+		if item.Name == "" || item.Path == "" {
+			return nil, fmt.Errorf("%s: item %d needs a name and a path", path, i+1)
+		}
+		if seen[item.Name] {
+			return nil, fmt.Errorf("%s: duplicate agent '%s'", path, item.Name)
+		}
+		seen[item.Name] = true
+	}
+
+	return &list, nil
+}
+
+// Upsert adds an agent, replacing an existing entry with the same name, and
+// keeps the items sorted by name
+func (l *AgentList) Upsert(item AgentListItem) {
+	for i := range l.Items {
+		if l.Items[i].Name == item.Name {
+			l.Items[i] = item
+			return
+		}
+	}
+	l.Items = append(l.Items, item)
+	sort.Slice(l.Items, func(i, j int) bool {
+		return l.Items[i].Name < l.Items[j].Name
+	})
+}
+
+// Save writes the manifest to path
+func (l *AgentList) Save(path string) error {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(l); err != nil {
+		return fmt.Errorf("failed to marshal agent list: %w", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// Dir returns the directory of an item, resolved against the directory of
+// the manifest
+func (item AgentListItem) Dir(manifestPath string) string {
+	if filepath.IsAbs(item.Path) {
+		return item.Path
+	}
+	return filepath.Join(filepath.Dir(manifestPath), item.Path)
+}
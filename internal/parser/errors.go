@@ -0,0 +1,128 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// yamlLinePattern matches the position yaml.v3 puts in its error messages,
+// e.g. "yaml: line 5: found character that cannot start any token"
+var yamlLinePattern = regexp.MustCompile(`line (\d+)(?:, column (\d+))?:`)
+
+// ParseError is a YAML syntax error in agent.yaml with the position and
+// surrounding source lines
+type ParseError struct {
+	Line    int
+	Column  int // 0 if yaml did not report a column
+	Snippet string
+	Cause   error
+}
+
+// Error formats the error like compiler output, with the offending line
+// marked in the snippet
+func (e *ParseError) Error() string {
+	var b strings.Builder
+	if e.Column > 0 {
+		fmt.Fprintf(&b, "line %d, column %d: %s", e.Line, e.Column, yamlMessage(e.Cause))
+	} else {
+		fmt.Fprintf(&b, "line %d: %s", e.Line, yamlMessage(e.Cause))
+	}
+	if e.Snippet != "" {
+		b.WriteString("\n")
+		b.WriteString(e.Snippet)
+	}
+	return b.String()
+}
+
+// Unwrap returns the underlying YAML error
+func (e *ParseError) Unwrap() error {
+	return e.Cause
+}
+
+// newParseError wraps a YAML error with the source lines around the line it
+// reports. Errors without a line number are returned unchanged.
+func newParseError(data []byte, err error) error {
+	match := yamlLinePattern.FindStringSubmatch(err.Error())
+	if match == nil {
+		return err
+	}
+
+	line, _ := strconv.Atoi(match[1])
+	column, _ := strconv.Atoi(match[2])
+
+	text := strings.TrimSuffix(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	lines := strings.Split(text, "\n")
+	if line < 1 || line > len(lines) {
+		return &ParseError{Line: line, Column: column, Cause: err}
+	}
+
+	// yaml reports a tab in the indentation against the line before it, so
+	// move to the line that actually has the tab
+	if strings.Contains(err.Error(), "tab character") && !strings.Contains(indentOf(lines[line-1]), "\t") &&
+		line < len(lines) && strings.Contains(indentOf(lines[line]), "\t") {
+		line++
+	}
+
+	// Tabs are the most common cause of indentation errors, so point at the
+	// first one when yaml did not give a column
+	indent := indentOf(lines[line-1])
+	if column == 0 {
+		if i := strings.IndexByte(indent, '\t'); i >= 0 {
+			column = i + 1
+		}
+	}
+
+	return &ParseError{
+		Line:    line,
+		Column:  column,
+		Snippet: snippet(lines, line, column, strings.Contains(indent, "\t")),
+		Cause:   err,
+	}
+}
+
+// snippet renders the line before, the offending line and the line after,
+// with a caret under column when it is known
+func snippet(lines []string, line, column int, tabIndent bool) string {
+	first := max(line-1, 1)
+	last := min(line+1, len(lines))
+	width := len(strconv.Itoa(last))
+
+	var b strings.Builder
+	for n := first; n <= last; n++ {
+		marker := " "
+		if n == line {
+			marker = ">"
+		}
+		// Show tabs so mixed indentation is visible
+		text := strings.ReplaceAll(lines[n-1], "\t", "→")
+		fmt.Fprintf(&b, "%s %*d | %s\n", marker, width, n, text)
+		if n == line && column > 0 {
+			fmt.Fprintf(&b, "  %*s | %s^\n", width, "", strings.Repeat(" ", column-1))
+		}
+	}
+	if tabIndent {
+		b.WriteString("hint: line is indented with a tab; YAML indentation must use spaces\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// indentOf returns the leading whitespace of line
+func indentOf(line string) string {
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+}
+
+// yamlMessage strips the "yaml: line N:" prefix that ParseError replaces.
+// A single unmarshal error is unwrapped the same way; several are kept
+// as a list.
+func yamlMessage(err error) string {
+	msg := strings.TrimPrefix(err.Error(), "yaml: ")
+	if rest, ok := strings.CutPrefix(msg, "unmarshal errors:\n"); ok && !strings.Contains(rest, "\n") {
+		msg = strings.TrimSpace(rest)
+	}
+	if loc := yamlLinePattern.FindStringIndex(msg); loc != nil && loc[0] == 0 {
+		msg = msg[loc[1]:]
+	}
+	return strings.TrimSpace(msg)
+}
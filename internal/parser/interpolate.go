@@ -0,0 +1,193 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// InterpolateEnv returns a copy of spec with ${VAR}, $VAR and
+// ${VAR:-default} references in spec.environment values resolved from the
+// host environment. Referencing an unset variable without a default is an
+// error. Use $$ for a literal dollar sign.
+func (p *Parser) InterpolateEnv(spec *AgentSpec) (*AgentSpec, error) {
+	return p.InterpolateEnvWith(spec, os.LookupEnv)
+}
+
+// InterpolateEnvWith is InterpolateEnv with variables resolved by lookup
+// instead of from the host environment
+func (p *Parser) InterpolateEnvWith(spec *AgentSpec, lookup func(string) (string, bool)) (*AgentSpec, error) {
+	resolved := *spec
+	resolved.Spec.Environment = make([]EnvironmentVar, len(spec.Spec.Environment))
+
+	for i, env := range spec.Spec.Environment {
+		value, err := expandEnv(env.Value, lookup)
+		if err != nil {
+			return nil, fmt.Errorf("environment variable %s: %w", env.Name, err)
+		}
+		env.Value = value
+		resolved.Spec.Environment[i] = env
+	}
+
+	return &resolved, nil
+}
+
+// InterpolateBuildEnv is InterpolateEnv for values baked into an image.
+// Entries that reference an unset or secret-like variable, or that resolve
+// to a value with a newline, are left out of the returned spec and their
+// names returned instead; agent run resolves them when the container starts.
+func (p *Parser) InterpolateBuildEnv(spec *AgentSpec) (*AgentSpec, []string, error) {
+	resolved := *spec
+	resolved.Spec.Environment = make([]EnvironmentVar, 0, len(spec.Spec.Environment))
+
+	var deferred []string
+	for _, env := range spec.Spec.Environment {
+		references, err := envReferences(env.Value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("environment variable %s: %w", env.Name, err)
+		}
+
+		runTime := false
+		for _, name := range references {
+			if _, ok := os.LookupEnv(name); !ok || isSecretName(name) || isSecretName(env.Name) {
+				runTime = true
+			}
+		}
+		if !runTime {
+			value, err := ExpandEnv(env.Value)
+			if err != nil {
+				return nil, nil, fmt.Errorf("environment variable %s: %w", env.Name, err)
+			}
+			// A Dockerfile ENV instruction cannot hold a newline
+			runTime = strings.ContainsAny(value, "\r\n")
+			env.Value = value
+		}
+
+		if runTime {
+			deferred = append(deferred, env.Name)
+			continue
+		}
+		resolved.Spec.Environment = append(resolved.Spec.Environment, env)
+	}
+
+	return &resolved, deferred, nil
+}
+
+// envReferences returns the names of the variables value references
+func envReferences(value string) ([]string, error) {
+	var names []string
+	_, err := expandEnv(value, func(name string) (string, bool) {
+		names = append(names, name)
+		return "", true
+	})
+	return names, err
+}
+
+// secretNameParts are the parts of a variable name, split at underscores,
+// that mark it as holding a secret
+var secretNameParts = map[string]bool{
+	"KEY":         true,
+	"APIKEY":      true,
+	"SECRET":      true,
+	"TOKEN":       true,
+	"PASSWORD":    true,
+	"PASSWD":      true,
+	"CREDENTIAL":  true,
+	"CREDENTIALS": true,
+}
+
+// isSecretName reports whether the variable name looks like it holds a
+// secret, such as OPENAI_API_KEY or DB_PASSWORD
+func isSecretName(name string) bool {
+	for _, part := range strings.Split(strings.ToUpper(name), "_") {
+		if secretNameParts[part] {
+			return true
+		}
+	}
+	return false
+}
+
+// ExpandEnv resolves ${VAR}, $VAR and ${VAR:-default} references in value
+func ExpandEnv(value string) (string, error) {
+	return expandEnv(value, os.LookupEnv)
+}
+
+// expandEnv resolves the variable references in value with lookup
+func expandEnv(value string, lookup func(string) (string, bool)) (string, error) {
+	if !strings.Contains(value, "$") {
+		return value, nil
+	}
+
+	var out strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '$' || i+1 >= len(value) {
+			out.WriteByte(value[i])
+			continue
+		}
+
+		next := value[i+1]
+		switch {
+		case next == '$':
+			out.WriteByte('$')
+			i++
+		case next == '{':
+			end := strings.IndexByte(value[i+2:], '}')
+			if end < 0 {
+				return "", fmt.Errorf("unterminated variable reference in %q", value)
+			}
+			expr := value[i+2 : i+2+end]
+
+			name, def, hasDefault := strings.Cut(expr, ":-")
+			if !isEnvName(name) {
+				return "", fmt.Errorf("invalid variable name %q", name)
+			}
+
+			resolved, ok := lookup(name)
+			if !ok || (hasDefault && resolved == "") {
+				if !hasDefault {
+					return "", fmt.Errorf("${%s} is not set", name)
+				}
+				resolved = def
+			}
+			out.WriteString(resolved)
+			i += 2 + end
+		case isEnvNameStart(next):
+			j := i + 1
+			for j < len(value) && isEnvNameChar(value[j]) {
+				j++
+			}
+			name := value[i+1 : j]
+
+			resolved, ok := lookup(name)
+			if !ok {
+				return "", fmt.Errorf("$%s is not set", name)
+			}
+			out.WriteString(resolved)
+			i = j - 1
+		default:
+			out.WriteByte('$')
+		}
+	}
+
+	return out.String(), nil
+}
+
+func isEnvName(name string) bool {
+	if name == "" || !isEnvNameStart(name[0]) {
+		return false
+	}
+	for i := 1; i < len(name); i++ {
+		if !isEnvNameChar(name[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func isEnvNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isEnvNameChar(c byte) bool {
+	return isEnvNameStart(c) || (c >= '0' && c <= '9')
+}
@@ -0,0 +1,42 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInterpolateBuildEnv(t *testing.T) {
+	t.Setenv("AGENT_TEST_REGION", "eu-west-1")
+	t.Setenv("AGENT_TEST_API_KEY", "sk-secret")
+	t.Setenv("AGENT_TEST_BANNER", "line one\nline two")
+
+	spec := &AgentSpec{Spec: AgentSpecDetails{Environment: []EnvironmentVar{
+		{Name: "LITERAL", Value: "cost: $$5"},
+		{Name: "REGION", Value: "${AGENT_TEST_REGION}"},
+		{Name: "WITH_DEFAULT", Value: "${AGENT_TEST_UNSET:-fallback}"},
+		{Name: "UNSET", Value: "$AGENT_TEST_UNSET"},
+		{Name: "OPENAI_API_KEY", Value: "${AGENT_TEST_API_KEY}"},
+		{Name: "DB_PASSWORD", Value: "${AGENT_TEST_REGION}"},
+		{Name: "BANNER", Value: "${AGENT_TEST_BANNER}"},
+	}}}
+
+	resolved, deferred, err := New().InterpolateBuildEnv(spec)
+	if err != nil {
+		t.Fatalf("InterpolateBuildEnv() error = %v", err)
+	}
+
+	wantEnv := []EnvironmentVar{
+		{Name: "LITERAL", Value: "cost: $5"},
+		{Name: "REGION", Value: "eu-west-1"},
+	}
+	if !reflect.DeepEqual(resolved.Spec.Environment, wantEnv) {
+		t.Errorf("environment = %v, want %v", resolved.Spec.Environment, wantEnv)
+	}
+	wantDeferred := []string{"WITH_DEFAULT", "UNSET", "OPENAI_API_KEY", "DB_PASSWORD", "BANNER"}
+	if !reflect.DeepEqual(deferred, wantDeferred) {
+		t.Errorf("deferred = %v, want %v", deferred, wantDeferred)
+	}
+	if spec.Spec.Environment[1].Value != "${AGENT_TEST_REGION}" {
+		t.Errorf("InterpolateBuildEnv() modified the spec it was given")
+	}
+}
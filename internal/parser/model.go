@@ -0,0 +1,80 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pxkundu/agent-as-code/internal/log"
+)
+
+// ModelNameValidator checks that a model name follows the naming
+// conventions of its provider
+type ModelNameValidator interface {
+	Validate(name string) error
+}
+
+// modelNameValidators maps spec.model.provider to the validator of its
+// model names
+var modelNameValidators = map[string]ModelNameValidator{}
+
+// RegisterModelNameValidator sets the validator of the model names of
+// provider, replacing any registered before
+func RegisterModelNameValidator(provider string, validator ModelNameValidator) {
+	modelNameValidators[strings.ToLower(provider)] = validator
+}
+
+func init() {
+	ollama := &patternValidator{
+		provider: "Ollama",
+		example:  "llama3:8b",
+		// A lowercase name and an optional tag. Names pulled from another
+		// registry (e.g. hf.co/user/Model-GGUF) keep the registry's case.
+		pattern: regexp.MustCompile(`^([a-z0-9]+([._-][a-z0-9]+)*|([\w.-]+/)+[\w.-]+)(:[\w.-]+)?$`),
+	}
+
+	RegisterModelNameValidator("openai", &patternValidator{
+		provider: "OpenAI",
+		example:  "gpt-4",
+		// Fine-tuned models are named ft:<base model>:...
+		pattern: regexp.MustCompile(`^(ft:)?(gpt-[0-9]|o[0-9]|text-|davinci|curie)`),
+	})
+	RegisterModelNameValidator("anthropic", &patternValidator{
+		provider: "Anthropic",
+		example:  "claude-3-5-sonnet-latest",
+		pattern:  regexp.MustCompile(`^claude-`),
+	})
+	RegisterModelNameValidator("ollama", ollama)
+	// agent init accepts local/ as an alias for ollama/
+	RegisterModelNameValidator("local", ollama)
+}
+
+// patternValidator accepts the model names that match a regular expression
+type patternValidator struct {
+	provider string
+	example  string
+	pattern  *regexp.Regexp
+}
+
+// Validate returns an error naming the provider and an example of a valid
+// name if name does not match
+func (v *patternValidator) Validate(name string) error {
+	if !v.pattern.MatchString(name) {
+		return fmt.Errorf("for %s, model name should look like '%s', got '%s'", v.provider, v.example, name)
+	}
+	return nil
+}
+
+// validateModelName checks name against the validator of provider. Names
+// of providers without a validator are accepted with a warning.
+func validateModelName(provider, name string) error {
+	validator, ok := modelNameValidators[strings.ToLower(provider)]
+	if !ok {
+		log.Warn("unknown model provider, not checking the model name", "provider", provider, "model", name)
+		return nil
+	}
+	if err := validator.Validate(name); err != nil {
+		return fmt.Errorf("invalid spec.model.name: %w", err)
+	}
+	return nil
+}
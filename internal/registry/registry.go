@@ -10,6 +10,8 @@ import (
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/client"
+	"github.com/pxkundu/agent-as-code/internal/config"
+	"github.com/pxkundu/agent-as-code/internal/log"
 )
 
 // Registry handles registry operations
@@ -61,6 +63,7 @@ type ImageInfo struct {
 	ID         string
 	Repository string
 	Tag        string
+	Digest     string
 	Created    time.Time
 	Size       int64
 }
@@ -76,10 +79,19 @@ func New() *Registry {
 	return &Registry{
 		dockerClient: dockerClient,
 		registryURL:  os.Getenv("AGENT_REGISTRY_URL"),
-		authToken:    os.Getenv("AGENT_REGISTRY_TOKEN"),
+		authToken:    authTokenFromEnv(),
 	}
 }
 
+// authTokenFromEnv returns the registry token from AGENT_REGISTRY_TOKEN, or
+// a service account token from AGENT_SERVICE_ACCOUNT_TOKEN
+func authTokenFromEnv() string {
+	if token := os.Getenv("AGENT_REGISTRY_TOKEN"); token != "" {
+		return token
+	}
+	return os.Getenv(config.ServiceAccountTokenEnv)
+}
+
 // ValidateLocalImage validates that an image exists locally
 func (r *Registry) ValidateLocalImage(imageName string) error {
 	if r.dockerClient == nil {
@@ -92,7 +104,7 @@ func (r *Registry) ValidateLocalImage(imageName string) error {
 		return fmt.Errorf("image '%s' not found locally. Build it first with 'agent build'", imageName)
 	}
 
-	fmt.Printf("✓ Local image validated: %s\n", imageName)
+	log.Debug("local image validated", "image", imageName)
 	return nil
 }
 
@@ -102,7 +114,7 @@ func (r *Registry) Push(options *PushOptions) (*PushResult, error) {
 		return nil, fmt.Errorf("Docker client not available")
 	}
 
-	fmt.Printf("Pushing image: %s\n", options.Image)
+	log.Info("pushing image", "image", options.Image, "registry", options.Registry)
 
 	// Use registry-specific logic or Docker Hub
 	if r.isAgentRegistry(options.Registry) {
@@ -120,7 +132,7 @@ func (r *Registry) Pull(options *PullOptions) (*PullResult, error) {
 	}
 
 	if !options.Quiet {
-		fmt.Printf("Pulling image: %s\n", options.Image)
+		log.Info("pulling image", "image", options.Image, "registry", options.Registry)
 	}
 
 	// Use registry-specific logic or Docker Hub
@@ -162,6 +174,7 @@ func (r *Registry) ListLocal(options *ListOptions) ([]ImageInfo, error) {
 				ID:         img.ID,
 				Repository: repository,
 				Tag:        tag,
+				Digest:     repoDigest(img.RepoDigests, repository),
 				Created:    time.Unix(img.Created, 0),
 				Size:       img.Size,
 			}
@@ -176,6 +189,17 @@ func (r *Registry) ListLocal(options *ListOptions) ([]ImageInfo, error) {
 	return images, nil
 }
 
+// repoDigest returns the digest recorded for repository, if any. Repo
+// digests have the form name@sha256:...
+func repoDigest(repoDigests []string, repository string) string {
+	for _, rd := range repoDigests {
+		if name, digest, ok := strings.Cut(rd, "@"); ok && name == repository {
+			return digest
+		}
+	}
+	return ""
+}
+
 // isAgentRegistry checks if we're using the agent registry
 func (r *Registry) isAgentRegistry(registryURL string) bool {
 	if registryURL == "" {
@@ -217,10 +241,18 @@ func (r *Registry) pushToDockerRegistry(options *PushOptions) (*PushResult, erro
 	// Parse image name
 	repository, tag := parseImageName(options.Image)
 
+	// The push records the registry digest on the local image
+	digest := "sha256:unknown"
+	if inspect, _, err := r.dockerClient.ImageInspectWithRaw(ctx, options.Image); err == nil {
+		if d := repoDigest(inspect.RepoDigests, repository); d != "" {
+			digest = d
+		}
+	}
+
 	return &PushResult{
 		Repository:  repository,
 		Tag:         tag,
-		Digest:      "sha256:unknown", // Would be extracted from response
+		Digest:      digest,
 		Size:        "unknown",
 		RegistryURL: options.Registry,
 	}, nil
@@ -0,0 +1,176 @@
+package registry
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pxkundu/agent-as-code/internal/log"
+)
+
+// cosignInstallHelp is shown when cosign is not in PATH
+const cosignInstallHelp = `cosign is required to sign and verify images but was not found in PATH.
+Install it with one of:
+  brew install cosign
+  go install github.com/sigstore/cosign/v2/cmd/cosign@latest
+or download a release from https://github.com/sigstore/cosign/releases`
+
+// SigningReport describes the outcome of signing an image
+type SigningReport struct {
+	Signed         bool   `json:"signed"`
+	Digest         string `json:"digest"`
+	KeyFingerprint string `json:"keyFingerprint"`
+	Keyless        bool   `json:"keyless,omitempty"`
+	SignatureRef   string `json:"signatureRef"`
+}
+
+// historyEntry is a line of ~/.agent/history.jsonl
+type historyEntry struct {
+	Time   time.Time `json:"time"`
+	Action string    `json:"action"`
+	Image  string    `json:"image"`
+	SigningReport
+}
+
+// Sign signs an image with cosign and the private key at keyPath, which may
+// also be a KMS URI such as awskms://... imageDigest is a digest reference
+// of the pushed image, such as registry.example.com/my-agent@sha256:...
+// The outcome is appended to ~/.agent/history.jsonl.
+func (r *Registry) Sign(imageDigest, keyPath string) error {
+	return cosignSign(imageDigest, SigningReport{KeyFingerprint: keyFingerprint(keyPath)}, nil, "--key", keyPath)
+}
+
+// SignKeyless signs an image with an ephemeral key that Fulcio certifies
+// for the OIDC identity of the signer, such as the GitHub Actions or
+// Google Cloud workload identity, and records the signature in the Rekor
+// transparency log. Outside CI, cosign opens a browser to log in.
+func (r *Registry) SignKeyless(imageDigest string) error {
+	var env []string
+	if isCI() {
+		// cosign 1.x only signs keyless in experimental mode
+		env = append(env, "COSIGN_EXPERIMENTAL=1")
+	}
+	return cosignSign(imageDigest, SigningReport{Keyless: true}, env)
+}
+
+// cosignSign runs cosign sign on imageDigest with extra arguments and
+// environment variables and records the outcome in report
+func cosignSign(imageDigest string, report SigningReport, env []string, args ...string) error {
+	cosign, err := exec.LookPath("cosign")
+	if err != nil {
+		return errors.New(cosignInstallHelp)
+	}
+
+	repository, digest, ok := strings.Cut(imageDigest, "@")
+	if !ok || !strings.HasPrefix(digest, "sha256:") {
+		return fmt.Errorf("'%s' is not a digest reference (expected NAME@sha256:...)", imageDigest)
+	}
+
+	report.Digest = digest
+	// cosign stores the signature as a tag next to the image
+	report.SignatureRef = fmt.Sprintf("%s:%s.sig", repository, strings.Replace(digest, ":", "-", 1))
+
+	log.Info("signing image", "image", imageDigest, "keyless", report.Keyless)
+	cmd := exec.Command(cosign, append(append([]string{"sign", "--yes"}, args...), imageDigest)...)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	signErr := cmd.Run()
+	report.Signed = signErr == nil
+
+	if err := appendHistory("sign", imageDigest, report); err != nil {
+		log.Warn("failed to record signing in history", "error", err)
+	}
+
+	if signErr != nil {
+		return fmt.Errorf("cosign sign failed: %w", signErr)
+	}
+	return nil
+}
+
+// isCI reports whether the process runs in a CI job
+func isCI() bool {
+	for _, name := range []string{"GITHUB_ACTIONS", "GITLAB_CI", "CI"} {
+		if value := os.Getenv(name); value != "" && value != "false" {
+			return true
+		}
+	}
+	return false
+}
+
+// Verify checks the cosign signature of imageRef against the public key at
+// keyPath
+func (r *Registry) Verify(imageRef, keyPath string) error {
+	cosign, err := exec.LookPath("cosign")
+	if err != nil {
+		return errors.New(cosignInstallHelp)
+	}
+
+	log.Info("verifying image signature", "image", imageRef)
+	cmd := exec.Command(cosign, "verify", "--key", keyPath, imageRef)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("signature verification failed for %s: %s", imageRef, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// keyFingerprint returns the SHA-256 of the public key matching keyPath
+// (cosign.pub next to cosign.key), or of keyPath itself when there is no
+// public key. KMS URIs are returned unchanged.
+func keyFingerprint(keyPath string) string {
+	if strings.Contains(keyPath, "://") {
+		return keyPath
+	}
+
+	candidates := []string{keyPath}
+	if strings.HasSuffix(keyPath, ".key") {
+		candidates = append([]string{strings.TrimSuffix(keyPath, ".key") + ".pub"}, candidates...)
+	}
+	for _, path := range candidates {
+		if data, err := os.ReadFile(path); err == nil {
+			sum := sha256.Sum256(data)
+			return "sha256:" + hex.EncodeToString(sum[:])
+		}
+	}
+	return ""
+}
+
+// appendHistory appends an entry to ~/.agent/history.jsonl
+func appendHistory(action, image string, report SigningReport) error {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("failed to get home directory: %w", err)
+	}
+	path := filepath.Join(home, ".agent", "history.jsonl")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	line, err := json.Marshal(historyEntry{
+		Time:          time.Now().UTC(),
+		Action:        action,
+		Image:         image,
+		SigningReport: report,
+	})
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
@@ -0,0 +1,314 @@
+package runtime
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/go-connections/nat"
+	"github.com/pxkundu/agent-as-code/internal/log"
+)
+
+// checkpointManifestName is the manifest file stored at the root of a
+// checkpoint archive, next to the CRIU image directory
+const checkpointManifestName = "checkpoint-manifest.json"
+
+// CheckpointOptions represents checkpoint options
+type CheckpointOptions struct {
+	Container    string
+	OutputPath   string
+	Description  string
+	LeaveRunning bool
+}
+
+// RestoreOptions represents restore options
+type RestoreOptions struct {
+	CheckpointPath string
+	Name           string
+	Description    string // Overrides the checkpoint's description label
+}
+
+// CheckpointManifest describes a checkpoint archive and holds the container
+// configuration needed to recreate the container on restore
+type CheckpointManifest struct {
+	CheckpointID string      `json:"checkpointId"`
+	Description  string      `json:"description,omitempty"`
+	Container    string      `json:"container"`
+	Image        string      `json:"image"`
+	Env          []string    `json:"env,omitempty"`
+	Cmd          []string    `json:"cmd,omitempty"`
+	ExposedPorts nat.PortSet `json:"exposedPorts,omitempty"`
+	PortBindings nat.PortMap `json:"portBindings,omitempty"`
+	Binds        []string    `json:"binds,omitempty"`
+	CreatedAt    time.Time   `json:"createdAt"`
+}
+
+// Checkpoint saves the state of a running container with CRIU and writes it,
+// together with a manifest, to a tar archive. The Docker daemon must run
+// with experimental features enabled and have CRIU installed.
+func (r *Runtime) Checkpoint(options *CheckpointOptions) (*CheckpointManifest, error) {
+	if r.dockerClient == nil {
+		return nil, fmt.Errorf("Docker client not available. Please ensure Docker is running")
+	}
+
+	ctx := context.Background()
+
+	inspect, err := r.dockerClient.ContainerInspect(ctx, options.Container)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect container: %w", err)
+	}
+	if !inspect.State.Running {
+		return nil, fmt.Errorf("container '%s' is not running", options.Container)
+	}
+
+	// The daemon writes the CRIU image to <dir>/<checkpoint ID>, so the
+	// directory must be on the daemon's host
+	checkpointDir, err := os.MkdirTemp("", "agent-checkpoint-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create checkpoint directory: %w", err)
+	}
+	defer os.RemoveAll(checkpointDir)
+
+	manifest := &CheckpointManifest{
+		CheckpointID: fmt.Sprintf("agent-%d", time.Now().Unix()),
+		Description:  options.Description,
+		Container:    strings.TrimPrefix(inspect.Name, "/"),
+		Image:        inspect.Config.Image,
+		Env:          inspect.Config.Env,
+		Cmd:          inspect.Config.Cmd,
+		ExposedPorts: inspect.Config.ExposedPorts,
+		PortBindings: inspect.HostConfig.PortBindings,
+		Binds:        inspect.HostConfig.Binds,
+		CreatedAt:    time.Now().UTC(),
+	}
+
+	log.Info("creating checkpoint", "container", manifest.Container, "checkpoint", manifest.CheckpointID)
+	err = r.dockerClient.CheckpointCreate(ctx, inspect.ID, types.CheckpointCreateOptions{
+		CheckpointID:  manifest.CheckpointID,
+		CheckpointDir: checkpointDir,
+		Exit:          !options.LeaveRunning,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create checkpoint (requires CRIU and an experimental Docker daemon): %w", err)
+	}
+
+	if err := writeCheckpointArchive(options.OutputPath, checkpointDir, manifest); err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// Restore creates a new container from a checkpoint archive and starts it
+// from the saved CRIU image
+func (r *Runtime) Restore(options *RestoreOptions) (*ContainerInfo, *CheckpointManifest, error) {
+	if r.dockerClient == nil {
+		return nil, nil, fmt.Errorf("Docker client not available. Please ensure Docker is running")
+	}
+
+	ctx := context.Background()
+
+	checkpointDir, err := os.MkdirTemp("", "agent-restore-*")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create checkpoint directory: %w", err)
+	}
+	defer os.RemoveAll(checkpointDir)
+
+	manifest, err := extractCheckpointArchive(options.CheckpointPath, checkpointDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	containerName := options.Name
+	if containerName == "" {
+		containerName = manifest.Container + "-restored"
+	}
+
+	description := manifest.Description
+	if options.Description != "" {
+		description = options.Description
+	}
+
+	containerConfig := &container.Config{
+		Image:        manifest.Image,
+		Env:          manifest.Env,
+		Cmd:          manifest.Cmd,
+		ExposedPorts: manifest.ExposedPorts,
+		Labels: agentLabels(map[string]string{
+			"agent.dev/checkpoint-id":          manifest.CheckpointID,
+			"agent.dev/checkpoint-description": description,
+		}),
+	}
+	hostConfig := &container.HostConfig{
+		PortBindings: manifest.PortBindings,
+		Binds:        manifest.Binds,
+	}
+
+	log.Info("creating container", "name", containerName, "image", manifest.Image)
+	resp, err := r.dockerClient.ContainerCreate(ctx, containerConfig, hostConfig, nil, nil, containerName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create container: %w", err)
+	}
+
+	log.Info("restoring checkpoint", "id", resp.ID[:12], "checkpoint", manifest.CheckpointID)
+	err = r.dockerClient.ContainerStart(ctx, resp.ID, types.ContainerStartOptions{
+		CheckpointID:  manifest.CheckpointID,
+		CheckpointDir: checkpointDir,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to restore container: %w", err)
+	}
+
+	var ports []PortMapping
+	for port, bindings := range manifest.PortBindings {
+		for _, binding := range bindings {
+			ports = append(ports, PortMapping{
+				HostIP:    binding.HostIP,
+				Host:      binding.HostPort,
+				Container: port.Port(),
+				Protocol:  port.Proto(),
+			})
+		}
+	}
+
+	return &ContainerInfo{
+		ID:    resp.ID,
+		Name:  containerName,
+		Ports: ports,
+	}, manifest, nil
+}
+
+// writeCheckpointArchive writes the manifest and the CRIU image directory
+// <checkpointDir>/<checkpoint ID> to a tar archive at path
+func writeCheckpointArchive(path, checkpointDir string, manifest *CheckpointManifest) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create checkpoint archive: %w", err)
+	}
+	defer file.Close()
+
+	tw := tar.NewWriter(file)
+
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkpoint manifest: %w", err)
+	}
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    checkpointManifestName,
+		Mode:    0644,
+		Size:    int64(len(manifestData)),
+		ModTime: manifest.CreatedAt,
+	}); err != nil {
+		return fmt.Errorf("failed to write checkpoint archive: %w", err)
+	}
+	if _, err := tw.Write(manifestData); err != nil {
+		return fmt.Errorf("failed to write checkpoint archive: %w", err)
+	}
+
+	err = filepath.Walk(filepath.Join(checkpointDir, manifest.CheckpointID), func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(checkpointDir, filePath)
+		if err != nil {
+			return err
+		}
+
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(relPath)
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		src, err := os.Open(filePath)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+
+		_, err = io.Copy(tw, src)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to archive checkpoint: %w", err)
+	}
+
+	return tw.Close()
+}
+
+// extractCheckpointArchive extracts a checkpoint archive into dir and
+// returns its manifest
+func extractCheckpointArchive(path, dir string) (*CheckpointManifest, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open checkpoint archive: %w", err)
+	}
+	defer file.Close()
+
+	var manifest *CheckpointManifest
+	tr := tar.NewReader(file)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read checkpoint archive: %w", err)
+		}
+
+		target := filepath.Join(dir, filepath.FromSlash(header.Name))
+		if !strings.HasPrefix(target, filepath.Clean(dir)+string(os.PathSeparator)) {
+			return nil, fmt.Errorf("invalid path in checkpoint archive: %s", header.Name)
+		}
+
+		switch {
+		case header.Name == checkpointManifestName:
+			manifest = &CheckpointManifest{}
+			if err := json.NewDecoder(tr).Decode(manifest); err != nil {
+				return nil, fmt.Errorf("invalid checkpoint manifest: %w", err)
+			}
+		case header.Typeflag == tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return nil, fmt.Errorf("failed to extract checkpoint: %w", err)
+			}
+		case header.Typeflag == tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return nil, fmt.Errorf("failed to extract checkpoint: %w", err)
+			}
+			dst, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(header.Mode))
+			if err != nil {
+				return nil, fmt.Errorf("failed to extract checkpoint: %w", err)
+			}
+			if _, err := io.Copy(dst, tr); err != nil {
+				dst.Close()
+				return nil, fmt.Errorf("failed to extract checkpoint: %w", err)
+			}
+			dst.Close()
+		}
+	}
+
+	if manifest == nil {
+		return nil, fmt.Errorf("checkpoint archive has no %s", checkpointManifestName)
+	}
+	if manifest.CheckpointID == "" || manifest.Image == "" {
+		return nil, fmt.Errorf("checkpoint manifest is missing checkpointId or image")
+	}
+
+	return manifest, nil
+}
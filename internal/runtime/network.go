@@ -0,0 +1,134 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/pxkundu/agent-as-code/internal/log"
+)
+
+// CreateNetwork creates a bridge network unless one with the same name
+// exists
+func (r *Runtime) CreateNetwork(name string, labels map[string]string) error {
+	if r.dockerClient == nil {
+		return fmt.Errorf("Docker client not available. Please ensure Docker is running")
+	}
+
+	ctx := context.Background()
+
+	networks, err := r.dockerClient.NetworkList(ctx, types.NetworkListOptions{
+		Filters: filters.NewArgs(filters.Arg("name", name)),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list networks: %w", err)
+	}
+	// The name filter matches substrings
+	for _, existing := range networks {
+		if existing.Name == name {
+			log.Debug("network exists", "name", name)
+			return nil
+		}
+	}
+
+	log.Info("creating network", "name", name)
+	if _, err := r.dockerClient.NetworkCreate(ctx, name, types.NetworkCreate{
+		Driver:         "bridge",
+		CheckDuplicate: true,
+		Labels:         labels,
+	}); err != nil {
+		return fmt.Errorf("failed to create network: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveNetwork removes a network. A missing network is not an error.
+func (r *Runtime) RemoveNetwork(name string) error {
+	if r.dockerClient == nil {
+		return fmt.Errorf("Docker client not available")
+	}
+
+	log.Info("removing network", "name", name)
+	if err := r.dockerClient.NetworkRemove(context.Background(), name); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil
+		}
+		return fmt.Errorf("failed to remove network: %w", err)
+	}
+
+	return nil
+}
+
+// FindContainers returns the containers, running or not, that have all of
+// the given labels, newest first
+func (r *Runtime) FindContainers(labels map[string]string) ([]ContainerInfo, error) {
+	if r.dockerClient == nil {
+		return nil, fmt.Errorf("Docker client not available. Please ensure Docker is running")
+	}
+
+	args := filters.NewArgs()
+	for key, value := range labels {
+		args.Add("label", key+"="+value)
+	}
+
+	containers, err := r.dockerClient.ContainerList(context.Background(), types.ContainerListOptions{
+		All:     true,
+		Filters: args,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	var infos []ContainerInfo
+	for _, c := range containers {
+		infos = append(infos, containerInfo(c))
+	}
+
+	return infos, nil
+}
+
+// containerInfo maps a Docker container summary to ContainerInfo
+func containerInfo(c types.Container) ContainerInfo {
+	info := ContainerInfo{
+		ID:        c.ID,
+		ImageName: c.Image,
+		State:     c.State,
+		Status:    c.State,
+		Labels:    c.Labels,
+	}
+	if c.Status != "" {
+		info.Status = fmt.Sprintf("%s (%s)", c.State, c.Status)
+	}
+	if len(c.Names) > 0 {
+		info.Name = strings.TrimPrefix(c.Names[0], "/")
+	}
+	for _, port := range c.Ports {
+		mapping := PortMapping{
+			HostIP:    port.IP,
+			Container: fmt.Sprintf("%d", port.PrivatePort),
+			Protocol:  port.Type,
+		}
+		if port.PublicPort != 0 {
+			mapping.Host = fmt.Sprintf("%d", port.PublicPort)
+		}
+		info.Ports = append(info.Ports, mapping)
+	}
+	return info
+}
+
+// Remove removes a stopped container
+func (r *Runtime) Remove(containerID string) error {
+	if r.dockerClient == nil {
+		return fmt.Errorf("Docker client not available")
+	}
+
+	log.Info("removing container", "id", containerID[:12])
+	if err := r.dockerClient.ContainerRemove(context.Background(), containerID, types.ContainerRemoveOptions{}); err != nil {
+		return fmt.Errorf("failed to remove container: %w", err)
+	}
+
+	return nil
+}
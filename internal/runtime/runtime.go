@@ -4,17 +4,27 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net"
+	"net/http"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/docker/go-connections/nat"
+	"github.com/pxkundu/agent-as-code/internal/log"
 )
 
+// agentLabel marks the containers started by Run
+const agentLabel = "agent.dev/v1"
+
 // Runtime handles agent execution
 type Runtime struct {
 	dockerClient *client.Client
@@ -29,17 +39,56 @@ type RunOptions struct {
 	Name        string
 	Volumes     []string
 	Interactive bool
+
+	// Resource limits; zero leaves Docker's default
+	Memory    int64 // Bytes
+	CPUQuota  int64 // Microseconds per CPUPeriod
+	CPUPeriod int64 // Microseconds
+	PidsLimit int64
+
+	// Network to attach the container to, and the names other containers on
+	// it can reach the container by
+	Network        string
+	NetworkAliases []string
+	Labels         map[string]string
+
+	// GPUs to pass to the container through the NVIDIA Container Toolkit:
+	// "all", or a comma-separated list of device IDs or UUIDs
+	GPUs string
+	// Runtime is the OCI runtime, e.g. "nvidia" for GPU support on Docker
+	// versions without device requests
+	Runtime string
+
+	// Init runs Docker's init process (tini) as PID 1 to reap zombies
+	Init bool
+	// OomKillDisable keeps the kernel OOM killer from killing the container
+	// when it exceeds its memory limit
+	OomKillDisable bool
+	// OomScoreAdj adjusts the container's OOM score, from -1000 to 1000
+	OomScoreAdj int
+}
+
+// LogOptions represents log streaming options
+type LogOptions struct {
+	Since string // timestamp or relative duration (e.g. 10m)
+	Until string // timestamp or relative duration (e.g. 1m)
+	Tail  string // number of lines from the end, or "all"
 }
 
 // ContainerInfo represents container information
 type ContainerInfo struct {
-	ID    string
-	Name  string
-	Ports []PortMapping
+	ID        string
+	Name      string
+	ImageName string
+	Ports     []PortMapping
+	State     string // e.g. running or exited
+	Status    string // State with Docker's status, e.g. "running (Up 5 minutes)"
+	Labels    map[string]string
 }
 
 // PortMapping represents port mapping
 type PortMapping struct {
+	HostIP    string
 	Host      string
 	Container string
 	Protocol  string
@@ -71,7 +120,7 @@ func (r *Runtime) ValidateImage(imageName string) error {
 		return fmt.Errorf("image '%s' not found locally. Try 'agent pull %s' first", imageName, imageName)
 	}
 
-	fmt.Printf("✓ Image found: %s\n", imageName)
+	log.Debug("image found", "image", imageName)
 	return nil
 }
 
@@ -90,17 +139,25 @@ func (r *Runtime) Run(options *RunOptions) (*ContainerInfo, error) {
 	}
 
 	// Parse port mappings
-	ports := parsePortMappings(options.Ports)
+	ports, err := parsePortMappings(options.Ports)
+	if err != nil {
+		return nil, err
+	}
 	portBindings := make(nat.PortMap)
 	exposedPorts := make(nat.PortSet)
 
 	for _, port := range ports {
 		containerPort := nat.Port(fmt.Sprintf("%s/%s", port.Container, port.Protocol))
 		exposedPorts[containerPort] = struct{}{}
-		if port.Host != "" {
+		// An empty host port with a host IP lets Docker pick the port
+		if port.Host != "" || port.HostIP != "" {
+			hostIP := port.HostIP
+			if hostIP == "" {
+				hostIP = "0.0.0.0"
+			}
 			portBindings[containerPort] = []nat.PortBinding{
 				{
-					HostIP:   "0.0.0.0",
+					HostIP:   hostIP,
 					HostPort: port.Host,
 				},
 			}
@@ -112,12 +169,41 @@ func (r *Runtime) Run(options *RunOptions) (*ContainerInfo, error) {
 		Image:        options.Image,
 		Env:          options.Environment,
 		ExposedPorts: exposedPorts,
+		Labels:       agentLabels(options.Labels),
 	}
 
 	// Host configuration
 	hostConfig := &container.HostConfig{
 		PortBindings: portBindings,
+		Resources: container.Resources{
+			Memory:    options.Memory,
+			CPUQuota:  options.CPUQuota,
+			CPUPeriod: options.CPUPeriod,
+		},
+	}
+	if options.PidsLimit > 0 {
+		hostConfig.PidsLimit = &options.PidsLimit
+	}
+	if options.GPUs != "" {
+		request, err := gpuDeviceRequest(options.GPUs)
+		if err != nil {
+			return nil, err
+		}
+		hostConfig.Resources.DeviceRequests = []container.DeviceRequest{request}
+	}
+	hostConfig.Runtime = options.Runtime
+	if options.Init {
+		trueVal := true
+		hostConfig.Init = &trueVal
+	}
+	if options.OomKillDisable {
+		trueVal := true
+		hostConfig.Resources.OomKillDisable = &trueVal
 	}
+	if options.OomScoreAdj < -1000 || options.OomScoreAdj > 1000 {
+		return nil, fmt.Errorf("invalid OOM score adjustment %d: must be between -1000 and 1000", options.OomScoreAdj)
+	}
+	hostConfig.OomScoreAdj = options.OomScoreAdj
 
 	if options.Interactive {
 		containerConfig.Tty = true
@@ -130,46 +216,53 @@ func (r *Runtime) Run(options *RunOptions) (*ContainerInfo, error) {
 		hostConfig.Binds = options.Volumes
 	}
 
-	fmt.Printf("Creating container: %s\n", containerName)
+	var networkingConfig *network.NetworkingConfig
+	if options.Network != "" {
+		hostConfig.NetworkMode = container.NetworkMode(options.Network)
+		networkingConfig = &network.NetworkingConfig{
+			EndpointsConfig: map[string]*network.EndpointSettings{
+				options.Network: {Aliases: options.NetworkAliases},
+			},
+		}
+	}
+
+	log.Info("creating container", "name", containerName, "image", options.Image)
 
 	// Create container
-	resp, err := r.dockerClient.ContainerCreate(ctx, containerConfig, hostConfig, nil, nil, containerName)
+	resp, err := r.dockerClient.ContainerCreate(ctx, containerConfig, hostConfig, networkingConfig, nil, containerName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create container: %w", err)
 	}
 
 	containerID := resp.ID
-	fmt.Printf("Container ID: %s\n", containerID[:12])
+	log.Debug("container created", "id", containerID[:12])
 
-	// Show port mappings
-	if len(ports) > 0 {
-		fmt.Printf("Port mappings:\n")
-		for _, port := range ports {
-			fmt.Printf("  %s:%s -> %s/%s\n", port.Host, port.Container, port.Container, port.Protocol)
-		}
+	for _, port := range ports {
+		log.Debug("port mapping", "host_ip", port.HostIP, "host", port.Host, "container", port.Container, "protocol", port.Protocol)
 	}
 
-	// Show environment variables
-	if len(options.Environment) > 0 {
-		fmt.Printf("Environment variables:\n")
-		for _, env := range options.Environment {
-			fmt.Printf("  %s\n", env)
-		}
+	// Log variable names only; values may hold secrets
+	for _, env := range options.Environment {
+		log.Debug("environment variable", "name", strings.SplitN(env, "=", 2)[0])
 	}
 
 	// Start the container
-	fmt.Printf("Starting container...\n")
+	log.Info("starting container", "id", containerID[:12])
 	err = r.dockerClient.ContainerStart(ctx, containerID, types.ContainerStartOptions{})
 	if err != nil {
+		if options.GPUs != "" && strings.Contains(err.Error(), "could not select device driver") {
+			return nil, fmt.Errorf("failed to start container: %w (GPU support requires the NVIDIA Container Toolkit on the Docker host)", err)
+		}
 		return nil, fmt.Errorf("failed to start container: %w", err)
 	}
 
-	fmt.Printf("✅ Container started successfully\n")
+	log.Info("container started", "id", containerID[:12], "name", containerName)
 
 	return &ContainerInfo{
-		ID:    containerID,
-		Name:  containerName,
-		Ports: ports,
+		ID:        containerID,
+		Name:      containerName,
+		ImageName: options.Image,
+		Ports:     ports,
 	}, nil
 }
 
@@ -182,7 +275,7 @@ func (r *Runtime) Stop(containerID string) error {
 	ctx := context.Background()
 	timeout := int(30) // 30 second timeout
 
-	fmt.Printf("Stopping container %s...\n", containerID[:12])
+	log.Info("stopping container", "id", containerID[:12])
 
 	err := r.dockerClient.ContainerStop(ctx, containerID, container.StopOptions{
 		Timeout: &timeout,
@@ -191,34 +284,139 @@ func (r *Runtime) Stop(containerID string) error {
 		return fmt.Errorf("failed to stop container: %w", err)
 	}
 
-	fmt.Printf("✅ Container stopped\n")
+	log.Info("container stopped", "id", containerID[:12])
 	return nil
 }
 
-// StreamLogs streams container logs
-func (r *Runtime) StreamLogs(containerID string) error {
+// healthPollInterval is how often WaitHealthy checks the container
+const healthPollInterval = 500 * time.Millisecond
+
+// WaitHealthy waits until a started container is ready. Containers with a
+// Docker health check must report healthy; for others the first published
+// port's /health endpoint must answer. Containers with neither are ready
+// immediately.
+func (r *Runtime) WaitHealthy(containerID string, timeout time.Duration) error {
 	if r.dockerClient == nil {
 		return fmt.Errorf("Docker client not available")
 	}
 
 	ctx := context.Background()
+	deadline := time.Now().Add(timeout)
+	httpClient := &http.Client{Timeout: 2 * time.Second}
+	lastStatus := "starting"
+
+	log.Debug("waiting for container", "id", containerID[:12], "timeout", timeout)
+
+	for {
+		inspect, err := r.dockerClient.ContainerInspect(ctx, containerID)
+		if err != nil {
+			return fmt.Errorf("failed to inspect container: %w", err)
+		}
+		if inspect.State != nil && !inspect.State.Running {
+			return fmt.Errorf("container exited with code %d", inspect.State.ExitCode)
+		}
 
-	fmt.Printf("Streaming logs for container %s...\n", containerID[:12])
+		if inspect.State != nil && inspect.State.Health != nil {
+			lastStatus = inspect.State.Health.Status
+			switch lastStatus {
+			case types.Healthy:
+				return nil
+			case types.Unhealthy:
+				return fmt.Errorf("container is unhealthy")
+			}
+		} else {
+			url := healthURL(inspect)
+			if url == "" {
+				return nil
+			}
+			resp, err := httpClient.Get(url)
+			if err == nil {
+				resp.Body.Close()
+				// A 404 means the agent is serving but has no health endpoint
+				if resp.StatusCode < 300 || resp.StatusCode == http.StatusNotFound {
+					return nil
+				}
+				lastStatus = fmt.Sprintf("%s returned %d", url, resp.StatusCode)
+			} else {
+				lastStatus = fmt.Sprintf("%s not reachable", url)
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("container not healthy after %s (%s)", timeout, lastStatus)
+		}
+		time.Sleep(healthPollInterval)
+	}
+}
+
+// healthURL returns the /health URL on the first published port of a
+// container, or "" if no port is published
+func healthURL(inspect types.ContainerJSON) string {
+	if inspect.NetworkSettings == nil {
+		return ""
+	}
+
+	// Sort for a stable choice of port
+	ports := make([]string, 0, len(inspect.NetworkSettings.Ports))
+	for port := range inspect.NetworkSettings.Ports {
+		ports = append(ports, string(port))
+	}
+	sort.Strings(ports)
+
+	for _, port := range ports {
+		for _, binding := range inspect.NetworkSettings.Ports[nat.Port(port)] {
+			if binding.HostPort == "" || !strings.HasSuffix(port, "/tcp") {
+				continue
+			}
+			host := binding.HostIP
+			if host == "" || host == "0.0.0.0" || host == "::" {
+				host = "localhost"
+			}
+			return fmt.Sprintf("http://%s/health", net.JoinHostPort(host, binding.HostPort))
+		}
+	}
+
+	return ""
+}
+
+// StreamLogs follows the logs of a container, given by ID or name
+func (r *Runtime) StreamLogs(containerID string, options LogOptions) error {
+	if r.dockerClient == nil {
+		return fmt.Errorf("Docker client not available")
+	}
+
+	ctx := context.Background()
+
+	// TTY containers produce a raw stream; others are multiplexed.
+	// containerID may also be a name, so log the ID from the inspection.
+	inspect, err := r.dockerClient.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return fmt.Errorf("failed to inspect container: %w", err)
+	}
+
+	log.Debug("streaming logs", "id", inspect.ID[:12])
 
 	// Get container logs
-	reader, err := r.dockerClient.ContainerLogs(ctx, containerID, types.ContainerLogsOptions{
+	reader, err := r.dockerClient.ContainerLogs(ctx, inspect.ID, types.ContainerLogsOptions{
 		ShowStdout: true,
 		ShowStderr: true,
 		Follow:     true,
 		Timestamps: true,
+		Since:      options.Since,
+		Until:      options.Until,
+		Tail:       options.Tail,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to get container logs: %w", err)
 	}
 	defer reader.Close()
 
-	// Stream logs to stdout
-	_, err = io.Copy(os.Stdout, reader)
+	// Stream logs to stdout/stderr
+	if inspect.Config != nil && inspect.Config.Tty {
+		_, err = io.Copy(os.Stdout, reader)
+	} else {
+		_, err = stdcopy.StdCopy(os.Stdout, os.Stderr, reader)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to stream logs: %w", err)
 	}
@@ -226,75 +424,155 @@ func (r *Runtime) StreamLogs(containerID string) error {
 	return nil
 }
 
-// List lists running containers
+// List lists the running containers started by agent run, which carry the
+// agentLabel label
 func (r *Runtime) List() ([]ContainerInfo, error) {
-	// In a real implementation, this would list actual containers
-	return []ContainerInfo{
-		{
-			ID:   "abcdef123456",
-			Name: "my-agent",
-			Ports: []PortMapping{
-				{Host: "8080", Container: "8080", Protocol: "tcp"},
-			},
-		},
-	}, nil
+	if r.dockerClient == nil {
+		return nil, fmt.Errorf("Docker client not available. Please ensure Docker is running")
+	}
+
+	containers, err := r.dockerClient.ContainerList(context.Background(), types.ContainerListOptions{
+		Filters: filters.NewArgs(filters.Arg("label", agentLabel+"=true")),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	infos := make([]ContainerInfo, 0, len(containers))
+	for _, c := range containers {
+		infos = append(infos, containerInfo(c))
+	}
+
+	return infos, nil
 }
 
 // Helper functions
+
+// agentLabels returns labels plus the label List finds agent containers by
+func agentLabels(labels map[string]string) map[string]string {
+	merged := map[string]string{agentLabel: "true"}
+	for key, value := range labels {
+		merged[key] = value
+	}
+	return merged
+}
+
 func generateContainerName(imageName string) string {
 	// Generate a unique container name based on image
 	timestamp := time.Now().Unix()
 	return fmt.Sprintf("agent-%d", timestamp)
 }
 
-func parsePortMappings(ports []string) []PortMapping {
-	var mappings []PortMapping
+// gpuDeviceRequest returns the request for the NVIDIA GPUs in gpus: "all"
+// for every GPU, or a comma-separated list of device IDs or UUIDs, which
+// may be prefixed with "device=" as with docker run --gpus
+func gpuDeviceRequest(gpus string) (container.DeviceRequest, error) {
+	request := container.DeviceRequest{
+		Driver:       "nvidia",
+		Capabilities: [][]string{{"gpu"}},
+	}
+
+	gpus = strings.TrimPrefix(strings.TrimSpace(gpus), "device=")
+	if gpus == "all" {
+		request.Count = -1
+		return request, nil
+	}
+
+	for _, id := range strings.Split(gpus, ",") {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			return container.DeviceRequest{}, fmt.Errorf("invalid GPU list '%s': expected 'all' or device IDs such as 0,1", gpus)
+		}
+		request.DeviceIDs = append(request.DeviceIDs, id)
+	}
+	return request, nil
+}
+
+// parsePortMappings parses -p specs of the form
+// [[hostIP:]hostPort:]containerPort[/protocol]. With a host IP the host
+// port may be left empty (127.0.0.1::8080) for Docker to pick one, and IPv6
+// host IPs go in brackets ([::1]:8080:8080). Only when no spec is given at
+// all is port 8080 published on all interfaces.
+func parsePortMappings(ports []string) ([]PortMapping, error) {
+	if len(ports) == 0 {
+		return []PortMapping{{Host: "8080", Container: "8080", Protocol: "tcp"}}, nil
+	}
 
+	var mappings []PortMapping
 	for _, portStr := range ports {
-		// Parse port strings like "8080:8080", "80:8080/tcp", "8080"
-		mapping := PortMapping{
-			Protocol: "tcp", // Default protocol
+		mapping, err := parsePortMapping(portStr)
+		if err != nil {
+			return nil, err
 		}
+		mappings = append(mappings, mapping)
+	}
 
-		// Split by protocol if specified
-		parts := strings.Split(portStr, "/")
-		if len(parts) == 2 {
-			mapping.Protocol = parts[1]
-			portStr = parts[0]
+	return mappings, nil
+}
+
+// parsePortMapping parses a single -p spec for parsePortMappings
+func parsePortMapping(spec string) (PortMapping, error) {
+	mapping := PortMapping{
+		Protocol: "tcp", // Default protocol
+	}
+
+	// Split off the protocol, if specified
+	portStr, protocol, hasProtocol := strings.Cut(strings.TrimSpace(spec), "/")
+	if hasProtocol {
+		switch protocol = strings.ToLower(strings.TrimSpace(protocol)); protocol {
+		case "tcp", "udp":
+			mapping.Protocol = protocol
+		default:
+			return PortMapping{}, fmt.Errorf("invalid port mapping '%s': protocol must be tcp or udp", spec)
 		}
+	}
 
-		// Split host:container ports
+	// A bracketed IPv6 host IP contains colons itself
+	if strings.HasPrefix(portStr, "[") {
+		end := strings.Index(portStr, "]")
+		if end < 0 || !strings.HasPrefix(portStr[end+1:], ":") {
+			return PortMapping{}, fmt.Errorf("invalid port mapping '%s': expected [IPv6]:hostPort:containerPort", spec)
+		}
+		mapping.HostIP = portStr[1:end]
+		portStr = portStr[end+2:]
+		host, container, ok := strings.Cut(portStr, ":")
+		if !ok {
+			return PortMapping{}, fmt.Errorf("invalid port mapping '%s': expected [IPv6]:hostPort:containerPort", spec)
+		}
+		mapping.Host, mapping.Container = host, container
+	} else {
+		// Split ip:host:container ports
 		portParts := strings.Split(portStr, ":")
 		switch len(portParts) {
 		case 1:
-			// Only container port specified (e.g., "8080")
-			mapping.Container = strings.TrimSpace(portParts[0])
-			mapping.Host = strings.TrimSpace(portParts[0]) // Same as container
+			// Only container port specified (e.g., "8080"), published on the same host port
+			mapping.Container = portParts[0]
+			mapping.Host = portParts[0]
 		case 2:
 			// Both host and container ports (e.g., "80:8080")
-			mapping.Host = strings.TrimSpace(portParts[0])
-			mapping.Container = strings.TrimSpace(portParts[1])
+			mapping.Host = portParts[0]
+			mapping.Container = portParts[1]
+		case 3:
+			// Host IP, host and container ports (e.g., "127.0.0.1:80:8080")
+			mapping.HostIP = portParts[0]
+			mapping.Host = portParts[1]
+			mapping.Container = portParts[2]
 		default:
-			// Invalid format, skip
-			continue
-		}
-
-		// Validate port numbers
-		if isValidPort(mapping.Host) && isValidPort(mapping.Container) {
-			mappings = append(mappings, mapping)
+			return PortMapping{}, fmt.Errorf("invalid port mapping '%s': expected [[hostIP:]hostPort:]containerPort[/protocol]", spec)
 		}
 	}
 
-	// Default port mapping if none specified
-	if len(mappings) == 0 {
-		mappings = append(mappings, PortMapping{
-			Host:      "8080",
-			Container: "8080",
-			Protocol:  "tcp",
-		})
+	if mapping.HostIP != "" && net.ParseIP(mapping.HostIP) == nil {
+		return PortMapping{}, fmt.Errorf("invalid port mapping '%s': '%s' is not an IP address", spec, mapping.HostIP)
+	}
+	if !isValidPort(mapping.Container) {
+		return PortMapping{}, fmt.Errorf("invalid port mapping '%s': invalid container port '%s'", spec, mapping.Container)
+	}
+	if !isValidPort(mapping.Host) && !(mapping.Host == "" && mapping.HostIP != "") {
+		return PortMapping{}, fmt.Errorf("invalid port mapping '%s': invalid host port '%s'", spec, mapping.Host)
 	}
 
-	return mappings
+	return mapping, nil
 }
 
 // isValidPort checks if a port string is a valid port number
@@ -0,0 +1,58 @@
+package runtime
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePortMappings(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    PortMapping
+		wantErr bool
+	}{
+		{spec: "8080", want: PortMapping{Host: "8080", Container: "8080", Protocol: "tcp"}},
+		{spec: "9000:8080", want: PortMapping{Host: "9000", Container: "8080", Protocol: "tcp"}},
+		{spec: "9000:8080/udp", want: PortMapping{Host: "9000", Container: "8080", Protocol: "udp"}},
+		{spec: "127.0.0.1:9000:8080/tcp", want: PortMapping{HostIP: "127.0.0.1", Host: "9000", Container: "8080", Protocol: "tcp"}},
+		{spec: "127.0.0.1::8080", want: PortMapping{HostIP: "127.0.0.1", Container: "8080", Protocol: "tcp"}},
+		{spec: "[::1]:8080:8080", want: PortMapping{HostIP: "::1", Host: "8080", Container: "8080", Protocol: "tcp"}},
+		{spec: "[::1]::8080", want: PortMapping{HostIP: "::1", Container: "8080", Protocol: "tcp"}},
+		{spec: "localhost:8080:8080", wantErr: true},
+		{spec: "8080/sctp", wantErr: true},
+		{spec: ":8080", wantErr: true},
+		{spec: "9000:", wantErr: true},
+		{spec: "70000:8080", wantErr: true},
+		{spec: "[::1]8080:8080", wantErr: true},
+		{spec: "1.2.3.4:1:2:3", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := parsePortMappings([]string{tt.spec})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parsePortMappings(%q) = %v, want an error", tt.spec, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parsePortMappings(%q) error = %v", tt.spec, err)
+			}
+			if want := []PortMapping{tt.want}; !reflect.DeepEqual(got, want) {
+				t.Errorf("parsePortMappings(%q) = %v, want %v", tt.spec, got, want)
+			}
+		})
+	}
+}
+
+func TestParsePortMappingsDefault(t *testing.T) {
+	got, err := parsePortMappings(nil)
+	if err != nil {
+		t.Fatalf("parsePortMappings(nil) error = %v", err)
+	}
+	want := []PortMapping{{Host: "8080", Container: "8080", Protocol: "tcp"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parsePortMappings(nil) = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,159 @@
+// Package security scans agent images for known vulnerabilities
+package security
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+// Severities lists the Trivy severities from most to least severe
+var Severities = []string{"CRITICAL", "HIGH", "MEDIUM", "LOW", "UNKNOWN"}
+
+// Vulnerability is a vulnerable package found in an image
+type Vulnerability struct {
+	ID               string
+	Package          string
+	InstalledVersion string
+	FixedVersion     string
+	Severity         string
+	Title            string
+	Target           string // Image layer or file the package was found in
+}
+
+// ScanOptions configures a scan
+type ScanOptions struct {
+	// IgnoreUnfixed skips vulnerabilities without a released fix
+	IgnoreUnfixed bool
+}
+
+// trivyReport is the part of Trivy's JSON report the scanner reads
+type trivyReport struct {
+	Results []struct {
+		Target          string `json:"Target"`
+		Vulnerabilities []struct {
+			VulnerabilityID  string `json:"VulnerabilityID"`
+			PkgName          string `json:"PkgName"`
+			InstalledVersion string `json:"InstalledVersion"`
+			FixedVersion     string `json:"FixedVersion"`
+			Severity         string `json:"Severity"`
+			Title            string `json:"Title"`
+		} `json:"Vulnerabilities"`
+	} `json:"Results"`
+}
+
+// Scan runs Trivy against an image and returns the vulnerabilities found,
+// most severe first
+func Scan(image string, options ScanOptions) ([]Vulnerability, error) {
+	trivy, err := exec.LookPath("trivy")
+	if err != nil {
+		return nil, fmt.Errorf("trivy not found in PATH. Install it from https://aquasecurity.github.io/trivy")
+	}
+
+	output, err := os.CreateTemp("", "agent-scan-*.json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create report file: %w", err)
+	}
+	output.Close()
+	defer os.Remove(output.Name())
+
+	args := []string{"image", "--quiet", "--format", "json", "--output", output.Name()}
+	if options.IgnoreUnfixed {
+		args = append(args, "--ignore-unfixed")
+	}
+	args = append(args, image)
+
+	cmd := exec.Command(trivy, args...)
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("trivy scan failed: %w", err)
+	}
+
+	data, err := os.ReadFile(output.Name())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trivy report: %w", err)
+	}
+
+	return ParseTrivyReport(data)
+}
+
+// ParseTrivyReport reads the vulnerabilities from a Trivy JSON report, most
+// severe first
+func ParseTrivyReport(data []byte) ([]Vulnerability, error) {
+	var report trivyReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse trivy report: %w", err)
+	}
+
+	var vulnerabilities []Vulnerability
+	for _, result := range report.Results {
+		for _, v := range result.Vulnerabilities {
+			vulnerabilities = append(vulnerabilities, Vulnerability{
+				ID:               v.VulnerabilityID,
+				Package:          v.PkgName,
+				InstalledVersion: v.InstalledVersion,
+				FixedVersion:     v.FixedVersion,
+				Severity:         strings.ToUpper(v.Severity),
+				Title:            v.Title,
+				Target:           result.Target,
+			})
+		}
+	}
+
+	sort.SliceStable(vulnerabilities, func(i, j int) bool {
+		ri, rj := SeverityRank(vulnerabilities[i].Severity), SeverityRank(vulnerabilities[j].Severity)
+		if ri != rj {
+			return ri > rj
+		}
+		return vulnerabilities[i].ID < vulnerabilities[j].ID
+	})
+
+	return vulnerabilities, nil
+}
+
+// SeverityRank orders severities: CRITICAL is 4, LOW is 1, and UNKNOWN or
+// unrecognized severities are 0
+func SeverityRank(severity string) int {
+	switch strings.ToUpper(severity) {
+	case "CRITICAL":
+		return 4
+	case "HIGH":
+		return 3
+	case "MEDIUM":
+		return 2
+	case "LOW":
+		return 1
+	default:
+		return 0
+	}
+}
+
+// ParseSeverities parses a comma-separated severity list such as
+// HIGH,CRITICAL
+func ParseSeverities(list string) (map[string]bool, error) {
+	severities := make(map[string]bool)
+	for _, severity := range strings.Split(list, ",") {
+		severity = strings.ToUpper(strings.TrimSpace(severity))
+		if severity == "" {
+			continue
+		}
+		valid := false
+		for _, known := range Severities {
+			if severity == known {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return nil, fmt.Errorf("invalid severity '%s' (valid: %s)", severity, strings.Join(Severities, ", "))
+		}
+		severities[severity] = true
+	}
+	if len(severities) == 0 {
+		return nil, fmt.Errorf("no severities given")
+	}
+	return severities, nil
+}
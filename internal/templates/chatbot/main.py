@@ -4,6 +4,7 @@ Chatbot Agent - Customer Support Agent with Conversation Memory
 """
 
 import os
+import time
 import asyncio
 import logging
 from datetime import datetime
@@ -18,6 +19,9 @@ import openai
 logging.basicConfig(level=os.getenv("LOG_LEVEL", "INFO"))
 logger = logging.getLogger(__name__)
 
+# Used by the health check to report uptime
+start_time = time.time()
+
 # Initialize FastAPI app
 app = FastAPI(
     title="Chatbot Agent",
@@ -133,7 +137,6 @@ async def chat(request: ChatRequest):
 @app.get("/health", response_model=HealthResponse)
 async def health_check():
     """Health check endpoint"""
-    import time
     uptime = time.time() - start_time
     return HealthResponse(
         status="healthy",
@@ -148,14 +151,11 @@ async def root():
 
 if __name__ == "__main__":
     import uvicorn
-    import time
-    
-    start_time = time.time()
     
     logger.info("Starting Chatbot Agent...")
     uvicorn.run(
         app, 
         host="0.0.0.0", 
-        port=8080,
+        port=int(os.getenv("PORT", "8080")),
         log_level=os.getenv("LOG_LEVEL", "info").lower()
     )
@@ -0,0 +1,73 @@
+#!/usr/bin/env python3
+"""
+Tests for the Chatbot Agent. The OpenAI client is mocked, so no API key
+or network access is needed.
+"""
+
+import os
+import sys
+from unittest.mock import MagicMock, patch
+
+sys.path.insert(0, os.path.dirname(os.path.dirname(os.path.abspath(__file__))))
+os.environ.setdefault("OPENAI_API_KEY", "test-key")
+
+from fastapi.testclient import TestClient
+from main import app, chatbot
+
+client = TestClient(app)
+
+
+def completion(content):
+    """Build a chat completion response with the given content"""
+    response = MagicMock()
+    response.choices = [MagicMock()]
+    response.choices[0].message.content = content
+    return response
+
+
+def test_health_check():
+    response = client.get("/health")
+    assert response.status_code == 200
+    assert response.json()["status"] == "healthy"
+
+
+def test_root():
+    response = client.get("/")
+    assert response.status_code == 200
+    assert response.json()["status"] == "running"
+
+
+def test_chat_returns_model_response():
+    with patch.object(chatbot.client.chat.completions, "create", return_value=completion("Hi, how can I help?")):
+        response = client.post("/chat", json={"message": "Hello", "session_id": "test-session"})
+
+    assert response.status_code == 200
+    data = response.json()
+    assert data["response"] == "Hi, how can I help?"
+    assert data["session_id"] == "test-session"
+
+
+def test_chat_keeps_conversation_history():
+    with patch.object(chatbot.client.chat.completions, "create", return_value=completion("Noted.")) as create:
+        client.post("/chat", json={"message": "My order is #123", "session_id": "history-session"})
+        client.post("/chat", json={"message": "Where is it?", "session_id": "history-session"})
+
+    messages = create.call_args.kwargs["messages"]
+    assert {"role": "user", "content": "My order is #123"} in messages
+    assert messages[-1] == {"role": "user", "content": "Where is it?"}
+
+
+def test_chat_escalates_without_calling_model():
+    with patch.object(chatbot.client.chat.completions, "create") as create:
+        response = client.post("/chat", json={"message": "I want to talk to a human"})
+
+    assert response.status_code == 200
+    assert "human representative" in response.json()["response"]
+    create.assert_not_called()
+
+
+def test_chat_model_error():
+    with patch.object(chatbot.client.chat.completions, "create", side_effect=RuntimeError("boom")):
+        response = client.post("/chat", json={"message": "Hello"})
+
+    assert response.status_code == 500
@@ -0,0 +1,156 @@
+package templates
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// ciTemplates maps CI platforms to the file generated for them and its
+// template. The templates use [[ ]] delimiters so that the platforms' own
+// ${{ }} and $VAR syntax passes through unchanged.
+var ciTemplates = map[string]struct {
+	path     string
+	template string
+}{
+	"github": {filepath.Join(".github", "workflows", "agent-ci.yml"), githubWorkflowTemplate},
+	"gitlab": {".gitlab-ci.yml", gitlabCITemplate},
+}
+
+// CIPlatforms returns the CI platforms GenerateCI supports
+func CIPlatforms() []string {
+	return []string{"github", "gitlab"}
+}
+
+// GenerateCI writes a CI configuration for platform (github or gitlab) to
+// projectDir. The pipeline lints the agent, builds an image tagged with the
+// commit, tests it, and pushes it on the main branch using the
+// AGENT_REGISTRY_TOKEN secret.
+func (m *Manager) GenerateCI(platform, projectDir string, config *AgentConfig) error {
+	ci, ok := ciTemplates[platform]
+	if !ok {
+		return fmt.Errorf("unsupported CI platform '%s' (supported: %s)", platform, strings.Join(CIPlatforms(), ", "))
+	}
+
+	tmpl, err := template.New(platform).Delims("[[", "]]").Parse(ci.template)
+	if err != nil {
+		return fmt.Errorf("failed to parse CI template: %w", err)
+	}
+
+	var content strings.Builder
+	if err := tmpl.Execute(&content, config); err != nil {
+		return fmt.Errorf("failed to render CI template: %w", err)
+	}
+
+	outputPath := filepath.Join(projectDir, ci.path)
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(outputPath, []byte(content.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", ci.path, err)
+	}
+
+	return nil
+}
+
+const githubWorkflowTemplate = `# CI for the [[ .Name ]] agent, generated by agent init
+name: agent-ci
+
+on:
+  push:
+    branches: [main]
+  pull_request:
+
+env:
+  IMAGE: ${{ github.repository }}:${{ github.sha }}
+  # Arguments of the agent test step
+  AGENT_TEST_ARGS: --timeout 60s
+
+jobs:
+  agent:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+
+      - name: Install agent CLI
+        run: |
+          curl -fsSL -o /usr/local/bin/agent https://github.com/pxkundu/agent-as-code/releases/latest/download/agent-linux-amd64
+          chmod +x /usr/local/bin/agent
+
+      - name: Validate
+        run: agent lint .
+
+      - name: Build
+        run: agent build -t "$IMAGE" .
+
+      - name: Test
+        run: agent test $AGENT_TEST_ARGS "$IMAGE"
+
+      - name: Push
+        if: github.ref == 'refs/heads/main' && github.event_name == 'push'
+        env:
+          AGENT_REGISTRY_TOKEN: ${{ secrets.AGENT_REGISTRY_TOKEN }}
+        run: |
+          if [ -z "$AGENT_REGISTRY_TOKEN" ]; then
+            echo "AGENT_REGISTRY_TOKEN secret is not set" >&2
+            exit 1
+          fi
+          agent push "$IMAGE"
+`
+
+const gitlabCITemplate = `# CI for the [[ .Name ]] agent, generated by agent init
+stages:
+  - validate
+  - build
+  - test
+  - push
+
+variables:
+  IMAGE: $CI_PROJECT_PATH:$CI_COMMIT_SHA
+  # Arguments of the agent test job
+  AGENT_TEST_ARGS: --timeout 60s
+  DOCKER_HOST: tcp://docker:2375
+  DOCKER_TLS_CERTDIR: ""
+
+default:
+  image: docker:24
+  services:
+    - docker:24-dind
+  before_script:
+    - apk add --no-cache curl
+    - curl -fsSL -o /usr/local/bin/agent https://github.com/pxkundu/agent-as-code/releases/latest/download/agent-linux-amd64
+    - chmod +x /usr/local/bin/agent
+
+validate:
+  stage: validate
+  services: []
+  script:
+    - agent lint .
+
+build:
+  stage: build
+  script:
+    - agent build -t "$IMAGE" .
+    - docker save -o image.tar "$IMAGE"
+  artifacts:
+    paths:
+      - image.tar
+    expire_in: 1 day
+
+test:
+  stage: test
+  script:
+    - docker load -i image.tar
+    - agent test $AGENT_TEST_ARGS "$IMAGE"
+
+push:
+  stage: push
+  rules:
+    - if: $CI_COMMIT_BRANCH == "main"
+  script:
+    - test -n "$AGENT_REGISTRY_TOKEN" || { echo "AGENT_REGISTRY_TOKEN variable is not set" >&2; exit 1; }
+    - docker load -i image.tar
+    - agent push "$IMAGE"
+`
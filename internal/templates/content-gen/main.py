@@ -0,0 +1,163 @@
+#!/usr/bin/env python3
+"""
+Content Generator Agent - Writes blog posts, social media posts and marketing copy using AI
+"""
+
+import os
+import time
+import asyncio
+import logging
+from datetime import datetime
+from typing import List, Optional
+
+from fastapi import FastAPI, HTTPException
+from pydantic import BaseModel
+import openai
+
+# Configure logging
+logging.basicConfig(level=os.getenv("LOG_LEVEL", "INFO"))
+logger = logging.getLogger(__name__)
+
+# Used by the health check to report uptime
+start_time = time.time()
+
+# Initialize FastAPI app
+app = FastAPI(
+    title="Content Generator Agent",
+    description="AI-powered content generation for blogs, social media and marketing",
+    version="1.0.0"
+)
+
+CONTENT_TYPES = {
+    "blog": "a blog post with a title, an introduction, sections with headings and a conclusion, in Markdown",
+    "social": "a social media post with a hook in the first line and relevant hashtags at the end",
+    "marketing": "marketing copy with a headline, the key benefits and a call to action",
+    "email": "an email with a subject line, a greeting, the body and a sign-off",
+}
+
+# Approximate word counts for each length
+LENGTHS = {
+    "short": 100,
+    "medium": 300,
+    "long": 800,
+}
+
+# Request/Response models
+class GenerateRequest(BaseModel):
+    topic: str
+    content_type: Optional[str] = "blog"
+    tone: Optional[str] = None
+    length: Optional[str] = None
+    audience: Optional[str] = None
+    keywords: Optional[List[str]] = None
+
+class GenerateResponse(BaseModel):
+    content: str
+    content_type: str
+    tone: str
+    word_count: int
+    timestamp: str
+
+class HealthResponse(BaseModel):
+    status: str
+    uptime: str
+    timestamp: str
+
+class ContentGeneratorAgent:
+    def __init__(self):
+        self.client = openai.OpenAI(
+            api_key=os.getenv("OPENAI_API_KEY")
+        )
+        self.model = os.getenv("MODEL_NAME", "gpt-4")
+        self.default_tone = os.getenv("DEFAULT_TONE", "professional")
+        self.default_length = os.getenv("DEFAULT_LENGTH", "medium")
+
+    def build_prompt(self, request: GenerateRequest, tone: str, length: str) -> str:
+        """Describe the piece of content to write"""
+        prompt = (
+            f"Write {CONTENT_TYPES[request.content_type]} about: {request.topic}\n"
+            f"Tone: {tone}\n"
+            f"Length: about {LENGTHS[length]} words\n"
+        )
+        if request.audience:
+            prompt += f"Audience: {request.audience}\n"
+        if request.keywords:
+            prompt += f"Work in these keywords naturally: {', '.join(request.keywords)}\n"
+        return prompt
+
+    async def generate(self, request: GenerateRequest) -> GenerateResponse:
+        """Generate content for the request"""
+        tone = request.tone or self.default_tone
+        length = request.length or self.default_length
+
+        if not request.topic.strip():
+            raise HTTPException(status_code=400, detail="topic must not be empty")
+        if request.content_type not in CONTENT_TYPES:
+            raise HTTPException(status_code=400, detail=f"content_type must be one of: {', '.join(CONTENT_TYPES)}")
+        if length not in LENGTHS:
+            raise HTTPException(status_code=400, detail=f"length must be one of: {', '.join(LENGTHS)}")
+
+        try:
+            response = await asyncio.to_thread(
+                self.client.chat.completions.create,
+                model=self.model,
+                messages=[
+                    {"role": "system", "content": "You are an experienced copywriter. Write original, accurate content and respond with only the content."},
+                    {"role": "user", "content": self.build_prompt(request, tone, length)}
+                ],
+                max_tokens=LENGTHS[length] * 3,
+                temperature=0.7
+            )
+
+            content = response.choices[0].message.content.strip()
+
+            return GenerateResponse(
+                content=content,
+                content_type=request.content_type,
+                tone=tone,
+                word_count=len(content.split()),
+                timestamp=datetime.now().isoformat()
+            )
+
+        except Exception as e:
+            logger.error(f"Error generating content: {e}")
+            raise HTTPException(status_code=500, detail="Internal server error")
+
+# Initialize content generator agent
+generator = ContentGeneratorAgent()
+
+@app.post("/generate", response_model=GenerateResponse)
+async def generate(request: GenerateRequest):
+    """Generate content on the provided topic"""
+    return await generator.generate(request)
+
+@app.get("/content-types")
+async def content_types():
+    """List the content types and lengths that can be generated"""
+    return {"content_types": list(CONTENT_TYPES), "lengths": LENGTHS}
+
+@app.get("/health", response_model=HealthResponse)
+async def health_check():
+    """Health check endpoint"""
+    uptime = time.time() - start_time
+    return HealthResponse(
+        status="healthy",
+        uptime=f"{uptime:.2f}s",
+        timestamp=datetime.now().isoformat()
+    )
+
+@app.get("/")
+async def root():
+    """Root endpoint"""
+    return {"message": "Content Generator Agent API", "status": "running", "version": "1.0.0"}
+
+if __name__ == "__main__":
+    import uvicorn
+
+    logger.info("Starting Content Generator Agent...")
+    uvicorn.run(
+        app,
+        host="0.0.0.0",
+        port=int(os.getenv("PORT", "8080")),
+        log_level=os.getenv("LOG_LEVEL", "info").lower()
+    )
@@ -0,0 +1,85 @@
+#!/usr/bin/env python3
+"""
+Tests for the Content Generator Agent. The OpenAI client is mocked, so no
+API key or network access is needed.
+"""
+
+import os
+import sys
+from unittest.mock import MagicMock, patch
+
+sys.path.insert(0, os.path.dirname(os.path.dirname(os.path.abspath(__file__))))
+os.environ.setdefault("OPENAI_API_KEY", "test-key")
+
+import pytest
+from fastapi.testclient import TestClient
+from main import app, generator
+
+client = TestClient(app)
+
+
+def completion(content):
+    """Build a chat completion response with the given content"""
+    response = MagicMock()
+    response.choices = [MagicMock()]
+    response.choices[0].message.content = content
+    return response
+
+
+def test_health_check():
+    response = client.get("/health")
+    assert response.status_code == 200
+    assert response.json()["status"] == "healthy"
+
+
+def test_content_types():
+    response = client.get("/content-types")
+    assert response.status_code == 200
+    assert "blog" in response.json()["content_types"]
+
+
+def test_generate_defaults():
+    with patch.object(generator.client.chat.completions, "create", return_value=completion("# Title\n\nSome words here.")):
+        response = client.post("/generate", json={"topic": "container security"})
+
+    assert response.status_code == 200
+    data = response.json()
+    assert data["content_type"] == "blog"
+    assert data["tone"] == generator.default_tone
+    assert data["word_count"] == 5
+
+
+def test_generate_prompt_includes_options():
+    with patch.object(generator.client.chat.completions, "create", return_value=completion("Try it today! #ai")) as create:
+        response = client.post("/generate", json={
+            "topic": "our new release",
+            "content_type": "social",
+            "tone": "playful",
+            "length": "short",
+            "audience": "developers",
+            "keywords": ["agents", "docker"],
+        })
+
+    assert response.status_code == 200
+    prompt = create.call_args.kwargs["messages"][-1]["content"]
+    assert "social media post" in prompt
+    assert "Tone: playful" in prompt
+    assert "about 100 words" in prompt
+    assert "Audience: developers" in prompt
+    assert "agents, docker" in prompt
+
+
+@pytest.mark.parametrize("request_data", [
+    {"topic": " "},
+    {"topic": "x", "content_type": "novel"},
+    {"topic": "x", "length": "epic"},
+])
+def test_generate_invalid_request(request_data):
+    assert client.post("/generate", json=request_data).status_code == 400
+
+
+def test_generate_model_error():
+    with patch.object(generator.client.chat.completions, "create", side_effect=RuntimeError("boom")):
+        response = client.post("/generate", json={"topic": "anything"})
+
+    assert response.status_code == 500
@@ -0,0 +1,199 @@
+#!/usr/bin/env python3
+"""
+Data Analyzer Agent - Computes statistics on tabular data and explains them using AI
+"""
+
+import io
+import os
+import time
+import json
+import asyncio
+import logging
+from datetime import datetime
+from typing import Any, Dict, List, Optional
+
+from fastapi import FastAPI, HTTPException
+from pydantic import BaseModel
+import pandas as pd
+import openai
+
+# Configure logging
+logging.basicConfig(level=os.getenv("LOG_LEVEL", "INFO"))
+logger = logging.getLogger(__name__)
+
+# Used by the health check to report uptime
+start_time = time.time()
+
+# Initialize FastAPI app
+app = FastAPI(
+    title="Data Analyzer Agent",
+    description="AI-powered analysis of tabular data with statistics and insights",
+    version="1.0.0"
+)
+
+# Request/Response models
+class AnalyzeRequest(BaseModel):
+    csv: Optional[str] = None
+    records: Optional[List[Dict[str, Any]]] = None
+    question: Optional[str] = None
+    include_insights: Optional[bool] = True
+
+class ColumnStats(BaseModel):
+    name: str
+    dtype: str
+    missing: int
+    unique: int
+    mean: Optional[float] = None
+    std: Optional[float] = None
+    min: Optional[float] = None
+    max: Optional[float] = None
+    top: Optional[str] = None
+
+class AnalyzeResponse(BaseModel):
+    rows: int
+    columns: List[ColumnStats]
+    correlations: Dict[str, Dict[str, float]]
+    insights: Optional[str] = None
+    timestamp: str
+
+class HealthResponse(BaseModel):
+    status: str
+    uptime: str
+    timestamp: str
+
+def load_dataframe(request: AnalyzeRequest, max_rows: int) -> pd.DataFrame:
+    """Build a data frame from the CSV text or the records of the request"""
+    if (request.csv is None) == (request.records is None):
+        raise HTTPException(status_code=400, detail="provide exactly one of csv or records")
+
+    try:
+        if request.csv is not None:
+            df = pd.read_csv(io.StringIO(request.csv))
+        else:
+            df = pd.DataFrame.from_records(request.records)
+    except Exception as e:
+        raise HTTPException(status_code=400, detail=f"invalid data: {e}")
+
+    if df.empty:
+        raise HTTPException(status_code=400, detail="data has no rows")
+    if len(df) > max_rows:
+        raise HTTPException(status_code=400, detail=f"data has {len(df)} rows, the limit is {max_rows}")
+    return df
+
+def column_stats(df: pd.DataFrame) -> List[ColumnStats]:
+    """Describe every column: numeric columns get their distribution, others their most common value"""
+    stats = []
+    for name in df.columns:
+        column = df[name]
+        entry = ColumnStats(
+            name=str(name),
+            dtype=str(column.dtype),
+            missing=int(column.isna().sum()),
+            unique=int(column.nunique())
+        )
+        values = column.dropna()
+        if pd.api.types.is_numeric_dtype(column) and not values.empty:
+            entry.mean = round(float(values.mean()), 4)
+            entry.std = round(float(values.std()), 4) if len(values) > 1 else 0.0
+            entry.min = float(values.min())
+            entry.max = float(values.max())
+        elif not values.empty:
+            entry.top = str(values.mode().iloc[0])
+        stats.append(entry)
+    return stats
+
+def correlations(df: pd.DataFrame) -> Dict[str, Dict[str, float]]:
+    """Pearson correlations between the numeric columns"""
+    numeric = df.select_dtypes(include="number")
+    if numeric.shape[1] < 2:
+        return {}
+    matrix = numeric.corr().round(4).fillna(0.0)
+    return {str(k): {str(c): float(v) for c, v in row.items()} for k, row in matrix.to_dict(orient="index").items()}
+
+class DataAnalyzerAgent:
+    def __init__(self):
+        self.client = openai.OpenAI(
+            api_key=os.getenv("OPENAI_API_KEY")
+        )
+        self.model = os.getenv("MODEL_NAME", "gpt-4")
+        self.max_rows = int(os.getenv("MAX_ROWS", "10000"))
+
+    async def analyze(self, request: AnalyzeRequest) -> AnalyzeResponse:
+        """Compute statistics for the data and ask the model to interpret them"""
+        df = load_dataframe(request, self.max_rows)
+        stats = column_stats(df)
+        corr = correlations(df)
+
+        insights = None
+        if request.include_insights or request.question:
+            try:
+                # Only the statistics and a small sample are sent to the model
+                summary = {
+                    "rows": len(df),
+                    "columns": [s.model_dump(exclude_none=True) for s in stats],
+                    "correlations": corr,
+                    "sample": json.loads(df.head(5).to_json(orient="records")),
+                }
+                prompt = f"Statistics of a dataset:\n{json.dumps(summary, indent=2)}\n\n"
+                if request.question:
+                    prompt += f"Answer this question about the data: {request.question}"
+                else:
+                    prompt += "Describe the notable patterns, outliers and data quality issues in this data."
+
+                response = await asyncio.to_thread(
+                    self.client.chat.completions.create,
+                    model=self.model,
+                    messages=[
+                        {"role": "system", "content": "You are a data analyst. Base every statement on the statistics you are given and say when they are not enough to answer."},
+                        {"role": "user", "content": prompt}
+                    ],
+                    max_tokens=1500,
+                    temperature=0.1
+                )
+                insights = response.choices[0].message.content.strip()
+
+            except Exception as e:
+                logger.error(f"Error generating insights: {e}")
+                raise HTTPException(status_code=500, detail="Internal server error")
+
+        return AnalyzeResponse(
+            rows=len(df),
+            columns=stats,
+            correlations=corr,
+            insights=insights,
+            timestamp=datetime.now().isoformat()
+        )
+
+# Initialize data analyzer agent
+analyzer = DataAnalyzerAgent()
+
+@app.post("/analyze", response_model=AnalyzeResponse)
+async def analyze(request: AnalyzeRequest):
+    """Analyze the provided data"""
+    return await analyzer.analyze(request)
+
+@app.get("/health", response_model=HealthResponse)
+async def health_check():
+    """Health check endpoint"""
+    uptime = time.time() - start_time
+    return HealthResponse(
+        status="healthy",
+        uptime=f"{uptime:.2f}s",
+        timestamp=datetime.now().isoformat()
+    )
+
+@app.get("/")
+async def root():
+    """Root endpoint"""
+    return {"message": "Data Analyzer Agent API", "status": "running", "version": "1.0.0"}
+
+if __name__ == "__main__":
+    import uvicorn
+
+    logger.info("Starting Data Analyzer Agent...")
+    uvicorn.run(
+        app,
+        host="0.0.0.0",
+        port=int(os.getenv("PORT", "8080")),
+        log_level=os.getenv("LOG_LEVEL", "info").lower()
+    )
@@ -0,0 +1,87 @@
+#!/usr/bin/env python3
+"""
+Tests for the Data Analyzer Agent. The OpenAI client is mocked, so no API
+key or network access is needed.
+"""
+
+import os
+import sys
+from unittest.mock import MagicMock, patch
+
+sys.path.insert(0, os.path.dirname(os.path.dirname(os.path.abspath(__file__))))
+os.environ.setdefault("OPENAI_API_KEY", "test-key")
+
+from fastapi.testclient import TestClient
+from main import analyzer, app
+
+client = TestClient(app)
+
+CSV = "region,units,price\nnorth,10,2.5\nsouth,20,5.0\nnorth,30,7.5\n"
+
+
+def completion(content):
+    """Build a chat completion response with the given content"""
+    response = MagicMock()
+    response.choices = [MagicMock()]
+    response.choices[0].message.content = content
+    return response
+
+
+def test_health_check():
+    response = client.get("/health")
+    assert response.status_code == 200
+    assert response.json()["status"] == "healthy"
+
+
+def test_analyze_csv_statistics():
+    response = client.post("/analyze", json={"csv": CSV, "include_insights": False})
+
+    assert response.status_code == 200
+    data = response.json()
+    assert data["rows"] == 3
+    assert data["insights"] is None
+
+    columns = {c["name"]: c for c in data["columns"]}
+    assert columns["units"]["mean"] == 20.0
+    assert columns["units"]["min"] == 10.0
+    assert columns["units"]["max"] == 30.0
+    assert columns["region"]["top"] == "north"
+    assert data["correlations"]["units"]["price"] == 1.0
+
+
+def test_analyze_records():
+    records = [{"a": 1, "b": None}, {"a": 2, "b": "x"}]
+    response = client.post("/analyze", json={"records": records, "include_insights": False})
+
+    assert response.status_code == 200
+    columns = {c["name"]: c for c in response.json()["columns"]}
+    assert columns["b"]["missing"] == 1
+
+
+def test_analyze_question():
+    with patch.object(analyzer.client.chat.completions, "create", return_value=completion("North sells more units.")) as create:
+        response = client.post("/analyze", json={"csv": CSV, "question": "Which region sells more?"})
+
+    assert response.status_code == 200
+    assert response.json()["insights"] == "North sells more units."
+    assert "Which region sells more?" in create.call_args.kwargs["messages"][-1]["content"]
+
+
+def test_analyze_invalid_request():
+    assert client.post("/analyze", json={}).status_code == 400
+    assert client.post("/analyze", json={"csv": CSV, "records": [{"a": 1}]}).status_code == 400
+    assert client.post("/analyze", json={"records": []}).status_code == 400
+
+
+def test_analyze_row_limit():
+    with patch.object(analyzer, "max_rows", 2):
+        response = client.post("/analyze", json={"csv": CSV, "include_insights": False})
+
+    assert response.status_code == 400
+
+
+def test_analyze_model_error():
+    with patch.object(analyzer.client.chat.completions, "create", side_effect=RuntimeError("boom")):
+        response = client.post("/analyze", json={"csv": CSV})
+
+    assert response.status_code == 500
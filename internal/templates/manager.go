@@ -5,14 +5,17 @@ import (
 	"fmt"
 	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 	"text/template"
+
+	"gopkg.in/yaml.v3"
 )
 
 // Template directory structure embedded in binary
 //
-//go:embed chatbot/* sentiment/*
+//go:embed chatbot sentiment summarizer translator data-analyzer content-gen
 var templateFS embed.FS
 
 // AgentConfig represents the configuration for generating an agent
@@ -21,11 +24,18 @@ type AgentConfig struct {
 	Template string
 	Runtime  string
 	Model    string
+
+	// ModernPython lists Python dependencies in pyproject.toml, installed
+	// with uv, instead of requirements.txt
+	ModernPython bool
 }
 
 // Manager handles template operations
 type Manager struct {
 	templatesDir string
+	// templates holds on-disk templates keyed by template name, then by
+	// file path relative to the template root
+	templates map[string]map[string][]byte
 }
 
 // New creates a new template manager
@@ -35,15 +45,84 @@ func New() *Manager {
 	}
 }
 
-// NewWithDir creates a new template manager with custom templates directory
+// NewWithDir creates a new template manager with custom templates directory.
+// Templates found in dir take precedence over the embedded templates.
 func NewWithDir(dir string) *Manager {
-	return &Manager{
+	m := &Manager{
 		templatesDir: dir,
+		templates:    make(map[string]map[string][]byte),
+	}
+
+	if dirExists(dir) {
+		if err := m.loadTemplatesDir(dir); err != nil {
+			fmt.Printf("Warning: failed to load templates from %s: %v\n", dir, err)
+		}
+	}
+
+	return m
+}
+
+// loadTemplatesDir walks dir and loads each top-level subdirectory as a template
+func (m *Manager) loadTemplatesDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		templateRoot := filepath.Join(dir, entry.Name())
+		files := make(map[string][]byte)
+
+		err := filepath.WalkDir(templateRoot, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			// Skip hidden files and directories such as .git
+			if strings.HasPrefix(d.Name(), ".") && path != templateRoot {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			if d.IsDir() {
+				return nil
+			}
+
+			relPath, err := filepath.Rel(templateRoot, path)
+			if err != nil {
+				return err
+			}
+
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+
+			files[filepath.ToSlash(relPath)] = content
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to read template '%s': %w", entry.Name(), err)
+		}
+
+		m.templates[entry.Name()] = files
 	}
+
+	return nil
 }
 
 // Generate generates a new agent project from a template
 func (m *Manager) Generate(projectDir string, config *AgentConfig) error {
+	if err := m.ValidateTemplate(config.Template); err != nil {
+		return err
+	}
+
 	// Create agent.yaml
 	if err := m.generateAgentYAML(projectDir, config); err != nil {
 		return fmt.Errorf("failed to generate agent.yaml: %w", err)
@@ -54,6 +133,12 @@ func (m *Manager) Generate(projectDir string, config *AgentConfig) error {
 		return fmt.Errorf("failed to copy template files: %w", err)
 	}
 
+	if config.ModernPython && config.Runtime == "python" {
+		if err := convertToPyproject(projectDir, config); err != nil {
+			return fmt.Errorf("failed to generate pyproject.toml: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -182,6 +267,11 @@ spec:
 
 // copyTemplateFiles copies template files to the project directory
 func (m *Manager) copyTemplateFiles(templateDir, projectDir string, config *AgentConfig) error {
+	// Prefer templates loaded from a custom directory
+	if files, ok := m.templates[config.Template]; ok {
+		return m.writeTemplateFiles(files, projectDir)
+	}
+
 	// Use embedded templates
 	templatePrefix := config.Template
 
@@ -221,8 +311,8 @@ func (m *Manager) copyTemplateFiles(templateDir, projectDir string, config *Agen
 			return err
 		}
 
-		// Skip agent.yaml (we generate our own)
-		if relPath == "agent.yaml" || relPath == "agent.yml" {
+		// Skip agent.yaml (we generate our own) and the template metadata
+		if relPath == "agent.yaml" || relPath == "agent.yml" || relPath == "template.yaml" {
 			return nil
 		}
 
@@ -246,10 +336,36 @@ func (m *Manager) copyTemplateFiles(templateDir, projectDir string, config *Agen
 	})
 }
 
+// writeTemplateFiles writes on-disk template files to the project directory
+func (m *Manager) writeTemplateFiles(files map[string][]byte, projectDir string) error {
+	for relPath, content := range files {
+		// Skip agent.yaml (we generate our own) and the template metadata
+		if relPath == "agent.yaml" || relPath == "agent.yml" || relPath == "template.yaml" {
+			continue
+		}
+
+		destPath := filepath.Join(projectDir, filepath.FromSlash(relPath))
+		if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(destPath, content, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", relPath, err)
+		}
+	}
+
+	return nil
+}
+
 // ListTemplates returns available templates
 func (m *Manager) ListTemplates() ([]string, error) {
 	var templates []string
 
+	// Templates from a custom directory come first
+	for name := range m.templates {
+		templates = append(templates, name)
+	}
+
 	// Read from embedded FS
 	entries, err := fs.ReadDir(templateFS, ".")
 	if err != nil {
@@ -258,6 +374,9 @@ func (m *Manager) ListTemplates() ([]string, error) {
 
 	for _, entry := range entries {
 		if entry.IsDir() {
+			if _, ok := m.templates[entry.Name()]; ok {
+				continue
+			}
 			templates = append(templates, entry.Name())
 		}
 	}
@@ -267,6 +386,18 @@ func (m *Manager) ListTemplates() ([]string, error) {
 
 // GetTemplateInfo returns information about a template
 func (m *Manager) GetTemplateInfo(templateName string) (*TemplateInfo, error) {
+	// Check templates loaded from a custom directory
+	if files, ok := m.templates[templateName]; ok {
+		if data, ok := files["template.yaml"]; ok {
+			return parseTemplateMetadata(templateName, data)
+		}
+		return &TemplateInfo{
+			Name:        templateName,
+			Description: fmt.Sprintf("%s agent template (%s)", templateName, m.templatesDir),
+			Runtimes:    []string{"python"}, // Default
+		}, nil
+	}
+
 	// Check if template exists in embedded FS
 	entries, err := fs.ReadDir(templateFS, ".")
 	if err != nil {
@@ -286,25 +417,14 @@ func (m *Manager) GetTemplateInfo(templateName string) (*TemplateInfo, error) {
 		}
 	}
 
-	// If template not found in embedded FS, check if it's a supported template
-	supportedTemplates := []string{"chatbot", "sentiment", "summarizer", "translator", "data-analyzer", "content-gen"}
 	if !templateExists {
-		for _, supported := range supportedTemplates {
-			if templateName == supported {
-				return &TemplateInfo{
-					Name:        templateName,
-					Description: fmt.Sprintf("%s agent template (fallback)", templateName),
-					Runtimes:    []string{"python"}, // Default
-				}, nil
-			}
-		}
 		return nil, fmt.Errorf("template '%s' not found", templateName)
 	}
 
 	// Read template metadata (if exists)
-	metadataPath := filepath.Join(templateName, "template.yaml")
-	if _, err := fs.Stat(templateFS, metadataPath); err == nil {
-		return m.parseTemplateMetadata(metadataPath)
+	metadataPath := path.Join(templateName, "template.yaml")
+	if data, err := fs.ReadFile(templateFS, metadataPath); err == nil {
+		return parseTemplateMetadata(templateName, data)
 	}
 
 	// Return basic info
@@ -315,25 +435,98 @@ func (m *Manager) GetTemplateInfo(templateName string) (*TemplateInfo, error) {
 	}, nil
 }
 
+// ValidateTemplate checks that a template contains every file listed in the
+// required_files of its template.yaml. Templates without files of their own,
+// which are generated in code, are always complete.
+func (m *Manager) ValidateTemplate(templateName string) error {
+	hasFile := func(name string) bool {
+		if files, ok := m.templates[templateName]; ok {
+			_, exists := files[name]
+			return exists
+		}
+		_, err := fs.Stat(templateFS, path.Join(templateName, name))
+		return err == nil
+	}
+
+	if !hasFile("template.yaml") {
+		return nil
+	}
+
+	info, err := m.GetTemplateInfo(templateName)
+	if err != nil {
+		return err
+	}
+
+	var missing []string
+	for _, required := range info.RequiredFiles {
+		if !hasFile(required) {
+			missing = append(missing, required)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("template '%s' is incomplete, missing: %s", templateName, strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
 // TemplateInfo represents template information
 type TemplateInfo struct {
-	Name        string   `yaml:"name"`
-	Description string   `yaml:"description"`
-	Author      string   `yaml:"author,omitempty"`
-	Version     string   `yaml:"version,omitempty"`
-	Runtimes    []string `yaml:"runtimes"`
-	Tags        []string `yaml:"tags,omitempty"`
+	Name          string   `yaml:"name"`
+	Description   string   `yaml:"description"`
+	Author        string   `yaml:"author,omitempty"`
+	Version       string   `yaml:"version,omitempty"`
+	Runtimes      []string `yaml:"runtimes"`
+	Tags          []string `yaml:"tags,omitempty"`
+	RequiredFiles []string `yaml:"required_files,omitempty"`
 }
 
-// parseTemplateMetadata parses template metadata file
-func (m *Manager) parseTemplateMetadata(path string) (*TemplateInfo, error) {
-	// This would parse template.yaml metadata file
-	// For now, return basic info
-	return &TemplateInfo{
-		Name:        "template",
-		Description: "Agent template",
-		Runtimes:    []string{"python"},
-	}, nil
+// templateYAML is the format of a template's template.yaml
+type templateYAML struct {
+	Name          string   `yaml:"name"`
+	Description   string   `yaml:"description"`
+	Author        string   `yaml:"author"`
+	Version       string   `yaml:"version"`
+	Runtimes      []string `yaml:"runtimes"`
+	Tags          []string `yaml:"tags"`
+	RequiredFiles []string `yaml:"required_files"`
+}
+
+// parseTemplateMetadata parses the content of a template.yaml. Missing
+// fields fall back to the template name and the python runtime.
+func parseTemplateMetadata(templateName string, data []byte) (*TemplateInfo, error) {
+	var metadata templateYAML
+	if err := yaml.Unmarshal(data, &metadata); err != nil {
+		return nil, fmt.Errorf("invalid template.yaml for template '%s': %w", templateName, err)
+	}
+
+	for _, required := range metadata.RequiredFiles {
+		clean := path.Clean(required)
+		if required == "" || path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+			return nil, fmt.Errorf("invalid template.yaml for template '%s': required file '%s' must be a relative path inside the template", templateName, required)
+		}
+	}
+
+	info := &TemplateInfo{
+		Name:          metadata.Name,
+		Description:   metadata.Description,
+		Author:        metadata.Author,
+		Version:       metadata.Version,
+		Runtimes:      metadata.Runtimes,
+		Tags:          metadata.Tags,
+		RequiredFiles: metadata.RequiredFiles,
+	}
+	if info.Name == "" {
+		info.Name = templateName
+	}
+	if info.Description == "" {
+		info.Description = fmt.Sprintf("%s agent template", templateName)
+	}
+	if len(info.Runtimes) == 0 {
+		info.Runtimes = []string{"python"} // Default
+	}
+
+	return info, nil
 }
 
 // Helper functions
@@ -432,7 +625,7 @@ async def health():
 
 if __name__ == "__main__":
     import uvicorn
-    uvicorn.run(app, host="0.0.0.0", port=8080)
+    uvicorn.run(app, host="0.0.0.0", port=int(os.getenv("PORT", "8080")))
 `
 
 	// Create requirements.txt
@@ -494,7 +687,7 @@ async def health():
 
 if __name__ == "__main__":
     import uvicorn
-    uvicorn.run(app, host="0.0.0.0", port=8080)
+    uvicorn.run(app, host="0.0.0.0", port=int(os.getenv("PORT", "8080")))
 `
 
 	// Create requirements.txt
@@ -546,7 +739,7 @@ async def health():
 
 if __name__ == "__main__":
     import uvicorn
-    uvicorn.run(app, host="0.0.0.0", port=8080)
+    uvicorn.run(app, host="0.0.0.0", port=int(os.getenv("PORT", "8080")))
 `
 
 	// Create requirements.txt
@@ -0,0 +1,99 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateTemplate(t *testing.T) {
+	tests := []struct {
+		name         string
+		templateYAML string
+		files        []string
+		wantErr      string // substring of the error; empty for success
+		wantName     string // expected TemplateInfo.Name when valid
+	}{
+		{
+			name:         "invalid YAML",
+			templateYAML: "name: broken\nruntimes: [python, node\n",
+			wantErr:      "invalid template.yaml for template 'custom'",
+		},
+		{
+			name:         "missing name falls back to the template name",
+			templateYAML: "description: no name\nrequired_files: [main.py]\n",
+			files:        []string{"main.py"},
+			wantName:     "custom",
+		},
+		{
+			name:         "absolute required file",
+			templateYAML: "name: custom\nrequired_files: [/etc/passwd]\n",
+			wantErr:      "must be a relative path inside the template",
+		},
+		{
+			name:         "required file outside the template",
+			templateYAML: "name: custom\nrequired_files: [../secrets.txt]\n",
+			wantErr:      "must be a relative path inside the template",
+		},
+		{
+			name:         "missing required file",
+			templateYAML: "name: custom\nrequired_files: [main.py, requirements.txt]\n",
+			files:        []string{"main.py"},
+			wantErr:      "is incomplete, missing: requirements.txt",
+		},
+		{
+			name:         "valid template",
+			templateYAML: "name: my-agent\ndescription: A valid template\nruntimes: [python]\nrequired_files: [main.py, src/app.py]\n",
+			files:        []string{"main.py", "src/app.py"},
+			wantName:     "my-agent",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			root := filepath.Join(dir, "custom")
+			writeFile(t, filepath.Join(root, "template.yaml"), tt.templateYAML)
+			for _, file := range tt.files {
+				writeFile(t, filepath.Join(root, file), "# "+file+"\n")
+			}
+
+			m := NewWithDir(dir)
+			err := m.ValidateTemplate("custom")
+			if tt.wantErr != "" {
+				if err == nil {
+					t.Fatalf("ValidateTemplate() succeeded, want error containing %q", tt.wantErr)
+				}
+				if !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ValidateTemplate() error = %q, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ValidateTemplate() error = %v", err)
+			}
+
+			info, err := m.GetTemplateInfo("custom")
+			if err != nil {
+				t.Fatalf("GetTemplateInfo() error = %v", err)
+			}
+			if info.Name != tt.wantName {
+				t.Errorf("Name = %q, want %q", info.Name, tt.wantName)
+			}
+			if len(info.RequiredFiles) != len(tt.files) {
+				t.Errorf("RequiredFiles = %v, want %v", info.RequiredFiles, tt.files)
+			}
+		})
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,102 @@
+package templates
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// requiresPython matches the Python version of the images the builder
+// generates
+const requiresPython = ">=3.11"
+
+// uvLockPlaceholder stands in for uv.lock until 'uv lock' resolves the
+// dependencies; uv replaces a lock file it cannot parse
+const uvLockPlaceholder = `# Placeholder lock file generated by Agent-as-Code.
+# Run 'uv lock' to resolve and pin the dependencies in pyproject.toml.
+`
+
+// invalidProjectChars are the characters not allowed in a project name
+var invalidProjectChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// convertToPyproject replaces the requirements.txt a template wrote to
+// projectDir with a pyproject.toml listing the same dependencies under
+// [project], and adds a uv.lock placeholder
+func convertToPyproject(projectDir string, config *AgentConfig) error {
+	requirementsPath := filepath.Join(projectDir, "requirements.txt")
+	data, err := os.ReadFile(requirementsPath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	var dependencies []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.Index(line, " #"); i != -1 {
+			line = strings.TrimSpace(line[:i])
+		}
+		// Options such as -r and --index-url have no pyproject.toml
+		// equivalent
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "-") {
+			continue
+		}
+		dependencies = append(dependencies, line)
+	}
+
+	if err := os.WriteFile(filepath.Join(projectDir, "pyproject.toml"), []byte(pyprojectTOML(config, dependencies)), 0644); err != nil {
+		return fmt.Errorf("failed to write pyproject.toml: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(projectDir, "uv.lock"), []byte(uvLockPlaceholder), 0644); err != nil {
+		return fmt.Errorf("failed to write uv.lock: %w", err)
+	}
+	if err := os.Remove(requirementsPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	// Point the README's install instructions at uv
+	readmePath := filepath.Join(projectDir, "README.md")
+	if readme, err := os.ReadFile(readmePath); err == nil {
+		updated := strings.ReplaceAll(string(readme), "pip install -r requirements.txt", "uv pip install -r pyproject.toml")
+		if err := os.WriteFile(readmePath, []byte(updated), 0644); err != nil {
+			return fmt.Errorf("failed to write README.md: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// pyprojectTOML returns a pyproject.toml for the project with dependencies
+func pyprojectTOML(config *AgentConfig, dependencies []string) string {
+	name := strings.Trim(invalidProjectChars.ReplaceAllString(strings.ToLower(config.Name), "-"), "-._")
+	if name == "" {
+		name = "agent"
+	}
+
+	var b strings.Builder
+	b.WriteString("[project]\n")
+	fmt.Fprintf(&b, "name = %s\n", strconv.Quote(name))
+	b.WriteString("version = \"0.1.0\"\n")
+	fmt.Fprintf(&b, "description = %s\n", strconv.Quote(config.Name+" agent generated by Agent-as-Code"))
+	fmt.Fprintf(&b, "requires-python = %s\n", strconv.Quote(requiresPython))
+	if len(dependencies) == 0 {
+		b.WriteString("dependencies = []\n")
+	} else {
+		b.WriteString("dependencies = [\n")
+		for _, dep := range dependencies {
+			fmt.Fprintf(&b, "    %s,\n", strconv.Quote(dep))
+		}
+		b.WriteString("]\n")
+	}
+
+	// The agent runs as 'python main.py' from its directory and is never
+	// installed as a package, so uv only manages its environment
+	b.WriteString("\n[tool.uv]\n")
+	b.WriteString("package = false\n")
+	return b.String()
+}
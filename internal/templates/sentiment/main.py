@@ -4,6 +4,7 @@ Sentiment Analysis Agent - Analyzes text sentiment using AI
 """
 
 import os
+import time
 import logging
 from datetime import datetime
 from typing import Dict, List, Optional
@@ -16,6 +17,9 @@ import openai
 logging.basicConfig(level=os.getenv("LOG_LEVEL", "INFO"))
 logger = logging.getLogger(__name__)
 
+# Used by the health check to report uptime
+start_time = time.time()
+
 # Initialize FastAPI app
 app = FastAPI(
     title="Sentiment Analysis Agent",
@@ -97,7 +101,6 @@ async def analyze_sentiment(request: SentimentRequest):
 @app.get("/health", response_model=HealthResponse)
 async def health_check():
     """Health check endpoint"""
-    import time
     uptime = time.time() - start_time
     return HealthResponse(
         status="healthy",
@@ -112,14 +115,11 @@ async def root():
 
 if __name__ == "__main__":
     import uvicorn
-    import time
-    
-    start_time = time.time()
     
     logger.info("Starting Sentiment Analysis Agent...")
     uvicorn.run(
         app, 
         host="0.0.0.0", 
-        port=8080,
+        port=int(os.getenv("PORT", "8080")),
         log_level=os.getenv("LOG_LEVEL", "info").lower()
     )
@@ -0,0 +1,64 @@
+#!/usr/bin/env python3
+"""
+Tests for the Sentiment Analysis Agent. The OpenAI client is mocked, so no
+API key or network access is needed.
+"""
+
+import os
+import sys
+from unittest.mock import MagicMock, patch
+
+sys.path.insert(0, os.path.dirname(os.path.dirname(os.path.abspath(__file__))))
+os.environ.setdefault("OPENAI_API_KEY", "test-key")
+
+import pytest
+from fastapi.testclient import TestClient
+from main import app, sentiment_agent
+
+client = TestClient(app)
+
+
+def completion(content):
+    """Build a chat completion response with the given content"""
+    response = MagicMock()
+    response.choices = [MagicMock()]
+    response.choices[0].message.content = content
+    return response
+
+
+def test_health_check():
+    response = client.get("/health")
+    assert response.status_code == 200
+    assert response.json()["status"] == "healthy"
+
+
+@pytest.mark.parametrize("answer", ["positive", "negative", "neutral"])
+def test_analyze_returns_sentiment(answer):
+    with patch.object(sentiment_agent.client.chat.completions, "create", return_value=completion(f" {answer.upper()} ")):
+        response = client.post("/analyze", json={"text": "Some text"})
+
+    assert response.status_code == 200
+    data = response.json()
+    assert data["sentiment"] == answer
+    assert data["confidence"] is not None
+
+
+def test_analyze_unexpected_answer_is_neutral():
+    with patch.object(sentiment_agent.client.chat.completions, "create", return_value=completion("it depends")):
+        response = client.post("/analyze", json={"text": "Some text"})
+
+    assert response.json()["sentiment"] == "neutral"
+
+
+def test_analyze_without_confidence():
+    with patch.object(sentiment_agent.client.chat.completions, "create", return_value=completion("positive")):
+        response = client.post("/analyze", json={"text": "Great!", "include_confidence": False})
+
+    assert response.json()["confidence"] is None
+
+
+def test_analyze_model_error():
+    with patch.object(sentiment_agent.client.chat.completions, "create", side_effect=RuntimeError("boom")):
+        response = client.post("/analyze", json={"text": "Some text"})
+
+    assert response.status_code == 500
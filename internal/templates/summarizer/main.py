@@ -0,0 +1,179 @@
+#!/usr/bin/env python3
+"""
+Summarizer Agent - Summarizes documents and extracts their key points
+"""
+
+import os
+import time
+import asyncio
+import logging
+from datetime import datetime
+from typing import List, Optional
+
+from fastapi import FastAPI, HTTPException
+from pydantic import BaseModel
+import openai
+
+# Configure logging
+logging.basicConfig(level=os.getenv("LOG_LEVEL", "INFO"))
+logger = logging.getLogger(__name__)
+
+# Used by the health check to report uptime
+start_time = time.time()
+
+# Initialize FastAPI app
+app = FastAPI(
+    title="Summarizer Agent",
+    description="AI-powered document summarization with key point extraction",
+    version="1.0.0"
+)
+
+STYLES = {
+    "brief": "Write a brief summary of one short paragraph.",
+    "bullets": "Write the summary as a list of bullet points, one per line, each starting with '- '.",
+    "detailed": "Write a detailed summary that keeps every important fact, figure and conclusion.",
+}
+
+# Request/Response models
+class SummarizeRequest(BaseModel):
+    text: str
+    style: Optional[str] = "brief"
+    max_words: Optional[int] = 150
+    include_key_points: Optional[bool] = False
+
+class SummarizeResponse(BaseModel):
+    summary: str
+    key_points: Optional[List[str]] = None
+    chunks: int
+    original_words: int
+    summary_words: int
+    timestamp: str
+
+class HealthResponse(BaseModel):
+    status: str
+    uptime: str
+    timestamp: str
+
+def split_into_chunks(text: str, chunk_words: int) -> List[str]:
+    """Split text into chunks of at most chunk_words words, keeping paragraphs together where possible"""
+    chunks, current = [], []
+    for paragraph in text.split("\n\n"):
+        words = paragraph.split()
+        if current and len(current) + len(words) > chunk_words:
+            chunks.append(" ".join(current))
+            current = []
+        while len(words) > chunk_words:
+            chunks.append(" ".join(words[:chunk_words]))
+            words = words[chunk_words:]
+        current.extend(words)
+    if current:
+        chunks.append(" ".join(current))
+    return chunks
+
+class SummarizerAgent:
+    def __init__(self):
+        self.client = openai.OpenAI(
+            api_key=os.getenv("OPENAI_API_KEY")
+        )
+        self.model = os.getenv("MODEL_NAME", "gpt-4")
+        self.chunk_words = int(os.getenv("CHUNK_WORDS", "2000"))
+
+    async def complete(self, system: str, prompt: str, max_tokens: int) -> str:
+        """Send a single prompt to the model and return its answer"""
+        response = await asyncio.to_thread(
+            self.client.chat.completions.create,
+            model=self.model,
+            messages=[
+                {"role": "system", "content": system},
+                {"role": "user", "content": prompt}
+            ],
+            max_tokens=max_tokens,
+            temperature=0.3
+        )
+        return response.choices[0].message.content.strip()
+
+    async def summarize(self, request: SummarizeRequest) -> SummarizeResponse:
+        """Summarize the text, summarizing long texts chunk by chunk first"""
+        if not request.text.strip():
+            raise HTTPException(status_code=400, detail="text must not be empty")
+        if request.style not in STYLES:
+            raise HTTPException(status_code=400, detail=f"style must be one of: {', '.join(STYLES)}")
+
+        try:
+            system = "You are an expert editor who writes accurate, faithful summaries."
+            chunks = split_into_chunks(request.text, self.chunk_words)
+
+            # Summarize each chunk, then summarize the combined chunk summaries
+            text = request.text
+            if len(chunks) > 1:
+                partials = []
+                for i, chunk in enumerate(chunks):
+                    logger.info(f"Summarizing chunk {i + 1}/{len(chunks)}")
+                    partials.append(await self.complete(
+                        system,
+                        f"{STYLES['detailed']}\n\nText:\n{chunk}",
+                        max_tokens=500
+                    ))
+                text = "\n\n".join(partials)
+
+            summary = await self.complete(
+                system,
+                f"{STYLES[request.style]} Use at most {request.max_words} words.\n\nText:\n{text}",
+                max_tokens=request.max_words * 2
+            )
+
+            key_points = None
+            if request.include_key_points:
+                answer = await self.complete(
+                    system,
+                    f"List the key points of the following text, one per line, each starting with '- '.\n\nText:\n{text}",
+                    max_tokens=300
+                )
+                key_points = [line.lstrip("-*• ").strip() for line in answer.splitlines() if line.strip()]
+
+            return SummarizeResponse(
+                summary=summary,
+                key_points=key_points,
+                chunks=len(chunks),
+                original_words=len(request.text.split()),
+                summary_words=len(summary.split()),
+                timestamp=datetime.now().isoformat()
+            )
+
+        except Exception as e:
+            logger.error(f"Error summarizing text: {e}")
+            raise HTTPException(status_code=500, detail="Internal server error")
+
+# Initialize summarizer agent
+summarizer = SummarizerAgent()
+
+@app.post("/summarize", response_model=SummarizeResponse)
+async def summarize(request: SummarizeRequest):
+    """Summarize the provided text"""
+    return await summarizer.summarize(request)
+
+@app.get("/health", response_model=HealthResponse)
+async def health_check():
+    """Health check endpoint"""
+    uptime = time.time() - start_time
+    return HealthResponse(
+        status="healthy",
+        uptime=f"{uptime:.2f}s",
+        timestamp=datetime.now().isoformat()
+    )
+
+@app.get("/")
+async def root():
+    """Root endpoint"""
+    return {"message": "Summarizer Agent API", "status": "running", "version": "1.0.0"}
+
+if __name__ == "__main__":
+    import uvicorn
+
+    logger.info("Starting Summarizer Agent...")
+    uvicorn.run(
+        app,
+        host="0.0.0.0",
+        port=int(os.getenv("PORT", "8080")),
+        log_level=os.getenv("LOG_LEVEL", "info").lower()
+    )
@@ -0,0 +1,81 @@
+#!/usr/bin/env python3
+"""
+Tests for the Summarizer Agent. The OpenAI client is mocked, so no API key
+or network access is needed.
+"""
+
+import os
+import sys
+from unittest.mock import MagicMock, patch
+
+sys.path.insert(0, os.path.dirname(os.path.dirname(os.path.abspath(__file__))))
+os.environ.setdefault("OPENAI_API_KEY", "test-key")
+
+from fastapi.testclient import TestClient
+from main import app, split_into_chunks, summarizer
+
+client = TestClient(app)
+
+
+def completion(content):
+    """Build a chat completion response with the given content"""
+    response = MagicMock()
+    response.choices = [MagicMock()]
+    response.choices[0].message.content = content
+    return response
+
+
+def test_health_check():
+    response = client.get("/health")
+    assert response.status_code == 200
+    assert response.json()["status"] == "healthy"
+
+
+def test_split_into_chunks():
+    assert split_into_chunks("a b c\n\nd e\n\nf g h i j k l", 4) == ["a b c", "d e", "f g h i", "j k l"]
+    assert split_into_chunks("short text", 100) == ["short text"]
+
+
+def test_summarize_short_text():
+    with patch.object(summarizer.client.chat.completions, "create", return_value=completion("A short summary.")) as create:
+        response = client.post("/summarize", json={"text": "Some long article text.", "max_words": 50})
+
+    assert response.status_code == 200
+    data = response.json()
+    assert data["summary"] == "A short summary."
+    assert data["chunks"] == 1
+    assert data["key_points"] is None
+    assert create.call_count == 1
+    assert "at most 50 words" in create.call_args.kwargs["messages"][-1]["content"]
+
+
+def test_summarize_long_text_in_chunks():
+    text = "\n\n".join(["word " * 10] * 3)
+    with patch.object(summarizer, "chunk_words", 10), \
+         patch.object(summarizer.client.chat.completions, "create", return_value=completion("Summary.")) as create:
+        response = client.post("/summarize", json={"text": text})
+
+    assert response.status_code == 200
+    assert response.json()["chunks"] == 3
+    # One call per chunk and one for the final summary
+    assert create.call_count == 4
+
+
+def test_summarize_key_points():
+    answers = [completion("A summary."), completion("- First point\n- Second point")]
+    with patch.object(summarizer.client.chat.completions, "create", side_effect=answers):
+        response = client.post("/summarize", json={"text": "Some text.", "include_key_points": True})
+
+    assert response.json()["key_points"] == ["First point", "Second point"]
+
+
+def test_summarize_invalid_request():
+    assert client.post("/summarize", json={"text": "  "}).status_code == 400
+    assert client.post("/summarize", json={"text": "Some text.", "style": "poem"}).status_code == 400
+
+
+def test_summarize_model_error():
+    with patch.object(summarizer.client.chat.completions, "create", side_effect=RuntimeError("boom")):
+        response = client.post("/summarize", json={"text": "Some text."})
+
+    assert response.status_code == 500
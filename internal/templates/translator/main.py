@@ -0,0 +1,188 @@
+#!/usr/bin/env python3
+"""
+Translator Agent - Translates text between languages with context awareness
+"""
+
+import os
+import time
+import asyncio
+import logging
+from datetime import datetime
+from typing import List, Optional
+
+from fastapi import FastAPI, HTTPException
+from pydantic import BaseModel
+from langdetect import DetectorFactory, LangDetectException, detect
+import openai
+
+# Configure logging
+logging.basicConfig(level=os.getenv("LOG_LEVEL", "INFO"))
+logger = logging.getLogger(__name__)
+
+# Make language detection deterministic
+DetectorFactory.seed = 0
+
+# Used by the health check to report uptime
+start_time = time.time()
+
+# Initialize FastAPI app
+app = FastAPI(
+    title="Translator Agent",
+    description="AI-powered translation between languages with context awareness",
+    version="1.0.0"
+)
+
+LANGUAGE_NAMES = {
+    "en": "English", "es": "Spanish", "fr": "French", "de": "German",
+    "it": "Italian", "pt": "Portuguese", "ru": "Russian", "ja": "Japanese",
+    "ko": "Korean", "zh": "Chinese", "ar": "Arabic", "hi": "Hindi",
+}
+
+# Request/Response models
+class TranslateRequest(BaseModel):
+    text: str
+    target_language: Optional[str] = None
+    source_language: Optional[str] = None
+    context: Optional[str] = None
+
+class TranslateResponse(BaseModel):
+    translation: str
+    source_language: str
+    target_language: str
+    timestamp: str
+
+class DetectRequest(BaseModel):
+    text: str
+
+class DetectResponse(BaseModel):
+    language: str
+    timestamp: str
+
+class HealthResponse(BaseModel):
+    status: str
+    uptime: str
+    timestamp: str
+
+class TranslatorAgent:
+    def __init__(self):
+        self.client = openai.OpenAI(
+            api_key=os.getenv("OPENAI_API_KEY")
+        )
+        self.model = os.getenv("MODEL_NAME", "gpt-4")
+        self.default_target = os.getenv("DEFAULT_TARGET_LANGUAGE", "en")
+        self.supported_languages: List[str] = os.getenv(
+            "SUPPORTED_LANGUAGES", ",".join(LANGUAGE_NAMES)
+        ).split(",")
+
+    def detect_language(self, text: str) -> str:
+        """Detect the language of text, returning its ISO 639-1 code"""
+        try:
+            # langdetect reports Chinese as zh-cn or zh-tw
+            return detect(text).split("-")[0]
+        except LangDetectException:
+            raise HTTPException(status_code=400, detail="could not detect the language of the text")
+
+    def check_language(self, code: str):
+        """Reject languages that are not supported"""
+        if code not in self.supported_languages:
+            raise HTTPException(
+                status_code=400,
+                detail=f"unsupported language '{code}' (supported: {', '.join(self.supported_languages)})"
+            )
+
+    async def translate(self, request: TranslateRequest) -> TranslateResponse:
+        """Translate the text into the target language"""
+        if not request.text.strip():
+            raise HTTPException(status_code=400, detail="text must not be empty")
+
+        target = request.target_language or self.default_target
+        source = request.source_language or self.detect_language(request.text)
+        self.check_language(target)
+        self.check_language(source)
+
+        # Nothing to translate
+        if source == target:
+            return TranslateResponse(
+                translation=request.text,
+                source_language=source,
+                target_language=target,
+                timestamp=datetime.now().isoformat()
+            )
+
+        try:
+            source_name = LANGUAGE_NAMES.get(source, source)
+            target_name = LANGUAGE_NAMES.get(target, target)
+
+            prompt = f"Translate the following text from {source_name} to {target_name}."
+            if request.context:
+                prompt += f" Context for the translation: {request.context}"
+            prompt += f"\n\nText:\n{request.text}"
+
+            response = await asyncio.to_thread(
+                self.client.chat.completions.create,
+                model=self.model,
+                messages=[
+                    {"role": "system", "content": "You are a professional translator. Preserve meaning, tone and formatting. Respond with only the translation."},
+                    {"role": "user", "content": prompt}
+                ],
+                max_tokens=2000,
+                temperature=0.2
+            )
+
+            return TranslateResponse(
+                translation=response.choices[0].message.content.strip(),
+                source_language=source,
+                target_language=target,
+                timestamp=datetime.now().isoformat()
+            )
+
+        except Exception as e:
+            logger.error(f"Error translating text: {e}")
+            raise HTTPException(status_code=500, detail="Internal server error")
+
+# Initialize translator agent
+translator = TranslatorAgent()
+
+@app.post("/translate", response_model=TranslateResponse)
+async def translate(request: TranslateRequest):
+    """Translate the provided text"""
+    return await translator.translate(request)
+
+@app.post("/detect", response_model=DetectResponse)
+async def detect_language(request: DetectRequest):
+    """Detect the language of the provided text"""
+    return DetectResponse(
+        language=translator.detect_language(request.text),
+        timestamp=datetime.now().isoformat()
+    )
+
+@app.get("/languages")
+async def languages():
+    """List the supported languages"""
+    return {code: LANGUAGE_NAMES.get(code, code) for code in translator.supported_languages}
+
+@app.get("/health", response_model=HealthResponse)
+async def health_check():
+    """Health check endpoint"""
+    uptime = time.time() - start_time
+    return HealthResponse(
+        status="healthy",
+        uptime=f"{uptime:.2f}s",
+        timestamp=datetime.now().isoformat()
+    )
+
+@app.get("/")
+async def root():
+    """Root endpoint"""
+    return {"message": "Translator Agent API", "status": "running", "version": "1.0.0"}
+
+if __name__ == "__main__":
+    import uvicorn
+
+    logger.info("Starting Translator Agent...")
+    uvicorn.run(
+        app,
+        host="0.0.0.0",
+        port=int(os.getenv("PORT", "8080")),
+        log_level=os.getenv("LOG_LEVEL", "info").lower()
+    )
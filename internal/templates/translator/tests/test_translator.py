@@ -0,0 +1,86 @@
+#!/usr/bin/env python3
+"""
+Tests for the Translator Agent. The OpenAI client is mocked, so no API key
+or network access is needed.
+"""
+
+import os
+import sys
+from unittest.mock import MagicMock, patch
+
+sys.path.insert(0, os.path.dirname(os.path.dirname(os.path.abspath(__file__))))
+os.environ.setdefault("OPENAI_API_KEY", "test-key")
+
+from fastapi.testclient import TestClient
+from main import app, translator
+
+client = TestClient(app)
+
+
+def completion(content):
+    """Build a chat completion response with the given content"""
+    response = MagicMock()
+    response.choices = [MagicMock()]
+    response.choices[0].message.content = content
+    return response
+
+
+def test_health_check():
+    response = client.get("/health")
+    assert response.status_code == 200
+    assert response.json()["status"] == "healthy"
+
+
+def test_languages():
+    response = client.get("/languages")
+    assert response.status_code == 200
+    assert response.json()["es"] == "Spanish"
+
+
+def test_translate():
+    with patch.object(translator.client.chat.completions, "create", return_value=completion("Hola, mundo")) as create:
+        response = client.post("/translate", json={
+            "text": "Hello, world",
+            "source_language": "en",
+            "target_language": "es",
+            "context": "a greeting",
+        })
+
+    assert response.status_code == 200
+    data = response.json()
+    assert data["translation"] == "Hola, mundo"
+    assert data["source_language"] == "en"
+    assert data["target_language"] == "es"
+
+    prompt = create.call_args.kwargs["messages"][-1]["content"]
+    assert "from English to Spanish" in prompt
+    assert "a greeting" in prompt
+
+
+def test_translate_detects_source_language():
+    text = "This is a longer English sentence so that the language can be detected reliably."
+    with patch.object(translator.client.chat.completions, "create", return_value=completion("Ceci est une phrase.")):
+        response = client.post("/translate", json={"text": text, "target_language": "fr"})
+
+    assert response.status_code == 200
+    assert response.json()["source_language"] == "en"
+
+
+def test_translate_same_language_skips_model():
+    with patch.object(translator.client.chat.completions, "create") as create:
+        response = client.post("/translate", json={"text": "Hello", "source_language": "en", "target_language": "en"})
+
+    assert response.json()["translation"] == "Hello"
+    create.assert_not_called()
+
+
+def test_translate_unsupported_language():
+    response = client.post("/translate", json={"text": "Hello", "source_language": "en", "target_language": "xx"})
+    assert response.status_code == 400
+
+
+def test_translate_model_error():
+    with patch.object(translator.client.chat.completions, "create", side_effect=RuntimeError("boom")):
+        response = client.post("/translate", json={"text": "Hello", "source_language": "en", "target_language": "de"})
+
+    assert response.status_code == 500
@@ -6,6 +6,7 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"net/url"
 	"os"
 	"path/filepath"
 
@@ -22,6 +23,9 @@ func main() {
 		platform     = flag.String("platform", "", "Specific platform to upload")
 		arch         = flag.String("arch", "", "Specific architecture to upload")
 		dryRun       = flag.Bool("dry-run", false, "Show what would be uploaded")
+		output       = flag.String("output", "text", "Output format (text|json)")
+		parallelism  = flag.Int("parallelism", api.DefaultParallelism, "Number of concurrent uploads with -all-platforms")
+		proxy        = flag.String("proxy", "", "Proxy URL, overriding HTTPS_PROXY and HTTP_PROXY")
 	)
 
 	flag.Parse()
@@ -32,6 +36,17 @@ func main() {
 		os.Exit(1)
 	}
 
+	if *output != "text" && *output != "json" {
+		fmt.Println("Error: output must be text or json")
+		os.Exit(1)
+	}
+
+	// With JSON output, stdout carries only the JSON document
+	out := os.Stdout
+	if *output == "json" {
+		out = os.Stderr
+	}
+
 	authToken := *token
 	if authToken == "" {
 		authToken = os.Getenv("AGENT_REGISTRY_TOKEN")
@@ -41,24 +56,34 @@ func main() {
 		}
 	}
 
-	fmt.Printf("🚀 Agent CLI Binary Uploader\n")
-	fmt.Printf("Version: %s\n", *version)
-	fmt.Printf("Registry: %s\n", *registry)
+	fmt.Fprintf(out, "🚀 Agent CLI Binary Uploader\n")
+	fmt.Fprintf(out, "Version: %s\n", *version)
+	fmt.Fprintf(out, "Registry: %s\n", *registry)
 
 	if *dryRun {
-		fmt.Println("🔍 DRY RUN - No actual uploads will be performed")
+		fmt.Fprintln(out, "🔍 DRY RUN - No actual uploads will be performed")
 	}
 
 	uploader := api.NewUploader(*registry, authToken, *version)
+	uploader.SetParallelism(*parallelism)
+	if *proxy != "" {
+		if err := uploader.SetProxy(*proxy); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		// Print the proxy without the credentials it may carry
+		proxyURL, _ := url.Parse(*proxy)
+		fmt.Fprintf(out, "Proxy: %s\n", proxyURL.Redacted())
+	}
 
 	var results []*api.UploadResult
 
 	if *allPlatforms {
-		fmt.Printf("📦 Uploading agent CLI binaries for all platforms from %s...\n", *binDir)
+		fmt.Fprintf(out, "📦 Uploading agent CLI binaries for all platforms from %s...\n", *binDir)
 		if !*dryRun {
 			results = uploader.UploadAllPlatforms(*binDir)
 		} else {
-			fmt.Println("Would upload all platform binaries")
+			fmt.Fprintln(out, "Would upload all platform binaries")
 			results = []*api.UploadResult{
 				{Platform: "linux", Architecture: "amd64", Success: true},
 				{Platform: "linux", Architecture: "arm64", Success: true},
@@ -74,7 +99,7 @@ func main() {
 			binaryPath += ".exe"
 		}
 
-		fmt.Printf("📦 Uploading agent CLI binary for %s/%s...\n", *platform, *arch)
+		fmt.Fprintf(out, "📦 Uploading agent CLI binary for %s/%s...\n", *platform, *arch)
 
 		if !*dryRun {
 			opts := api.UploadOptions{
@@ -85,7 +110,7 @@ func main() {
 			result := uploader.UploadBinary(opts)
 			results = []*api.UploadResult{result}
 		} else {
-			fmt.Printf("Would upload: %s\n", binaryPath)
+			fmt.Fprintf(out, "Would upload: %s\n", binaryPath)
 			results = []*api.UploadResult{
 				{Platform: *platform, Architecture: *arch, Success: true},
 			}
@@ -96,8 +121,13 @@ func main() {
 	}
 
 	// Display results
-	summary := api.GetUploadSummary(results)
-	fmt.Print(summary)
+	if *output == "json" {
+		if err := api.WriteAsJSON(results, os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+	} else {
+		fmt.Print(api.GetUploadSummary(results))
+	}
 
 	// Check for failures
 	for _, result := range results {
@@ -106,8 +136,8 @@ func main() {
 		}
 	}
 
-	fmt.Println("\n✅ Agent CLI binaries are now available for installation!")
-	fmt.Printf("Users can install via:\n")
-	fmt.Printf("  pip install agent-as-code==%s\n", *version)
-	fmt.Printf("  curl -L %s/install.sh | sh\n", *registry)
+	fmt.Fprintln(out, "\n✅ Agent CLI binaries are now available for installation!")
+	fmt.Fprintf(out, "Users can install via:\n")
+	fmt.Fprintf(out, "  pip install agent-as-code==%s\n", *version)
+	fmt.Fprintf(out, "  curl -L %s/install.sh | sh\n", *registry)
 }